@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/agent"
 	"github.com/grnsv/GophKeeper/internal/client/app"
+	"github.com/grnsv/GophKeeper/internal/client/cli"
 	"github.com/grnsv/GophKeeper/internal/client/config"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
 	"github.com/grnsv/GophKeeper/internal/client/service"
 	"github.com/grnsv/GophKeeper/internal/client/storage"
+	"github.com/grnsv/GophKeeper/internal/client/transport"
+	"github.com/grnsv/GophKeeper/internal/telemetry"
+	"go.opentelemetry.io/otel"
 )
 
 // buildVersion is set at compile time using -ldflags.
@@ -36,32 +45,85 @@ func fatalIfErr(prefix string, err error) {
 }
 
 func main() {
+	os.Exit(run())
+}
+
+// run starts the terminal UI, or runs the subcommand given after the flags.
+func run() int {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "--version", "-v":
-			fmt.Fprintf(os.Stdout, "Version: %s\nBuild date: %s\n", buildVersion, buildDate)
-			os.Exit(0)
-		}
+	var version bool
+	flag.BoolVar(&version, "version", false, "print version and exit")
+	flag.BoolVar(&version, "v", false, "print version and exit")
+	traceFile := flag.String("trace", "", "record traces of sync runs and server requests to `FILE`, e.g. for a bug report")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command [args]]\n\nWithout a command the terminal UI is started.\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output())
+		cli.PrintCommands(flag.CommandLine.Output())
+	}
+	flag.Parse()
+
+	if version {
+		fmt.Fprintf(os.Stdout, "Version: %s\nBuild date: %s\n", buildVersion, buildDate)
+		return 0
 	}
 
 	cfg, err := config.Parse()
 	fatalIfErr("config error", err)
+	if *traceFile != "" {
+		cfg.Tracing = config.TracingConfig{Exporter: telemetry.ExporterFile, File: *traceFile}
+	}
+
+	tracing, err := telemetry.NewTracerProvider(ctx, cfg.Tracing.Telemetry(), "gophkeeper-client", buildVersion)
+	fatalIfErr("tracing error", err)
+	otel.SetTracerProvider(tracing)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = tracing.Shutdown(ctx)
+	}()
+
+	httpTransport, err := transport.New(cfg.Transport())
+	fatalIfErr("config error", err)
 
-	security := service.NewSecuritySource()
-	client, err := api.NewClient(cfg.ServerAddress, security)
-	fatalIfErr("client error", err)
+	// The service of the agent keeps the cache itself, the others attach to
+	// a running agent.
+	newService := func(newCryptoStorage interfaces.NewCryptoStorage) interfaces.Service {
+		security := service.NewSecuritySource()
+		client, err := api.NewClient(cfg.ServerAddress, security,
+			api.WithTracerProvider(tracing),
+			api.WithClient(&http.Client{Transport: telemetry.Transport(httpTransport)}),
+		)
+		fatalIfErr("client error", err)
 
-	srv := service.New(client, security,
-		service.NewAuthService,
-		service.NewCryptoService,
-		service.NewSyncService,
-		storage.New,
-	)
+		return service.New(client, security, cfg.ServerAddress,
+			service.NewAuthService,
+			service.NewCryptoService,
+			service.NewSyncService,
+			service.NewTokenService,
+			service.NewShareService,
+			service.NewOrgService,
+			service.NewEmergencyService,
+			service.NewSendService,
+			newCryptoStorage,
+		)
+	}
+	agentClient := agent.NewClient()
+	srv := agentClient.Service(newService(agentClient.NewCryptoStorage(storage.New)))
 	defer srv.Close()
 
-	_, err = tea.NewProgram(app.New(srv, buildVersion, buildDate), tea.WithContext(ctx), tea.WithAltScreen()).Run()
+	args := flag.Args()
+	if cmd, ok := cli.HelperCommand(os.Args[0]); ok {
+		args = append([]string{cmd}, args...)
+	}
+	if len(args) > 0 {
+		newAgentService := func() interfaces.Service { return newService(storage.New) }
+		return cli.New(srv, agentClient, newAgentService, cfg.NativeHost.AllowedOrigins).Run(ctx, args)
+	}
+
+	_, err = tea.NewProgram(app.New(srv, agentClient.Attach, buildVersion, buildDate), tea.WithContext(ctx), tea.WithAltScreen()).Run()
 	fatalIfErr("program error", err)
+	return 0
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/grnsv/GophKeeper/internal/server/admin"
+	"github.com/grnsv/GophKeeper/internal/server/config"
+	"github.com/grnsv/GophKeeper/internal/server/storage"
+)
+
+func runAdmin(ctx context.Context, args []string) error {
+	cfg, args, err := config.ParseCommand(args)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	s, err := storage.New(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	defer s.Close()
+
+	return admin.New(s, os.Stdout).Run(ctx, args)
+}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/grnsv/GophKeeper/internal/server/app"
+	"github.com/grnsv/GophKeeper/internal/server/config"
 )
 
 // buildVersion is set at compile time using -ldflags.
@@ -24,23 +28,67 @@ var buildVersion string
 var buildDate string
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrate(os.Args[2:]); err != nil {
+				log.Fatalf("Migrate failed: %v", err)
+			}
+			return
+		case "admin":
+			if err := runAdmin(context.Background(), os.Args[2:]); err != nil {
+				log.Fatalf("Admin command failed: %v", err)
+			}
+			return
+		}
+	}
+
+	cfg, err := config.Parse(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to parse config", "error", err)
+		os.Exit(2)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatalf("Print config failed: %v", err)
+		}
+		return
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	app, err := app.New(ctx, buildVersion, buildDate)
+	app, err := app.New(ctx, cfg, buildVersion, buildDate)
 	if err != nil {
-		log.Fatalf("Failed to create application: %v", err)
+		slog.Error("Failed to create application", "error", err)
+		os.Exit(1)
 	}
 
 	go app.Run()
-	<-ctx.Done()
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+wait:
+	for {
+		select {
+		case <-reload:
+			app.ReloadTLS()
+		case <-ctx.Done():
+			break wait
+		}
+	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	if err := app.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Graceful shutdown failed: %v", err)
+		slog.Error("Graceful shutdown failed", "error", err)
+		os.Exit(1)
 	}
 
-	log.Println("Server stopped gracefully")
+	slog.Info("Server stopped gracefully")
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/grnsv/GophKeeper/internal/server/config"
+	"github.com/grnsv/GophKeeper/internal/server/storage"
+)
+
+const migrateUsage = `usage: goph-keeper-server migrate [config flags] <command>
+
+Commands:
+  up [N]         apply all or N pending migrations
+  down [N]       roll back N applied migrations (default 1)
+  status         print the applied and the latest migration versions
+  force VERSION  set the migration version without running migrations, clearing the dirty state`
+
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...any) {
+	log.Printf(format, v...)
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
+
+func runMigrate(args []string) error {
+	cfg, args, err := config.ParseCommand(args)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	m, err := storage.NewMigrator(cfg.DatabaseDSN)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	m.Log = migrateLogger{}
+
+	switch args[0] {
+	case "up":
+		var steps int
+		if steps, err = parseSteps(args[1:], 0); err != nil {
+			return err
+		}
+		if steps == 0 {
+			err = m.Up()
+		} else {
+			err = m.Steps(steps)
+		}
+	case "down":
+		var steps int
+		if steps, err = parseSteps(args[1:], 1); err != nil {
+			return err
+		}
+		err = m.Steps(-steps)
+	case "status":
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		var version int
+		if version, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		err = m.Force(version)
+	default:
+		return errors.New(migrateUsage)
+	}
+	var shortLimit migrate.ErrShortLimit
+	if errors.As(err, &shortLimit) {
+		log.Printf("Only %d migrations were available", shortLimit.Short)
+	} else if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Version: %d\nLatest: %d\nDirty: %t\n", status.Version, status.Latest, status.Dirty)
+
+	return nil
+}
+
+func parseSteps(args []string, defaultSteps int) (int, error) {
+	switch len(args) {
+	case 0:
+		return defaultSteps, nil
+	case 1:
+		steps, err := strconv.Atoi(args[0])
+		if err != nil || steps < 1 {
+			return 0, fmt.Errorf("invalid number of migrations %q", args[0])
+		}
+		return steps, nil
+	default:
+		return 0, errors.New(migrateUsage)
+	}
+}
@@ -1,10 +1,102 @@
 // Code generated by ogen, DO NOT EDIT.
 package api
 
+type AccountAuditGetRes interface {
+	accountAuditGetRes()
+}
+
+type AccountDeleteRes interface {
+	accountDeleteRes()
+}
+
+type AccountKeysGetRes interface {
+	accountKeysGetRes()
+}
+
+type AccountKeysPutRes interface {
+	accountKeysPutRes()
+}
+
+type EmergencyContactsGetRes interface {
+	emergencyContactsGetRes()
+}
+
+type EmergencyContactsIDApprovePostRes interface {
+	emergencyContactsIDApprovePostRes()
+}
+
+type EmergencyContactsIDDeleteRes interface {
+	emergencyContactsIDDeleteRes()
+}
+
+type EmergencyContactsIDRejectPostRes interface {
+	emergencyContactsIDRejectPostRes()
+}
+
+type EmergencyContactsPostRes interface {
+	emergencyContactsPostRes()
+}
+
+type EmergencyGrantsGetRes interface {
+	emergencyGrantsGetRes()
+}
+
+type EmergencyGrantsIDRequestPostRes interface {
+	emergencyGrantsIDRequestPostRes()
+}
+
+type EmergencyGrantsIDVaultGetRes interface {
+	emergencyGrantsIDVaultGetRes()
+}
+
 type LoginPostRes interface {
 	loginPostRes()
 }
 
+type OrgsGetRes interface {
+	orgsGetRes()
+}
+
+type OrgsOrgIDDeleteRes interface {
+	orgsOrgIDDeleteRes()
+}
+
+type OrgsOrgIDMembersGetRes interface {
+	orgsOrgIDMembersGetRes()
+}
+
+type OrgsOrgIDMembersPostRes interface {
+	orgsOrgIDMembersPostRes()
+}
+
+type OrgsOrgIDMembersUserIDDeleteRes interface {
+	orgsOrgIDMembersUserIDDeleteRes()
+}
+
+type OrgsOrgIDMembersUserIDPutRes interface {
+	orgsOrgIDMembersUserIDPutRes()
+}
+
+type OrgsOrgIDRecordsGetRes interface {
+	orgsOrgIDRecordsGetRes()
+}
+
+type OrgsOrgIDRecordsIDDeleteRes interface {
+	orgsOrgIDRecordsIDDeleteRes()
+}
+
+type OrgsOrgIDRecordsIDPutRes interface {
+	orgsOrgIDRecordsIDPutRes()
+}
+
+type OrgsOrgIDRekeyPostRes interface {
+	orgsOrgIDRekeyPostRes()
+}
+
+type OrgsPostRes interface {
+	orgsPostRes()
+}
+
 type RecordsGetRes interface {
 	recordsGetRes()
 }
@@ -21,6 +113,50 @@ type RecordsIDPutRes interface {
 	recordsIDPutRes()
 }
 
+type RecordsIDSharesGetRes interface {
+	recordsIDSharesGetRes()
+}
+
+type RecordsIDSharesPostRes interface {
+	recordsIDSharesPostRes()
+}
+
+type RecordsIDSharesUserIDDeleteRes interface {
+	recordsIDSharesUserIDDeleteRes()
+}
+
 type RegisterPostRes interface {
 	registerPostRes()
 }
+
+type SendsIDGetRes interface {
+	sendsIDGetRes()
+}
+
+type SendsPostRes interface {
+	sendsPostRes()
+}
+
+type SharedGetRes interface {
+	sharedGetRes()
+}
+
+type TokensCurrentGetRes interface {
+	tokensCurrentGetRes()
+}
+
+type TokensGetRes interface {
+	tokensGetRes()
+}
+
+type TokensIDDeleteRes interface {
+	tokensIDDeleteRes()
+}
+
+type TokensPostRes interface {
+	tokensPostRes()
+}
+
+type UsersLoginPublicKeyGetRes interface {
+	usersLoginPublicKeyGetRes()
+}
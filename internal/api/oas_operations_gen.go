@@ -6,11 +6,47 @@ package api
 type OperationName = string
 
 const (
-	LoginPostOperation       OperationName = "LoginPost"
-	RecordsGetOperation      OperationName = "RecordsGet"
-	RecordsIDDeleteOperation OperationName = "RecordsIDDelete"
-	RecordsIDGetOperation    OperationName = "RecordsIDGet"
-	RecordsIDPutOperation    OperationName = "RecordsIDPut"
-	RegisterPostOperation    OperationName = "RegisterPost"
-	VersionGetOperation      OperationName = "VersionGet"
+	AccountAuditGetOperation                OperationName = "AccountAuditGet"
+	AccountDeleteOperation                  OperationName = "AccountDelete"
+	AccountKeysGetOperation                 OperationName = "AccountKeysGet"
+	AccountKeysPutOperation                 OperationName = "AccountKeysPut"
+	EmergencyContactsGetOperation           OperationName = "EmergencyContactsGet"
+	EmergencyContactsIDApprovePostOperation OperationName = "EmergencyContactsIDApprovePost"
+	EmergencyContactsIDDeleteOperation      OperationName = "EmergencyContactsIDDelete"
+	EmergencyContactsIDRejectPostOperation  OperationName = "EmergencyContactsIDRejectPost"
+	EmergencyContactsPostOperation          OperationName = "EmergencyContactsPost"
+	EmergencyGrantsGetOperation             OperationName = "EmergencyGrantsGet"
+	EmergencyGrantsIDRequestPostOperation   OperationName = "EmergencyGrantsIDRequestPost"
+	EmergencyGrantsIDVaultGetOperation      OperationName = "EmergencyGrantsIDVaultGet"
+	LoginPostOperation                      OperationName = "LoginPost"
+	OrgsGetOperation                        OperationName = "OrgsGet"
+	OrgsOrgIDDeleteOperation                OperationName = "OrgsOrgIDDelete"
+	OrgsOrgIDMembersGetOperation            OperationName = "OrgsOrgIDMembersGet"
+	OrgsOrgIDMembersPostOperation           OperationName = "OrgsOrgIDMembersPost"
+	OrgsOrgIDMembersUserIDDeleteOperation   OperationName = "OrgsOrgIDMembersUserIDDelete"
+	OrgsOrgIDMembersUserIDPutOperation      OperationName = "OrgsOrgIDMembersUserIDPut"
+	OrgsOrgIDRecordsGetOperation            OperationName = "OrgsOrgIDRecordsGet"
+	OrgsOrgIDRecordsIDDeleteOperation       OperationName = "OrgsOrgIDRecordsIDDelete"
+	OrgsOrgIDRecordsIDPutOperation          OperationName = "OrgsOrgIDRecordsIDPut"
+	OrgsOrgIDRekeyPostOperation             OperationName = "OrgsOrgIDRekeyPost"
+	OrgsPostOperation                       OperationName = "OrgsPost"
+	RecordsGetOperation                     OperationName = "RecordsGet"
+	RecordsIDDeleteOperation                OperationName = "RecordsIDDelete"
+	RecordsIDGetOperation                   OperationName = "RecordsIDGet"
+	RecordsIDPutOperation                   OperationName = "RecordsIDPut"
+	RecordsIDSharesGetOperation             OperationName = "RecordsIDSharesGet"
+	RecordsIDSharesPostOperation            OperationName = "RecordsIDSharesPost"
+	RecordsIDSharesUserIDDeleteOperation    OperationName = "RecordsIDSharesUserIDDelete"
+	RegisterPostOperation                   OperationName = "RegisterPost"
+	RegistrationGetOperation                OperationName = "RegistrationGet"
+	SendsIDGetOperation                     OperationName = "SendsIDGet"
+	SendsPostOperation                      OperationName = "SendsPost"
+	SharedGetOperation                      OperationName = "SharedGet"
+	TokensCurrentGetOperation               OperationName = "TokensCurrentGet"
+	TokensGetOperation                      OperationName = "TokensGet"
+	TokensIDDeleteOperation                 OperationName = "TokensIDDelete"
+	TokensPostOperation                     OperationName = "TokensPost"
+	UsersLoginPublicKeyGetOperation         OperationName = "UsersLoginPublicKeyGet"
+	VersionGetOperation                     OperationName = "VersionGet"
+	WellKnownJwksJSONGetOperation           OperationName = "WellKnownJwksJSONGet"
 )
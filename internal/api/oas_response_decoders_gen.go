@@ -14,7 +14,7 @@ import (
 	"github.com/ogen-go/ogen/validate"
 )
 
-func decodeLoginPostResponse(resp *http.Response) (res LoginPostRes, _ error) {
+func decodeAccountAuditGetResponse(resp *http.Response) (res AccountAuditGetRes, _ error) {
 	switch resp.StatusCode {
 	case 200:
 		// Code 200.
@@ -30,7 +30,7 @@ func decodeLoginPostResponse(resp *http.Response) (res LoginPostRes, _ error) {
 			}
 			d := jx.DecodeBytes(buf)
 
-			var response AuthToken
+			var response AccountAuditGetOKApplicationJSON
 			if err := func() error {
 				if err := response.Decode(d); err != nil {
 					return err
@@ -47,13 +47,22 @@ func decodeLoginPostResponse(resp *http.Response) (res LoginPostRes, _ error) {
 				}
 				return res, err
 			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
 			return &response, nil
 		default:
 			return res, validate.InvalidContentType(ct)
 		}
 	case 400:
 		// Code 400.
-		return &LoginPostBadRequest{}, nil
+		return &AccountAuditGetBadRequest{}, nil
 	case 401:
 		// Code 401.
 		return &Unauthorized{}, nil
@@ -61,7 +70,25 @@ func decodeLoginPostResponse(resp *http.Response) (res LoginPostRes, _ error) {
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeRecordsGetResponse(resp *http.Response) (res RecordsGetRes, _ error) {
+func decodeAccountDeleteResponse(resp *http.Response) (res AccountDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &AccountDeleteNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &AccountDeleteBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 409:
+		// Code 409.
+		return &AccountDeleteConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeAccountKeysGetResponse(resp *http.Response) (res AccountKeysGetRes, _ error) {
 	switch resp.StatusCode {
 	case 200:
 		// Code 200.
@@ -77,7 +104,75 @@ func decodeRecordsGetResponse(resp *http.Response) (res RecordsGetRes, _ error)
 			}
 			d := jx.DecodeBytes(buf)
 
-			var response RecordsGetOKApplicationJSON
+			var response UserKeys
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &AccountKeysGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeAccountKeysPutResponse(resp *http.Response) (res AccountKeysPutRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &AccountKeysPutNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &AccountKeysPutBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 409:
+		// Code 409.
+		return &AccountKeysPutConflict{}, nil
+	case 422:
+		// Code 422.
+		return &AccountKeysPutUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeEmergencyContactsGetResponse(resp *http.Response) (res EmergencyContactsGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response EmergencyContactsGetOKApplicationJSON
 			if err := func() error {
 				if err := response.Decode(d); err != nil {
 					return err
@@ -114,19 +209,120 @@ func decodeRecordsGetResponse(resp *http.Response) (res RecordsGetRes, _ error)
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeRecordsIDDeleteResponse(resp *http.Response) (res RecordsIDDeleteRes, _ error) {
+func decodeEmergencyContactsIDApprovePostResponse(resp *http.Response) (res EmergencyContactsIDApprovePostRes, _ error) {
 	switch resp.StatusCode {
 	case 204:
 		// Code 204.
-		return &RecordsIDDeleteNoContent{}, nil
+		return &EmergencyContactsIDApprovePostNoContent{}, nil
 	case 401:
 		// Code 401.
 		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &EmergencyContactsIDApprovePostNotFound{}, nil
+	case 409:
+		// Code 409.
+		return &EmergencyContactsIDApprovePostConflict{}, nil
 	}
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeRecordsIDGetResponse(resp *http.Response) (res RecordsIDGetRes, _ error) {
+func decodeEmergencyContactsIDDeleteResponse(resp *http.Response) (res EmergencyContactsIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &EmergencyContactsIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &EmergencyContactsIDDeleteNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeEmergencyContactsIDRejectPostResponse(resp *http.Response) (res EmergencyContactsIDRejectPostRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &EmergencyContactsIDRejectPostNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &EmergencyContactsIDRejectPostNotFound{}, nil
+	case 409:
+		// Code 409.
+		return &EmergencyContactsIDRejectPostConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeEmergencyContactsPostResponse(resp *http.Response) (res EmergencyContactsPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response EmergencyAccess
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &EmergencyContactsPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 409:
+		// Code 409.
+		return &EmergencyContactsPostConflict{}, nil
+	case 422:
+		// Code 422.
+		return &EmergencyContactsPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeEmergencyGrantsGetResponse(resp *http.Response) (res EmergencyGrantsGetRes, _ error) {
 	switch resp.StatusCode {
 	case 200:
 		// Code 200.
@@ -142,7 +338,7 @@ func decodeRecordsIDGetResponse(resp *http.Response) (res RecordsIDGetRes, _ err
 			}
 			d := jx.DecodeBytes(buf)
 
-			var response RecordWithId
+			var response EmergencyGrantsGetOKApplicationJSON
 			if err := func() error {
 				if err := response.Decode(d); err != nil {
 					return err
@@ -175,35 +371,91 @@ func decodeRecordsIDGetResponse(resp *http.Response) (res RecordsIDGetRes, _ err
 	case 401:
 		// Code 401.
 		return &Unauthorized{}, nil
-	case 404:
-		// Code 404.
-		return &RecordsIDGetNotFound{}, nil
 	}
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeRecordsIDPutResponse(resp *http.Response) (res RecordsIDPutRes, _ error) {
+func decodeEmergencyGrantsIDRequestPostResponse(resp *http.Response) (res EmergencyGrantsIDRequestPostRes, _ error) {
 	switch resp.StatusCode {
 	case 204:
 		// Code 204.
-		return &RecordsIDPutNoContent{}, nil
-	case 400:
-		// Code 400.
-		return &RecordsIDPutBadRequest{}, nil
+		return &EmergencyGrantsIDRequestPostNoContent{}, nil
 	case 401:
 		// Code 401.
 		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &EmergencyGrantsIDRequestPostNotFound{}, nil
 	case 409:
 		// Code 409.
-		return &RecordsIDPutConflict{}, nil
+		return &EmergencyGrantsIDRequestPostConflict{}, nil
 	}
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeRegisterPostResponse(resp *http.Response) (res RegisterPostRes, _ error) {
+func decodeEmergencyGrantsIDVaultGetResponse(resp *http.Response) (res EmergencyGrantsIDVaultGetRes, _ error) {
 	switch resp.StatusCode {
-	case 201:
-		// Code 201.
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response EmergencyVault
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &EmergencyGrantsIDVaultGetForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &EmergencyGrantsIDVaultGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeLoginPostResponse(resp *http.Response) (res LoginPostRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
 		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
 		if err != nil {
 			return res, errors.Wrap(err, "parse media type")
@@ -239,15 +491,18 @@ func decodeRegisterPostResponse(resp *http.Response) (res RegisterPostRes, _ err
 		}
 	case 400:
 		// Code 400.
-		return &RegisterPostBadRequest{}, nil
-	case 409:
-		// Code 409.
-		return &RegisterPostConflict{}, nil
+		return &LoginPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &LoginPostForbidden{}, nil
 	}
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
 
-func decodeVersionGetResponse(resp *http.Response) (res *VersionInfo, _ error) {
+func decodeOrgsGetResponse(resp *http.Response) (res OrgsGetRes, _ error) {
 	switch resp.StatusCode {
 	case 200:
 		// Code 200.
@@ -263,7 +518,7 @@ func decodeVersionGetResponse(resp *http.Response) (res *VersionInfo, _ error) {
 			}
 			d := jx.DecodeBytes(buf)
 
-			var response VersionInfo
+			var response OrgsGetOKApplicationJSON
 			if err := func() error {
 				if err := response.Decode(d); err != nil {
 					return err
@@ -280,6 +535,1214 @@ func decodeVersionGetResponse(resp *http.Response) (res *VersionInfo, _ error) {
 				}
 				return res, err
 			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDDeleteResponse(resp *http.Response) (res OrgsOrgIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDDeleteForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDDeleteNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDMembersGetResponse(resp *http.Response) (res OrgsOrgIDMembersGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response OrgsOrgIDMembersGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDMembersGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDMembersPostResponse(resp *http.Response) (res OrgsOrgIDMembersPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response OrgMember
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &OrgsOrgIDMembersPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDMembersPostForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDMembersPostNotFound{}, nil
+	case 409:
+		// Code 409.
+		return &OrgsOrgIDMembersPostConflict{}, nil
+	case 422:
+		// Code 422.
+		return &OrgsOrgIDMembersPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDMembersUserIDDeleteResponse(resp *http.Response) (res OrgsOrgIDMembersUserIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDMembersUserIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDMembersUserIDDeleteForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDMembersUserIDDeleteNotFound{}, nil
+	case 422:
+		// Code 422.
+		return &OrgsOrgIDMembersUserIDDeleteUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDMembersUserIDPutResponse(resp *http.Response) (res OrgsOrgIDMembersUserIDPutRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDMembersUserIDPutNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &OrgsOrgIDMembersUserIDPutBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDMembersUserIDPutForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDMembersUserIDPutNotFound{}, nil
+	case 422:
+		// Code 422.
+		return &OrgsOrgIDMembersUserIDPutUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDRecordsGetResponse(resp *http.Response) (res OrgsOrgIDRecordsGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response OrgsOrgIDRecordsGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDRecordsGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDRecordsIDDeleteResponse(resp *http.Response) (res OrgsOrgIDRecordsIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDRecordsIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDRecordsIDDeleteForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDRecordsIDDeleteNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDRecordsIDPutResponse(resp *http.Response) (res OrgsOrgIDRecordsIDPutRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDRecordsIDPutNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &OrgsOrgIDRecordsIDPutBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDRecordsIDPutForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDRecordsIDPutNotFound{}, nil
+	case 409:
+		// Code 409.
+		return &OrgsOrgIDRecordsIDPutConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsOrgIDRekeyPostResponse(resp *http.Response) (res OrgsOrgIDRekeyPostRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &OrgsOrgIDRekeyPostNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &OrgsOrgIDRekeyPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OrgsOrgIDRekeyPostForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &OrgsOrgIDRekeyPostNotFound{}, nil
+	case 409:
+		// Code 409.
+		return &OrgsOrgIDRekeyPostConflict{}, nil
+	case 422:
+		// Code 422.
+		return &OrgsOrgIDRekeyPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOrgsPostResponse(resp *http.Response) (res OrgsPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response Organization
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &OrgsPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 422:
+		// Code 422.
+		return &OrgsPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsGetResponse(resp *http.Response) (res RecordsGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response RecordsGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDDeleteResponse(resp *http.Response) (res RecordsIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &RecordsIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &RecordsIDDeleteForbidden{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDGetResponse(resp *http.Response) (res RecordsIDGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response RecordWithId
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &RecordsIDGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDPutResponse(resp *http.Response) (res RecordsIDPutRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &RecordsIDPutNoContent{}, nil
+	case 400:
+		// Code 400.
+		return &RecordsIDPutBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &RecordsIDPutForbidden{}, nil
+	case 409:
+		// Code 409.
+		return &RecordsIDPutConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDSharesGetResponse(resp *http.Response) (res RecordsIDSharesGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response RecordsIDSharesGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &RecordsIDSharesGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDSharesPostResponse(resp *http.Response) (res RecordsIDSharesPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response Share
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &RecordsIDSharesPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &RecordsIDSharesPostNotFound{}, nil
+	case 422:
+		// Code 422.
+		return &RecordsIDSharesPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRecordsIDSharesUserIDDeleteResponse(resp *http.Response) (res RecordsIDSharesUserIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &RecordsIDSharesUserIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &RecordsIDSharesUserIDDeleteNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRegisterPostResponse(resp *http.Response) (res RegisterPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response AuthToken
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &RegisterPostBadRequest{}, nil
+	case 403:
+		// Code 403.
+		return &RegisterPostForbidden{}, nil
+	case 409:
+		// Code 409.
+		return &RegisterPostConflict{}, nil
+	case 422:
+		// Code 422.
+		return &RegisterPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeRegistrationGetResponse(resp *http.Response) (res *RegistrationInfo, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response RegistrationInfo
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeSendsIDGetResponse(resp *http.Response) (res SendsIDGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response SendData
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 403:
+		// Code 403.
+		return &SendsIDGetForbidden{}, nil
+	case 404:
+		// Code 404.
+		return &SendsIDGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeSendsPostResponse(resp *http.Response) (res SendsPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response Send
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &SendsPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 422:
+		// Code 422.
+		return &SendsPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeSharedGetResponse(resp *http.Response) (res SharedGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response SharedGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeTokensCurrentGetResponse(resp *http.Response) (res TokensCurrentGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response AccessTokenKeys
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &TokensCurrentGetForbidden{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeTokensGetResponse(resp *http.Response) (res TokensGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response TokensGetOKApplicationJSON
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeTokensIDDeleteResponse(resp *http.Response) (res TokensIDDeleteRes, _ error) {
+	switch resp.StatusCode {
+	case 204:
+		// Code 204.
+		return &TokensIDDeleteNoContent{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &TokensIDDeleteNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeTokensPostResponse(resp *http.Response) (res TokensPostRes, _ error) {
+	switch resp.StatusCode {
+	case 201:
+		// Code 201.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response CreatedAccessToken
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 400:
+		// Code 400.
+		return &TokensPostBadRequest{}, nil
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 422:
+		// Code 422.
+		return &TokensPostUnprocessableEntity{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeUsersLoginPublicKeyGetResponse(resp *http.Response) (res UsersLoginPublicKeyGetRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response PublicKey
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &Unauthorized{}, nil
+	case 404:
+		// Code 404.
+		return &UsersLoginPublicKeyGetNotFound{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeVersionGetResponse(resp *http.Response) (res *VersionInfo, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response VersionInfo
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeWellKnownJwksJSONGetResponse(resp *http.Response) (res *JWKSet, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response JWKSet
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			// Validate response.
+			if err := func() error {
+				if err := response.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return res, errors.Wrap(err, "validate")
+			}
 			return &response, nil
 		default:
 			return res, validate.InvalidContentType(ct)
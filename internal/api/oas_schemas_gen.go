@@ -9,6 +9,531 @@ import (
 	"github.com/google/uuid"
 )
 
+// Ref: #/components/schemas/AccessToken
+type AccessToken struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	ReadOnly bool      `json:"read_only"`
+	// Records the token is limited to, absent for a token to all records.
+	Records    []uuid.UUID `json:"records"`
+	Tags       []string    `json:"tags"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	LastUsedAt OptDateTime `json:"last_used_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// GetID returns the value of ID.
+func (s *AccessToken) GetID() uuid.UUID {
+	return s.ID
+}
+
+// GetName returns the value of Name.
+func (s *AccessToken) GetName() string {
+	return s.Name
+}
+
+// GetReadOnly returns the value of ReadOnly.
+func (s *AccessToken) GetReadOnly() bool {
+	return s.ReadOnly
+}
+
+// GetRecords returns the value of Records.
+func (s *AccessToken) GetRecords() []uuid.UUID {
+	return s.Records
+}
+
+// GetTags returns the value of Tags.
+func (s *AccessToken) GetTags() []string {
+	return s.Tags
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *AccessToken) GetExpiresAt() time.Time {
+	return s.ExpiresAt
+}
+
+// GetLastUsedAt returns the value of LastUsedAt.
+func (s *AccessToken) GetLastUsedAt() OptDateTime {
+	return s.LastUsedAt
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *AccessToken) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *AccessToken) SetID(val uuid.UUID) {
+	s.ID = val
+}
+
+// SetName sets the value of Name.
+func (s *AccessToken) SetName(val string) {
+	s.Name = val
+}
+
+// SetReadOnly sets the value of ReadOnly.
+func (s *AccessToken) SetReadOnly(val bool) {
+	s.ReadOnly = val
+}
+
+// SetRecords sets the value of Records.
+func (s *AccessToken) SetRecords(val []uuid.UUID) {
+	s.Records = val
+}
+
+// SetTags sets the value of Tags.
+func (s *AccessToken) SetTags(val []string) {
+	s.Tags = val
+}
+
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *AccessToken) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
+}
+
+// SetLastUsedAt sets the value of LastUsedAt.
+func (s *AccessToken) SetLastUsedAt(val OptDateTime) {
+	s.LastUsedAt = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *AccessToken) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// Ref: #/components/schemas/AccessTokenKeys
+type AccessTokenKeys struct {
+	ReadOnly   bool        `json:"read_only"`
+	WrappedKey []byte      `json:"wrapped_key"`
+	RecordKeys []RecordKey `json:"record_keys"`
+}
+
+// GetReadOnly returns the value of ReadOnly.
+func (s *AccessTokenKeys) GetReadOnly() bool {
+	return s.ReadOnly
+}
+
+// GetWrappedKey returns the value of WrappedKey.
+func (s *AccessTokenKeys) GetWrappedKey() []byte {
+	return s.WrappedKey
+}
+
+// GetRecordKeys returns the value of RecordKeys.
+func (s *AccessTokenKeys) GetRecordKeys() []RecordKey {
+	return s.RecordKeys
+}
+
+// SetReadOnly sets the value of ReadOnly.
+func (s *AccessTokenKeys) SetReadOnly(val bool) {
+	s.ReadOnly = val
+}
+
+// SetWrappedKey sets the value of WrappedKey.
+func (s *AccessTokenKeys) SetWrappedKey(val []byte) {
+	s.WrappedKey = val
+}
+
+// SetRecordKeys sets the value of RecordKeys.
+func (s *AccessTokenKeys) SetRecordKeys(val []RecordKey) {
+	s.RecordKeys = val
+}
+
+func (*AccessTokenKeys) tokensCurrentGetRes() {}
+
+// Ref: #/components/schemas/AccessTokenRequest
+type AccessTokenRequest struct {
+	Name      string    `json:"name"`
+	ReadOnly  OptBool   `json:"read_only"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Tags the records were selected by, kept for display.
+	Tags []string `json:"tags"`
+	// Vault key wrapped with the token key, for a token to all records.
+	WrappedKey []byte `json:"wrapped_key"`
+	// Record keys wrapped with the token key, for a token limited to records.
+	RecordKeys []RecordKey `json:"record_keys"`
+}
+
+// GetName returns the value of Name.
+func (s *AccessTokenRequest) GetName() string {
+	return s.Name
+}
+
+// GetReadOnly returns the value of ReadOnly.
+func (s *AccessTokenRequest) GetReadOnly() OptBool {
+	return s.ReadOnly
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *AccessTokenRequest) GetExpiresAt() time.Time {
+	return s.ExpiresAt
+}
+
+// GetTags returns the value of Tags.
+func (s *AccessTokenRequest) GetTags() []string {
+	return s.Tags
+}
+
+// GetWrappedKey returns the value of WrappedKey.
+func (s *AccessTokenRequest) GetWrappedKey() []byte {
+	return s.WrappedKey
+}
+
+// GetRecordKeys returns the value of RecordKeys.
+func (s *AccessTokenRequest) GetRecordKeys() []RecordKey {
+	return s.RecordKeys
+}
+
+// SetName sets the value of Name.
+func (s *AccessTokenRequest) SetName(val string) {
+	s.Name = val
+}
+
+// SetReadOnly sets the value of ReadOnly.
+func (s *AccessTokenRequest) SetReadOnly(val OptBool) {
+	s.ReadOnly = val
+}
+
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *AccessTokenRequest) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
+}
+
+// SetTags sets the value of Tags.
+func (s *AccessTokenRequest) SetTags(val []string) {
+	s.Tags = val
+}
+
+// SetWrappedKey sets the value of WrappedKey.
+func (s *AccessTokenRequest) SetWrappedKey(val []byte) {
+	s.WrappedKey = val
+}
+
+// SetRecordKeys sets the value of RecordKeys.
+func (s *AccessTokenRequest) SetRecordKeys(val []RecordKey) {
+	s.RecordKeys = val
+}
+
+// AccountAuditGetBadRequest is response for AccountAuditGet operation.
+type AccountAuditGetBadRequest struct{}
+
+func (*AccountAuditGetBadRequest) accountAuditGetRes() {}
+
+type AccountAuditGetOKApplicationJSON []AuditEvent
+
+func (*AccountAuditGetOKApplicationJSON) accountAuditGetRes() {}
+
+// AccountDeleteBadRequest is response for AccountDelete operation.
+type AccountDeleteBadRequest struct{}
+
+func (*AccountDeleteBadRequest) accountDeleteRes() {}
+
+// AccountDeleteConflict is response for AccountDelete operation.
+type AccountDeleteConflict struct{}
+
+func (*AccountDeleteConflict) accountDeleteRes() {}
+
+// AccountDeleteNoContent is response for AccountDelete operation.
+type AccountDeleteNoContent struct{}
+
+func (*AccountDeleteNoContent) accountDeleteRes() {}
+
+// AccountKeysGetNotFound is response for AccountKeysGet operation.
+type AccountKeysGetNotFound struct{}
+
+func (*AccountKeysGetNotFound) accountKeysGetRes() {}
+
+// AccountKeysPutBadRequest is response for AccountKeysPut operation.
+type AccountKeysPutBadRequest struct{}
+
+func (*AccountKeysPutBadRequest) accountKeysPutRes() {}
+
+// AccountKeysPutConflict is response for AccountKeysPut operation.
+type AccountKeysPutConflict struct{}
+
+func (*AccountKeysPutConflict) accountKeysPutRes() {}
+
+// AccountKeysPutNoContent is response for AccountKeysPut operation.
+type AccountKeysPutNoContent struct{}
+
+func (*AccountKeysPutNoContent) accountKeysPutRes() {}
+
+// AccountKeysPutUnprocessableEntity is response for AccountKeysPut operation.
+type AccountKeysPutUnprocessableEntity struct{}
+
+func (*AccountKeysPutUnprocessableEntity) accountKeysPutRes() {}
+
+// Ref: #/components/schemas/AuditEvent
+type AuditEvent struct {
+	ID        int64          `json:"id"`
+	Type      AuditEventType `json:"type"`
+	Details   OptString      `json:"details"`
+	IP        OptString      `json:"ip"`
+	UserAgent OptString      `json:"user_agent"`
+	// X-Request-ID of the request that caused the event.
+	RequestID OptString `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetID returns the value of ID.
+func (s *AuditEvent) GetID() int64 {
+	return s.ID
+}
+
+// GetType returns the value of Type.
+func (s *AuditEvent) GetType() AuditEventType {
+	return s.Type
+}
+
+// GetDetails returns the value of Details.
+func (s *AuditEvent) GetDetails() OptString {
+	return s.Details
+}
+
+// GetIP returns the value of IP.
+func (s *AuditEvent) GetIP() OptString {
+	return s.IP
+}
+
+// GetUserAgent returns the value of UserAgent.
+func (s *AuditEvent) GetUserAgent() OptString {
+	return s.UserAgent
+}
+
+// GetRequestID returns the value of RequestID.
+func (s *AuditEvent) GetRequestID() OptString {
+	return s.RequestID
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *AuditEvent) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *AuditEvent) SetID(val int64) {
+	s.ID = val
+}
+
+// SetType sets the value of Type.
+func (s *AuditEvent) SetType(val AuditEventType) {
+	s.Type = val
+}
+
+// SetDetails sets the value of Details.
+func (s *AuditEvent) SetDetails(val OptString) {
+	s.Details = val
+}
+
+// SetIP sets the value of IP.
+func (s *AuditEvent) SetIP(val OptString) {
+	s.IP = val
+}
+
+// SetUserAgent sets the value of UserAgent.
+func (s *AuditEvent) SetUserAgent(val OptString) {
+	s.UserAgent = val
+}
+
+// SetRequestID sets the value of RequestID.
+func (s *AuditEvent) SetRequestID(val OptString) {
+	s.RequestID = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *AuditEvent) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// Ref: #/components/schemas/AuditEventType
+type AuditEventType string
+
+const (
+	AuditEventTypeLogin                    AuditEventType = "login"
+	AuditEventTypeLoginFailed              AuditEventType = "login_failed"
+	AuditEventTypeRegister                 AuditEventType = "register"
+	AuditEventTypeRecordDelete             AuditEventType = "record_delete"
+	AuditEventTypeTokenIssued              AuditEventType = "token_issued"
+	AuditEventTypeAccessTokenCreated       AuditEventType = "access_token_created"
+	AuditEventTypeAccessTokenRevoked       AuditEventType = "access_token_revoked"
+	AuditEventTypeRecordShared             AuditEventType = "record_shared"
+	AuditEventTypeRecordShareRevoked       AuditEventType = "record_share_revoked"
+	AuditEventTypeOrgCreated               AuditEventType = "org_created"
+	AuditEventTypeOrgDeleted               AuditEventType = "org_deleted"
+	AuditEventTypeOrgMemberAdded           AuditEventType = "org_member_added"
+	AuditEventTypeOrgMemberRoleChanged     AuditEventType = "org_member_role_changed"
+	AuditEventTypeOrgMemberRemoved         AuditEventType = "org_member_removed"
+	AuditEventTypeOrgRekeyed               AuditEventType = "org_rekeyed"
+	AuditEventTypeEmergencyContactAdded    AuditEventType = "emergency_contact_added"
+	AuditEventTypeEmergencyContactRemoved  AuditEventType = "emergency_contact_removed"
+	AuditEventTypeEmergencyAccessRequested AuditEventType = "emergency_access_requested"
+	AuditEventTypeEmergencyAccessApproved  AuditEventType = "emergency_access_approved"
+	AuditEventTypeEmergencyAccessRejected  AuditEventType = "emergency_access_rejected"
+	AuditEventTypeEmergencyVaultAccessed   AuditEventType = "emergency_vault_accessed"
+	AuditEventTypeSendCreated              AuditEventType = "send_created"
+	AuditEventTypeSendOpened               AuditEventType = "send_opened"
+)
+
+// AllValues returns all AuditEventType values.
+func (AuditEventType) AllValues() []AuditEventType {
+	return []AuditEventType{
+		AuditEventTypeLogin,
+		AuditEventTypeLoginFailed,
+		AuditEventTypeRegister,
+		AuditEventTypeRecordDelete,
+		AuditEventTypeTokenIssued,
+		AuditEventTypeAccessTokenCreated,
+		AuditEventTypeAccessTokenRevoked,
+		AuditEventTypeRecordShared,
+		AuditEventTypeRecordShareRevoked,
+		AuditEventTypeOrgCreated,
+		AuditEventTypeOrgDeleted,
+		AuditEventTypeOrgMemberAdded,
+		AuditEventTypeOrgMemberRoleChanged,
+		AuditEventTypeOrgMemberRemoved,
+		AuditEventTypeOrgRekeyed,
+		AuditEventTypeEmergencyContactAdded,
+		AuditEventTypeEmergencyContactRemoved,
+		AuditEventTypeEmergencyAccessRequested,
+		AuditEventTypeEmergencyAccessApproved,
+		AuditEventTypeEmergencyAccessRejected,
+		AuditEventTypeEmergencyVaultAccessed,
+		AuditEventTypeSendCreated,
+		AuditEventTypeSendOpened,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuditEventType) MarshalText() ([]byte, error) {
+	switch s {
+	case AuditEventTypeLogin:
+		return []byte(s), nil
+	case AuditEventTypeLoginFailed:
+		return []byte(s), nil
+	case AuditEventTypeRegister:
+		return []byte(s), nil
+	case AuditEventTypeRecordDelete:
+		return []byte(s), nil
+	case AuditEventTypeTokenIssued:
+		return []byte(s), nil
+	case AuditEventTypeAccessTokenCreated:
+		return []byte(s), nil
+	case AuditEventTypeAccessTokenRevoked:
+		return []byte(s), nil
+	case AuditEventTypeRecordShared:
+		return []byte(s), nil
+	case AuditEventTypeRecordShareRevoked:
+		return []byte(s), nil
+	case AuditEventTypeOrgCreated:
+		return []byte(s), nil
+	case AuditEventTypeOrgDeleted:
+		return []byte(s), nil
+	case AuditEventTypeOrgMemberAdded:
+		return []byte(s), nil
+	case AuditEventTypeOrgMemberRoleChanged:
+		return []byte(s), nil
+	case AuditEventTypeOrgMemberRemoved:
+		return []byte(s), nil
+	case AuditEventTypeOrgRekeyed:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyContactAdded:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyContactRemoved:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyAccessRequested:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyAccessApproved:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyAccessRejected:
+		return []byte(s), nil
+	case AuditEventTypeEmergencyVaultAccessed:
+		return []byte(s), nil
+	case AuditEventTypeSendCreated:
+		return []byte(s), nil
+	case AuditEventTypeSendOpened:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuditEventType) UnmarshalText(data []byte) error {
+	switch AuditEventType(data) {
+	case AuditEventTypeLogin:
+		*s = AuditEventTypeLogin
+		return nil
+	case AuditEventTypeLoginFailed:
+		*s = AuditEventTypeLoginFailed
+		return nil
+	case AuditEventTypeRegister:
+		*s = AuditEventTypeRegister
+		return nil
+	case AuditEventTypeRecordDelete:
+		*s = AuditEventTypeRecordDelete
+		return nil
+	case AuditEventTypeTokenIssued:
+		*s = AuditEventTypeTokenIssued
+		return nil
+	case AuditEventTypeAccessTokenCreated:
+		*s = AuditEventTypeAccessTokenCreated
+		return nil
+	case AuditEventTypeAccessTokenRevoked:
+		*s = AuditEventTypeAccessTokenRevoked
+		return nil
+	case AuditEventTypeRecordShared:
+		*s = AuditEventTypeRecordShared
+		return nil
+	case AuditEventTypeRecordShareRevoked:
+		*s = AuditEventTypeRecordShareRevoked
+		return nil
+	case AuditEventTypeOrgCreated:
+		*s = AuditEventTypeOrgCreated
+		return nil
+	case AuditEventTypeOrgDeleted:
+		*s = AuditEventTypeOrgDeleted
+		return nil
+	case AuditEventTypeOrgMemberAdded:
+		*s = AuditEventTypeOrgMemberAdded
+		return nil
+	case AuditEventTypeOrgMemberRoleChanged:
+		*s = AuditEventTypeOrgMemberRoleChanged
+		return nil
+	case AuditEventTypeOrgMemberRemoved:
+		*s = AuditEventTypeOrgMemberRemoved
+		return nil
+	case AuditEventTypeOrgRekeyed:
+		*s = AuditEventTypeOrgRekeyed
+		return nil
+	case AuditEventTypeEmergencyContactAdded:
+		*s = AuditEventTypeEmergencyContactAdded
+		return nil
+	case AuditEventTypeEmergencyContactRemoved:
+		*s = AuditEventTypeEmergencyContactRemoved
+		return nil
+	case AuditEventTypeEmergencyAccessRequested:
+		*s = AuditEventTypeEmergencyAccessRequested
+		return nil
+	case AuditEventTypeEmergencyAccessApproved:
+		*s = AuditEventTypeEmergencyAccessApproved
+		return nil
+	case AuditEventTypeEmergencyAccessRejected:
+		*s = AuditEventTypeEmergencyAccessRejected
+		return nil
+	case AuditEventTypeEmergencyVaultAccessed:
+		*s = AuditEventTypeEmergencyVaultAccessed
+		return nil
+	case AuditEventTypeSendCreated:
+		*s = AuditEventTypeSendCreated
+		return nil
+	case AuditEventTypeSendOpened:
+		*s = AuditEventTypeSendOpened
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
 // Ref: #/components/schemas/AuthToken
 type AuthToken struct {
 	// JWT authentication token.
@@ -53,270 +578,2227 @@ func (s *BearerAuth) SetRoles(val []string) {
 	s.Roles = val
 }
 
-// LoginPostBadRequest is response for LoginPost operation.
-type LoginPostBadRequest struct{}
+// Ref: #/components/schemas/CreatedAccessToken
+type CreatedAccessToken struct {
+	ID uuid.UUID `json:"id"`
+	// Token without the key part, the client appends it.
+	Token string `json:"token"`
+}
 
-func (*LoginPostBadRequest) loginPostRes() {}
+// GetID returns the value of ID.
+func (s *CreatedAccessToken) GetID() uuid.UUID {
+	return s.ID
+}
 
-// NewOptDate returns new OptDate with value set to v.
-func NewOptDate(v time.Time) OptDate {
-	return OptDate{
-		Value: v,
-		Set:   true,
-	}
+// GetToken returns the value of Token.
+func (s *CreatedAccessToken) GetToken() string {
+	return s.Token
 }
 
-// OptDate is optional time.Time.
-type OptDate struct {
-	Value time.Time
-	Set   bool
+// SetID sets the value of ID.
+func (s *CreatedAccessToken) SetID(val uuid.UUID) {
+	s.ID = val
 }
 
-// IsSet returns true if OptDate was set.
-func (o OptDate) IsSet() bool { return o.Set }
+// SetToken sets the value of Token.
+func (s *CreatedAccessToken) SetToken(val string) {
+	s.Token = val
+}
 
-// Reset unsets value.
-func (o *OptDate) Reset() {
-	var v time.Time
-	o.Value = v
-	o.Set = false
+func (*CreatedAccessToken) tokensPostRes() {}
+
+// Ref: #/components/schemas/EmergencyAccess
+type EmergencyAccess struct {
+	ID uuid.UUID `json:"id"`
+	// The contact for the grantor, the grantor for the contact.
+	Login       string          `json:"login"`
+	WaitDays    int             `json:"wait_days"`
+	Status      EmergencyStatus `json:"status"`
+	RequestedAt OptDateTime     `json:"requested_at"`
+	// When a pending request is approved unless rejected.
+	AvailableAt OptDateTime `json:"available_at"`
+	CreatedAt   time.Time   `json:"created_at"`
 }
 
-// SetTo sets value to v.
-func (o *OptDate) SetTo(v time.Time) {
-	o.Set = true
-	o.Value = v
+// GetID returns the value of ID.
+func (s *EmergencyAccess) GetID() uuid.UUID {
+	return s.ID
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptDate) Get() (v time.Time, ok bool) {
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// GetLogin returns the value of Login.
+func (s *EmergencyAccess) GetLogin() string {
+	return s.Login
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptDate) Or(d time.Time) time.Time {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// GetWaitDays returns the value of WaitDays.
+func (s *EmergencyAccess) GetWaitDays() int {
+	return s.WaitDays
 }
 
-// NewOptString returns new OptString with value set to v.
-func NewOptString(v string) OptString {
+// GetStatus returns the value of Status.
+func (s *EmergencyAccess) GetStatus() EmergencyStatus {
+	return s.Status
+}
+
+// GetRequestedAt returns the value of RequestedAt.
+func (s *EmergencyAccess) GetRequestedAt() OptDateTime {
+	return s.RequestedAt
+}
+
+// GetAvailableAt returns the value of AvailableAt.
+func (s *EmergencyAccess) GetAvailableAt() OptDateTime {
+	return s.AvailableAt
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *EmergencyAccess) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *EmergencyAccess) SetID(val uuid.UUID) {
+	s.ID = val
+}
+
+// SetLogin sets the value of Login.
+func (s *EmergencyAccess) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetWaitDays sets the value of WaitDays.
+func (s *EmergencyAccess) SetWaitDays(val int) {
+	s.WaitDays = val
+}
+
+// SetStatus sets the value of Status.
+func (s *EmergencyAccess) SetStatus(val EmergencyStatus) {
+	s.Status = val
+}
+
+// SetRequestedAt sets the value of RequestedAt.
+func (s *EmergencyAccess) SetRequestedAt(val OptDateTime) {
+	s.RequestedAt = val
+}
+
+// SetAvailableAt sets the value of AvailableAt.
+func (s *EmergencyAccess) SetAvailableAt(val OptDateTime) {
+	s.AvailableAt = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *EmergencyAccess) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+func (*EmergencyAccess) emergencyContactsPostRes() {}
+
+// Ref: #/components/schemas/EmergencyContactRequest
+type EmergencyContactRequest struct {
+	Login string `json:"login"`
+	// Days the contact waits after requesting access unless the user rejects it.
+	WaitDays int `json:"wait_days"`
+	// Base64 encoded vault key wrapped for the public key of the contact.
+	Key []byte `json:"key"`
+}
+
+// GetLogin returns the value of Login.
+func (s *EmergencyContactRequest) GetLogin() string {
+	return s.Login
+}
+
+// GetWaitDays returns the value of WaitDays.
+func (s *EmergencyContactRequest) GetWaitDays() int {
+	return s.WaitDays
+}
+
+// GetKey returns the value of Key.
+func (s *EmergencyContactRequest) GetKey() []byte {
+	return s.Key
+}
+
+// SetLogin sets the value of Login.
+func (s *EmergencyContactRequest) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetWaitDays sets the value of WaitDays.
+func (s *EmergencyContactRequest) SetWaitDays(val int) {
+	s.WaitDays = val
+}
+
+// SetKey sets the value of Key.
+func (s *EmergencyContactRequest) SetKey(val []byte) {
+	s.Key = val
+}
+
+type EmergencyContactsGetOKApplicationJSON []EmergencyAccess
+
+func (*EmergencyContactsGetOKApplicationJSON) emergencyContactsGetRes() {}
+
+// EmergencyContactsIDApprovePostConflict is response for EmergencyContactsIDApprovePost operation.
+type EmergencyContactsIDApprovePostConflict struct{}
+
+func (*EmergencyContactsIDApprovePostConflict) emergencyContactsIDApprovePostRes() {}
+
+// EmergencyContactsIDApprovePostNoContent is response for EmergencyContactsIDApprovePost operation.
+type EmergencyContactsIDApprovePostNoContent struct{}
+
+func (*EmergencyContactsIDApprovePostNoContent) emergencyContactsIDApprovePostRes() {}
+
+// EmergencyContactsIDApprovePostNotFound is response for EmergencyContactsIDApprovePost operation.
+type EmergencyContactsIDApprovePostNotFound struct{}
+
+func (*EmergencyContactsIDApprovePostNotFound) emergencyContactsIDApprovePostRes() {}
+
+// EmergencyContactsIDDeleteNoContent is response for EmergencyContactsIDDelete operation.
+type EmergencyContactsIDDeleteNoContent struct{}
+
+func (*EmergencyContactsIDDeleteNoContent) emergencyContactsIDDeleteRes() {}
+
+// EmergencyContactsIDDeleteNotFound is response for EmergencyContactsIDDelete operation.
+type EmergencyContactsIDDeleteNotFound struct{}
+
+func (*EmergencyContactsIDDeleteNotFound) emergencyContactsIDDeleteRes() {}
+
+// EmergencyContactsIDRejectPostConflict is response for EmergencyContactsIDRejectPost operation.
+type EmergencyContactsIDRejectPostConflict struct{}
+
+func (*EmergencyContactsIDRejectPostConflict) emergencyContactsIDRejectPostRes() {}
+
+// EmergencyContactsIDRejectPostNoContent is response for EmergencyContactsIDRejectPost operation.
+type EmergencyContactsIDRejectPostNoContent struct{}
+
+func (*EmergencyContactsIDRejectPostNoContent) emergencyContactsIDRejectPostRes() {}
+
+// EmergencyContactsIDRejectPostNotFound is response for EmergencyContactsIDRejectPost operation.
+type EmergencyContactsIDRejectPostNotFound struct{}
+
+func (*EmergencyContactsIDRejectPostNotFound) emergencyContactsIDRejectPostRes() {}
+
+// EmergencyContactsPostBadRequest is response for EmergencyContactsPost operation.
+type EmergencyContactsPostBadRequest struct{}
+
+func (*EmergencyContactsPostBadRequest) emergencyContactsPostRes() {}
+
+// EmergencyContactsPostConflict is response for EmergencyContactsPost operation.
+type EmergencyContactsPostConflict struct{}
+
+func (*EmergencyContactsPostConflict) emergencyContactsPostRes() {}
+
+// EmergencyContactsPostUnprocessableEntity is response for EmergencyContactsPost operation.
+type EmergencyContactsPostUnprocessableEntity struct{}
+
+func (*EmergencyContactsPostUnprocessableEntity) emergencyContactsPostRes() {}
+
+type EmergencyGrantsGetOKApplicationJSON []EmergencyAccess
+
+func (*EmergencyGrantsGetOKApplicationJSON) emergencyGrantsGetRes() {}
+
+// EmergencyGrantsIDRequestPostConflict is response for EmergencyGrantsIDRequestPost operation.
+type EmergencyGrantsIDRequestPostConflict struct{}
+
+func (*EmergencyGrantsIDRequestPostConflict) emergencyGrantsIDRequestPostRes() {}
+
+// EmergencyGrantsIDRequestPostNoContent is response for EmergencyGrantsIDRequestPost operation.
+type EmergencyGrantsIDRequestPostNoContent struct{}
+
+func (*EmergencyGrantsIDRequestPostNoContent) emergencyGrantsIDRequestPostRes() {}
+
+// EmergencyGrantsIDRequestPostNotFound is response for EmergencyGrantsIDRequestPost operation.
+type EmergencyGrantsIDRequestPostNotFound struct{}
+
+func (*EmergencyGrantsIDRequestPostNotFound) emergencyGrantsIDRequestPostRes() {}
+
+// EmergencyGrantsIDVaultGetForbidden is response for EmergencyGrantsIDVaultGet operation.
+type EmergencyGrantsIDVaultGetForbidden struct{}
+
+func (*EmergencyGrantsIDVaultGetForbidden) emergencyGrantsIDVaultGetRes() {}
+
+// EmergencyGrantsIDVaultGetNotFound is response for EmergencyGrantsIDVaultGet operation.
+type EmergencyGrantsIDVaultGetNotFound struct{}
+
+func (*EmergencyGrantsIDVaultGetNotFound) emergencyGrantsIDVaultGetRes() {}
+
+// Ref: #/components/schemas/EmergencyStatus
+type EmergencyStatus string
+
+const (
+	EmergencyStatusGranted   EmergencyStatus = "granted"
+	EmergencyStatusRequested EmergencyStatus = "requested"
+	EmergencyStatusApproved  EmergencyStatus = "approved"
+)
+
+// AllValues returns all EmergencyStatus values.
+func (EmergencyStatus) AllValues() []EmergencyStatus {
+	return []EmergencyStatus{
+		EmergencyStatusGranted,
+		EmergencyStatusRequested,
+		EmergencyStatusApproved,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s EmergencyStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case EmergencyStatusGranted:
+		return []byte(s), nil
+	case EmergencyStatusRequested:
+		return []byte(s), nil
+	case EmergencyStatusApproved:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *EmergencyStatus) UnmarshalText(data []byte) error {
+	switch EmergencyStatus(data) {
+	case EmergencyStatusGranted:
+		*s = EmergencyStatusGranted
+		return nil
+	case EmergencyStatusRequested:
+		*s = EmergencyStatusRequested
+		return nil
+	case EmergencyStatusApproved:
+		*s = EmergencyStatusApproved
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/EmergencyVault
+type EmergencyVault struct {
+	// Login of the grantor.
+	Login string `json:"login"`
+	// Base64 encoded vault key of the grantor wrapped for the public key of the user.
+	Key     []byte         `json:"key"`
+	Records []RecordWithId `json:"records"`
+}
+
+// GetLogin returns the value of Login.
+func (s *EmergencyVault) GetLogin() string {
+	return s.Login
+}
+
+// GetKey returns the value of Key.
+func (s *EmergencyVault) GetKey() []byte {
+	return s.Key
+}
+
+// GetRecords returns the value of Records.
+func (s *EmergencyVault) GetRecords() []RecordWithId {
+	return s.Records
+}
+
+// SetLogin sets the value of Login.
+func (s *EmergencyVault) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetKey sets the value of Key.
+func (s *EmergencyVault) SetKey(val []byte) {
+	s.Key = val
+}
+
+// SetRecords sets the value of Records.
+func (s *EmergencyVault) SetRecords(val []RecordWithId) {
+	s.Records = val
+}
+
+func (*EmergencyVault) emergencyGrantsIDVaultGetRes() {}
+
+// Ref: #/components/schemas/JWK
+type JWK struct {
+	Kty JWKKty `json:"kty"`
+	Kid string `json:"kid"`
+	Alg JWKAlg `json:"alg"`
+	Use JWKUse `json:"use"`
+	Crv JWKCrv `json:"crv"`
+	// Base64url encoded public key or X coordinate.
+	X string `json:"x"`
+	// Base64url encoded Y coordinate, for EC keys only.
+	Y OptString `json:"y"`
+}
+
+// GetKty returns the value of Kty.
+func (s *JWK) GetKty() JWKKty {
+	return s.Kty
+}
+
+// GetKid returns the value of Kid.
+func (s *JWK) GetKid() string {
+	return s.Kid
+}
+
+// GetAlg returns the value of Alg.
+func (s *JWK) GetAlg() JWKAlg {
+	return s.Alg
+}
+
+// GetUse returns the value of Use.
+func (s *JWK) GetUse() JWKUse {
+	return s.Use
+}
+
+// GetCrv returns the value of Crv.
+func (s *JWK) GetCrv() JWKCrv {
+	return s.Crv
+}
+
+// GetX returns the value of X.
+func (s *JWK) GetX() string {
+	return s.X
+}
+
+// GetY returns the value of Y.
+func (s *JWK) GetY() OptString {
+	return s.Y
+}
+
+// SetKty sets the value of Kty.
+func (s *JWK) SetKty(val JWKKty) {
+	s.Kty = val
+}
+
+// SetKid sets the value of Kid.
+func (s *JWK) SetKid(val string) {
+	s.Kid = val
+}
+
+// SetAlg sets the value of Alg.
+func (s *JWK) SetAlg(val JWKAlg) {
+	s.Alg = val
+}
+
+// SetUse sets the value of Use.
+func (s *JWK) SetUse(val JWKUse) {
+	s.Use = val
+}
+
+// SetCrv sets the value of Crv.
+func (s *JWK) SetCrv(val JWKCrv) {
+	s.Crv = val
+}
+
+// SetX sets the value of X.
+func (s *JWK) SetX(val string) {
+	s.X = val
+}
+
+// SetY sets the value of Y.
+func (s *JWK) SetY(val OptString) {
+	s.Y = val
+}
+
+type JWKAlg string
+
+const (
+	JWKAlgEdDSA JWKAlg = "EdDSA"
+	JWKAlgES256 JWKAlg = "ES256"
+)
+
+// AllValues returns all JWKAlg values.
+func (JWKAlg) AllValues() []JWKAlg {
+	return []JWKAlg{
+		JWKAlgEdDSA,
+		JWKAlgES256,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s JWKAlg) MarshalText() ([]byte, error) {
+	switch s {
+	case JWKAlgEdDSA:
+		return []byte(s), nil
+	case JWKAlgES256:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *JWKAlg) UnmarshalText(data []byte) error {
+	switch JWKAlg(data) {
+	case JWKAlgEdDSA:
+		*s = JWKAlgEdDSA
+		return nil
+	case JWKAlgES256:
+		*s = JWKAlgES256
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type JWKCrv string
+
+const (
+	JWKCrvEd25519 JWKCrv = "Ed25519"
+	JWKCrvP256    JWKCrv = "P-256"
+)
+
+// AllValues returns all JWKCrv values.
+func (JWKCrv) AllValues() []JWKCrv {
+	return []JWKCrv{
+		JWKCrvEd25519,
+		JWKCrvP256,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s JWKCrv) MarshalText() ([]byte, error) {
+	switch s {
+	case JWKCrvEd25519:
+		return []byte(s), nil
+	case JWKCrvP256:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *JWKCrv) UnmarshalText(data []byte) error {
+	switch JWKCrv(data) {
+	case JWKCrvEd25519:
+		*s = JWKCrvEd25519
+		return nil
+	case JWKCrvP256:
+		*s = JWKCrvP256
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type JWKKty string
+
+const (
+	JWKKtyOKP JWKKty = "OKP"
+	JWKKtyEC  JWKKty = "EC"
+)
+
+// AllValues returns all JWKKty values.
+func (JWKKty) AllValues() []JWKKty {
+	return []JWKKty{
+		JWKKtyOKP,
+		JWKKtyEC,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s JWKKty) MarshalText() ([]byte, error) {
+	switch s {
+	case JWKKtyOKP:
+		return []byte(s), nil
+	case JWKKtyEC:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *JWKKty) UnmarshalText(data []byte) error {
+	switch JWKKty(data) {
+	case JWKKtyOKP:
+		*s = JWKKtyOKP
+		return nil
+	case JWKKtyEC:
+		*s = JWKKtyEC
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/JWKSet
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// GetKeys returns the value of Keys.
+func (s *JWKSet) GetKeys() []JWK {
+	return s.Keys
+}
+
+// SetKeys sets the value of Keys.
+func (s *JWKSet) SetKeys(val []JWK) {
+	s.Keys = val
+}
+
+type JWKUse string
+
+const (
+	JWKUseSig JWKUse = "sig"
+)
+
+// AllValues returns all JWKUse values.
+func (JWKUse) AllValues() []JWKUse {
+	return []JWKUse{
+		JWKUseSig,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s JWKUse) MarshalText() ([]byte, error) {
+	switch s {
+	case JWKUseSig:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *JWKUse) UnmarshalText(data []byte) error {
+	switch JWKUse(data) {
+	case JWKUseSig:
+		*s = JWKUseSig
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// LoginPostBadRequest is response for LoginPost operation.
+type LoginPostBadRequest struct{}
+
+func (*LoginPostBadRequest) loginPostRes() {}
+
+// LoginPostForbidden is response for LoginPost operation.
+type LoginPostForbidden struct{}
+
+func (*LoginPostForbidden) loginPostRes() {}
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDate returns new OptDate with value set to v.
+func NewOptDate(v time.Time) OptDate {
+	return OptDate{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDate is optional time.Time.
+type OptDate struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDate was set.
+func (o OptDate) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDate) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDate) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDate) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDate) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
 	return OptString{
 		Value: v,
 		Set:   true,
 	}
 }
 
-// OptString is optional string.
-type OptString struct {
-	Value string
-	Set   bool
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptUUID returns new OptUUID with value set to v.
+func NewOptUUID(v uuid.UUID) OptUUID {
+	return OptUUID{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptUUID is optional uuid.UUID.
+type OptUUID struct {
+	Value uuid.UUID
+	Set   bool
+}
+
+// IsSet returns true if OptUUID was set.
+func (o OptUUID) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptUUID) Reset() {
+	var v uuid.UUID
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptUUID) SetTo(v uuid.UUID) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptUUID) Get() (v uuid.UUID, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptUUID) Or(d uuid.UUID) uuid.UUID {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// Ref: #/components/schemas/OrgInvite
+type OrgInvite struct {
+	Login string  `json:"login"`
+	Role  OrgRole `json:"role"`
+	// Base64 encoded organization key wrapped for the public key of the invitee.
+	Key []byte `json:"key"`
+	// Version of the organization key that was wrapped.
+	KeyVersion int64 `json:"key_version"`
+}
+
+// GetLogin returns the value of Login.
+func (s *OrgInvite) GetLogin() string {
+	return s.Login
+}
+
+// GetRole returns the value of Role.
+func (s *OrgInvite) GetRole() OrgRole {
+	return s.Role
+}
+
+// GetKey returns the value of Key.
+func (s *OrgInvite) GetKey() []byte {
+	return s.Key
+}
+
+// GetKeyVersion returns the value of KeyVersion.
+func (s *OrgInvite) GetKeyVersion() int64 {
+	return s.KeyVersion
+}
+
+// SetLogin sets the value of Login.
+func (s *OrgInvite) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetRole sets the value of Role.
+func (s *OrgInvite) SetRole(val OrgRole) {
+	s.Role = val
+}
+
+// SetKey sets the value of Key.
+func (s *OrgInvite) SetKey(val []byte) {
+	s.Key = val
+}
+
+// SetKeyVersion sets the value of KeyVersion.
+func (s *OrgInvite) SetKeyVersion(val int64) {
+	s.KeyVersion = val
+}
+
+// Ref: #/components/schemas/OrgMember
+type OrgMember struct {
+	UserID uuid.UUID `json:"user_id"`
+	Login  string    `json:"login"`
+	Role   OrgRole   `json:"role"`
+	// Base64 encoded X25519 public key, used to wrap a new key on re-key.
+	PublicKey []byte    `json:"public_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetUserID returns the value of UserID.
+func (s *OrgMember) GetUserID() uuid.UUID {
+	return s.UserID
+}
+
+// GetLogin returns the value of Login.
+func (s *OrgMember) GetLogin() string {
+	return s.Login
+}
+
+// GetRole returns the value of Role.
+func (s *OrgMember) GetRole() OrgRole {
+	return s.Role
+}
+
+// GetPublicKey returns the value of PublicKey.
+func (s *OrgMember) GetPublicKey() []byte {
+	return s.PublicKey
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *OrgMember) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetUserID sets the value of UserID.
+func (s *OrgMember) SetUserID(val uuid.UUID) {
+	s.UserID = val
+}
+
+// SetLogin sets the value of Login.
+func (s *OrgMember) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetRole sets the value of Role.
+func (s *OrgMember) SetRole(val OrgRole) {
+	s.Role = val
+}
+
+// SetPublicKey sets the value of PublicKey.
+func (s *OrgMember) SetPublicKey(val []byte) {
+	s.PublicKey = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *OrgMember) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+func (*OrgMember) orgsOrgIDMembersPostRes() {}
+
+// Ref: #/components/schemas/OrgMemberKey
+type OrgMemberKey struct {
+	UserID uuid.UUID `json:"user_id"`
+	Key    []byte    `json:"key"`
+}
+
+// GetUserID returns the value of UserID.
+func (s *OrgMemberKey) GetUserID() uuid.UUID {
+	return s.UserID
+}
+
+// GetKey returns the value of Key.
+func (s *OrgMemberKey) GetKey() []byte {
+	return s.Key
+}
+
+// SetUserID sets the value of UserID.
+func (s *OrgMemberKey) SetUserID(val uuid.UUID) {
+	s.UserID = val
+}
+
+// SetKey sets the value of Key.
+func (s *OrgMemberKey) SetKey(val []byte) {
+	s.Key = val
+}
+
+// Merged schema.
+// Ref: #/components/schemas/OrgRecord
+type OrgRecord struct {
+	ID   OptUUID    `json:"id"`
+	Type RecordType `json:"type"`
+	// Base64 encoded encrypted data.
+	Data []byte `json:"data"`
+	// Base64 encoded nonce used for encryption.
+	Nonce []byte `json:"nonce"`
+	// Data version for synchronization.
+	Version int `json:"version"`
+	// Base64 encoded record key wrapped with the vault key, absent for records encrypted with the vault
+	// key.
+	Key []byte `json:"key"`
+	// Version of the organization key the record is encrypted with.
+	KeyVersion int64 `json:"key_version"`
+}
+
+// GetID returns the value of ID.
+func (s *OrgRecord) GetID() OptUUID {
+	return s.ID
+}
+
+// GetType returns the value of Type.
+func (s *OrgRecord) GetType() RecordType {
+	return s.Type
+}
+
+// GetData returns the value of Data.
+func (s *OrgRecord) GetData() []byte {
+	return s.Data
+}
+
+// GetNonce returns the value of Nonce.
+func (s *OrgRecord) GetNonce() []byte {
+	return s.Nonce
+}
+
+// GetVersion returns the value of Version.
+func (s *OrgRecord) GetVersion() int {
+	return s.Version
+}
+
+// GetKey returns the value of Key.
+func (s *OrgRecord) GetKey() []byte {
+	return s.Key
+}
+
+// GetKeyVersion returns the value of KeyVersion.
+func (s *OrgRecord) GetKeyVersion() int64 {
+	return s.KeyVersion
+}
+
+// SetID sets the value of ID.
+func (s *OrgRecord) SetID(val OptUUID) {
+	s.ID = val
+}
+
+// SetType sets the value of Type.
+func (s *OrgRecord) SetType(val RecordType) {
+	s.Type = val
+}
+
+// SetData sets the value of Data.
+func (s *OrgRecord) SetData(val []byte) {
+	s.Data = val
+}
+
+// SetNonce sets the value of Nonce.
+func (s *OrgRecord) SetNonce(val []byte) {
+	s.Nonce = val
+}
+
+// SetVersion sets the value of Version.
+func (s *OrgRecord) SetVersion(val int) {
+	s.Version = val
+}
+
+// SetKey sets the value of Key.
+func (s *OrgRecord) SetKey(val []byte) {
+	s.Key = val
+}
+
+// SetKeyVersion sets the value of KeyVersion.
+func (s *OrgRecord) SetKeyVersion(val int64) {
+	s.KeyVersion = val
+}
+
+// Ref: #/components/schemas/OrgRekey
+type OrgRekey struct {
+	// The new version, one more than the current.
+	KeyVersion int64 `json:"key_version"`
+	// The new key wrapped for every member.
+	Keys []OrgMemberKey `json:"keys"`
+	// Every record encrypted with the new key, with its version incremented.
+	Records []RecordWithId `json:"records"`
+}
+
+// GetKeyVersion returns the value of KeyVersion.
+func (s *OrgRekey) GetKeyVersion() int64 {
+	return s.KeyVersion
+}
+
+// GetKeys returns the value of Keys.
+func (s *OrgRekey) GetKeys() []OrgMemberKey {
+	return s.Keys
+}
+
+// GetRecords returns the value of Records.
+func (s *OrgRekey) GetRecords() []RecordWithId {
+	return s.Records
+}
+
+// SetKeyVersion sets the value of KeyVersion.
+func (s *OrgRekey) SetKeyVersion(val int64) {
+	s.KeyVersion = val
+}
+
+// SetKeys sets the value of Keys.
+func (s *OrgRekey) SetKeys(val []OrgMemberKey) {
+	s.Keys = val
+}
+
+// SetRecords sets the value of Records.
+func (s *OrgRekey) SetRecords(val []RecordWithId) {
+	s.Records = val
+}
+
+// Ref: #/components/schemas/OrgRole
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleWriter OrgRole = "writer"
+	OrgRoleReader OrgRole = "reader"
+)
+
+// AllValues returns all OrgRole values.
+func (OrgRole) AllValues() []OrgRole {
+	return []OrgRole{
+		OrgRoleOwner,
+		OrgRoleAdmin,
+		OrgRoleWriter,
+		OrgRoleReader,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s OrgRole) MarshalText() ([]byte, error) {
+	switch s {
+	case OrgRoleOwner:
+		return []byte(s), nil
+	case OrgRoleAdmin:
+		return []byte(s), nil
+	case OrgRoleWriter:
+		return []byte(s), nil
+	case OrgRoleReader:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *OrgRole) UnmarshalText(data []byte) error {
+	switch OrgRole(data) {
+	case OrgRoleOwner:
+		*s = OrgRoleOwner
+		return nil
+	case OrgRoleAdmin:
+		*s = OrgRoleAdmin
+		return nil
+	case OrgRoleWriter:
+		*s = OrgRoleWriter
+		return nil
+	case OrgRoleReader:
+		*s = OrgRoleReader
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/OrgRoleRequest
+type OrgRoleRequest struct {
+	Role OrgRole `json:"role"`
+}
+
+// GetRole returns the value of Role.
+func (s *OrgRoleRequest) GetRole() OrgRole {
+	return s.Role
+}
+
+// SetRole sets the value of Role.
+func (s *OrgRoleRequest) SetRole(val OrgRole) {
+	s.Role = val
+}
+
+// Ref: #/components/schemas/Organization
+type Organization struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Role OrgRole   `json:"role"`
+	// Base64 encoded organization key wrapped for the public key of the user.
+	Key []byte `json:"key"`
+	// Incremented on every re-key.
+	KeyVersion int64 `json:"key_version"`
+	// A member left since the last re-key.
+	RekeyNeeded bool      `json:"rekey_needed"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetID returns the value of ID.
+func (s *Organization) GetID() uuid.UUID {
+	return s.ID
+}
+
+// GetName returns the value of Name.
+func (s *Organization) GetName() string {
+	return s.Name
+}
+
+// GetRole returns the value of Role.
+func (s *Organization) GetRole() OrgRole {
+	return s.Role
+}
+
+// GetKey returns the value of Key.
+func (s *Organization) GetKey() []byte {
+	return s.Key
+}
+
+// GetKeyVersion returns the value of KeyVersion.
+func (s *Organization) GetKeyVersion() int64 {
+	return s.KeyVersion
+}
+
+// GetRekeyNeeded returns the value of RekeyNeeded.
+func (s *Organization) GetRekeyNeeded() bool {
+	return s.RekeyNeeded
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Organization) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *Organization) SetID(val uuid.UUID) {
+	s.ID = val
+}
+
+// SetName sets the value of Name.
+func (s *Organization) SetName(val string) {
+	s.Name = val
+}
+
+// SetRole sets the value of Role.
+func (s *Organization) SetRole(val OrgRole) {
+	s.Role = val
+}
+
+// SetKey sets the value of Key.
+func (s *Organization) SetKey(val []byte) {
+	s.Key = val
+}
+
+// SetKeyVersion sets the value of KeyVersion.
+func (s *Organization) SetKeyVersion(val int64) {
+	s.KeyVersion = val
+}
+
+// SetRekeyNeeded sets the value of RekeyNeeded.
+func (s *Organization) SetRekeyNeeded(val bool) {
+	s.RekeyNeeded = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Organization) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+func (*Organization) orgsPostRes() {}
+
+// Ref: #/components/schemas/OrganizationRequest
+type OrganizationRequest struct {
+	Name string `json:"name"`
+	// Base64 encoded organization key wrapped for the public key of the user.
+	Key []byte `json:"key"`
+}
+
+// GetName returns the value of Name.
+func (s *OrganizationRequest) GetName() string {
+	return s.Name
+}
+
+// GetKey returns the value of Key.
+func (s *OrganizationRequest) GetKey() []byte {
+	return s.Key
+}
+
+// SetName sets the value of Name.
+func (s *OrganizationRequest) SetName(val string) {
+	s.Name = val
+}
+
+// SetKey sets the value of Key.
+func (s *OrganizationRequest) SetKey(val []byte) {
+	s.Key = val
+}
+
+type OrgsGetOKApplicationJSON []Organization
+
+func (*OrgsGetOKApplicationJSON) orgsGetRes() {}
+
+// OrgsOrgIDDeleteForbidden is response for OrgsOrgIDDelete operation.
+type OrgsOrgIDDeleteForbidden struct{}
+
+func (*OrgsOrgIDDeleteForbidden) orgsOrgIDDeleteRes() {}
+
+// OrgsOrgIDDeleteNoContent is response for OrgsOrgIDDelete operation.
+type OrgsOrgIDDeleteNoContent struct{}
+
+func (*OrgsOrgIDDeleteNoContent) orgsOrgIDDeleteRes() {}
+
+// OrgsOrgIDDeleteNotFound is response for OrgsOrgIDDelete operation.
+type OrgsOrgIDDeleteNotFound struct{}
+
+func (*OrgsOrgIDDeleteNotFound) orgsOrgIDDeleteRes() {}
+
+// OrgsOrgIDMembersGetNotFound is response for OrgsOrgIDMembersGet operation.
+type OrgsOrgIDMembersGetNotFound struct{}
+
+func (*OrgsOrgIDMembersGetNotFound) orgsOrgIDMembersGetRes() {}
+
+type OrgsOrgIDMembersGetOKApplicationJSON []OrgMember
+
+func (*OrgsOrgIDMembersGetOKApplicationJSON) orgsOrgIDMembersGetRes() {}
+
+// OrgsOrgIDMembersPostBadRequest is response for OrgsOrgIDMembersPost operation.
+type OrgsOrgIDMembersPostBadRequest struct{}
+
+func (*OrgsOrgIDMembersPostBadRequest) orgsOrgIDMembersPostRes() {}
+
+// OrgsOrgIDMembersPostConflict is response for OrgsOrgIDMembersPost operation.
+type OrgsOrgIDMembersPostConflict struct{}
+
+func (*OrgsOrgIDMembersPostConflict) orgsOrgIDMembersPostRes() {}
+
+// OrgsOrgIDMembersPostForbidden is response for OrgsOrgIDMembersPost operation.
+type OrgsOrgIDMembersPostForbidden struct{}
+
+func (*OrgsOrgIDMembersPostForbidden) orgsOrgIDMembersPostRes() {}
+
+// OrgsOrgIDMembersPostNotFound is response for OrgsOrgIDMembersPost operation.
+type OrgsOrgIDMembersPostNotFound struct{}
+
+func (*OrgsOrgIDMembersPostNotFound) orgsOrgIDMembersPostRes() {}
+
+// OrgsOrgIDMembersPostUnprocessableEntity is response for OrgsOrgIDMembersPost operation.
+type OrgsOrgIDMembersPostUnprocessableEntity struct{}
+
+func (*OrgsOrgIDMembersPostUnprocessableEntity) orgsOrgIDMembersPostRes() {}
+
+// OrgsOrgIDMembersUserIDDeleteForbidden is response for OrgsOrgIDMembersUserIDDelete operation.
+type OrgsOrgIDMembersUserIDDeleteForbidden struct{}
+
+func (*OrgsOrgIDMembersUserIDDeleteForbidden) orgsOrgIDMembersUserIDDeleteRes() {}
+
+// OrgsOrgIDMembersUserIDDeleteNoContent is response for OrgsOrgIDMembersUserIDDelete operation.
+type OrgsOrgIDMembersUserIDDeleteNoContent struct{}
+
+func (*OrgsOrgIDMembersUserIDDeleteNoContent) orgsOrgIDMembersUserIDDeleteRes() {}
+
+// OrgsOrgIDMembersUserIDDeleteNotFound is response for OrgsOrgIDMembersUserIDDelete operation.
+type OrgsOrgIDMembersUserIDDeleteNotFound struct{}
+
+func (*OrgsOrgIDMembersUserIDDeleteNotFound) orgsOrgIDMembersUserIDDeleteRes() {}
+
+// OrgsOrgIDMembersUserIDDeleteUnprocessableEntity is response for OrgsOrgIDMembersUserIDDelete operation.
+type OrgsOrgIDMembersUserIDDeleteUnprocessableEntity struct{}
+
+func (*OrgsOrgIDMembersUserIDDeleteUnprocessableEntity) orgsOrgIDMembersUserIDDeleteRes() {}
+
+// OrgsOrgIDMembersUserIDPutBadRequest is response for OrgsOrgIDMembersUserIDPut operation.
+type OrgsOrgIDMembersUserIDPutBadRequest struct{}
+
+func (*OrgsOrgIDMembersUserIDPutBadRequest) orgsOrgIDMembersUserIDPutRes() {}
+
+// OrgsOrgIDMembersUserIDPutForbidden is response for OrgsOrgIDMembersUserIDPut operation.
+type OrgsOrgIDMembersUserIDPutForbidden struct{}
+
+func (*OrgsOrgIDMembersUserIDPutForbidden) orgsOrgIDMembersUserIDPutRes() {}
+
+// OrgsOrgIDMembersUserIDPutNoContent is response for OrgsOrgIDMembersUserIDPut operation.
+type OrgsOrgIDMembersUserIDPutNoContent struct{}
+
+func (*OrgsOrgIDMembersUserIDPutNoContent) orgsOrgIDMembersUserIDPutRes() {}
+
+// OrgsOrgIDMembersUserIDPutNotFound is response for OrgsOrgIDMembersUserIDPut operation.
+type OrgsOrgIDMembersUserIDPutNotFound struct{}
+
+func (*OrgsOrgIDMembersUserIDPutNotFound) orgsOrgIDMembersUserIDPutRes() {}
+
+// OrgsOrgIDMembersUserIDPutUnprocessableEntity is response for OrgsOrgIDMembersUserIDPut operation.
+type OrgsOrgIDMembersUserIDPutUnprocessableEntity struct{}
+
+func (*OrgsOrgIDMembersUserIDPutUnprocessableEntity) orgsOrgIDMembersUserIDPutRes() {}
+
+// OrgsOrgIDRecordsGetNotFound is response for OrgsOrgIDRecordsGet operation.
+type OrgsOrgIDRecordsGetNotFound struct{}
+
+func (*OrgsOrgIDRecordsGetNotFound) orgsOrgIDRecordsGetRes() {}
+
+type OrgsOrgIDRecordsGetOKApplicationJSON []RecordWithId
+
+func (*OrgsOrgIDRecordsGetOKApplicationJSON) orgsOrgIDRecordsGetRes() {}
+
+// OrgsOrgIDRecordsIDDeleteForbidden is response for OrgsOrgIDRecordsIDDelete operation.
+type OrgsOrgIDRecordsIDDeleteForbidden struct{}
+
+func (*OrgsOrgIDRecordsIDDeleteForbidden) orgsOrgIDRecordsIDDeleteRes() {}
+
+// OrgsOrgIDRecordsIDDeleteNoContent is response for OrgsOrgIDRecordsIDDelete operation.
+type OrgsOrgIDRecordsIDDeleteNoContent struct{}
+
+func (*OrgsOrgIDRecordsIDDeleteNoContent) orgsOrgIDRecordsIDDeleteRes() {}
+
+// OrgsOrgIDRecordsIDDeleteNotFound is response for OrgsOrgIDRecordsIDDelete operation.
+type OrgsOrgIDRecordsIDDeleteNotFound struct{}
+
+func (*OrgsOrgIDRecordsIDDeleteNotFound) orgsOrgIDRecordsIDDeleteRes() {}
+
+// OrgsOrgIDRecordsIDPutBadRequest is response for OrgsOrgIDRecordsIDPut operation.
+type OrgsOrgIDRecordsIDPutBadRequest struct{}
+
+func (*OrgsOrgIDRecordsIDPutBadRequest) orgsOrgIDRecordsIDPutRes() {}
+
+// OrgsOrgIDRecordsIDPutConflict is response for OrgsOrgIDRecordsIDPut operation.
+type OrgsOrgIDRecordsIDPutConflict struct{}
+
+func (*OrgsOrgIDRecordsIDPutConflict) orgsOrgIDRecordsIDPutRes() {}
+
+// OrgsOrgIDRecordsIDPutForbidden is response for OrgsOrgIDRecordsIDPut operation.
+type OrgsOrgIDRecordsIDPutForbidden struct{}
+
+func (*OrgsOrgIDRecordsIDPutForbidden) orgsOrgIDRecordsIDPutRes() {}
+
+// OrgsOrgIDRecordsIDPutNoContent is response for OrgsOrgIDRecordsIDPut operation.
+type OrgsOrgIDRecordsIDPutNoContent struct{}
+
+func (*OrgsOrgIDRecordsIDPutNoContent) orgsOrgIDRecordsIDPutRes() {}
+
+// OrgsOrgIDRecordsIDPutNotFound is response for OrgsOrgIDRecordsIDPut operation.
+type OrgsOrgIDRecordsIDPutNotFound struct{}
+
+func (*OrgsOrgIDRecordsIDPutNotFound) orgsOrgIDRecordsIDPutRes() {}
+
+// OrgsOrgIDRekeyPostBadRequest is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostBadRequest struct{}
+
+func (*OrgsOrgIDRekeyPostBadRequest) orgsOrgIDRekeyPostRes() {}
+
+// OrgsOrgIDRekeyPostConflict is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostConflict struct{}
+
+func (*OrgsOrgIDRekeyPostConflict) orgsOrgIDRekeyPostRes() {}
+
+// OrgsOrgIDRekeyPostForbidden is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostForbidden struct{}
+
+func (*OrgsOrgIDRekeyPostForbidden) orgsOrgIDRekeyPostRes() {}
+
+// OrgsOrgIDRekeyPostNoContent is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostNoContent struct{}
+
+func (*OrgsOrgIDRekeyPostNoContent) orgsOrgIDRekeyPostRes() {}
+
+// OrgsOrgIDRekeyPostNotFound is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostNotFound struct{}
+
+func (*OrgsOrgIDRekeyPostNotFound) orgsOrgIDRekeyPostRes() {}
+
+// OrgsOrgIDRekeyPostUnprocessableEntity is response for OrgsOrgIDRekeyPost operation.
+type OrgsOrgIDRekeyPostUnprocessableEntity struct{}
+
+func (*OrgsOrgIDRekeyPostUnprocessableEntity) orgsOrgIDRekeyPostRes() {}
+
+// OrgsPostBadRequest is response for OrgsPost operation.
+type OrgsPostBadRequest struct{}
+
+func (*OrgsPostBadRequest) orgsPostRes() {}
+
+// OrgsPostUnprocessableEntity is response for OrgsPost operation.
+type OrgsPostUnprocessableEntity struct{}
+
+func (*OrgsPostUnprocessableEntity) orgsPostRes() {}
+
+// Ref: #/components/schemas/PasswordConfirmation
+type PasswordConfirmation struct {
+	Password string `json:"password"`
+}
+
+// GetPassword returns the value of Password.
+func (s *PasswordConfirmation) GetPassword() string {
+	return s.Password
+}
+
+// SetPassword sets the value of Password.
+func (s *PasswordConfirmation) SetPassword(val string) {
+	s.Password = val
+}
+
+// Ref: #/components/schemas/PublicKey
+type PublicKey struct {
+	Login string `json:"login"`
+	// Base64 encoded X25519 public key.
+	PublicKey []byte `json:"public_key"`
+}
+
+// GetLogin returns the value of Login.
+func (s *PublicKey) GetLogin() string {
+	return s.Login
+}
+
+// GetPublicKey returns the value of PublicKey.
+func (s *PublicKey) GetPublicKey() []byte {
+	return s.PublicKey
+}
+
+// SetLogin sets the value of Login.
+func (s *PublicKey) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetPublicKey sets the value of PublicKey.
+func (s *PublicKey) SetPublicKey(val []byte) {
+	s.PublicKey = val
+}
+
+func (*PublicKey) usersLoginPublicKeyGetRes() {}
+
+// Ref: #/components/schemas/Record
+type Record struct {
+	ID   OptUUID    `json:"id"`
+	Type RecordType `json:"type"`
+	// Base64 encoded encrypted data.
+	Data []byte `json:"data"`
+	// Base64 encoded nonce used for encryption.
+	Nonce []byte `json:"nonce"`
+	// Data version for synchronization.
+	Version int `json:"version"`
+	// Base64 encoded record key wrapped with the vault key, absent for records encrypted with the vault
+	// key.
+	Key []byte `json:"key"`
+}
+
+// GetID returns the value of ID.
+func (s *Record) GetID() OptUUID {
+	return s.ID
+}
+
+// GetType returns the value of Type.
+func (s *Record) GetType() RecordType {
+	return s.Type
+}
+
+// GetData returns the value of Data.
+func (s *Record) GetData() []byte {
+	return s.Data
+}
+
+// GetNonce returns the value of Nonce.
+func (s *Record) GetNonce() []byte {
+	return s.Nonce
+}
+
+// GetVersion returns the value of Version.
+func (s *Record) GetVersion() int {
+	return s.Version
+}
+
+// GetKey returns the value of Key.
+func (s *Record) GetKey() []byte {
+	return s.Key
+}
+
+// SetID sets the value of ID.
+func (s *Record) SetID(val OptUUID) {
+	s.ID = val
+}
+
+// SetType sets the value of Type.
+func (s *Record) SetType(val RecordType) {
+	s.Type = val
+}
+
+// SetData sets the value of Data.
+func (s *Record) SetData(val []byte) {
+	s.Data = val
+}
+
+// SetNonce sets the value of Nonce.
+func (s *Record) SetNonce(val []byte) {
+	s.Nonce = val
+}
+
+// SetVersion sets the value of Version.
+func (s *Record) SetVersion(val int) {
+	s.Version = val
+}
+
+// SetKey sets the value of Key.
+func (s *Record) SetKey(val []byte) {
+	s.Key = val
+}
+
+// Ref: #/components/schemas/RecordKey
+type RecordKey struct {
+	RecordID uuid.UUID `json:"record_id"`
+	Key      []byte    `json:"key"`
+}
+
+// GetRecordID returns the value of RecordID.
+func (s *RecordKey) GetRecordID() uuid.UUID {
+	return s.RecordID
+}
+
+// GetKey returns the value of Key.
+func (s *RecordKey) GetKey() []byte {
+	return s.Key
+}
+
+// SetRecordID sets the value of RecordID.
+func (s *RecordKey) SetRecordID(val uuid.UUID) {
+	s.RecordID = val
+}
+
+// SetKey sets the value of Key.
+func (s *RecordKey) SetKey(val []byte) {
+	s.Key = val
+}
+
+// Ref: #/components/schemas/RecordType
+type RecordType string
+
+const (
+	RecordTypeCredentials RecordType = "credentials"
+	RecordTypeText        RecordType = "text"
+	RecordTypeBinary      RecordType = "binary"
+	RecordTypeCard        RecordType = "card"
+	RecordTypeSSHKey      RecordType = "ssh_key"
+)
+
+// AllValues returns all RecordType values.
+func (RecordType) AllValues() []RecordType {
+	return []RecordType{
+		RecordTypeCredentials,
+		RecordTypeText,
+		RecordTypeBinary,
+		RecordTypeCard,
+		RecordTypeSSHKey,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s RecordType) MarshalText() ([]byte, error) {
+	switch s {
+	case RecordTypeCredentials:
+		return []byte(s), nil
+	case RecordTypeText:
+		return []byte(s), nil
+	case RecordTypeBinary:
+		return []byte(s), nil
+	case RecordTypeCard:
+		return []byte(s), nil
+	case RecordTypeSSHKey:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
 }
 
-// IsSet returns true if OptString was set.
-func (o OptString) IsSet() bool { return o.Set }
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *RecordType) UnmarshalText(data []byte) error {
+	switch RecordType(data) {
+	case RecordTypeCredentials:
+		*s = RecordTypeCredentials
+		return nil
+	case RecordTypeText:
+		*s = RecordTypeText
+		return nil
+	case RecordTypeBinary:
+		*s = RecordTypeBinary
+		return nil
+	case RecordTypeCard:
+		*s = RecordTypeCard
+		return nil
+	case RecordTypeSSHKey:
+		*s = RecordTypeSSHKey
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Merged schema.
+// Ref: #/components/schemas/RecordWithId
+type RecordWithId struct {
+	ID   uuid.UUID  `json:"id"`
+	Type RecordType `json:"type"`
+	// Base64 encoded encrypted data.
+	Data []byte `json:"data"`
+	// Base64 encoded nonce used for encryption.
+	Nonce []byte `json:"nonce"`
+	// Data version for synchronization.
+	Version int `json:"version"`
+	// Base64 encoded record key wrapped with the vault key, absent for records encrypted with the vault
+	// key.
+	Key []byte `json:"key"`
+}
+
+// GetID returns the value of ID.
+func (s *RecordWithId) GetID() uuid.UUID {
+	return s.ID
+}
+
+// GetType returns the value of Type.
+func (s *RecordWithId) GetType() RecordType {
+	return s.Type
+}
+
+// GetData returns the value of Data.
+func (s *RecordWithId) GetData() []byte {
+	return s.Data
+}
+
+// GetNonce returns the value of Nonce.
+func (s *RecordWithId) GetNonce() []byte {
+	return s.Nonce
+}
+
+// GetVersion returns the value of Version.
+func (s *RecordWithId) GetVersion() int {
+	return s.Version
+}
+
+// GetKey returns the value of Key.
+func (s *RecordWithId) GetKey() []byte {
+	return s.Key
+}
+
+// SetID sets the value of ID.
+func (s *RecordWithId) SetID(val uuid.UUID) {
+	s.ID = val
+}
+
+// SetType sets the value of Type.
+func (s *RecordWithId) SetType(val RecordType) {
+	s.Type = val
+}
+
+// SetData sets the value of Data.
+func (s *RecordWithId) SetData(val []byte) {
+	s.Data = val
+}
+
+// SetNonce sets the value of Nonce.
+func (s *RecordWithId) SetNonce(val []byte) {
+	s.Nonce = val
+}
+
+// SetVersion sets the value of Version.
+func (s *RecordWithId) SetVersion(val int) {
+	s.Version = val
+}
+
+// SetKey sets the value of Key.
+func (s *RecordWithId) SetKey(val []byte) {
+	s.Key = val
+}
+
+func (*RecordWithId) recordsIDGetRes() {}
+
+type RecordsGetOKApplicationJSON []RecordWithId
+
+func (*RecordsGetOKApplicationJSON) recordsGetRes() {}
+
+// RecordsIDDeleteForbidden is response for RecordsIDDelete operation.
+type RecordsIDDeleteForbidden struct{}
+
+func (*RecordsIDDeleteForbidden) recordsIDDeleteRes() {}
+
+// RecordsIDDeleteNoContent is response for RecordsIDDelete operation.
+type RecordsIDDeleteNoContent struct{}
+
+func (*RecordsIDDeleteNoContent) recordsIDDeleteRes() {}
+
+// RecordsIDGetNotFound is response for RecordsIDGet operation.
+type RecordsIDGetNotFound struct{}
+
+func (*RecordsIDGetNotFound) recordsIDGetRes() {}
+
+// RecordsIDPutBadRequest is response for RecordsIDPut operation.
+type RecordsIDPutBadRequest struct{}
+
+func (*RecordsIDPutBadRequest) recordsIDPutRes() {}
+
+// RecordsIDPutConflict is response for RecordsIDPut operation.
+type RecordsIDPutConflict struct{}
+
+func (*RecordsIDPutConflict) recordsIDPutRes() {}
+
+// RecordsIDPutForbidden is response for RecordsIDPut operation.
+type RecordsIDPutForbidden struct{}
+
+func (*RecordsIDPutForbidden) recordsIDPutRes() {}
+
+// RecordsIDPutNoContent is response for RecordsIDPut operation.
+type RecordsIDPutNoContent struct{}
+
+func (*RecordsIDPutNoContent) recordsIDPutRes() {}
+
+// RecordsIDSharesGetNotFound is response for RecordsIDSharesGet operation.
+type RecordsIDSharesGetNotFound struct{}
+
+func (*RecordsIDSharesGetNotFound) recordsIDSharesGetRes() {}
+
+type RecordsIDSharesGetOKApplicationJSON []Share
+
+func (*RecordsIDSharesGetOKApplicationJSON) recordsIDSharesGetRes() {}
+
+// RecordsIDSharesPostBadRequest is response for RecordsIDSharesPost operation.
+type RecordsIDSharesPostBadRequest struct{}
+
+func (*RecordsIDSharesPostBadRequest) recordsIDSharesPostRes() {}
+
+// RecordsIDSharesPostNotFound is response for RecordsIDSharesPost operation.
+type RecordsIDSharesPostNotFound struct{}
+
+func (*RecordsIDSharesPostNotFound) recordsIDSharesPostRes() {}
+
+// RecordsIDSharesPostUnprocessableEntity is response for RecordsIDSharesPost operation.
+type RecordsIDSharesPostUnprocessableEntity struct{}
+
+func (*RecordsIDSharesPostUnprocessableEntity) recordsIDSharesPostRes() {}
+
+// RecordsIDSharesUserIDDeleteNoContent is response for RecordsIDSharesUserIDDelete operation.
+type RecordsIDSharesUserIDDeleteNoContent struct{}
+
+func (*RecordsIDSharesUserIDDeleteNoContent) recordsIDSharesUserIDDeleteRes() {}
+
+// RecordsIDSharesUserIDDeleteNotFound is response for RecordsIDSharesUserIDDelete operation.
+type RecordsIDSharesUserIDDeleteNotFound struct{}
+
+func (*RecordsIDSharesUserIDDeleteNotFound) recordsIDSharesUserIDDeleteRes() {}
+
+// RegisterPostBadRequest is response for RegisterPost operation.
+type RegisterPostBadRequest struct{}
+
+func (*RegisterPostBadRequest) registerPostRes() {}
+
+// RegisterPostConflict is response for RegisterPost operation.
+type RegisterPostConflict struct{}
+
+func (*RegisterPostConflict) registerPostRes() {}
+
+// RegisterPostForbidden is response for RegisterPost operation.
+type RegisterPostForbidden struct{}
+
+func (*RegisterPostForbidden) registerPostRes() {}
+
+// RegisterPostUnprocessableEntity is response for RegisterPost operation.
+type RegisterPostUnprocessableEntity struct{}
+
+func (*RegisterPostUnprocessableEntity) registerPostRes() {}
+
+// Ref: #/components/schemas/RegistrationInfo
+type RegistrationInfo struct {
+	Mode RegistrationMode `json:"mode"`
+}
+
+// GetMode returns the value of Mode.
+func (s *RegistrationInfo) GetMode() RegistrationMode {
+	return s.Mode
+}
+
+// SetMode sets the value of Mode.
+func (s *RegistrationInfo) SetMode(val RegistrationMode) {
+	s.Mode = val
+}
+
+// Ref: #/components/schemas/RegistrationMode
+type RegistrationMode string
+
+const (
+	RegistrationModeOpen       RegistrationMode = "open"
+	RegistrationModeInviteOnly RegistrationMode = "invite-only"
+	RegistrationModeClosed     RegistrationMode = "closed"
+)
+
+// AllValues returns all RegistrationMode values.
+func (RegistrationMode) AllValues() []RegistrationMode {
+	return []RegistrationMode{
+		RegistrationModeOpen,
+		RegistrationModeInviteOnly,
+		RegistrationModeClosed,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s RegistrationMode) MarshalText() ([]byte, error) {
+	switch s {
+	case RegistrationModeOpen:
+		return []byte(s), nil
+	case RegistrationModeInviteOnly:
+		return []byte(s), nil
+	case RegistrationModeClosed:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *RegistrationMode) UnmarshalText(data []byte) error {
+	switch RegistrationMode(data) {
+	case RegistrationModeOpen:
+		*s = RegistrationModeOpen
+		return nil
+	case RegistrationModeInviteOnly:
+		*s = RegistrationModeInviteOnly
+		return nil
+	case RegistrationModeClosed:
+		*s = RegistrationModeClosed
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/Send
+type Send struct {
+	ID                uuid.UUID `json:"id"`
+	MaxViews          int       `json:"max_views"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	PasswordProtected bool      `json:"password_protected"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// GetID returns the value of ID.
+func (s *Send) GetID() uuid.UUID {
+	return s.ID
+}
+
+// GetMaxViews returns the value of MaxViews.
+func (s *Send) GetMaxViews() int {
+	return s.MaxViews
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *Send) GetExpiresAt() time.Time {
+	return s.ExpiresAt
+}
+
+// GetPasswordProtected returns the value of PasswordProtected.
+func (s *Send) GetPasswordProtected() bool {
+	return s.PasswordProtected
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Send) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *Send) SetID(val uuid.UUID) {
+	s.ID = val
+}
 
-// Reset unsets value.
-func (o *OptString) Reset() {
-	var v string
-	o.Value = v
-	o.Set = false
+// SetMaxViews sets the value of MaxViews.
+func (s *Send) SetMaxViews(val int) {
+	s.MaxViews = val
 }
 
-// SetTo sets value to v.
-func (o *OptString) SetTo(v string) {
-	o.Set = true
-	o.Value = v
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *Send) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptString) Get() (v string, ok bool) {
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// SetPasswordProtected sets the value of PasswordProtected.
+func (s *Send) SetPasswordProtected(val bool) {
+	s.PasswordProtected = val
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptString) Or(d string) string {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Send) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
 }
 
-// NewOptUUID returns new OptUUID with value set to v.
-func NewOptUUID(v uuid.UUID) OptUUID {
-	return OptUUID{
-		Value: v,
-		Set:   true,
-	}
+func (*Send) sendsPostRes() {}
+
+// Ref: #/components/schemas/SendData
+type SendData struct {
+	Data  []byte `json:"data"`
+	Nonce []byte `json:"nonce"`
+	// Views left after this one.
+	ViewsLeft int       `json:"views_left"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
-// OptUUID is optional uuid.UUID.
-type OptUUID struct {
-	Value uuid.UUID
-	Set   bool
+// GetData returns the value of Data.
+func (s *SendData) GetData() []byte {
+	return s.Data
 }
 
-// IsSet returns true if OptUUID was set.
-func (o OptUUID) IsSet() bool { return o.Set }
+// GetNonce returns the value of Nonce.
+func (s *SendData) GetNonce() []byte {
+	return s.Nonce
+}
 
-// Reset unsets value.
-func (o *OptUUID) Reset() {
-	var v uuid.UUID
-	o.Value = v
-	o.Set = false
+// GetViewsLeft returns the value of ViewsLeft.
+func (s *SendData) GetViewsLeft() int {
+	return s.ViewsLeft
 }
 
-// SetTo sets value to v.
-func (o *OptUUID) SetTo(v uuid.UUID) {
-	o.Set = true
-	o.Value = v
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *SendData) GetExpiresAt() time.Time {
+	return s.ExpiresAt
 }
 
-// Get returns value and boolean that denotes whether value was set.
-func (o OptUUID) Get() (v uuid.UUID, ok bool) {
-	if !o.Set {
-		return v, false
-	}
-	return o.Value, true
+// SetData sets the value of Data.
+func (s *SendData) SetData(val []byte) {
+	s.Data = val
 }
 
-// Or returns value if set, or given parameter if does not.
-func (o OptUUID) Or(d uuid.UUID) uuid.UUID {
-	if v, ok := o.Get(); ok {
-		return v
-	}
-	return d
+// SetNonce sets the value of Nonce.
+func (s *SendData) SetNonce(val []byte) {
+	s.Nonce = val
 }
 
-// Ref: #/components/schemas/Record
-type Record struct {
-	ID   OptUUID    `json:"id"`
-	Type RecordType `json:"type"`
-	// Base64 encoded encrypted data.
-	Data []byte `json:"data"`
-	// Base64 encoded nonce used for encryption.
-	Nonce []byte `json:"nonce"`
-	// Data version for synchronization.
-	Version int `json:"version"`
+// SetViewsLeft sets the value of ViewsLeft.
+func (s *SendData) SetViewsLeft(val int) {
+	s.ViewsLeft = val
 }
 
-// GetID returns the value of ID.
-func (s *Record) GetID() OptUUID {
-	return s.ID
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *SendData) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
 }
 
-// GetType returns the value of Type.
-func (s *Record) GetType() RecordType {
-	return s.Type
+func (*SendData) sendsIDGetRes() {}
+
+// Ref: #/components/schemas/SendRequest
+type SendRequest struct {
+	// Base64 encoded encrypted data.
+	Data []byte `json:"data"`
+	// Base64 encoded nonce used for encryption.
+	Nonce    []byte `json:"nonce"`
+	MaxViews int    `json:"max_views"`
+	// At most 30 days ahead.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Optional access password, only its hash is stored.
+	Password OptString `json:"password"`
 }
 
 // GetData returns the value of Data.
-func (s *Record) GetData() []byte {
+func (s *SendRequest) GetData() []byte {
 	return s.Data
 }
 
 // GetNonce returns the value of Nonce.
-func (s *Record) GetNonce() []byte {
+func (s *SendRequest) GetNonce() []byte {
 	return s.Nonce
 }
 
-// GetVersion returns the value of Version.
-func (s *Record) GetVersion() int {
-	return s.Version
+// GetMaxViews returns the value of MaxViews.
+func (s *SendRequest) GetMaxViews() int {
+	return s.MaxViews
 }
 
-// SetID sets the value of ID.
-func (s *Record) SetID(val OptUUID) {
-	s.ID = val
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *SendRequest) GetExpiresAt() time.Time {
+	return s.ExpiresAt
 }
 
-// SetType sets the value of Type.
-func (s *Record) SetType(val RecordType) {
-	s.Type = val
+// GetPassword returns the value of Password.
+func (s *SendRequest) GetPassword() OptString {
+	return s.Password
 }
 
 // SetData sets the value of Data.
-func (s *Record) SetData(val []byte) {
+func (s *SendRequest) SetData(val []byte) {
 	s.Data = val
 }
 
 // SetNonce sets the value of Nonce.
-func (s *Record) SetNonce(val []byte) {
+func (s *SendRequest) SetNonce(val []byte) {
 	s.Nonce = val
 }
 
-// SetVersion sets the value of Version.
-func (s *Record) SetVersion(val int) {
-	s.Version = val
+// SetMaxViews sets the value of MaxViews.
+func (s *SendRequest) SetMaxViews(val int) {
+	s.MaxViews = val
 }
 
-// Ref: #/components/schemas/RecordType
-type RecordType string
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *SendRequest) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
+}
 
-const (
-	RecordTypeCredentials RecordType = "credentials"
-	RecordTypeText        RecordType = "text"
-	RecordTypeBinary      RecordType = "binary"
-	RecordTypeCard        RecordType = "card"
-)
+// SetPassword sets the value of Password.
+func (s *SendRequest) SetPassword(val OptString) {
+	s.Password = val
+}
 
-// AllValues returns all RecordType values.
-func (RecordType) AllValues() []RecordType {
-	return []RecordType{
-		RecordTypeCredentials,
-		RecordTypeText,
-		RecordTypeBinary,
-		RecordTypeCard,
-	}
+// SendsIDGetForbidden is response for SendsIDGet operation.
+type SendsIDGetForbidden struct{}
+
+func (*SendsIDGetForbidden) sendsIDGetRes() {}
+
+// SendsIDGetNotFound is response for SendsIDGet operation.
+type SendsIDGetNotFound struct{}
+
+func (*SendsIDGetNotFound) sendsIDGetRes() {}
+
+// SendsPostBadRequest is response for SendsPost operation.
+type SendsPostBadRequest struct{}
+
+func (*SendsPostBadRequest) sendsPostRes() {}
+
+// SendsPostUnprocessableEntity is response for SendsPost operation.
+type SendsPostUnprocessableEntity struct{}
+
+func (*SendsPostUnprocessableEntity) sendsPostRes() {}
+
+// Ref: #/components/schemas/Share
+type Share struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Login     string    `json:"login"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-// MarshalText implements encoding.TextMarshaler.
-func (s RecordType) MarshalText() ([]byte, error) {
-	switch s {
-	case RecordTypeCredentials:
-		return []byte(s), nil
-	case RecordTypeText:
-		return []byte(s), nil
-	case RecordTypeBinary:
-		return []byte(s), nil
-	case RecordTypeCard:
-		return []byte(s), nil
-	default:
-		return nil, errors.Errorf("invalid value: %q", s)
-	}
+// GetUserID returns the value of UserID.
+func (s *Share) GetUserID() uuid.UUID {
+	return s.UserID
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler.
-func (s *RecordType) UnmarshalText(data []byte) error {
-	switch RecordType(data) {
-	case RecordTypeCredentials:
-		*s = RecordTypeCredentials
-		return nil
-	case RecordTypeText:
-		*s = RecordTypeText
-		return nil
-	case RecordTypeBinary:
-		*s = RecordTypeBinary
-		return nil
-	case RecordTypeCard:
-		*s = RecordTypeCard
-		return nil
-	default:
-		return errors.Errorf("invalid value: %q", data)
-	}
+// GetLogin returns the value of Login.
+func (s *Share) GetLogin() string {
+	return s.Login
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Share) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetUserID sets the value of UserID.
+func (s *Share) SetUserID(val uuid.UUID) {
+	s.UserID = val
+}
+
+// SetLogin sets the value of Login.
+func (s *Share) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Share) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+func (*Share) recordsIDSharesPostRes() {}
+
+// Ref: #/components/schemas/ShareRequest
+type ShareRequest struct {
+	// Login of the recipient.
+	Login string `json:"login"`
+	// Base64 encoded record key wrapped for the public key of the recipient.
+	Key []byte `json:"key"`
+}
+
+// GetLogin returns the value of Login.
+func (s *ShareRequest) GetLogin() string {
+	return s.Login
+}
+
+// GetKey returns the value of Key.
+func (s *ShareRequest) GetKey() []byte {
+	return s.Key
 }
 
+// SetLogin sets the value of Login.
+func (s *ShareRequest) SetLogin(val string) {
+	s.Login = val
+}
+
+// SetKey sets the value of Key.
+func (s *ShareRequest) SetKey(val []byte) {
+	s.Key = val
+}
+
+type SharedGetOKApplicationJSON []SharedRecord
+
+func (*SharedGetOKApplicationJSON) sharedGetRes() {}
+
 // Merged schema.
-// Ref: #/components/schemas/RecordWithId
-type RecordWithId struct {
+// Ref: #/components/schemas/SharedRecord
+type SharedRecord struct {
 	ID   uuid.UUID  `json:"id"`
 	Type RecordType `json:"type"`
 	// Base64 encoded encrypted data.
@@ -325,112 +2807,170 @@ type RecordWithId struct {
 	Nonce []byte `json:"nonce"`
 	// Data version for synchronization.
 	Version int `json:"version"`
+	// Merged property.
+	Key []byte `json:"key"`
+	// Login of the owner.
+	Owner    string    `json:"owner"`
+	SharedAt time.Time `json:"shared_at"`
 }
 
 // GetID returns the value of ID.
-func (s *RecordWithId) GetID() uuid.UUID {
+func (s *SharedRecord) GetID() uuid.UUID {
 	return s.ID
 }
 
 // GetType returns the value of Type.
-func (s *RecordWithId) GetType() RecordType {
+func (s *SharedRecord) GetType() RecordType {
 	return s.Type
 }
 
 // GetData returns the value of Data.
-func (s *RecordWithId) GetData() []byte {
+func (s *SharedRecord) GetData() []byte {
 	return s.Data
 }
 
 // GetNonce returns the value of Nonce.
-func (s *RecordWithId) GetNonce() []byte {
+func (s *SharedRecord) GetNonce() []byte {
 	return s.Nonce
 }
 
 // GetVersion returns the value of Version.
-func (s *RecordWithId) GetVersion() int {
+func (s *SharedRecord) GetVersion() int {
 	return s.Version
 }
 
+// GetKey returns the value of Key.
+func (s *SharedRecord) GetKey() []byte {
+	return s.Key
+}
+
+// GetOwner returns the value of Owner.
+func (s *SharedRecord) GetOwner() string {
+	return s.Owner
+}
+
+// GetSharedAt returns the value of SharedAt.
+func (s *SharedRecord) GetSharedAt() time.Time {
+	return s.SharedAt
+}
+
 // SetID sets the value of ID.
-func (s *RecordWithId) SetID(val uuid.UUID) {
+func (s *SharedRecord) SetID(val uuid.UUID) {
 	s.ID = val
 }
 
 // SetType sets the value of Type.
-func (s *RecordWithId) SetType(val RecordType) {
+func (s *SharedRecord) SetType(val RecordType) {
 	s.Type = val
 }
 
 // SetData sets the value of Data.
-func (s *RecordWithId) SetData(val []byte) {
+func (s *SharedRecord) SetData(val []byte) {
 	s.Data = val
 }
 
 // SetNonce sets the value of Nonce.
-func (s *RecordWithId) SetNonce(val []byte) {
+func (s *SharedRecord) SetNonce(val []byte) {
 	s.Nonce = val
 }
 
 // SetVersion sets the value of Version.
-func (s *RecordWithId) SetVersion(val int) {
+func (s *SharedRecord) SetVersion(val int) {
 	s.Version = val
 }
 
-func (*RecordWithId) recordsIDGetRes() {}
-
-type RecordsGetOKApplicationJSON []RecordWithId
-
-func (*RecordsGetOKApplicationJSON) recordsGetRes() {}
+// SetKey sets the value of Key.
+func (s *SharedRecord) SetKey(val []byte) {
+	s.Key = val
+}
 
-// RecordsIDDeleteNoContent is response for RecordsIDDelete operation.
-type RecordsIDDeleteNoContent struct{}
+// SetOwner sets the value of Owner.
+func (s *SharedRecord) SetOwner(val string) {
+	s.Owner = val
+}
 
-func (*RecordsIDDeleteNoContent) recordsIDDeleteRes() {}
+// SetSharedAt sets the value of SharedAt.
+func (s *SharedRecord) SetSharedAt(val time.Time) {
+	s.SharedAt = val
+}
 
-// RecordsIDGetNotFound is response for RecordsIDGet operation.
-type RecordsIDGetNotFound struct{}
+// TokensCurrentGetForbidden is response for TokensCurrentGet operation.
+type TokensCurrentGetForbidden struct{}
 
-func (*RecordsIDGetNotFound) recordsIDGetRes() {}
+func (*TokensCurrentGetForbidden) tokensCurrentGetRes() {}
 
-// RecordsIDPutBadRequest is response for RecordsIDPut operation.
-type RecordsIDPutBadRequest struct{}
+type TokensGetOKApplicationJSON []AccessToken
 
-func (*RecordsIDPutBadRequest) recordsIDPutRes() {}
+func (*TokensGetOKApplicationJSON) tokensGetRes() {}
 
-// RecordsIDPutConflict is response for RecordsIDPut operation.
-type RecordsIDPutConflict struct{}
+// TokensIDDeleteNoContent is response for TokensIDDelete operation.
+type TokensIDDeleteNoContent struct{}
 
-func (*RecordsIDPutConflict) recordsIDPutRes() {}
+func (*TokensIDDeleteNoContent) tokensIDDeleteRes() {}
 
-// RecordsIDPutNoContent is response for RecordsIDPut operation.
-type RecordsIDPutNoContent struct{}
+// TokensIDDeleteNotFound is response for TokensIDDelete operation.
+type TokensIDDeleteNotFound struct{}
 
-func (*RecordsIDPutNoContent) recordsIDPutRes() {}
+func (*TokensIDDeleteNotFound) tokensIDDeleteRes() {}
 
-// RegisterPostBadRequest is response for RegisterPost operation.
-type RegisterPostBadRequest struct{}
+// TokensPostBadRequest is response for TokensPost operation.
+type TokensPostBadRequest struct{}
 
-func (*RegisterPostBadRequest) registerPostRes() {}
+func (*TokensPostBadRequest) tokensPostRes() {}
 
-// RegisterPostConflict is response for RegisterPost operation.
-type RegisterPostConflict struct{}
+// TokensPostUnprocessableEntity is response for TokensPost operation.
+type TokensPostUnprocessableEntity struct{}
 
-func (*RegisterPostConflict) registerPostRes() {}
+func (*TokensPostUnprocessableEntity) tokensPostRes() {}
 
 // Ref: #/components/responses/Unauthorized
 type Unauthorized struct{}
 
-func (*Unauthorized) loginPostRes()       {}
-func (*Unauthorized) recordsGetRes()      {}
-func (*Unauthorized) recordsIDDeleteRes() {}
-func (*Unauthorized) recordsIDGetRes()    {}
-func (*Unauthorized) recordsIDPutRes()    {}
+func (*Unauthorized) accountAuditGetRes()                {}
+func (*Unauthorized) accountDeleteRes()                  {}
+func (*Unauthorized) accountKeysGetRes()                 {}
+func (*Unauthorized) accountKeysPutRes()                 {}
+func (*Unauthorized) emergencyContactsGetRes()           {}
+func (*Unauthorized) emergencyContactsIDApprovePostRes() {}
+func (*Unauthorized) emergencyContactsIDDeleteRes()      {}
+func (*Unauthorized) emergencyContactsIDRejectPostRes()  {}
+func (*Unauthorized) emergencyContactsPostRes()          {}
+func (*Unauthorized) emergencyGrantsGetRes()             {}
+func (*Unauthorized) emergencyGrantsIDRequestPostRes()   {}
+func (*Unauthorized) emergencyGrantsIDVaultGetRes()      {}
+func (*Unauthorized) loginPostRes()                      {}
+func (*Unauthorized) orgsGetRes()                        {}
+func (*Unauthorized) orgsOrgIDDeleteRes()                {}
+func (*Unauthorized) orgsOrgIDMembersGetRes()            {}
+func (*Unauthorized) orgsOrgIDMembersPostRes()           {}
+func (*Unauthorized) orgsOrgIDMembersUserIDDeleteRes()   {}
+func (*Unauthorized) orgsOrgIDMembersUserIDPutRes()      {}
+func (*Unauthorized) orgsOrgIDRecordsGetRes()            {}
+func (*Unauthorized) orgsOrgIDRecordsIDDeleteRes()       {}
+func (*Unauthorized) orgsOrgIDRecordsIDPutRes()          {}
+func (*Unauthorized) orgsOrgIDRekeyPostRes()             {}
+func (*Unauthorized) orgsPostRes()                       {}
+func (*Unauthorized) recordsGetRes()                     {}
+func (*Unauthorized) recordsIDDeleteRes()                {}
+func (*Unauthorized) recordsIDGetRes()                   {}
+func (*Unauthorized) recordsIDPutRes()                   {}
+func (*Unauthorized) recordsIDSharesGetRes()             {}
+func (*Unauthorized) recordsIDSharesPostRes()            {}
+func (*Unauthorized) recordsIDSharesUserIDDeleteRes()    {}
+func (*Unauthorized) sendsPostRes()                      {}
+func (*Unauthorized) sharedGetRes()                      {}
+func (*Unauthorized) tokensCurrentGetRes()               {}
+func (*Unauthorized) tokensGetRes()                      {}
+func (*Unauthorized) tokensIDDeleteRes()                 {}
+func (*Unauthorized) tokensPostRes()                     {}
+func (*Unauthorized) usersLoginPublicKeyGetRes()         {}
 
 // Ref: #/components/schemas/UserCredentials
 type UserCredentials struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	// Required for registration when the server is invite-only.
+	InviteCode OptString `json:"invite_code"`
 }
 
 // GetLogin returns the value of Login.
@@ -443,6 +2983,11 @@ func (s *UserCredentials) GetPassword() string {
 	return s.Password
 }
 
+// GetInviteCode returns the value of InviteCode.
+func (s *UserCredentials) GetInviteCode() OptString {
+	return s.InviteCode
+}
+
 // SetLogin sets the value of Login.
 func (s *UserCredentials) SetLogin(val string) {
 	s.Login = val
@@ -453,6 +2998,46 @@ func (s *UserCredentials) SetPassword(val string) {
 	s.Password = val
 }
 
+// SetInviteCode sets the value of InviteCode.
+func (s *UserCredentials) SetInviteCode(val OptString) {
+	s.InviteCode = val
+}
+
+// Ref: #/components/schemas/UserKeys
+type UserKeys struct {
+	// Base64 encoded X25519 public key.
+	PublicKey []byte `json:"public_key"`
+	// Base64 encoded X25519 private key wrapped with the vault key.
+	WrappedPrivateKey []byte `json:"wrapped_private_key"`
+}
+
+// GetPublicKey returns the value of PublicKey.
+func (s *UserKeys) GetPublicKey() []byte {
+	return s.PublicKey
+}
+
+// GetWrappedPrivateKey returns the value of WrappedPrivateKey.
+func (s *UserKeys) GetWrappedPrivateKey() []byte {
+	return s.WrappedPrivateKey
+}
+
+// SetPublicKey sets the value of PublicKey.
+func (s *UserKeys) SetPublicKey(val []byte) {
+	s.PublicKey = val
+}
+
+// SetWrappedPrivateKey sets the value of WrappedPrivateKey.
+func (s *UserKeys) SetWrappedPrivateKey(val []byte) {
+	s.WrappedPrivateKey = val
+}
+
+func (*UserKeys) accountKeysGetRes() {}
+
+// UsersLoginPublicKeyGetNotFound is response for UsersLoginPublicKeyGet operation.
+type UsersLoginPublicKeyGetNotFound struct{}
+
+func (*UsersLoginPublicKeyGetNotFound) usersLoginPublicKeyGetRes() {}
+
 // Ref: #/components/schemas/VersionInfo
 type VersionInfo struct {
 	BuildVersion OptString `json:"build_version"`
@@ -15,6 +15,7 @@ import (
 // SecurityHandler is handler for security parameters.
 type SecurityHandler interface {
 	// HandleBearerAuth handles bearerAuth security.
+	// A session JWT or a personal access token (gkp_...).
 	HandleBearerAuth(ctx context.Context, operationName OperationName, t BearerAuth) (context.Context, error)
 }
 
@@ -34,10 +35,43 @@ func findAuthorization(h http.Header, prefix string) (string, bool) {
 }
 
 var operationRolesBearerAuth = map[string][]string{
-	RecordsGetOperation:      []string{},
-	RecordsIDDeleteOperation: []string{},
-	RecordsIDGetOperation:    []string{},
-	RecordsIDPutOperation:    []string{},
+	AccountAuditGetOperation:                []string{},
+	AccountDeleteOperation:                  []string{},
+	AccountKeysGetOperation:                 []string{},
+	AccountKeysPutOperation:                 []string{},
+	EmergencyContactsGetOperation:           []string{},
+	EmergencyContactsIDApprovePostOperation: []string{},
+	EmergencyContactsIDDeleteOperation:      []string{},
+	EmergencyContactsIDRejectPostOperation:  []string{},
+	EmergencyContactsPostOperation:          []string{},
+	EmergencyGrantsGetOperation:             []string{},
+	EmergencyGrantsIDRequestPostOperation:   []string{},
+	EmergencyGrantsIDVaultGetOperation:      []string{},
+	OrgsGetOperation:                        []string{},
+	OrgsOrgIDDeleteOperation:                []string{},
+	OrgsOrgIDMembersGetOperation:            []string{},
+	OrgsOrgIDMembersPostOperation:           []string{},
+	OrgsOrgIDMembersUserIDDeleteOperation:   []string{},
+	OrgsOrgIDMembersUserIDPutOperation:      []string{},
+	OrgsOrgIDRecordsGetOperation:            []string{},
+	OrgsOrgIDRecordsIDDeleteOperation:       []string{},
+	OrgsOrgIDRecordsIDPutOperation:          []string{},
+	OrgsOrgIDRekeyPostOperation:             []string{},
+	OrgsPostOperation:                       []string{},
+	RecordsGetOperation:                     []string{},
+	RecordsIDDeleteOperation:                []string{},
+	RecordsIDGetOperation:                   []string{},
+	RecordsIDPutOperation:                   []string{},
+	RecordsIDSharesGetOperation:             []string{},
+	RecordsIDSharesPostOperation:            []string{},
+	RecordsIDSharesUserIDDeleteOperation:    []string{},
+	SendsPostOperation:                      []string{},
+	SharedGetOperation:                      []string{},
+	TokensCurrentGetOperation:               []string{},
+	TokensGetOperation:                      []string{},
+	TokensIDDeleteOperation:                 []string{},
+	TokensPostOperation:                     []string{},
+	UsersLoginPublicKeyGetOperation:         []string{},
 }
 
 func (s *Server) securityBearerAuth(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
@@ -60,6 +94,7 @@ func (s *Server) securityBearerAuth(ctx context.Context, operationName Operation
 // SecuritySource is provider of security values (tokens, passwords, etc.).
 type SecuritySource interface {
 	// BearerAuth provides bearerAuth security value.
+	// A session JWT or a personal access token (gkp_...).
 	BearerAuth(ctx context.Context, operationName OperationName) (BearerAuth, error)
 }
 
@@ -8,12 +8,151 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// AccountAuditGet implements GET /account/audit operation.
+	//
+	// Logins, failed logins, registration, record deletes and issued tokens, newest first.
+	//
+	// GET /account/audit
+	AccountAuditGet(ctx context.Context, params AccountAuditGetParams) (AccountAuditGetRes, error)
+	// AccountDelete implements DELETE /account operation.
+	//
+	// Requires the password of the account to be entered again.
+	//
+	// DELETE /account
+	AccountDelete(ctx context.Context, req *PasswordConfirmation) (AccountDeleteRes, error)
+	// AccountKeysGet implements GET /account/keys operation.
+	//
+	// Get the key pair records are shared with.
+	//
+	// GET /account/keys
+	AccountKeysGet(ctx context.Context) (AccountKeysGetRes, error)
+	// AccountKeysPut implements PUT /account/keys operation.
+	//
+	// The key pair can only be set once, records shared with the user are wrapped for it.
+	//
+	// PUT /account/keys
+	AccountKeysPut(ctx context.Context, req *UserKeys) (AccountKeysPutRes, error)
+	// EmergencyContactsGet implements GET /emergency/contacts operation.
+	//
+	// List the trusted contacts of the user.
+	//
+	// GET /emergency/contacts
+	EmergencyContactsGet(ctx context.Context) (EmergencyContactsGetRes, error)
+	// EmergencyContactsIDApprovePost implements POST /emergency/contacts/{id}/approve operation.
+	//
+	// Approve a pending request before the waiting period is over.
+	//
+	// POST /emergency/contacts/{id}/approve
+	EmergencyContactsIDApprovePost(ctx context.Context, params EmergencyContactsIDApprovePostParams) (EmergencyContactsIDApprovePostRes, error)
+	// EmergencyContactsIDDelete implements DELETE /emergency/contacts/{id} operation.
+	//
+	// Remove a trusted contact.
+	//
+	// DELETE /emergency/contacts/{id}
+	EmergencyContactsIDDelete(ctx context.Context, params EmergencyContactsIDDeleteParams) (EmergencyContactsIDDeleteRes, error)
+	// EmergencyContactsIDRejectPost implements POST /emergency/contacts/{id}/reject operation.
+	//
+	// Reject a request or withdraw an approved access.
+	//
+	// POST /emergency/contacts/{id}/reject
+	EmergencyContactsIDRejectPost(ctx context.Context, params EmergencyContactsIDRejectPostParams) (EmergencyContactsIDRejectPostRes, error)
+	// EmergencyContactsPost implements POST /emergency/contacts operation.
+	//
+	// Designate a trusted contact for emergency access.
+	//
+	// POST /emergency/contacts
+	EmergencyContactsPost(ctx context.Context, req *EmergencyContactRequest) (EmergencyContactsPostRes, error)
+	// EmergencyGrantsGet implements GET /emergency/grants operation.
+	//
+	// List the users who made the user their trusted contact.
+	//
+	// GET /emergency/grants
+	EmergencyGrantsGet(ctx context.Context) (EmergencyGrantsGetRes, error)
+	// EmergencyGrantsIDRequestPost implements POST /emergency/grants/{id}/request operation.
+	//
+	// Request emergency access, starting the waiting period.
+	//
+	// POST /emergency/grants/{id}/request
+	EmergencyGrantsIDRequestPost(ctx context.Context, params EmergencyGrantsIDRequestPostParams) (EmergencyGrantsIDRequestPostRes, error)
+	// EmergencyGrantsIDVaultGet implements GET /emergency/grants/{id}/vault operation.
+	//
+	// Get the vault of the grantor once access is approved.
+	//
+	// GET /emergency/grants/{id}/vault
+	EmergencyGrantsIDVaultGet(ctx context.Context, params EmergencyGrantsIDVaultGetParams) (EmergencyGrantsIDVaultGetRes, error)
 	// LoginPost implements POST /login operation.
 	//
 	// Authenticate user.
 	//
 	// POST /login
 	LoginPost(ctx context.Context, req *UserCredentials) (LoginPostRes, error)
+	// OrgsGet implements GET /orgs operation.
+	//
+	// List the organizations of the user.
+	//
+	// GET /orgs
+	OrgsGet(ctx context.Context) (OrgsGetRes, error)
+	// OrgsOrgIDDelete implements DELETE /orgs/{org_id} operation.
+	//
+	// Delete an organization with its records.
+	//
+	// DELETE /orgs/{org_id}
+	OrgsOrgIDDelete(ctx context.Context, params OrgsOrgIDDeleteParams) (OrgsOrgIDDeleteRes, error)
+	// OrgsOrgIDMembersGet implements GET /orgs/{org_id}/members operation.
+	//
+	// List the members of an organization.
+	//
+	// GET /orgs/{org_id}/members
+	OrgsOrgIDMembersGet(ctx context.Context, params OrgsOrgIDMembersGetParams) (OrgsOrgIDMembersGetRes, error)
+	// OrgsOrgIDMembersPost implements POST /orgs/{org_id}/members operation.
+	//
+	// Invite a user to an organization.
+	//
+	// POST /orgs/{org_id}/members
+	OrgsOrgIDMembersPost(ctx context.Context, req *OrgInvite, params OrgsOrgIDMembersPostParams) (OrgsOrgIDMembersPostRes, error)
+	// OrgsOrgIDMembersUserIDDelete implements DELETE /orgs/{org_id}/members/{user_id} operation.
+	//
+	// The organization needs re-keying afterwards, see rekey_needed.
+	//
+	// DELETE /orgs/{org_id}/members/{user_id}
+	OrgsOrgIDMembersUserIDDelete(ctx context.Context, params OrgsOrgIDMembersUserIDDeleteParams) (OrgsOrgIDMembersUserIDDeleteRes, error)
+	// OrgsOrgIDMembersUserIDPut implements PUT /orgs/{org_id}/members/{user_id} operation.
+	//
+	// Change the role of a member.
+	//
+	// PUT /orgs/{org_id}/members/{user_id}
+	OrgsOrgIDMembersUserIDPut(ctx context.Context, req *OrgRoleRequest, params OrgsOrgIDMembersUserIDPutParams) (OrgsOrgIDMembersUserIDPutRes, error)
+	// OrgsOrgIDRecordsGet implements GET /orgs/{org_id}/records operation.
+	//
+	// Get the records of an organization.
+	//
+	// GET /orgs/{org_id}/records
+	OrgsOrgIDRecordsGet(ctx context.Context, params OrgsOrgIDRecordsGetParams) (OrgsOrgIDRecordsGetRes, error)
+	// OrgsOrgIDRecordsIDDelete implements DELETE /orgs/{org_id}/records/{id} operation.
+	//
+	// Delete an organization record.
+	//
+	// DELETE /orgs/{org_id}/records/{id}
+	OrgsOrgIDRecordsIDDelete(ctx context.Context, params OrgsOrgIDRecordsIDDeleteParams) (OrgsOrgIDRecordsIDDeleteRes, error)
+	// OrgsOrgIDRecordsIDPut implements PUT /orgs/{org_id}/records/{id} operation.
+	//
+	// Create or update an organization record.
+	//
+	// PUT /orgs/{org_id}/records/{id}
+	OrgsOrgIDRecordsIDPut(ctx context.Context, req *OrgRecord, params OrgsOrgIDRecordsIDPutParams) (OrgsOrgIDRecordsIDPutRes, error)
+	// OrgsOrgIDRekeyPost implements POST /orgs/{org_id}/rekey operation.
+	//
+	// Atomically replaces the wrapped key of every member and every record re-encrypted with the new key.
+	//  Both must be complete.
+	//
+	// POST /orgs/{org_id}/rekey
+	OrgsOrgIDRekeyPost(ctx context.Context, req *OrgRekey, params OrgsOrgIDRekeyPostParams) (OrgsOrgIDRekeyPostRes, error)
+	// OrgsPost implements POST /orgs operation.
+	//
+	// Create an organization, the user becomes its owner.
+	//
+	// POST /orgs
+	OrgsPost(ctx context.Context, req *OrganizationRequest) (OrgsPostRes, error)
 	// RecordsGet implements GET /records operation.
 	//
 	// Get all user records.
@@ -38,18 +177,99 @@ type Handler interface {
 	//
 	// PUT /records/{id}
 	RecordsIDPut(ctx context.Context, req *Record, params RecordsIDPutParams) (RecordsIDPutRes, error)
+	// RecordsIDSharesGet implements GET /records/{id}/shares operation.
+	//
+	// List the users a record is shared with.
+	//
+	// GET /records/{id}/shares
+	RecordsIDSharesGet(ctx context.Context, params RecordsIDSharesGetParams) (RecordsIDSharesGetRes, error)
+	// RecordsIDSharesPost implements POST /records/{id}/shares operation.
+	//
+	// Sharing again with the same user replaces the wrapped key.
+	//
+	// POST /records/{id}/shares
+	RecordsIDSharesPost(ctx context.Context, req *ShareRequest, params RecordsIDSharesPostParams) (RecordsIDSharesPostRes, error)
+	// RecordsIDSharesUserIDDelete implements DELETE /records/{id}/shares/{user_id} operation.
+	//
+	// Revoke a share.
+	//
+	// DELETE /records/{id}/shares/{user_id}
+	RecordsIDSharesUserIDDelete(ctx context.Context, params RecordsIDSharesUserIDDeleteParams) (RecordsIDSharesUserIDDeleteRes, error)
 	// RegisterPost implements POST /register operation.
 	//
 	// Register new user.
 	//
 	// POST /register
 	RegisterPost(ctx context.Context, req *UserCredentials) (RegisterPostRes, error)
+	// RegistrationGet implements GET /registration operation.
+	//
+	// Get registration policy.
+	//
+	// GET /registration
+	RegistrationGet(ctx context.Context) (*RegistrationInfo, error)
+	// SendsIDGet implements GET /sends/{id} operation.
+	//
+	// Counts a view, the send is deleted after its last view. No account is needed.
+	//
+	// GET /sends/{id}
+	SendsIDGet(ctx context.Context, params SendsIDGetParams) (SendsIDGetRes, error)
+	// SendsPost implements POST /sends operation.
+	//
+	// The data is encrypted by the client with a key that never reaches the server, it is kept in the
+	// fragment of the link.
+	//
+	// POST /sends
+	SendsPost(ctx context.Context, req *SendRequest) (SendsPostRes, error)
+	// SharedGet implements GET /shared operation.
+	//
+	// Get the records other users shared with the user.
+	//
+	// GET /shared
+	SharedGet(ctx context.Context) (SharedGetRes, error)
+	// TokensCurrentGet implements GET /tokens/current operation.
+	//
+	// Get the scope and wrapped keys of the access token in use.
+	//
+	// GET /tokens/current
+	TokensCurrentGet(ctx context.Context) (TokensCurrentGetRes, error)
+	// TokensGet implements GET /tokens operation.
+	//
+	// List personal access tokens.
+	//
+	// GET /tokens
+	TokensGet(ctx context.Context) (TokensGetRes, error)
+	// TokensIDDelete implements DELETE /tokens/{id} operation.
+	//
+	// Revoke a personal access token.
+	//
+	// DELETE /tokens/{id}
+	TokensIDDelete(ctx context.Context, params TokensIDDeleteParams) (TokensIDDeleteRes, error)
+	// TokensPost implements POST /tokens operation.
+	//
+	// The token can only be created with a session token. The keys are
+	// wrapped by the client with the key part of the token, which is never
+	// sent to the server.
+	//
+	// POST /tokens
+	TokensPost(ctx context.Context, req *AccessTokenRequest) (TokensPostRes, error)
+	// UsersLoginPublicKeyGet implements GET /users/{login}/public-key operation.
+	//
+	// Get the public key of a user to share records with.
+	//
+	// GET /users/{login}/public-key
+	UsersLoginPublicKeyGet(ctx context.Context, params UsersLoginPublicKeyGetParams) (UsersLoginPublicKeyGetRes, error)
 	// VersionGet implements GET /version operation.
 	//
 	// Get server version.
 	//
 	// GET /version
 	VersionGet(ctx context.Context) (*VersionInfo, error)
+	// WellKnownJwksJSONGet implements GET /.well-known/jwks.json operation.
+	//
+	// Empty when the server signs tokens with a shared secret.
+	//
+	// GET /.well-known/jwks.json
+	WellKnownJwksJSONGet(ctx context.Context) (*JWKSet, error)
 }
 
 // Server implements http server based on OpenAPI v3 specification and
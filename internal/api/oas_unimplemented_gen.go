@@ -13,6 +13,114 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// AccountAuditGet implements GET /account/audit operation.
+//
+// Logins, failed logins, registration, record deletes and issued tokens, newest first.
+//
+// GET /account/audit
+func (UnimplementedHandler) AccountAuditGet(ctx context.Context, params AccountAuditGetParams) (r AccountAuditGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// AccountDelete implements DELETE /account operation.
+//
+// Requires the password of the account to be entered again.
+//
+// DELETE /account
+func (UnimplementedHandler) AccountDelete(ctx context.Context, req *PasswordConfirmation) (r AccountDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// AccountKeysGet implements GET /account/keys operation.
+//
+// Get the key pair records are shared with.
+//
+// GET /account/keys
+func (UnimplementedHandler) AccountKeysGet(ctx context.Context) (r AccountKeysGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// AccountKeysPut implements PUT /account/keys operation.
+//
+// The key pair can only be set once, records shared with the user are wrapped for it.
+//
+// PUT /account/keys
+func (UnimplementedHandler) AccountKeysPut(ctx context.Context, req *UserKeys) (r AccountKeysPutRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyContactsGet implements GET /emergency/contacts operation.
+//
+// List the trusted contacts of the user.
+//
+// GET /emergency/contacts
+func (UnimplementedHandler) EmergencyContactsGet(ctx context.Context) (r EmergencyContactsGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyContactsIDApprovePost implements POST /emergency/contacts/{id}/approve operation.
+//
+// Approve a pending request before the waiting period is over.
+//
+// POST /emergency/contacts/{id}/approve
+func (UnimplementedHandler) EmergencyContactsIDApprovePost(ctx context.Context, params EmergencyContactsIDApprovePostParams) (r EmergencyContactsIDApprovePostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyContactsIDDelete implements DELETE /emergency/contacts/{id} operation.
+//
+// Remove a trusted contact.
+//
+// DELETE /emergency/contacts/{id}
+func (UnimplementedHandler) EmergencyContactsIDDelete(ctx context.Context, params EmergencyContactsIDDeleteParams) (r EmergencyContactsIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyContactsIDRejectPost implements POST /emergency/contacts/{id}/reject operation.
+//
+// Reject a request or withdraw an approved access.
+//
+// POST /emergency/contacts/{id}/reject
+func (UnimplementedHandler) EmergencyContactsIDRejectPost(ctx context.Context, params EmergencyContactsIDRejectPostParams) (r EmergencyContactsIDRejectPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyContactsPost implements POST /emergency/contacts operation.
+//
+// Designate a trusted contact for emergency access.
+//
+// POST /emergency/contacts
+func (UnimplementedHandler) EmergencyContactsPost(ctx context.Context, req *EmergencyContactRequest) (r EmergencyContactsPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyGrantsGet implements GET /emergency/grants operation.
+//
+// List the users who made the user their trusted contact.
+//
+// GET /emergency/grants
+func (UnimplementedHandler) EmergencyGrantsGet(ctx context.Context) (r EmergencyGrantsGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyGrantsIDRequestPost implements POST /emergency/grants/{id}/request operation.
+//
+// Request emergency access, starting the waiting period.
+//
+// POST /emergency/grants/{id}/request
+func (UnimplementedHandler) EmergencyGrantsIDRequestPost(ctx context.Context, params EmergencyGrantsIDRequestPostParams) (r EmergencyGrantsIDRequestPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// EmergencyGrantsIDVaultGet implements GET /emergency/grants/{id}/vault operation.
+//
+// Get the vault of the grantor once access is approved.
+//
+// GET /emergency/grants/{id}/vault
+func (UnimplementedHandler) EmergencyGrantsIDVaultGet(ctx context.Context, params EmergencyGrantsIDVaultGetParams) (r EmergencyGrantsIDVaultGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // LoginPost implements POST /login operation.
 //
 // Authenticate user.
@@ -22,6 +130,107 @@ func (UnimplementedHandler) LoginPost(ctx context.Context, req *UserCredentials)
 	return r, ht.ErrNotImplemented
 }
 
+// OrgsGet implements GET /orgs operation.
+//
+// List the organizations of the user.
+//
+// GET /orgs
+func (UnimplementedHandler) OrgsGet(ctx context.Context) (r OrgsGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDDelete implements DELETE /orgs/{org_id} operation.
+//
+// Delete an organization with its records.
+//
+// DELETE /orgs/{org_id}
+func (UnimplementedHandler) OrgsOrgIDDelete(ctx context.Context, params OrgsOrgIDDeleteParams) (r OrgsOrgIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDMembersGet implements GET /orgs/{org_id}/members operation.
+//
+// List the members of an organization.
+//
+// GET /orgs/{org_id}/members
+func (UnimplementedHandler) OrgsOrgIDMembersGet(ctx context.Context, params OrgsOrgIDMembersGetParams) (r OrgsOrgIDMembersGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDMembersPost implements POST /orgs/{org_id}/members operation.
+//
+// Invite a user to an organization.
+//
+// POST /orgs/{org_id}/members
+func (UnimplementedHandler) OrgsOrgIDMembersPost(ctx context.Context, req *OrgInvite, params OrgsOrgIDMembersPostParams) (r OrgsOrgIDMembersPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDMembersUserIDDelete implements DELETE /orgs/{org_id}/members/{user_id} operation.
+//
+// The organization needs re-keying afterwards, see rekey_needed.
+//
+// DELETE /orgs/{org_id}/members/{user_id}
+func (UnimplementedHandler) OrgsOrgIDMembersUserIDDelete(ctx context.Context, params OrgsOrgIDMembersUserIDDeleteParams) (r OrgsOrgIDMembersUserIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDMembersUserIDPut implements PUT /orgs/{org_id}/members/{user_id} operation.
+//
+// Change the role of a member.
+//
+// PUT /orgs/{org_id}/members/{user_id}
+func (UnimplementedHandler) OrgsOrgIDMembersUserIDPut(ctx context.Context, req *OrgRoleRequest, params OrgsOrgIDMembersUserIDPutParams) (r OrgsOrgIDMembersUserIDPutRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDRecordsGet implements GET /orgs/{org_id}/records operation.
+//
+// Get the records of an organization.
+//
+// GET /orgs/{org_id}/records
+func (UnimplementedHandler) OrgsOrgIDRecordsGet(ctx context.Context, params OrgsOrgIDRecordsGetParams) (r OrgsOrgIDRecordsGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDRecordsIDDelete implements DELETE /orgs/{org_id}/records/{id} operation.
+//
+// Delete an organization record.
+//
+// DELETE /orgs/{org_id}/records/{id}
+func (UnimplementedHandler) OrgsOrgIDRecordsIDDelete(ctx context.Context, params OrgsOrgIDRecordsIDDeleteParams) (r OrgsOrgIDRecordsIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDRecordsIDPut implements PUT /orgs/{org_id}/records/{id} operation.
+//
+// Create or update an organization record.
+//
+// PUT /orgs/{org_id}/records/{id}
+func (UnimplementedHandler) OrgsOrgIDRecordsIDPut(ctx context.Context, req *OrgRecord, params OrgsOrgIDRecordsIDPutParams) (r OrgsOrgIDRecordsIDPutRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsOrgIDRekeyPost implements POST /orgs/{org_id}/rekey operation.
+//
+// Atomically replaces the wrapped key of every member and every record re-encrypted with the new key.
+//
+//	Both must be complete.
+//
+// POST /orgs/{org_id}/rekey
+func (UnimplementedHandler) OrgsOrgIDRekeyPost(ctx context.Context, req *OrgRekey, params OrgsOrgIDRekeyPostParams) (r OrgsOrgIDRekeyPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// OrgsPost implements POST /orgs operation.
+//
+// Create an organization, the user becomes its owner.
+//
+// POST /orgs
+func (UnimplementedHandler) OrgsPost(ctx context.Context, req *OrganizationRequest) (r OrgsPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // RecordsGet implements GET /records operation.
 //
 // Get all user records.
@@ -58,6 +267,33 @@ func (UnimplementedHandler) RecordsIDPut(ctx context.Context, req *Record, param
 	return r, ht.ErrNotImplemented
 }
 
+// RecordsIDSharesGet implements GET /records/{id}/shares operation.
+//
+// List the users a record is shared with.
+//
+// GET /records/{id}/shares
+func (UnimplementedHandler) RecordsIDSharesGet(ctx context.Context, params RecordsIDSharesGetParams) (r RecordsIDSharesGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// RecordsIDSharesPost implements POST /records/{id}/shares operation.
+//
+// Sharing again with the same user replaces the wrapped key.
+//
+// POST /records/{id}/shares
+func (UnimplementedHandler) RecordsIDSharesPost(ctx context.Context, req *ShareRequest, params RecordsIDSharesPostParams) (r RecordsIDSharesPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// RecordsIDSharesUserIDDelete implements DELETE /records/{id}/shares/{user_id} operation.
+//
+// Revoke a share.
+//
+// DELETE /records/{id}/shares/{user_id}
+func (UnimplementedHandler) RecordsIDSharesUserIDDelete(ctx context.Context, params RecordsIDSharesUserIDDeleteParams) (r RecordsIDSharesUserIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // RegisterPost implements POST /register operation.
 //
 // Register new user.
@@ -67,6 +303,90 @@ func (UnimplementedHandler) RegisterPost(ctx context.Context, req *UserCredentia
 	return r, ht.ErrNotImplemented
 }
 
+// RegistrationGet implements GET /registration operation.
+//
+// Get registration policy.
+//
+// GET /registration
+func (UnimplementedHandler) RegistrationGet(ctx context.Context) (r *RegistrationInfo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SendsIDGet implements GET /sends/{id} operation.
+//
+// Counts a view, the send is deleted after its last view. No account is needed.
+//
+// GET /sends/{id}
+func (UnimplementedHandler) SendsIDGet(ctx context.Context, params SendsIDGetParams) (r SendsIDGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SendsPost implements POST /sends operation.
+//
+// The data is encrypted by the client with a key that never reaches the server, it is kept in the
+// fragment of the link.
+//
+// POST /sends
+func (UnimplementedHandler) SendsPost(ctx context.Context, req *SendRequest) (r SendsPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SharedGet implements GET /shared operation.
+//
+// Get the records other users shared with the user.
+//
+// GET /shared
+func (UnimplementedHandler) SharedGet(ctx context.Context) (r SharedGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// TokensCurrentGet implements GET /tokens/current operation.
+//
+// Get the scope and wrapped keys of the access token in use.
+//
+// GET /tokens/current
+func (UnimplementedHandler) TokensCurrentGet(ctx context.Context) (r TokensCurrentGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// TokensGet implements GET /tokens operation.
+//
+// List personal access tokens.
+//
+// GET /tokens
+func (UnimplementedHandler) TokensGet(ctx context.Context) (r TokensGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// TokensIDDelete implements DELETE /tokens/{id} operation.
+//
+// Revoke a personal access token.
+//
+// DELETE /tokens/{id}
+func (UnimplementedHandler) TokensIDDelete(ctx context.Context, params TokensIDDeleteParams) (r TokensIDDeleteRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// TokensPost implements POST /tokens operation.
+//
+// The token can only be created with a session token. The keys are
+// wrapped by the client with the key part of the token, which is never
+// sent to the server.
+//
+// POST /tokens
+func (UnimplementedHandler) TokensPost(ctx context.Context, req *AccessTokenRequest) (r TokensPostRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// UsersLoginPublicKeyGet implements GET /users/{login}/public-key operation.
+//
+// Get the public key of a user to share records with.
+//
+// GET /users/{login}/public-key
+func (UnimplementedHandler) UsersLoginPublicKeyGet(ctx context.Context, params UsersLoginPublicKeyGetParams) (r UsersLoginPublicKeyGetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // VersionGet implements GET /version operation.
 //
 // Get server version.
@@ -75,3 +395,12 @@ func (UnimplementedHandler) RegisterPost(ctx context.Context, req *UserCredentia
 func (UnimplementedHandler) VersionGet(ctx context.Context) (r *VersionInfo, _ error) {
 	return r, ht.ErrNotImplemented
 }
+
+// WellKnownJwksJSONGet implements GET /.well-known/jwks.json operation.
+//
+// Empty when the server signs tokens with a shared secret.
+//
+// GET /.well-known/jwks.json
+func (UnimplementedHandler) WellKnownJwksJSONGet(ctx context.Context) (r *JWKSet, _ error) {
+	return r, ht.ErrNotImplemented
+}
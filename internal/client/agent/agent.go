@@ -0,0 +1,456 @@
+// Package agent implements a local daemon holding the unlocked vault. It
+// serves the records of the local cache on a Unix socket, so that CLI and
+// TUI instances do not have to derive the vault key themselves.
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const (
+	// DefaultIdleTimeout locks the agent when no client used it for that long.
+	DefaultIdleTimeout = 15 * time.Minute
+	// syncInterval is the interval of the background sync, the same as in the TUI.
+	syncInterval = 10 * time.Second
+	socketName   = "agent.sock"
+	tokenName    = "agent.token"
+	// sessionKeySize is the size of the token and of the keys sessions are
+	// wrapped with.
+	sessionKeySize = 32
+)
+
+// Error codes of the responses of the agent.
+const (
+	codeLocked       = "locked"
+	codeNotFound     = "not_found"
+	codeUnauthorized = "unauthorized"
+	codeDisabled     = "account_disabled"
+	codeBadRequest   = "bad_request"
+	codeInvalidToken = "invalid_token"
+	codeError        = "error"
+)
+
+// Dir returns the directory of the socket and the token of the agent. Only
+// the user may enter it.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "GophKeeper", "agent"), nil
+}
+
+// Agent holds the service of the unlocked vault. newService returns a new
+// locked service on every unlock, so nothing of a previous one is kept.
+type Agent struct {
+	newService  func() interfaces.Service
+	idleTimeout time.Duration
+	token       string
+	logger      *slog.Logger
+
+	// syncMu serializes syncs with locking, so that the cache is not
+	// closed during a sync.
+	syncMu   sync.Mutex
+	mu       sync.Mutex
+	svc      interfaces.Service
+	login    string
+	lastUsed time.Time
+	syncErr  error
+}
+
+func New(newService func() interfaces.Service, idleTimeout time.Duration, logger *slog.Logger) *Agent {
+	return &Agent{newService: newService, idleTimeout: idleTimeout, logger: logger}
+}
+
+// ListenAndServe serves on the socket in Dir until ctx is done. The socket
+// and the token authenticating the clients are removed afterwards.
+func (a *Agent) ListenAndServe(ctx context.Context) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err = os.Chmod(dir, 0700); err != nil {
+		return err
+	}
+	socketPath := filepath.Join(dir, socketName)
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return errors.New("an agent is already running")
+	}
+	_ = os.Remove(socketPath)
+
+	token := make([]byte, sessionKeySize)
+	if _, err = rand.Read(token); err != nil {
+		return err
+	}
+	a.token = hex.EncodeToString(token)
+	tokenPath := filepath.Join(dir, tokenName)
+	if err = os.WriteFile(tokenPath, []byte(a.token), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(tokenPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socketPath)
+	if err = os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	server := &http.Server{Handler: a.routes(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+	go a.loop(ctx)
+	defer a.lock()
+
+	a.logger.Info("agent started", slog.String("socket", socketPath), slog.Duration("idle_timeout", a.idleTimeout))
+	if err = server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loop syncs the unlocked vault in the background and locks it once it was
+// idle for too long.
+func (a *Agent) loop(ctx context.Context) {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		a.mu.Lock()
+		idle := a.svc != nil && a.idleTimeout > 0 && time.Since(a.lastUsed) > a.idleTimeout
+		a.mu.Unlock()
+		if idle {
+			a.logger.Info("agent locked after being idle", slog.Duration("idle_timeout", a.idleTimeout))
+			a.lock()
+			continue
+		}
+		if _, err := a.sync(ctx); err != nil && !errors.Is(err, errLocked) {
+			a.logger.Warn("background sync failed", slog.Any("error", err))
+		}
+	}
+}
+
+var errLocked = errors.New("the agent is locked")
+
+// service returns the service of the unlocked vault and marks the agent as used.
+func (a *Agent) service() (interfaces.Service, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.svc == nil {
+		return nil, errLocked
+	}
+	a.lastUsed = time.Now()
+	return a.svc, nil
+}
+
+func (a *Agent) sync(ctx context.Context) (bool, error) {
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+	a.mu.Lock()
+	svc := a.svc
+	a.mu.Unlock()
+	if svc == nil {
+		return false, errLocked
+	}
+	hasConflicts, err := svc.Sync(ctx)
+	a.mu.Lock()
+	a.syncErr = err
+	a.mu.Unlock()
+	return hasConflicts, err
+}
+
+// unlock logs in with a new service. A vault unlocked before is locked
+// first, also when it is the same login, which renews the session.
+func (a *Agent) unlock(ctx context.Context, login, password string) error {
+	a.lock()
+	svc := a.newService()
+	if _, err := svc.Login(ctx, login, password); err != nil {
+		_ = svc.Close()
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.svc, a.login, a.lastUsed, a.syncErr = svc, login, time.Now(), nil
+	return nil
+}
+
+func (a *Agent) lock() {
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.svc == nil {
+		return
+	}
+	if err := a.svc.Close(); err != nil {
+		a.logger.Warn("failed to close the cache", slog.Any("error", err))
+	}
+	a.svc, a.login, a.syncErr = nil, "", nil
+}
+
+func (a *Agent) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", a.handleStatus)
+	mux.HandleFunc("POST /unlock", a.handleUnlock)
+	mux.HandleFunc("POST /lock", a.handleLock)
+	mux.HandleFunc("GET /session", a.handleSession)
+	mux.HandleFunc("POST /sync", a.handleSync)
+	mux.HandleFunc("GET /records", a.handleGetRecords)
+	mux.HandleFunc("GET /records/{id}", a.handleGetRecord)
+	mux.HandleFunc("PUT /records/{id}", a.handleSaveRecord)
+	mux.HandleFunc("DELETE /records/{id}", a.handleDeleteRecord)
+	mux.HandleFunc("DELETE /cache", a.handleDestroy)
+	return a.authenticate(mux)
+}
+
+// authenticate checks the token the agent wrote next to its socket.
+func (a *Agent) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, codeInvalidToken, "invalid agent token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Status is the state of the agent.
+type Status struct {
+	Locked      bool          `json:"locked"`
+	Login       string        `json:"login,omitempty"`
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	SyncError   string        `json:"sync_error,omitempty"`
+}
+
+func (a *Agent) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	a.mu.Lock()
+	status := Status{Locked: a.svc == nil, Login: a.login, IdleTimeout: a.idleTimeout}
+	if a.syncErr != nil {
+		status.SyncError = a.syncErr.Error()
+	}
+	a.mu.Unlock()
+	writeJSON(w, http.StatusOK, status)
+}
+
+type unlockRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+func (a *Agent) handleUnlock(w http.ResponseWriter, r *http.Request) {
+	var req unlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Login == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "login and password are required")
+		return
+	}
+	if err := a.unlock(r.Context(), req.Login, req.Password); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	a.logger.Info("agent unlocked", slog.String("login", req.Login))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) handleLock(w http.ResponseWriter, _ *http.Request) {
+	a.lock()
+	a.logger.Info("agent locked")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionResponse carries a session of the unlocked vault with the key its
+// vault key is wrapped with.
+type sessionResponse struct {
+	Session *models.Session `json:"session"`
+	Key     string          `json:"key"`
+}
+
+func (a *Agent) handleSession(w http.ResponseWriter, _ *http.Request) {
+	svc, err := a.service()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	key := make([]byte, sessionKeySize)
+	if _, err = rand.Read(key); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	session, err := svc.Session(key)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessionResponse{Session: session, Key: base64.RawURLEncoding.EncodeToString(key)})
+}
+
+type syncResponse struct {
+	HasConflicts bool `json:"has_conflicts"`
+}
+
+func (a *Agent) handleSync(w http.ResponseWriter, r *http.Request) {
+	if _, err := a.service(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	hasConflicts, err := a.sync(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, syncResponse{HasConflicts: hasConflicts})
+}
+
+func (a *Agent) handleGetRecords(w http.ResponseWriter, _ *http.Request) {
+	svc, err := a.service()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	records, err := svc.GetRecords()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (a *Agent) handleGetRecord(w http.ResponseWriter, r *http.Request) {
+	svc, id, ok := a.recordRequest(w, r)
+	if !ok {
+		return
+	}
+	record, err := svc.GetRecord(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (a *Agent) handleSaveRecord(w http.ResponseWriter, r *http.Request) {
+	svc, id, ok := a.recordRequest(w, r)
+	if !ok {
+		return
+	}
+	var record models.Record
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil || record.ID != id {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid record")
+		return
+	}
+	if err := svc.SaveRecord(&record); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) handleDeleteRecord(w http.ResponseWriter, r *http.Request) {
+	svc, id, ok := a.recordRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := svc.DeleteRecord(id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDestroy destroys the cache of a deleted account and locks the agent.
+func (a *Agent) handleDestroy(w http.ResponseWriter, _ *http.Request) {
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.svc == nil {
+		writeServiceError(w, errLocked)
+		return
+	}
+	err := a.svc.Destroy()
+	a.svc, a.login, a.syncErr = nil, "", nil
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) recordRequest(w http.ResponseWriter, r *http.Request) (interfaces.Service, uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid record ID")
+		return nil, uuid.Nil, false
+	}
+	svc, err := a.service()
+	if err != nil {
+		writeServiceError(w, err)
+		return nil, uuid.Nil, false
+	}
+	return svc, id, true
+}
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errLocked):
+		writeError(w, http.StatusLocked, codeLocked, err.Error())
+	case errors.Is(err, interfaces.ErrNotFound):
+		writeError(w, http.StatusNotFound, codeNotFound, err.Error())
+	case errors.Is(err, interfaces.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, codeUnauthorized, err.Error())
+	case errors.Is(err, interfaces.ErrAccountDisabled):
+		writeError(w, http.StatusForbidden, codeDisabled, err.Error())
+	case errors.Is(err, interfaces.ErrBadRequest):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, codeError, err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Code: code, Message: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+var (
+	ErrNotRunning = errors.New("the agent is not running")
+	ErrLocked     = errors.New("the agent is locked, log in again")
+)
+
+// Client talks to the agent of the user. It is usable when no agent runs,
+// the methods then return ErrNotRunning.
+type Client struct {
+	http *http.Client
+	dir  string
+
+	mu       sync.Mutex
+	attached bool
+}
+
+func NewClient() *Client {
+	c := &Client{}
+	c.dir, _ = Dir()
+	c.http = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", filepath.Join(c.dir, socketName))
+		},
+	}}
+	return c
+}
+
+// Running reports whether an agent answers on the socket.
+func (c *Client) Running(ctx context.Context) bool {
+	_, err := c.Status(ctx)
+	return err == nil
+}
+
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := c.do(ctx, http.MethodGet, "/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Unlock makes the agent log in, deriving the vault key in the agent.
+func (c *Client) Unlock(ctx context.Context, login, password string) error {
+	return c.do(ctx, http.MethodPost, "/unlock", unlockRequest{Login: login, Password: password}, nil)
+}
+
+func (c *Client) Lock(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/lock", nil, nil)
+}
+
+// Attach resumes the session of the unlocked agent in svc. Its records are
+// then read and written through the agent, see NewCryptoStorage.
+func (c *Client) Attach(ctx context.Context, svc interfaces.Service) error {
+	_, err := c.attach(ctx, svc)
+	return err
+}
+
+func (c *Client) attach(ctx context.Context, svc interfaces.Service) (*models.Session, error) {
+	var res sessionResponse
+	if err := c.do(ctx, http.MethodGet, "/session", nil, &res); err != nil {
+		return nil, err
+	}
+	key, err := base64.RawURLEncoding.DecodeString(res.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session key from the agent: %w", err)
+	}
+
+	c.setAttached(true)
+	if err = svc.ResumeSession(res.Session, key); err != nil {
+		c.setAttached(false)
+		return nil, err
+	}
+	// The key pair is loaded on a best effort basis: the records work
+	// offline, only sharing needs it.
+	_ = svc.EnsureKeyPair(ctx)
+	return res.Session, nil
+}
+
+func (c *Client) setAttached(attached bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attached = attached
+}
+
+func (c *Client) isAttached() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attached
+}
+
+// NewCryptoStorage returns the storage of the agent while attaching to it
+// and the storage of newCryptoStorage otherwise.
+func (c *Client) NewCryptoStorage(newCryptoStorage interfaces.NewCryptoStorage) interfaces.NewCryptoStorage {
+	return func(userID string, encryptionKey []byte) (interfaces.Storage, error) {
+		if c.isAttached() {
+			return &storage{client: c}, nil
+		}
+		return newCryptoStorage(userID, encryptionKey)
+	}
+}
+
+// Service returns svc logging in through the agent when one is running and
+// syncing through the agent once attached.
+func (c *Client) Service(svc interfaces.Service) interfaces.Service {
+	return &service{Service: svc, client: c}
+}
+
+type service struct {
+	interfaces.Service
+	client *Client
+}
+
+func (s *service) Login(ctx context.Context, login, password string) (string, error) {
+	if !s.client.Running(ctx) {
+		s.client.setAttached(false)
+		return s.Service.Login(ctx, login, password)
+	}
+	if err := s.client.Unlock(ctx, login, password); err != nil {
+		return "", err
+	}
+	session, err := s.client.attach(ctx, s.Service)
+	if err != nil {
+		return "", err
+	}
+	return session.UserID, nil
+}
+
+func (s *service) Sync(ctx context.Context) (bool, error) {
+	if !s.client.isAttached() {
+		return s.Service.Sync(ctx)
+	}
+	var res syncResponse
+	err := s.client.do(ctx, http.MethodPost, "/sync", nil, &res)
+	return res.HasConflicts, err
+}
+
+// storage is the local cache held by the agent.
+type storage struct {
+	client *Client
+}
+
+// Close leaves the cache open, it belongs to the agent.
+func (s *storage) Close() error {
+	s.client.setAttached(false)
+	return nil
+}
+
+func (s *storage) Destroy() error {
+	return s.client.do(context.Background(), http.MethodDelete, "/cache", nil, nil)
+}
+
+func (s *storage) GetRecords() ([]*models.Record, error) {
+	var records []*models.Record
+	err := s.client.do(context.Background(), http.MethodGet, "/records", nil, &records)
+	return records, err
+}
+
+func (s *storage) SaveRecord(record *models.Record) error {
+	return s.client.do(context.Background(), http.MethodPut, "/records/"+record.ID.String(), record, nil)
+}
+
+func (s *storage) GetRecord(id uuid.UUID) (*models.Record, error) {
+	var record models.Record
+	if err := s.client.do(context.Background(), http.MethodGet, "/records/"+id.String(), nil, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *storage) IsRecordExists(id uuid.UUID) (bool, error) {
+	_, err := s.GetRecord(id)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *storage) DeleteRecord(id uuid.UUID) error {
+	return s.client.do(context.Background(), http.MethodDelete, "/records/"+id.String(), nil, nil)
+}
+
+// do sends a request authenticated with the token of the agent and decodes
+// the response into res.
+func (c *Client) do(ctx context.Context, method, path string, req, res any) error {
+	if c.dir == "" {
+		return ErrNotRunning
+	}
+	token, err := os.ReadFile(filepath.Join(c.dir, tokenName))
+	if err != nil {
+		return ErrNotRunning
+	}
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpRes, err := c.http.Do(httpReq)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return ErrNotRunning
+		}
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode >= http.StatusBadRequest {
+		var errRes errorResponse
+		_ = json.NewDecoder(httpRes.Body).Decode(&errRes)
+		switch errRes.Code {
+		case codeLocked:
+			return ErrLocked
+		case codeNotFound:
+			return interfaces.ErrNotFound
+		case codeUnauthorized:
+			return interfaces.ErrUnauthorized
+		case codeDisabled:
+			return interfaces.ErrAccountDisabled
+		case codeBadRequest:
+			return interfaces.ErrBadRequest
+		case codeInvalidToken:
+			return errors.New("the agent rejected its token, restart it")
+		default:
+			return fmt.Errorf("agent: %s", errRes.Message)
+		}
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(httpRes.Body).Decode(res)
+}
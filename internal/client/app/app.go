@@ -6,10 +6,12 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/screens"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
 	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
 )
 
 type appModel struct {
 	svc           interfaces.Service
+	attach        commands.AttachFunc
 	screen        tea.Model
 	versions      models.Versions
 	width         int
@@ -17,12 +19,32 @@ type appModel struct {
 	connected     bool
 	authenticated bool
 	hasConflicts  bool
+	// pinMismatch blocks the application on the pin mismatch screen.
+	pinMismatch bool
+	sshAgent    *sshAgentState
+	// sshConfirm is shown over the screen while the SSH agent waits for
+	// the user to allow the use of a key.
+	sshConfirm     tea.Model
+	sshConfirmDone <-chan struct{}
 }
 
-func New(svc interfaces.Service, clientBuildVersion, clientBuildDate string) tea.Model {
+// sshAgentState is shared by the copies of the model, the SSH agent runs
+// until it is stopped from its screen or the application quits.
+type sshAgentState struct {
+	socket        string
+	stop          func()
+	confirmations chan *sshagent.ConfirmRequest
+}
+
+// New returns the application, which starts logged in when attach succeeds.
+func New(svc interfaces.Service, attach commands.AttachFunc, clientBuildVersion, clientBuildDate string) tea.Model {
 	return appModel{
 		svc:    svc,
+		attach: attach,
 		screen: screens.NewMenu(svc, screens.MenuGuest),
+		sshAgent: &sshAgentState{
+			confirmations: make(chan *sshagent.ConfirmRequest),
+		},
 		versions: models.Versions{Client: models.VersionInfo{
 			BuildVersion: models.NewOptString(clientBuildVersion),
 			BuildDate:    models.NewOptString(clientBuildDate),
@@ -31,5 +53,6 @@ func New(svc interfaces.Service, clientBuildVersion, clientBuildDate string) tea
 }
 
 func (m appModel) Init() tea.Cmd {
-	return tea.Batch(commands.FetchVersions(m.svc), tea.WindowSize())
+	return tea.Batch(commands.FetchVersions(m.svc), commands.Attach(m.svc, m.attach),
+		commands.WaitSSHConfirm(m.sshAgent.confirmations), tea.WindowSize())
 }
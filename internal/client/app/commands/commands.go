@@ -1,15 +1,21 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
 	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
 )
 
 const timeout = 5 * time.Second
@@ -52,11 +58,20 @@ func BackToMenu() tea.Msg {
 	return types.BackToMenuMsg{}
 }
 
-func Register(svc interfaces.Service, login, password string) tea.Cmd {
+func FetchRegistrationMode(svc interfaces.Service) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		_, err := svc.Register(ctx, login, password)
+		mode, err := svc.FetchRegistrationMode(ctx)
+		return types.RegistrationModeMsg{Mode: mode, Err: err}
+	}
+}
+
+func Register(svc interfaces.Service, login, password, inviteCode string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, err := svc.Register(ctx, login, password, inviteCode)
 		return types.AuthMsg{Err: err}
 	}
 }
@@ -70,6 +85,30 @@ func Login(svc interfaces.Service, login, password string) tea.Cmd {
 	}
 }
 
+// AttachFunc attaches the service to a running agent holding the unlocked vault.
+type AttachFunc func(ctx context.Context, svc interfaces.Service) error
+
+// Attach logs in through the agent if one is running and unlocked, nothing
+// happens otherwise.
+func Attach(svc interfaces.Service, attach AttachFunc) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := attach(ctx, svc); err != nil {
+			return nil
+		}
+		return types.AuthMsg{}
+	}
+}
+
+func DeleteAccount(svc interfaces.Service, password string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.AccountDeletedMsg{Err: svc.DeleteAccount(ctx, password)}
+	}
+}
+
 func Show(svc interfaces.Service) tea.Cmd {
 	return func() tea.Msg {
 		records, err := svc.GetRecords()
@@ -112,13 +151,13 @@ func Error(err error) tea.Cmd {
 	}
 }
 
-func SubmitMetadata(metadata types.Metadata) tea.Cmd {
+func SubmitMetadata(metadata models.Metadata) tea.Cmd {
 	return func() tea.Msg {
 		return types.MetadataMsg{Metadata: metadata}
 	}
 }
 
-func SubmitData[T types.Data](data T) tea.Cmd {
+func SubmitData[T models.Data](data T) tea.Cmd {
 	return func() tea.Msg {
 		data, err := json.Marshal(data)
 		if err != nil {
@@ -153,3 +192,334 @@ func PullRecord(svc interfaces.Service, id uuid.UUID) tea.Cmd {
 		return types.RecordPulledMsg{Record: record}
 	}
 }
+
+func ListAccessTokens(svc interfaces.Service) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		tokens, err := svc.ListAccessTokens(ctx)
+		return types.AccessTokensMsg{Tokens: tokens, Err: err}
+	}
+}
+
+func CreateAccessToken(svc interfaces.Service, req models.AccessTokenRequest) tea.Cmd {
+	return func() tea.Msg {
+		token, err := svc.CreateAccessToken(context.Background(), req)
+		return types.AccessTokenCreatedMsg{Token: token, Err: err}
+	}
+}
+
+func RevokeAccessToken(svc interfaces.Service, id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.AccessTokenRevokedMsg{Err: svc.RevokeAccessToken(ctx, id)}
+	}
+}
+
+func SelectShare(record *models.Record) tea.Cmd {
+	return func() tea.Msg {
+		return types.ShareSelectedMsg{Record: record}
+	}
+}
+
+func ListShares(svc interfaces.Service, recordID uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		shares, err := svc.ListShares(ctx, recordID)
+		return types.SharesMsg{Shares: shares, Err: err}
+	}
+}
+
+func ShareRecord(svc interfaces.Service, record *models.Record, login string) tea.Cmd {
+	return func() tea.Msg {
+		return types.RecordSharedMsg{Err: svc.ShareRecord(context.Background(), record, login)}
+	}
+}
+
+func RevokeShare(svc interfaces.Service, recordID, userID uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.ShareRevokedMsg{Err: svc.RevokeShare(ctx, recordID, userID)}
+	}
+}
+
+func FetchSharedRecords(svc interfaces.Service) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		records, err := svc.FetchSharedRecords(ctx)
+		return types.SharedRecordsMsg{Records: records, Err: err}
+	}
+}
+
+func ListOrgs(svc interfaces.Service) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		orgs, err := svc.ListOrgs(ctx)
+		return types.OrgsMsg{Orgs: orgs, Err: err}
+	}
+}
+
+func CreateOrg(svc interfaces.Service, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, err := svc.CreateOrg(ctx, name)
+		return types.OrgCreatedMsg{Err: err}
+	}
+}
+
+// DeleteOrg deletes the organization, switching to the personal vault if
+// it was in use.
+func DeleteOrg(svc interfaces.Service, org *models.Organization) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := svc.DeleteOrg(ctx, org)
+		if err == nil {
+			leaveVault(ctx, svc, org)
+		}
+		return types.OrgDeletedMsg{Err: err}
+	}
+}
+
+func SwitchVault(svc interfaces.Service, org *models.Organization) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.VaultSwitchedMsg{Err: svc.SwitchVault(ctx, org)}
+	}
+}
+
+func SelectOrg(org *models.Organization) tea.Cmd {
+	return func() tea.Msg {
+		return types.OrgSelectedMsg{Org: org}
+	}
+}
+
+func ListOrgMembers(svc interfaces.Service, org *models.Organization) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		members, err := svc.ListOrgMembers(ctx, org)
+		return types.OrgMembersMsg{Members: members, Err: err}
+	}
+}
+
+func InviteOrgMember(svc interfaces.Service, org *models.Organization, login string, role models.OrgRole) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.OrgMembersChangedMsg{Err: svc.InviteOrgMember(ctx, org, login, role)}
+	}
+}
+
+func SetOrgMemberRole(svc interfaces.Service, org *models.Organization, userID uuid.UUID, role models.OrgRole) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.OrgMembersChangedMsg{Err: svc.SetOrgMemberRole(ctx, org, userID, role)}
+	}
+}
+
+// RemoveOrgMember removes the member and, for owners and admins, re-keys
+// the organization right away so the removed member cannot read the records
+// written afterwards. The re-key failing with not found means the user
+// removed themselves.
+func RemoveOrgMember(svc interfaces.Service, org *models.Organization, userID uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := svc.RemoveOrgMember(ctx, org, userID); err != nil {
+			return types.OrgMembersChangedMsg{Err: err}
+		}
+		if !org.Role.CanManage() {
+			leaveVault(ctx, svc, org)
+			return types.OrgMembersChangedMsg{Left: true}
+		}
+		err := rekeyOrg(ctx, svc, org)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			leaveVault(ctx, svc, org)
+			return types.OrgMembersChangedMsg{Left: true}
+		}
+		return types.OrgMembersChangedMsg{Err: err}
+	}
+}
+
+func RekeyOrg(svc interfaces.Service, org *models.Organization) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.OrgMembersChangedMsg{Err: rekeyOrg(ctx, svc, org)}
+	}
+}
+
+// rekeyOrg re-keys the organization and reloads the team vault if it is in
+// use, so its records are saved with the new key.
+func rekeyOrg(ctx context.Context, svc interfaces.Service, org *models.Organization) error {
+	if err := svc.RekeyOrg(ctx, org); err != nil {
+		return err
+	}
+	if vault := svc.CurrentVault(); vault != nil && vault.ID == org.ID {
+		return svc.SwitchVault(ctx, org)
+	}
+	return nil
+}
+
+func leaveVault(ctx context.Context, svc interfaces.Service, org *models.Organization) {
+	if vault := svc.CurrentVault(); vault != nil && vault.ID == org.ID {
+		_ = svc.SwitchVault(ctx, nil)
+	}
+}
+
+func ListEmergency(svc interfaces.Service) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		contacts, err := svc.ListEmergencyContacts(ctx)
+		if err != nil {
+			return types.EmergencyMsg{Err: err}
+		}
+		grants, err := svc.ListEmergencyGrants(ctx)
+		return types.EmergencyMsg{Contacts: contacts, Grants: grants, Err: err}
+	}
+}
+
+func AddEmergencyContact(svc interfaces.Service, login string, waitDays int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.EmergencyChangedMsg{Err: svc.AddEmergencyContact(ctx, login, waitDays)}
+	}
+}
+
+func RemoveEmergencyContact(svc interfaces.Service, id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.EmergencyChangedMsg{Err: svc.RemoveEmergencyContact(ctx, id)}
+	}
+}
+
+func ApproveEmergencyAccess(svc interfaces.Service, id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.EmergencyChangedMsg{Err: svc.ApproveEmergencyAccess(ctx, id)}
+	}
+}
+
+func RejectEmergencyAccess(svc interfaces.Service, id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.EmergencyChangedMsg{Err: svc.RejectEmergencyAccess(ctx, id)}
+	}
+}
+
+func RequestEmergencyAccess(svc interfaces.Service, id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return types.EmergencyChangedMsg{Err: svc.RequestEmergencyAccess(ctx, id)}
+	}
+}
+
+func FetchEmergencyVault(svc interfaces.Service, grant *models.EmergencyAccess) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		records, err := svc.FetchEmergencyVault(ctx, grant.ID)
+		return types.EmergencyVaultMsg{Login: grant.Login, Records: records, Err: err}
+	}
+}
+
+func CreateSend(svc interfaces.Service, req *models.SendRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		link, err := svc.CreateSend(ctx, req)
+		return types.SendCreatedMsg{Link: link, Err: err}
+	}
+}
+
+func OpenSend(svc interfaces.Service, link, password string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		send, err := svc.OpenSend(ctx, link, password)
+		return types.SendOpenedMsg{Send: send, Err: err}
+	}
+}
+
+// Export writes the records of the current vault to the file, which is
+// readable only by the user.
+func Export(svc interfaces.Service, format, path, password string) tea.Cmd {
+	return func() tea.Msg {
+		records, err := svc.GetRecords()
+		if err != nil {
+			return types.ExportedMsg{Err: err}
+		}
+		var buf bytes.Buffer
+		if err = exporter.Write(&buf, format, records, password); err != nil {
+			return types.ExportedMsg{Err: err}
+		}
+		if err = os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			return types.ExportedMsg{Err: err}
+		}
+		count := 0
+		for _, record := range records {
+			if record.Status != models.RecordStatusDeleted {
+				count++
+			}
+		}
+		return types.ExportedMsg{Path: path, Count: count}
+	}
+}
+
+func ToggleSSHAgent() tea.Msg {
+	return types.SSHAgentToggleMsg{}
+}
+
+// StartSSHAgent serves the SSH keys of the vault until Stop of the result is
+// called. Keys requiring confirmation are sent to confirmations.
+func StartSSHAgent(svc interfaces.Service, confirmations chan<- *sshagent.ConfirmRequest) tea.Cmd {
+	return func() tea.Msg {
+		socket, err := sshagent.DefaultSocket()
+		if err != nil {
+			return types.SSHAgentMsg{Err: err}
+		}
+		listener, err := sshagent.Listen(socket)
+		if err != nil {
+			return types.SSHAgentMsg{Err: err}
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		agent := sshagent.New(svc.GetRecords, sshagent.ChannelConfirm(confirmations), slog.New(slog.DiscardHandler))
+		go func() { _ = agent.Serve(ctx, listener) }()
+		return types.SSHAgentMsg{Socket: socket, Stop: cancel}
+	}
+}
+
+// WaitSSHConfirm waits for the next key requiring confirmation.
+func WaitSSHConfirm(confirmations <-chan *sshagent.ConfirmRequest) tea.Cmd {
+	return func() tea.Msg {
+		req := <-confirmations
+		return types.SSHConfirmMsg{
+			Name:        req.Key.Name,
+			Fingerprint: req.Key.Fingerprint,
+			Answer:      req.Answer,
+			Done:        req.Done(),
+		}
+	}
+}
+
+func WaitSSHConfirmDone(done <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-done
+		return types.SSHConfirmDoneMsg{Done: done}
+	}
+}
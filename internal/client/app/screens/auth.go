@@ -10,7 +10,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 type AuthMode int
@@ -20,13 +22,20 @@ const (
 	AuthModeRegister
 )
 
+const (
+	authLogin = iota
+	authPassword
+	authInviteCode
+)
+
 type authModel struct {
-	svc        interfaces.Service
-	mode       AuthMode
-	focusIndex int
-	inputs     []textinput.Model
-	cursorMode cursor.Mode
-	bodyHeight int
+	svc              interfaces.Service
+	mode             AuthMode
+	registrationMode models.RegistrationMode
+	focusIndex       int
+	inputs           []textinput.Model
+	cursorMode       cursor.Mode
+	bodyHeight       int
 }
 
 func NewAuth(svc interfaces.Service, mode AuthMode) tea.Model {
@@ -35,33 +44,40 @@ func NewAuth(svc interfaces.Service, mode AuthMode) tea.Model {
 		mode:   mode,
 		inputs: make([]textinput.Model, 2),
 	}
-
-	var t textinput.Model
 	for i := range m.inputs {
-		t = textinput.New()
-		t.Cursor.Style = styles.CursorStyle
-		t.CharLimit = 32
-		t.Width = 32
-
-		switch i {
-		case 0:
-			t.Placeholder = "Login"
-			t.Focus()
-			t.PromptStyle = styles.FocusedStyle
-			t.TextStyle = styles.FocusedStyle
-		case 1:
-			t.Placeholder = "Password"
-			t.EchoMode = textinput.EchoPassword
-			t.EchoCharacter = '•'
-		}
-
-		m.inputs[i] = t
+		m.inputs[i] = newAuthInput(i)
 	}
 
 	return m
 }
 
+func newAuthInput(i int) textinput.Model {
+	t := textinput.New()
+	t.Cursor.Style = styles.CursorStyle
+	t.CharLimit = 32
+	t.Width = 32
+
+	switch i {
+	case authLogin:
+		t.Placeholder = "Login"
+		t.Focus()
+		t.PromptStyle = styles.FocusedStyle
+		t.TextStyle = styles.FocusedStyle
+	case authPassword:
+		t.Placeholder = "Password"
+		t.EchoMode = textinput.EchoPassword
+		t.EchoCharacter = '•'
+	case authInviteCode:
+		t.Placeholder = "Invite code"
+	}
+
+	return t
+}
+
 func (m authModel) Init() tea.Cmd {
+	if m.mode == AuthModeRegister {
+		return tea.Batch(textinput.Blink, tea.WindowSize(), commands.FetchRegistrationMode(m.svc))
+	}
 	return tea.Batch(textinput.Blink, tea.WindowSize())
 }
 
@@ -85,13 +101,20 @@ func (m authModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			s := msg.String()
 
 			if s == "enter" && m.focusIndex == len(m.inputs) {
-				login := m.inputs[0].Value()
-				password := m.inputs[1].Value()
+				login := m.inputs[authLogin].Value()
+				password := m.inputs[authPassword].Value()
 				switch m.mode {
 				case AuthModeLogin:
 					return m, commands.Login(m.svc, login, password)
 				case AuthModeRegister:
-					return m, commands.Register(m.svc, login, password)
+					if m.registrationMode == models.RegistrationClosed {
+						return m, commands.Error(interfaces.ErrRegistrationClosed)
+					}
+					var inviteCode string
+					if len(m.inputs) > authInviteCode {
+						inviteCode = m.inputs[authInviteCode].Value()
+					}
+					return m, commands.Register(m.svc, login, password, inviteCode)
 				}
 			}
 
@@ -123,6 +146,21 @@ func (m authModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+	case types.RegistrationModeMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.registrationMode = msg.Mode
+		if m.registrationMode == models.RegistrationInviteOnly && len(m.inputs) == authInviteCode {
+			input := newAuthInput(authInviteCode)
+			input.Cursor.SetMode(m.cursorMode)
+			m.inputs = append(m.inputs, input)
+			if m.focusIndex == authInviteCode {
+				m.focusIndex++
+			}
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
 		return m, nil
@@ -146,6 +184,13 @@ func (m *authModel) updateInputs(msg tea.Msg) tea.Cmd {
 func (m authModel) View() string {
 	var b strings.Builder
 
+	switch m.registrationMode {
+	case models.RegistrationInviteOnly:
+		b.WriteString("Registration on this server requires an invite code.\n\n")
+	case models.RegistrationClosed:
+		b.WriteString("Registration on this server is closed.\n\n")
+	}
+
 	for i := range m.inputs {
 		b.WriteString(m.inputs[i].View())
 		if i < len(m.inputs)-1 {
@@ -0,0 +1,253 @@
+package screens
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const (
+	tokenName = iota
+	tokenExpiry
+	tokenRecords
+	tokenTags
+	tokenReadOnly
+	tokenInputs
+)
+
+type createTokenModel struct {
+	svc        interfaces.Service
+	inputs     []textinput.Model
+	focusIndex int
+	records    []*models.Record
+	// token is shown once after it is created.
+	token      string
+	creating   bool
+	bodyHeight int
+}
+
+func NewCreateToken(svc interfaces.Service) tea.Model {
+	m := createTokenModel{svc: svc, inputs: make([]textinput.Model, tokenInputs)}
+	for i := range m.inputs {
+		t := textinput.New()
+		t.Cursor.Style = styles.CursorStyle
+		t.Width = 48
+		switch i {
+		case tokenName:
+			t.Placeholder = "Name"
+			t.CharLimit = 64
+			t.Focus()
+			t.PromptStyle = styles.FocusedStyle
+			t.TextStyle = styles.FocusedStyle
+		case tokenExpiry:
+			t.Placeholder = "Expires in days (30)"
+			t.CharLimit = 4
+		case tokenRecords:
+			t.Placeholder = "Record IDs, comma separated (all records)"
+		case tokenTags:
+			t.Placeholder = "Tags: key or key=value, comma separated"
+		case tokenReadOnly:
+			t.Placeholder = "Read-only? (y/N)"
+			t.CharLimit = 3
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m createTokenModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize(), commands.Show(m.svc))
+}
+
+func (m createTokenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.RecordsMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.records = msg.Records
+		return m, nil
+
+	case types.AccessTokenCreatedMsg:
+		m.creating = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.token = msg.Token
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.token != "" {
+			if msg.String() == "esc" || msg.String() == "enter" {
+				return m, commands.Select(MenuAccessTokens)
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.Select(MenuAccessTokens)
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if m.creating {
+					return m, nil
+				}
+				req, err := m.request()
+				if err != nil {
+					return m, commands.Error(err)
+				}
+				m.creating = true
+				return m, commands.CreateAccessToken(m.svc, req)
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				if i == m.focusIndex {
+					cmds[i] = m.inputs[i].Focus()
+					m.inputs[i].PromptStyle = styles.FocusedStyle
+					m.inputs[i].TextStyle = styles.FocusedStyle
+					continue
+				}
+				m.inputs[i].Blur()
+				m.inputs[i].PromptStyle = styles.NoStyle
+				m.inputs[i].TextStyle = styles.NoStyle
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m createTokenModel) request() (models.AccessTokenRequest, error) {
+	req := models.AccessTokenRequest{Name: strings.TrimSpace(m.inputs[tokenName].Value())}
+	if req.Name == "" {
+		return req, errors.New("name is required")
+	}
+
+	days := 30
+	if v := strings.TrimSpace(m.inputs[tokenExpiry].Value()); v != "" {
+		var err error
+		if days, err = strconv.Atoi(v); err != nil || days < 1 {
+			return req, errors.New("expiry must be a positive number of days")
+		}
+	}
+	req.ExpiresAt = time.Now().AddDate(0, 0, days)
+
+	for _, prefix := range splitList(m.inputs[tokenRecords].Value()) {
+		id, err := m.resolveRecord(prefix)
+		if err != nil {
+			return req, err
+		}
+		req.RecordIDs = append(req.RecordIDs, id)
+	}
+	req.Tags = splitList(m.inputs[tokenTags].Value())
+
+	switch strings.ToLower(strings.TrimSpace(m.inputs[tokenReadOnly].Value())) {
+	case "y", "yes":
+		req.ReadOnly = true
+	case "", "n", "no":
+	default:
+		return req, errors.New("answer y or n for read-only")
+	}
+	return req, nil
+}
+
+// resolveRecord accepts the short IDs shown in the record list.
+func (m createTokenModel) resolveRecord(prefix string) (uuid.UUID, error) {
+	prefix = strings.ToLower(strings.ReplaceAll(prefix, "-", ""))
+	var found uuid.UUID
+	for _, record := range m.records {
+		if record.Status == models.RecordStatusDeleted || !strings.HasPrefix(hex.EncodeToString(record.ID[:]), prefix) {
+			continue
+		}
+		if found != uuid.Nil {
+			return uuid.Nil, fmt.Errorf("record ID %s is ambiguous", prefix)
+		}
+		found = record.ID
+	}
+	if found == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("record %s not found", prefix)
+	}
+	return found, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m createTokenModel) View() string {
+	var b strings.Builder
+
+	if m.token != "" {
+		b.WriteString("Access token created. Copy it now, it is not shown again:\n\n")
+		b.WriteString(styles.InputTextStyle.Render(m.token))
+		b.WriteString("\n\nAnyone holding it can decrypt the records it gives access to.")
+		return lipgloss.JoinVertical(lipgloss.Top,
+			lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+			styles.FooterStyle.Render("Press Enter or Esc to return to the access tokens."),
+		)
+	}
+
+	b.WriteString("Leave record IDs and tags empty for a token to all records.\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i < len(m.inputs)-1 {
+			b.WriteRune('\n')
+		}
+	}
+
+	button := "Create"
+	if m.creating {
+		button = "Creating..."
+	}
+	if m.focusIndex == len(m.inputs) {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Esc to return to the access tokens."),
+	)
+}
@@ -0,0 +1,100 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+)
+
+type deleteAccountModel struct {
+	svc        interfaces.Service
+	input      textinput.Model
+	focusIndex int
+	bodyHeight int
+}
+
+func NewDeleteAccount(svc interfaces.Service) tea.Model {
+	t := textinput.New()
+	t.Cursor.Style = styles.CursorStyle
+	t.CharLimit = 32
+	t.Width = 32
+	t.Placeholder = "Password"
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '•'
+	t.Focus()
+	t.PromptStyle = styles.FocusedStyle
+	t.TextStyle = styles.FocusedStyle
+
+	return deleteAccountModel{svc: svc, input: t}
+}
+
+func (m deleteAccountModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m deleteAccountModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "tab", "shift+tab", "up", "down":
+			m.focusIndex = (m.focusIndex + 1) % 2
+			if m.focusIndex == 0 {
+				m.input.PromptStyle = styles.FocusedStyle
+				m.input.TextStyle = styles.FocusedStyle
+				return m, m.input.Focus()
+			}
+			m.input.Blur()
+			m.input.PromptStyle = styles.NoStyle
+			m.input.TextStyle = styles.NoStyle
+			return m, nil
+		case "enter":
+			if m.focusIndex == 1 {
+				return m, commands.DeleteAccount(m.svc, m.input.Value())
+			}
+			m.focusIndex = 1
+			m.input.Blur()
+			m.input.PromptStyle = styles.NoStyle
+			m.input.TextStyle = styles.NoStyle
+			return m, nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	return m, cmd
+}
+
+func (m deleteAccountModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("Your account and all its records will be deleted from the server\n")
+	b.WriteString("and from this device. This cannot be undone.\n\n")
+	b.WriteString("Enter your password to confirm:\n\n")
+	b.WriteString(m.input.View())
+
+	button := "Delete account"
+	if m.focusIndex == 1 {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Esc to return to the menu."),
+	)
+}
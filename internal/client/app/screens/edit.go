@@ -40,6 +40,8 @@ func getScreenByType(record *models.Record) (tea.Model, error) {
 		return NewEditBinary(record.Data)
 	case models.RecordTypeCard:
 		return NewEditCard(record.Data)
+	case models.RecordTypeSSHKey:
+		return NewEditSSHKey(record.Data)
 	default:
 		return NewEditType(), nil
 	}
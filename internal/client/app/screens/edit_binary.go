@@ -13,10 +13,11 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 type editBinaryModel struct {
-	data           types.Binary
+	data           models.Binary
 	focusIndex     int
 	filepicker     filepicker.Model
 	selectedFile   string
@@ -41,8 +42,8 @@ func NewEditBinary(data []byte) (tea.Model, error) {
 	return m, nil
 }
 
-func (m editBinaryModel) decodeData(bytes []byte) (data types.Binary, err error) {
-	data.Metadata = make(types.Metadata)
+func (m editBinaryModel) decodeData(bytes []byte) (data models.Binary, err error) {
+	data.Metadata = make(models.Metadata)
 	if len(bytes) > 0 {
 		err = json.Unmarshal(bytes, &data)
 	}
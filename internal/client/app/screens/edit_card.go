@@ -11,6 +11,7 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 const (
@@ -21,7 +22,7 @@ const (
 )
 
 type editCardModel struct {
-	data           types.Card
+	data           models.Card
 	focusIndex     int
 	inputs         []textinput.Model
 	metadataScreen tea.Model
@@ -121,8 +122,8 @@ func NewEditCard(data []byte) (tea.Model, error) {
 	return m, nil
 }
 
-func (m editCardModel) decodeData(bytes []byte) (data types.Card, err error) {
-	data.Metadata = make(types.Metadata)
+func (m editCardModel) decodeData(bytes []byte) (data models.Card, err error) {
+	data.Metadata = make(models.Metadata)
 	if len(bytes) > 0 {
 		err = json.Unmarshal(bytes, &data)
 	}
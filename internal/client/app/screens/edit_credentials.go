@@ -10,6 +10,7 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 const (
@@ -19,7 +20,7 @@ const (
 )
 
 type editCredentialsModel struct {
-	data           types.Credentials
+	data           models.Credentials
 	focusIndex     int
 	inputs         []textinput.Model
 	metadataScreen tea.Model
@@ -68,8 +69,8 @@ func NewEditCredentials(data []byte) (tea.Model, error) {
 	return m, nil
 }
 
-func (m editCredentialsModel) decodeData(bytes []byte) (data types.Credentials, err error) {
-	data.Metadata = make(types.Metadata)
+func (m editCredentialsModel) decodeData(bytes []byte) (data models.Credentials, err error) {
+	data.Metadata = make(models.Metadata)
 	if len(bytes) > 0 {
 		err = json.Unmarshal(bytes, &data)
 	}
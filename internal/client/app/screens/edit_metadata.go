@@ -9,7 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
-	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 type Row struct {
@@ -32,12 +32,12 @@ func newRow() Row {
 }
 
 type editMetadataModel struct {
-	metadata   types.Metadata
+	metadata   models.Metadata
 	rows       []Row
 	focusIndex int
 }
 
-func NewEditMetadata(metadata types.Metadata) tea.Model {
+func NewEditMetadata(metadata models.Metadata) tea.Model {
 	m := editMetadataModel{
 		metadata: metadata,
 		rows:     make([]Row, 0, len(metadata)),
@@ -109,7 +109,7 @@ func (m *editMetadataModel) handleEnter() tea.Cmd {
 	submitBtnIndex := addBtnIndex + 1
 
 	if m.focusIndex == submitBtnIndex {
-		newMetadata := make(types.Metadata)
+		newMetadata := make(models.Metadata)
 		for _, row := range m.rows {
 			key := strings.TrimSpace(row.KeyInput.Value())
 			if key != "" {
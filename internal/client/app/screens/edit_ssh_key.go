@@ -0,0 +1,274 @@
+package screens
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+)
+
+const (
+	sshKeyComment = iota
+	sshKeyType
+	sshKeyConfirm
+	sshKeyFile
+	sshKeyPassphrase
+	sshKeyInputs
+)
+
+// editSSHKeyModel generates a key, imports one from a file or changes the
+// comment and the confirmation of the stored key.
+type editSSHKeyModel struct {
+	data           models.SSHKey
+	fingerprint    string
+	focusIndex     int
+	inputs         []textinput.Model
+	metadataScreen tea.Model
+}
+
+func NewEditSSHKey(data []byte) (tea.Model, error) {
+	m := editSSHKeyModel{
+		inputs: make([]textinput.Model, sshKeyInputs),
+	}
+
+	var err error
+	if m.data, err = m.decodeData(data); err != nil {
+		return nil, err
+	}
+	if m.data.PublicKey != "" {
+		if m.fingerprint, err = sshagent.Fingerprint(m.data); err != nil {
+			return nil, err
+		}
+	}
+
+	var t textinput.Model
+	for i := range m.inputs {
+		t = textinput.New()
+		t.Cursor.Style = styles.CursorStyle
+		t.CharLimit = 64
+		t.Width = 64
+		t.Prompt = ""
+
+		switch i {
+		case sshKeyComment:
+			t.Placeholder = "user@host"
+			t.Focus()
+			t.PromptStyle = styles.FocusedStyle
+			t.TextStyle = styles.FocusedStyle
+			t.SetValue(m.data.Comment)
+		case sshKeyType:
+			t.Placeholder = sshagent.KeyTypeEd25519 + " or " + sshagent.KeyTypeRSA
+			t.CharLimit = 7
+			t.Width = 15
+		case sshKeyFile:
+			t.Placeholder = "~/.ssh/id_ed25519"
+			t.CharLimit = 256
+		case sshKeyPassphrase:
+			t.Placeholder = "Passphrase of the file"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		case sshKeyConfirm:
+			t.Placeholder = "y/n"
+			t.CharLimit = 1
+			t.Width = 3
+			if m.data.Confirm {
+				t.SetValue("y")
+			}
+		}
+
+		m.inputs[i] = t
+	}
+
+	return m, nil
+}
+
+func (m editSSHKeyModel) decodeData(bytes []byte) (data models.SSHKey, err error) {
+	data.Metadata = make(models.Metadata)
+	if len(bytes) > 0 {
+		err = json.Unmarshal(bytes, &data)
+	}
+	return
+}
+
+func (m editSSHKeyModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m editSSHKeyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.MetadataMsg:
+		m.data.Metadata = msg.Metadata
+		return m, commands.SubmitData(m.data)
+	}
+
+	var cmd tea.Cmd
+	if m.metadataScreen != nil {
+		m.metadataScreen, cmd = m.metadataScreen.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				data, err := m.key()
+				if err != nil {
+					return m, commands.Error(err)
+				}
+				m.data = data
+				m.metadataScreen = NewEditMetadata(m.data.Metadata)
+				return m, m.metadataScreen.Init()
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := 0; i <= len(m.inputs)-1; i++ {
+				if i == m.focusIndex {
+					cmds[i] = m.inputs[i].Focus()
+					m.inputs[i].PromptStyle = styles.FocusedStyle
+					m.inputs[i].TextStyle = styles.FocusedStyle
+					continue
+				}
+				m.inputs[i].Blur()
+				m.inputs[i].PromptStyle = styles.NoStyle
+				m.inputs[i].TextStyle = styles.NoStyle
+			}
+
+			return m, tea.Batch(cmds...)
+		}
+	}
+
+	cmd = m.updateInputs(msg)
+
+	return m, cmd
+}
+
+// key imports the key of the file if one is given. Otherwise it keeps the
+// stored key or generates a new one for a new record.
+func (m editSSHKeyModel) key() (models.SSHKey, error) {
+	comment := m.inputs[sshKeyComment].Value()
+	var confirm bool
+	switch strings.ToLower(m.inputs[sshKeyConfirm].Value()) {
+	case "y":
+		confirm = true
+	case "", "n":
+	default:
+		return models.SSHKey{}, fmt.Errorf("confirm each use must be y or n")
+	}
+
+	var data models.SSHKey
+	var err error
+	switch path := m.inputs[sshKeyFile].Value(); {
+	case path != "":
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return models.SSHKey{}, err
+			}
+			path = filepath.Join(home, rest)
+		}
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return models.SSHKey{}, err
+		}
+		data, err = sshagent.Import(pemBytes, []byte(m.inputs[sshKeyPassphrase].Value()), comment)
+		if err != nil {
+			return models.SSHKey{}, err
+		}
+	case m.data.PrivateKey == "":
+		keyType := m.inputs[sshKeyType].Value()
+		if keyType == "" {
+			keyType = sshagent.KeyTypeEd25519
+		}
+		if data, err = sshagent.Generate(keyType, comment); err != nil {
+			return models.SSHKey{}, err
+		}
+	case comment != m.data.Comment:
+		if data, err = sshagent.SetComment(m.data, comment); err != nil {
+			return models.SSHKey{}, err
+		}
+	default:
+		data = m.data
+	}
+	data.Confirm = confirm
+	data.Metadata = m.data.Metadata
+	return data, nil
+}
+
+func (m *editSSHKeyModel) updateInputs(msg tea.Msg) tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m editSSHKeyModel) View() string {
+	if m.metadataScreen != nil {
+		return m.metadataScreen.View()
+	}
+
+	var b strings.Builder
+	if m.fingerprint == "" {
+		b.WriteString("Generate a new key, or import the private key of a file")
+	} else {
+		fmt.Fprintf(&b, "Key %s\nImport a file to replace it", m.fingerprint)
+	}
+	fmt.Fprintf(&b, `
+
+ %s
+ %s
+
+ %s  %s
+ %s  %s
+
+ %s
+ %s
+
+ %s
+ %s
+`,
+		styles.InputTextStyle.Width(64).Render("Comment"),
+		m.inputs[sshKeyComment].View(),
+		styles.InputTextStyle.Width(16).Render("Key type"), styles.InputTextStyle.Width(24).Render("Confirm each use"),
+		m.inputs[sshKeyType].View(), m.inputs[sshKeyConfirm].View(),
+		styles.InputTextStyle.Width(64).Render("Private key file"),
+		m.inputs[sshKeyFile].View(),
+		styles.InputTextStyle.Width(64).Render("Passphrase"),
+		m.inputs[sshKeyPassphrase].View(),
+	)
+
+	button := "Continue"
+	if m.focusIndex == len(m.inputs) {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n%s\n", button)
+
+	return b.String()
+}
@@ -10,10 +10,11 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/commands"
 	"github.com/grnsv/GophKeeper/internal/client/app/styles"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 type editTextModel struct {
-	data           types.Text
+	data           models.Text
 	focusIndex     int
 	textarea       textarea.Model
 	metadataScreen tea.Model
@@ -36,8 +37,8 @@ func NewEditText(data []byte) (tea.Model, error) {
 	return m, nil
 }
 
-func (m editTextModel) decodeData(bytes []byte) (data types.Text, err error) {
-	data.Metadata = make(types.Metadata)
+func (m editTextModel) decodeData(bytes []byte) (data models.Text, err error) {
+	data.Metadata = make(models.Metadata)
 	if len(bytes) > 0 {
 		err = json.Unmarshal(bytes, &data)
 	}
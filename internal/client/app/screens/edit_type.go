@@ -21,6 +21,7 @@ func NewEditType() tea.Model {
 		models.RecordTypeText,
 		models.RecordTypeBinary,
 		models.RecordTypeCard,
+		models.RecordTypeSSHKey,
 	}}
 }
 
@@ -0,0 +1,324 @@
+package screens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const MenuEmergency = "Emergency access"
+
+// defaultEmergencyWaitDays is used when no waiting period is entered.
+const defaultEmergencyWaitDays = 7
+
+// emergencyModel lists the trusted contacts of the user followed by the users
+// who made the user their trusted contact. Once access to a vault is
+// approved, its records are shown read-only.
+type emergencyModel struct {
+	svc        interfaces.Service
+	contacts   []*models.EmergencyAccess
+	grants     []*models.EmergencyAccess
+	loaded     bool
+	cursor     int
+	input      textinput.Model
+	adding     bool
+	busy       bool
+	vaultLogin string
+	records    []*models.Record
+	vaultIdx   int
+	selected   *models.Record
+	bodyHeight int
+}
+
+func NewEmergency(svc interfaces.Service) tea.Model {
+	input := textinput.New()
+	input.Placeholder = fmt.Sprintf("login [waiting days, %d by default]", defaultEmergencyWaitDays)
+	input.CharLimit = 80
+	input.Width = 40
+	input.Cursor.Style = styles.CursorStyle
+	input.PromptStyle = styles.FocusedStyle
+	input.TextStyle = styles.FocusedStyle
+	return emergencyModel{svc: svc, input: input}
+}
+
+func (m emergencyModel) Init() tea.Cmd {
+	return tea.Batch(commands.ListEmergency(m.svc), tea.WindowSize())
+}
+
+func (m emergencyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.EmergencyMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.contacts = msg.Contacts
+		m.grants = msg.Grants
+		m.loaded = true
+		m.cursor = min(m.cursor, max(0, len(m.contacts)+len(m.grants)-1))
+		return m, nil
+
+	case types.EmergencyChangedMsg:
+		m.busy = false
+		if msg.Err != nil {
+			return m, tea.Batch(commands.Error(msg.Err), commands.ListEmergency(m.svc))
+		}
+		return m, commands.ListEmergency(m.svc)
+
+	case types.EmergencyVaultMsg:
+		m.busy = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.vaultLogin = msg.Login
+		m.records = msg.Records
+		m.vaultIdx = 0
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case m.adding:
+			return m.updateAdding(msg)
+		case m.records != nil:
+			return m.updateVault(msg), nil
+		case m.busy && msg.String() != "esc":
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "tab":
+			if m.cursor < len(m.contacts)+len(m.grants)-1 {
+				m.cursor++
+			}
+		case "n":
+			m.adding = true
+			return m, m.input.Focus()
+		}
+		if contact := m.selectedContact(); contact != nil {
+			return m.updateContact(msg, contact)
+		}
+		if grant := m.selectedGrant(); grant != nil {
+			return m.updateGrant(msg, grant)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m emergencyModel) updateContact(msg tea.KeyMsg, contact *models.EmergencyAccess) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "a":
+		m.busy = true
+		return m, commands.ApproveEmergencyAccess(m.svc, contact.ID)
+	case "r":
+		m.busy = true
+		return m, commands.RejectEmergencyAccess(m.svc, contact.ID)
+	case "delete":
+		m.busy = true
+		return m, commands.RemoveEmergencyContact(m.svc, contact.ID)
+	}
+	return m, nil
+}
+
+func (m emergencyModel) updateGrant(msg tea.KeyMsg, grant *models.EmergencyAccess) (tea.Model, tea.Cmd) {
+	if msg.String() != "enter" {
+		return m, nil
+	}
+	m.busy = true
+	if grant.Status == models.EmergencyApproved {
+		return m, commands.FetchEmergencyVault(m.svc, grant)
+	}
+	return m, commands.RequestEmergencyAccess(m.svc, grant.ID)
+}
+
+func (m emergencyModel) updateAdding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.adding = false
+		m.input.Blur()
+		m.input.Reset()
+		return m, nil
+	case "enter":
+		fields := strings.Fields(m.input.Value())
+		if len(fields) == 0 || len(fields) > 2 {
+			return m, nil
+		}
+		waitDays := defaultEmergencyWaitDays
+		if len(fields) == 2 {
+			days, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return m, commands.Error(fmt.Errorf("%w: waiting period must be a number of days", interfaces.ErrInvalidEmergency))
+			}
+			waitDays = days
+		}
+		m.adding = false
+		m.busy = true
+		m.input.Blur()
+		m.input.Reset()
+		return m, commands.AddEmergencyContact(m.svc, fields[0], waitDays)
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m emergencyModel) updateVault(msg tea.KeyMsg) emergencyModel {
+	if m.selected != nil {
+		if msg.String() == "esc" || msg.String() == "enter" {
+			m.selected = nil
+		}
+		return m
+	}
+	switch msg.String() {
+	case "esc":
+		m.records = nil
+		m.vaultLogin = ""
+	case "up", "shift+tab":
+		if m.vaultIdx > 0 {
+			m.vaultIdx--
+		}
+	case "down", "tab":
+		if m.vaultIdx < len(m.records)-1 {
+			m.vaultIdx++
+		}
+	case "enter":
+		if len(m.records) > 0 {
+			m.selected = m.records[m.vaultIdx]
+		}
+	}
+	return m
+}
+
+func (m emergencyModel) selectedContact() *models.EmergencyAccess {
+	if m.cursor < len(m.contacts) {
+		return m.contacts[m.cursor]
+	}
+	return nil
+}
+
+func (m emergencyModel) selectedGrant() *models.EmergencyAccess {
+	if i := m.cursor - len(m.contacts); i >= 0 && i < len(m.grants) {
+		return m.grants[i]
+	}
+	return nil
+}
+
+func (m emergencyModel) View() string {
+	switch {
+	case m.selected != nil:
+		return m.recordView()
+	case m.records != nil:
+		return m.vaultView()
+	}
+
+	var b strings.Builder
+	if !m.loaded {
+		b.WriteString("Loading emergency access...\n")
+	} else {
+		b.WriteString("Your trusted contacts:\n\n")
+		if len(m.contacts) == 0 {
+			b.WriteString("  none\n")
+		}
+		for i, contact := range m.contacts {
+			m.writeAccess(&b, i, contact)
+		}
+		b.WriteString("\nUsers who trust you:\n\n")
+		if len(m.grants) == 0 {
+			b.WriteString("  none\n")
+		}
+		for i, grant := range m.grants {
+			m.writeAccess(&b, len(m.contacts)+i, grant)
+		}
+	}
+	if m.adding {
+		b.WriteString("\n")
+		b.WriteString(m.input.View())
+	}
+
+	footer := "Press n to add a contact, Esc to return to the menu."
+	switch {
+	case m.adding:
+		footer = "Press Enter to add the trusted contact. Esc to cancel."
+	case m.selectedContact() != nil:
+		footer = "Press n to add a contact, a to approve, r to reject, Del to remove, Esc to return to the menu."
+	case m.selectedGrant() != nil && m.selectedGrant().Status == models.EmergencyApproved:
+		footer = "Press Enter to open the vault, n to add a contact, Esc to return to the menu."
+	case m.selectedGrant() != nil:
+		footer = "Press Enter to request access, n to add a contact, Esc to return to the menu."
+	}
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render(footer),
+	)
+}
+
+func (m emergencyModel) writeAccess(b *strings.Builder, i int, access *models.EmergencyAccess) {
+	cursor := " "
+	if m.cursor == i {
+		cursor = styles.CursorStyle.Render(">")
+	}
+	fmt.Fprintf(b, "%s %s %s, waiting period %d days", cursor, styles.TypeStyle.Render(string(access.Status)), access.Login, access.WaitDays)
+	if access.Status == models.EmergencyRequested {
+		fmt.Fprintf(b, ", available on %s", access.AvailableAt.Local().Format(time.DateTime))
+	}
+	b.WriteString("\n")
+}
+
+func (m emergencyModel) vaultView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Vault of %s:\n\n", m.vaultLogin)
+	if len(m.records) == 0 {
+		b.WriteString("The vault is empty.")
+	}
+	for i, record := range m.records {
+		cursor := " "
+		if m.vaultIdx == i {
+			cursor = styles.CursorStyle.Render(">")
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", cursor, styles.TypeStyle.Render(string(record.Type)), truncateString(string(record.Data), 36))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Enter to view a record, Esc to return to emergency access."),
+	)
+}
+
+func (m emergencyModel) recordView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s from the vault of %s\n\n", styles.TypeStyle.Render(string(m.selected.Type)), m.vaultLogin)
+	var data bytes.Buffer
+	if err := json.Indent(&data, m.selected.Data, "", "  "); err != nil {
+		data.Reset()
+		data.Write(m.selected.Data)
+	}
+	b.Write(data.Bytes())
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("This record is read-only. Press Esc to return to the list."),
+	)
+}
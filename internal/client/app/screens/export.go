@@ -0,0 +1,238 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+)
+
+const MenuExport = "Export"
+
+const (
+	exportFormat = iota
+	exportPath
+	exportPassword
+	exportRepeat
+	exportInputs
+)
+
+// exportModel writes the records of the current vault to a file. Plaintext
+// formats are written only after the user confirmed a warning.
+type exportModel struct {
+	svc        interfaces.Service
+	inputs     []textinput.Model
+	focusIndex int
+	// confirming is set while the plaintext warning is shown.
+	confirming bool
+	exporting  bool
+	// done is shown once the file is written.
+	done       string
+	bodyHeight int
+}
+
+func NewExport(svc interfaces.Service) tea.Model {
+	m := exportModel{svc: svc, inputs: make([]textinput.Model, exportInputs)}
+	for i := range m.inputs {
+		t := textinput.New()
+		t.Cursor.Style = styles.CursorStyle
+		t.Width = 48
+		switch i {
+		case exportFormat:
+			t.Placeholder = "Format: archive, json or csv (archive)"
+			t.CharLimit = 7
+			t.Focus()
+			t.PromptStyle = styles.FocusedStyle
+			t.TextStyle = styles.FocusedStyle
+		case exportPath:
+			t.Placeholder = "File"
+			t.CharLimit = 4096
+		case exportPassword:
+			t.Placeholder = "Archive password"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		case exportRepeat:
+			t.Placeholder = "Repeat the password"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m exportModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m exportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.ExportedMsg:
+		m.exporting = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.done = fmt.Sprintf("Exported %d records to %s.", msg.Count, msg.Path)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.done != "" {
+			if msg.String() == "esc" || msg.String() == "enter" {
+				return m, commands.BackToMenu
+			}
+			return m, nil
+		}
+		if m.confirming {
+			m.confirming = false
+			if msg.String() != "y" {
+				return m, nil
+			}
+			m.exporting = true
+			return m, commands.Export(m.svc, m.format(), m.path(), "")
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if m.exporting {
+					return m, nil
+				}
+				password, err := m.validate()
+				if err != nil {
+					return m, commands.Error(err)
+				}
+				if exporter.Plaintext(m.format()) {
+					m.confirming = true
+					return m, nil
+				}
+				m.exporting = true
+				return m, commands.Export(m.svc, m.format(), m.path(), password)
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				if i == m.focusIndex {
+					cmds[i] = m.inputs[i].Focus()
+					m.inputs[i].PromptStyle = styles.FocusedStyle
+					m.inputs[i].TextStyle = styles.FocusedStyle
+					continue
+				}
+				m.inputs[i].Blur()
+				m.inputs[i].PromptStyle = styles.NoStyle
+				m.inputs[i].TextStyle = styles.NoStyle
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m exportModel) format() string {
+	if format := strings.ToLower(strings.TrimSpace(m.inputs[exportFormat].Value())); format != "" {
+		return format
+	}
+	return exporter.FormatArchive
+}
+
+func (m exportModel) path() string {
+	return strings.TrimSpace(m.inputs[exportPath].Value())
+}
+
+// validate checks the inputs and returns the password of the archive.
+func (m exportModel) validate() (string, error) {
+	if !slices.Contains(exporter.Formats, m.format()) {
+		return "", fmt.Errorf("format must be one of %s", strings.Join(exporter.Formats, ", "))
+	}
+	if m.path() == "" {
+		return "", errors.New("file is required")
+	}
+	if exporter.Plaintext(m.format()) {
+		return "", nil
+	}
+	password := m.inputs[exportPassword].Value()
+	if password == "" {
+		return "", errors.New("the archive needs a password")
+	}
+	if m.inputs[exportRepeat].Value() != password {
+		return "", errors.New("the passwords do not match")
+	}
+	return password, nil
+}
+
+func (m exportModel) View() string {
+	var b strings.Builder
+
+	if m.done != "" {
+		b.WriteString(m.done)
+		return lipgloss.JoinVertical(lipgloss.Top,
+			lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+			styles.FooterStyle.Render("Press Enter or Esc to return to the menu."),
+		)
+	}
+
+	if m.confirming {
+		fmt.Fprintf(&b, "The records will be written to %s unencrypted.\n", m.path())
+		b.WriteString("Anyone who can read the file can read every password in it.\n")
+		b.WriteString("Keep it safe and delete it when done.")
+		return lipgloss.JoinVertical(lipgloss.Top,
+			lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+			styles.FooterStyle.Render("Press y to export, any other key to go back."),
+		)
+	}
+
+	b.WriteString("An archive is encrypted with its password and can be imported into any\n")
+	b.WriteString("account with gophkeeper import. JSON and CSV are not encrypted.\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i < len(m.inputs)-1 {
+			b.WriteRune('\n')
+		}
+	}
+
+	button := "Export"
+	if m.exporting {
+		button = "Exporting..."
+	}
+	if m.focusIndex == len(m.inputs) {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Esc to return to the menu."),
+	)
+}
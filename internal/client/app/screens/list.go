@@ -54,6 +54,15 @@ func (m listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case tea.KeyEnter:
 			return m, commands.SelectRecord(m.records[m.cursor])
+		case tea.KeyRunes:
+			switch {
+			case msg.String() == "s" && len(m.records) > 0:
+				return m, commands.SelectShare(m.records[m.cursor])
+			case msg.String() == "a" && len(m.records) > 0 && m.records[m.cursor].Status == models.RecordStatusCaptured:
+				// Approving pushes the captured record, then the list shows
+				// its new status.
+				return m, tea.Sequence(commands.SaveRecord(m.svc, m.records[m.cursor]), commands.Show(m.svc))
+			}
 		case tea.KeyDelete:
 			record := m.records[m.cursor]
 			m.records = append(m.records[:m.cursor], m.records[m.cursor+1:]...)
@@ -88,6 +97,8 @@ func (m listModel) View() string {
 				status = styles.StatusErrorStyle.Render(string(models.RecordStatusConflict))
 			case models.RecordStatusDeleted:
 				status = styles.StatusDeletedStyle.Render(string(models.RecordStatusDeleted))
+			case models.RecordStatusCaptured:
+				status = styles.StatusPendingStyle.Render(string(models.RecordStatusCaptured))
 			default:
 				status = styles.StatusErrorStyle.Render("unknown")
 			}
@@ -104,7 +115,7 @@ func (m listModel) View() string {
 
 	return lipgloss.JoinVertical(lipgloss.Top,
 		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
-		styles.FooterStyle.Render("Press Del to delete record, Esc to return to the menu."),
+		styles.FooterStyle.Render("Press a to approve a captured login, s to share record, Del to delete record, Esc to return to the menu."),
 	)
 }
 
@@ -0,0 +1,180 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// membersModel lists the members of an organization. Owners and admins
+// invite users with "login role", change roles and remove members, which
+// re-keys the organization.
+type membersModel struct {
+	svc        interfaces.Service
+	org        *models.Organization
+	members    []*models.OrgMember
+	loaded     bool
+	cursor     int
+	invite     textinput.Model
+	inviting   bool
+	busy       bool
+	bodyHeight int
+}
+
+func NewMembers(svc interfaces.Service, org *models.Organization) tea.Model {
+	invite := textinput.New()
+	invite.Placeholder = "login [owner|admin|writer|reader]"
+	invite.CharLimit = 80
+	invite.Width = 40
+	invite.Cursor.Style = styles.CursorStyle
+	invite.PromptStyle = styles.FocusedStyle
+	invite.TextStyle = styles.FocusedStyle
+	return membersModel{svc: svc, org: org, invite: invite}
+}
+
+func (m membersModel) Init() tea.Cmd {
+	return tea.Batch(commands.ListOrgMembers(m.svc, m.org), tea.WindowSize())
+}
+
+func (m membersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.OrgMembersMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.members = msg.Members
+		m.loaded = true
+		m.cursor = min(m.cursor, max(0, len(m.members)-1))
+		return m, nil
+
+	case types.OrgMembersChangedMsg:
+		m.busy = false
+		if msg.Left {
+			return m, commands.Select(MenuVaults)
+		}
+		if msg.Err != nil {
+			return m, tea.Batch(commands.Error(msg.Err), commands.ListOrgMembers(m.svc, m.org))
+		}
+		return m, commands.ListOrgMembers(m.svc, m.org)
+
+	case tea.KeyMsg:
+		if m.inviting {
+			return m.updateInviting(msg)
+		}
+		if m.busy && msg.String() != "esc" {
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.Select(MenuVaults)
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "tab":
+			if m.cursor < len(m.members)-1 {
+				m.cursor++
+			}
+		case "i":
+			m.inviting = true
+			return m, m.invite.Focus()
+		case "r":
+			if len(m.members) == 0 {
+				return m, nil
+			}
+			member := m.members[m.cursor]
+			next := models.OrgRoles[(slices.Index(models.OrgRoles, member.Role)+1)%len(models.OrgRoles)]
+			m.busy = true
+			return m, commands.SetOrgMemberRole(m.svc, m.org, member.UserID, next)
+		case "k":
+			m.busy = true
+			return m, commands.RekeyOrg(m.svc, m.org)
+		case "delete":
+			if len(m.members) == 0 {
+				return m, nil
+			}
+			m.busy = true
+			return m, commands.RemoveOrgMember(m.svc, m.org, m.members[m.cursor].UserID)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.invite, cmd = m.invite.Update(msg)
+	return m, cmd
+}
+
+func (m membersModel) updateInviting(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.inviting = false
+		m.invite.Blur()
+		m.invite.Reset()
+		return m, nil
+	case "enter":
+		fields := strings.Fields(m.invite.Value())
+		if len(fields) == 0 || len(fields) > 2 {
+			return m, nil
+		}
+		role := models.OrgRoleWriter
+		if len(fields) == 2 {
+			role = models.OrgRole(fields[1])
+		}
+		m.inviting = false
+		m.busy = true
+		m.invite.Blur()
+		m.invite.Reset()
+		return m, commands.InviteOrgMember(m.svc, m.org, fields[0], role)
+	}
+	var cmd tea.Cmd
+	m.invite, cmd = m.invite.Update(msg)
+	return m, cmd
+}
+
+func (m membersModel) View() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Members of %s, you are %s", m.org.Name, m.org.Role)
+	if m.org.RekeyNeeded {
+		b.WriteString(" " + styles.HelpStyle.Render("(re-key needed)"))
+	}
+	b.WriteString("\n\n")
+	switch {
+	case !m.loaded:
+		b.WriteString("Loading members...\n")
+	default:
+		for i, member := range m.members {
+			cursor := " "
+			if m.cursor == i {
+				cursor = styles.CursorStyle.Render(">")
+			}
+			fmt.Fprintf(&b, "%s %s %s since %s\n", cursor, styles.TypeStyle.Render(string(member.Role)), member.Login, member.CreatedAt.Local().Format(time.DateTime))
+		}
+	}
+	if m.inviting {
+		b.WriteString("\n")
+		b.WriteString(m.invite.View())
+	}
+
+	footer := "Press i to invite, r to change the role, Del to remove, k to re-key, Esc to return to the vaults."
+	if m.inviting {
+		footer = "Press Enter to invite, the role defaults to writer. Esc to cancel."
+	}
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render(footer),
+	)
+}
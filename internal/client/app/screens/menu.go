@@ -34,6 +34,7 @@ func NewMenu(svc interfaces.Service, mode MenuMode) tea.Model {
 		m.choices = []string{
 			"Login",
 			"Register",
+			MenuOpenSend,
 			"About",
 		}
 	case MenuAuth:
@@ -41,7 +42,16 @@ func NewMenu(svc interfaces.Service, mode MenuMode) tea.Model {
 			"Show",
 			"Add",
 			"Sync",
+			MenuExport,
+			MenuSharedWithMe,
+			MenuVaults,
+			MenuEmergency,
+			MenuAccessTokens,
+			MenuSSHAgent,
+			MenuSend,
+			MenuOpenSend,
 			"About",
+			"Delete account",
 		}
 	}
 
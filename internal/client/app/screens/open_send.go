@@ -0,0 +1,166 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const MenuOpenSend = "Open a send"
+
+const (
+	openSendLink = iota
+	openSendPassword
+	openSendInputs
+)
+
+// openSendModel opens the send behind a link, which uses up one of its views.
+type openSendModel struct {
+	svc        interfaces.Service
+	inputs     []textinput.Model
+	focusIndex int
+	send       *models.OpenedSend
+	opening    bool
+	bodyHeight int
+}
+
+func NewOpenSend(svc interfaces.Service) tea.Model {
+	m := openSendModel{svc: svc, inputs: make([]textinput.Model, openSendInputs)}
+	for i := range m.inputs {
+		t := textinput.New()
+		t.Cursor.Style = styles.CursorStyle
+		t.Width = 48
+		switch i {
+		case openSendLink:
+			t.Placeholder = "Link"
+			t.CharLimit = 512
+			t.Focus()
+			t.PromptStyle = styles.FocusedStyle
+			t.TextStyle = styles.FocusedStyle
+		case openSendPassword:
+			t.Placeholder = "Access password (none)"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m openSendModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m openSendModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.SendOpenedMsg:
+		m.opening = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.send = msg.Send
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.send != nil {
+			if msg.String() == "esc" || msg.String() == "enter" {
+				return m, commands.BackToMenu
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if m.opening || strings.TrimSpace(m.inputs[openSendLink].Value()) == "" {
+					return m, nil
+				}
+				m.opening = true
+				return m, commands.OpenSend(m.svc, m.inputs[openSendLink].Value(), m.inputs[openSendPassword].Value())
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				if i == m.focusIndex {
+					cmds[i] = m.inputs[i].Focus()
+					m.inputs[i].PromptStyle = styles.FocusedStyle
+					m.inputs[i].TextStyle = styles.FocusedStyle
+					continue
+				}
+				m.inputs[i].Blur()
+				m.inputs[i].PromptStyle = styles.NoStyle
+				m.inputs[i].TextStyle = styles.NoStyle
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m openSendModel) View() string {
+	var b strings.Builder
+
+	if m.send != nil {
+		b.WriteString(styles.InputTextStyle.Render(string(m.send.Data)))
+		fmt.Fprintf(&b, "\n\n%d views left, expires on %s.", m.send.ViewsLeft, m.send.ExpiresAt.Local().Format(time.DateTime))
+		return lipgloss.JoinVertical(lipgloss.Top,
+			lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+			styles.FooterStyle.Render("Copy the secret now. Press Enter or Esc to return to the menu."),
+		)
+	}
+
+	b.WriteString("Opening a send uses up one of its views.\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i < len(m.inputs)-1 {
+			b.WriteRune('\n')
+		}
+	}
+
+	button := "Open"
+	if m.opening {
+		button = "Opening..."
+	}
+	if m.focusIndex == len(m.inputs) {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Esc to return to the menu."),
+	)
+}
@@ -0,0 +1,62 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/transport"
+)
+
+// pinMismatchModel stops all work with the server, it can only be left by quitting.
+type pinMismatchModel struct {
+	err        *transport.PinMismatchError
+	bodyHeight int
+}
+
+func NewPinMismatch(err *transport.PinMismatchError) tea.Model {
+	return pinMismatchModel{err: err}
+}
+
+func (m pinMismatchModel) Init() tea.Cmd {
+	return tea.WindowSize()
+}
+
+func (m pinMismatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "enter":
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+
+	}
+
+	return m, nil
+}
+
+func (m pinMismatchModel) View() string {
+	title := lipgloss.NewStyle().Foreground(styles.Error).Bold(true).Render("SERVER PUBLIC KEY MISMATCH")
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(title+fmt.Sprintf(`
+
+The server %s presented a certificate with the public key
+
+    %s
+
+which does not match any of the keys pinned in the "pins" setting of the
+[tls] table in config.toml. The connection was closed before anything was
+sent, and GophKeeper will not talk to this server.
+
+Either the connection is being intercepted, or the server key was replaced.
+Only if the administrator of the server confirms that the key was replaced,
+add the pin above to config.toml and start GophKeeper again.`, m.err.Host, m.err.Pin)),
+		styles.FooterStyle.Render("Press q to quit."),
+	)
+}
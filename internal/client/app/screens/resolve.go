@@ -49,6 +49,10 @@ func (m resolveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				record = m.serverRecord
 			}
 			record.Version = max(m.localRecord.Version, m.serverRecord.Version)
+			if len(record.Key) == 0 {
+				// Keep the record key, access tokens may have been given it.
+				record.Key = m.serverRecord.Key
+			}
 			return m, tea.Batch(commands.BackToMenu, commands.SaveRecord(m.svc, record))
 		}
 	case types.RecordPulledMsg:
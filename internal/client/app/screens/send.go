@@ -0,0 +1,205 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const MenuSend = "Send a secret"
+
+const (
+	sendSecret = iota
+	sendViews
+	sendExpiry
+	sendPassword
+	sendInputs
+)
+
+// sendModel creates a one-time send for someone without an account.
+type sendModel struct {
+	svc        interfaces.Service
+	inputs     []textinput.Model
+	focusIndex int
+	// link is shown once after the send is created.
+	link       string
+	creating   bool
+	bodyHeight int
+}
+
+func NewSend(svc interfaces.Service) tea.Model {
+	m := sendModel{svc: svc, inputs: make([]textinput.Model, sendInputs)}
+	for i := range m.inputs {
+		t := textinput.New()
+		t.Cursor.Style = styles.CursorStyle
+		t.Width = 48
+		switch i {
+		case sendSecret:
+			t.Placeholder = "Secret"
+			t.CharLimit = 4096
+			t.Focus()
+			t.PromptStyle = styles.FocusedStyle
+			t.TextStyle = styles.FocusedStyle
+		case sendViews:
+			t.Placeholder = "Views (1)"
+			t.CharLimit = 3
+		case sendExpiry:
+			t.Placeholder = "Expires in days (7)"
+			t.CharLimit = 2
+		case sendPassword:
+			t.Placeholder = "Access password (none)"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m sendModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m sendModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.SendCreatedMsg:
+		m.creating = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.link = msg.Link
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.link != "" {
+			if msg.String() == "esc" || msg.String() == "enter" {
+				return m, commands.BackToMenu
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if m.creating {
+					return m, nil
+				}
+				req, err := m.request()
+				if err != nil {
+					return m, commands.Error(err)
+				}
+				m.creating = true
+				return m, commands.CreateSend(m.svc, req)
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				if i == m.focusIndex {
+					cmds[i] = m.inputs[i].Focus()
+					m.inputs[i].PromptStyle = styles.FocusedStyle
+					m.inputs[i].TextStyle = styles.FocusedStyle
+					continue
+				}
+				m.inputs[i].Blur()
+				m.inputs[i].PromptStyle = styles.NoStyle
+				m.inputs[i].TextStyle = styles.NoStyle
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m sendModel) request() (*models.SendRequest, error) {
+	req := &models.SendRequest{Data: []byte(m.inputs[sendSecret].Value()), MaxViews: 1}
+	if len(req.Data) == 0 {
+		return nil, errors.New("secret is required")
+	}
+	if v := strings.TrimSpace(m.inputs[sendViews].Value()); v != "" {
+		var err error
+		if req.MaxViews, err = strconv.Atoi(v); err != nil || req.MaxViews < 1 {
+			return nil, errors.New("views must be a positive number")
+		}
+	}
+	days := 7
+	if v := strings.TrimSpace(m.inputs[sendExpiry].Value()); v != "" {
+		var err error
+		if days, err = strconv.Atoi(v); err != nil || days < 1 {
+			return nil, errors.New("expiry must be a positive number of days")
+		}
+	}
+	req.ExpiresAt = time.Now().AddDate(0, 0, days)
+	req.Password = m.inputs[sendPassword].Value()
+	return req, nil
+}
+
+func (m sendModel) View() string {
+	var b strings.Builder
+
+	if m.link != "" {
+		b.WriteString("Send created. Copy the link now, it is not shown again:\n\n")
+		b.WriteString(styles.InputTextStyle.Render(m.link))
+		b.WriteString("\n\nThe key is in the part after #, anyone holding the link can open the send.")
+		return lipgloss.JoinVertical(lipgloss.Top,
+			lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+			styles.FooterStyle.Render("Press Enter or Esc to return to the menu."),
+		)
+	}
+
+	b.WriteString("The secret is encrypted with a key that stays in the link.\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i < len(m.inputs)-1 {
+			b.WriteRune('\n')
+		}
+	}
+
+	button := "Create"
+	if m.creating {
+		button = "Creating..."
+	}
+	if m.focusIndex == len(m.inputs) {
+		button = styles.FocusedButtonStyle.Render(button)
+	} else {
+		button = styles.ButtonStyle.Render(button)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Esc to return to the menu."),
+	)
+}
@@ -0,0 +1,133 @@
+package screens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const MenuSharedWithMe = "Shared with me"
+
+// sharedModel shows the records other users shared. They are read-only and
+// fetched from the server each time.
+type sharedModel struct {
+	svc        interfaces.Service
+	records    []*models.SharedRecord
+	loaded     bool
+	cursor     int
+	selected   *models.SharedRecord
+	bodyHeight int
+}
+
+func NewShared(svc interfaces.Service) tea.Model {
+	return sharedModel{svc: svc}
+}
+
+func (m sharedModel) Init() tea.Cmd {
+	return tea.Batch(commands.FetchSharedRecords(m.svc), tea.WindowSize())
+}
+
+func (m sharedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.SharedRecordsMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.records = msg.Records
+		m.loaded = true
+		m.cursor = min(m.cursor, max(0, len(m.records)-1))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.selected != nil {
+			if msg.String() == "esc" || msg.String() == "enter" {
+				m.selected = nil
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "tab":
+			if m.cursor < len(m.records)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if len(m.records) > 0 {
+				m.selected = m.records[m.cursor]
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+	}
+
+	return m, nil
+}
+
+func (m sharedModel) View() string {
+	if m.selected != nil {
+		return m.detailView()
+	}
+
+	var b strings.Builder
+	switch {
+	case !m.loaded:
+		b.WriteString("Loading shared records...")
+	case len(m.records) == 0:
+		b.WriteString("Nothing is shared with you.")
+	default:
+		b.WriteString("Records shared with you:\n\n")
+		for i, record := range m.records {
+			cursor := " "
+			if m.cursor == i {
+				cursor = styles.CursorStyle.Render(">")
+			}
+			fmt.Fprintf(&b, "%s %s %s %s\n",
+				cursor,
+				styles.TypeStyle.Render(string(record.Type)),
+				truncateString(record.Owner, 16),
+				truncateString(string(record.Data), 20),
+			)
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Enter to view a record, Esc to return to the menu."),
+	)
+}
+
+func (m sharedModel) detailView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s shared by %s on %s\n\n",
+		styles.TypeStyle.Render(string(m.selected.Type)),
+		m.selected.Owner,
+		m.selected.SharedAt.Local().Format(time.DateTime),
+	)
+	var data bytes.Buffer
+	if err := json.Indent(&data, m.selected.Data, "", "  "); err != nil {
+		data.Reset()
+		data.Write(m.selected.Data)
+	}
+	b.Write(data.Bytes())
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("This record is read-only. Press Esc to return to the list."),
+	)
+}
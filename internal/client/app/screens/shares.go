@@ -0,0 +1,138 @@
+package screens
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// sharesModel lists the users a record is shared with and shares it with
+// the login typed in.
+type sharesModel struct {
+	svc        interfaces.Service
+	record     *models.Record
+	shares     []*models.Share
+	loaded     bool
+	cursor     int
+	login      textinput.Model
+	sharing    bool
+	bodyHeight int
+}
+
+func NewShares(svc interfaces.Service, record *models.Record) tea.Model {
+	login := textinput.New()
+	login.Placeholder = "Login to share with"
+	login.CharLimit = 64
+	login.Width = 32
+	login.Cursor.Style = styles.CursorStyle
+	login.PromptStyle = styles.FocusedStyle
+	login.TextStyle = styles.FocusedStyle
+	login.Focus()
+	return sharesModel{svc: svc, record: record, login: login}
+}
+
+func (m sharesModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize(), commands.ListShares(m.svc, m.record.ID))
+}
+
+func (m sharesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.SharesMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.shares = msg.Shares
+		m.loaded = true
+		m.cursor = min(m.cursor, max(0, len(m.shares)-1))
+		return m, nil
+
+	case types.RecordSharedMsg:
+		m.sharing = false
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.login.Reset()
+		return m, commands.ListShares(m.svc, m.record.ID)
+
+	case types.ShareRevokedMsg:
+		if msg.Err != nil {
+			return m, tea.Batch(commands.Error(msg.Err), commands.ListShares(m.svc, m.record.ID))
+		}
+		return m, commands.ListShares(m.svc, m.record.ID)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, commands.Select("Show")
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "tab":
+			if m.cursor < len(m.shares)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case "enter":
+			login := strings.TrimSpace(m.login.Value())
+			if login == "" || m.sharing {
+				return m, nil
+			}
+			m.sharing = true
+			return m, commands.ShareRecord(m.svc, m.record, login)
+		case "delete":
+			if len(m.shares) == 0 {
+				return m, nil
+			}
+			share := m.shares[m.cursor]
+			m.shares = append(m.shares[:m.cursor], m.shares[m.cursor+1:]...)
+			m.cursor = min(m.cursor, max(0, len(m.shares)-1))
+			return m, commands.RevokeShare(m.svc, m.record.ID, share.UserID)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.login, cmd = m.login.Update(msg)
+	return m, cmd
+}
+
+func (m sharesModel) View() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sharing %s %s\n\n", hex.EncodeToString(m.record.ID[:4]), styles.TypeStyle.Render(string(m.record.Type)))
+	switch {
+	case !m.loaded:
+		b.WriteString("Loading shares...\n")
+	case len(m.shares) == 0:
+		b.WriteString("Not shared with anyone.\n")
+	default:
+		for i, share := range m.shares {
+			cursor := " "
+			if m.cursor == i {
+				cursor = styles.CursorStyle.Render(">")
+			}
+			fmt.Fprintf(&b, "%s %s since %s\n", cursor, share.Login, share.CreatedAt.Local().Format(time.DateTime))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(m.login.View())
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press Enter to share, Del to revoke access, Esc to return to the list."),
+	)
+}
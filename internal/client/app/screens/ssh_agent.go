@@ -0,0 +1,121 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+)
+
+const MenuSSHAgent = "SSH agent"
+
+// sshAgentModel starts and stops the SSH agent serving the SSH keys of the
+// vault. The agent runs while the application runs.
+type sshAgentModel struct {
+	socket     string
+	bodyHeight int
+}
+
+// NewSSHAgent shows the agent listening on socket, which is empty when the
+// agent is stopped.
+func NewSSHAgent(socket string) tea.Model {
+	return sshAgentModel{socket: socket}
+}
+
+func (m sshAgentModel) Init() tea.Cmd {
+	return tea.WindowSize()
+}
+
+func (m sshAgentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			return m, commands.ToggleSSHAgent
+		case "esc", "q":
+			return m, commands.BackToMenu
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+
+	}
+
+	return m, nil
+}
+
+func (m sshAgentModel) View() string {
+	body := `The SSH agent is stopped.
+
+It serves the SSH keys of the vault to ssh, git and other programs through
+SSH_AUTH_SOCK while GophKeeper runs. Keys marked to confirm each use are
+only used after you allow it here.`
+	footer := "Press Enter to start the agent, Esc to return to the menu."
+	if m.socket != "" {
+		body = fmt.Sprintf(`The SSH agent is running. Use it in a shell with
+
+    export SSH_AUTH_SOCK=%s
+
+and check the keys with ssh-add -l.`, m.socket)
+		footer = "Press Enter to stop the agent, Esc to return to the menu."
+	}
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(body),
+		styles.FooterStyle.Render(footer),
+	)
+}
+
+// sshConfirmModel asks whether the SSH agent may use a key. It is shown over
+// the current screen until it is answered or the agent stops waiting.
+type sshConfirmModel struct {
+	req        types.SSHConfirmMsg
+	bodyHeight int
+}
+
+func NewSSHConfirm(req types.SSHConfirmMsg) tea.Model {
+	return sshConfirmModel{req: req}
+}
+
+func (m sshConfirmModel) Init() tea.Cmd {
+	return tea.WindowSize()
+}
+
+func (m sshConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y":
+			m.req.Answer(true)
+		case "n", "esc":
+			m.req.Answer(false)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+
+	}
+
+	return m, nil
+}
+
+func (m sshConfirmModel) View() string {
+	title := lipgloss.NewStyle().Foreground(styles.Error).Bold(true).Render("SSH KEY REQUESTED")
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(title+fmt.Sprintf(`
+
+A program asks the SSH agent to sign with the key
+
+    %s
+    %s
+
+Allow it only if you just started ssh, git or another program using the key.`, m.req.Name, m.req.Fingerprint)),
+		styles.FooterStyle.Render("Press y to allow, n to refuse."),
+	)
+}
@@ -0,0 +1,135 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const (
+	MenuAccessTokens   = "Access tokens"
+	MenuNewAccessToken = "New access token"
+)
+
+type tokensModel struct {
+	svc        interfaces.Service
+	tokens     []*models.AccessToken
+	loaded     bool
+	cursor     int
+	bodyHeight int
+}
+
+func NewTokens(svc interfaces.Service) tea.Model {
+	return tokensModel{svc: svc}
+}
+
+func (m tokensModel) Init() tea.Cmd {
+	return tea.Batch(commands.ListAccessTokens(m.svc), tea.WindowSize())
+}
+
+func (m tokensModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.AccessTokensMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.tokens = msg.Tokens
+		m.loaded = true
+		m.cursor = min(m.cursor, max(0, len(m.tokens)-1))
+		return m, nil
+
+	case types.AccessTokenRevokedMsg:
+		if msg.Err != nil {
+			return m, tea.Batch(commands.Error(msg.Err), commands.ListAccessTokens(m.svc))
+		}
+		return m, commands.ListAccessTokens(m.svc)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "tab":
+			if m.cursor < len(m.tokens)-1 {
+				m.cursor++
+			}
+		case "n":
+			return m, commands.Select(MenuNewAccessToken)
+		case "delete":
+			if len(m.tokens) == 0 {
+				return m, nil
+			}
+			token := m.tokens[m.cursor]
+			m.tokens = append(m.tokens[:m.cursor], m.tokens[m.cursor+1:]...)
+			m.cursor = min(m.cursor, max(0, len(m.tokens)-1))
+			return m, commands.RevokeAccessToken(m.svc, token.ID)
+		}
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+	}
+
+	return m, nil
+}
+
+func (m tokensModel) View() string {
+	var b strings.Builder
+	switch {
+	case !m.loaded:
+		b.WriteString("Loading access tokens...")
+	case len(m.tokens) == 0:
+		b.WriteString("No access tokens.")
+	default:
+		b.WriteString("Your access tokens:\n\n")
+		for i, token := range m.tokens {
+			cursor := " "
+			if m.cursor == i {
+				cursor = styles.CursorStyle.Render(">")
+			}
+			fmt.Fprintf(&b, "%s %s %s %s\n", cursor, styles.TypeStyle.Render(truncateString(token.Name, 10)),
+				tokenScope(token), tokenUsage(token))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render("Press n to create a token, Del to revoke it, Esc to return to the menu."),
+	)
+}
+
+func tokenScope(token *models.AccessToken) string {
+	access := "read-write"
+	if token.ReadOnly {
+		access = "read-only"
+	}
+	scope := "all records"
+	switch {
+	case len(token.Tags) > 0:
+		scope = fmt.Sprintf("%d records tagged %s", len(token.Records), strings.Join(token.Tags, ", "))
+	case token.Records != nil:
+		scope = fmt.Sprintf("%d records", len(token.Records))
+	}
+	return access + ", " + scope
+}
+
+func tokenUsage(token *models.AccessToken) string {
+	usage := "expires " + token.ExpiresAt.Local().Format(time.DateOnly)
+	if !token.ExpiresAt.After(time.Now()) {
+		usage = styles.StatusErrorStyle.UnsetWidth().Render("expired")
+	}
+	if !token.LastUsedAt.IsZero() {
+		usage += ", used " + token.LastUsedAt.Local().Format(time.DateTime)
+	}
+	return usage
+}
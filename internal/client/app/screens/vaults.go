@@ -0,0 +1,187 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grnsv/GophKeeper/internal/client/app/commands"
+	"github.com/grnsv/GophKeeper/internal/client/app/styles"
+	"github.com/grnsv/GophKeeper/internal/client/app/types"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const MenuVaults = "Vaults"
+
+// vaultsModel lists the personal vault and the team vaults of the
+// organizations the user is a member of. The first entry is the personal vault.
+type vaultsModel struct {
+	svc        interfaces.Service
+	orgs       []*models.Organization
+	loaded     bool
+	cursor     int
+	name       textinput.Model
+	creating   bool
+	bodyHeight int
+}
+
+func NewVaults(svc interfaces.Service) tea.Model {
+	name := textinput.New()
+	name.Placeholder = "Organization name"
+	name.CharLimit = 64
+	name.Width = 32
+	name.Cursor.Style = styles.CursorStyle
+	name.PromptStyle = styles.FocusedStyle
+	name.TextStyle = styles.FocusedStyle
+	return vaultsModel{svc: svc, name: name}
+}
+
+func (m vaultsModel) Init() tea.Cmd {
+	return tea.Batch(commands.ListOrgs(m.svc), tea.WindowSize())
+}
+
+func (m vaultsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case types.OrgsMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.orgs = msg.Orgs
+		m.loaded = true
+		m.cursor = min(m.cursor, len(m.orgs))
+		return m, nil
+
+	case types.OrgCreatedMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		m.name.Reset()
+		return m, commands.ListOrgs(m.svc)
+
+	case types.OrgDeletedMsg:
+		if msg.Err != nil {
+			return m, tea.Batch(commands.Error(msg.Err), commands.ListOrgs(m.svc))
+		}
+		return m, commands.ListOrgs(m.svc)
+
+	case types.VaultSwitchedMsg:
+		if msg.Err != nil {
+			return m, commands.Error(msg.Err)
+		}
+		return m, commands.Select("Show")
+
+	case tea.KeyMsg:
+		if m.creating {
+			return m.updateCreating(msg)
+		}
+		switch msg.String() {
+		case "esc":
+			return m, commands.BackToMenu
+		case "up", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "tab":
+			if m.cursor < len(m.orgs) {
+				m.cursor++
+			}
+		case "enter":
+			return m, commands.SwitchVault(m.svc, m.selected())
+		case "n":
+			m.creating = true
+			return m, m.name.Focus()
+		case "m":
+			if org := m.selected(); org != nil {
+				return m, commands.SelectOrg(org)
+			}
+		case "delete":
+			if org := m.selected(); org != nil {
+				return m, commands.DeleteOrg(m.svc, org)
+			}
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.bodyHeight = styles.CalcBodyHeight(msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.name, cmd = m.name.Update(msg)
+	return m, cmd
+}
+
+func (m vaultsModel) updateCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.creating = false
+		m.name.Blur()
+		m.name.Reset()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.name.Value())
+		if name == "" {
+			return m, nil
+		}
+		m.creating = false
+		m.name.Blur()
+		return m, commands.CreateOrg(m.svc, name)
+	}
+	var cmd tea.Cmd
+	m.name, cmd = m.name.Update(msg)
+	return m, cmd
+}
+
+// selected returns the organization under the cursor, nil for the personal vault.
+func (m vaultsModel) selected() *models.Organization {
+	if m.cursor == 0 || m.cursor > len(m.orgs) {
+		return nil
+	}
+	return m.orgs[m.cursor-1]
+}
+
+func (m vaultsModel) View() string {
+	current := m.svc.CurrentVault()
+	var b strings.Builder
+	b.WriteString(m.renderItem(0, "Personal", current == nil))
+	switch {
+	case !m.loaded:
+		b.WriteString("\nLoading organizations...\n")
+	default:
+		for i, org := range m.orgs {
+			name := fmt.Sprintf("%s %s", styles.TypeStyle.Render(string(org.Role)), org.Name)
+			if org.RekeyNeeded {
+				name += " " + styles.HelpStyle.Render("(re-key needed)")
+			}
+			b.WriteString(m.renderItem(i+1, name, current != nil && current.ID == org.ID))
+		}
+	}
+	if m.creating {
+		b.WriteString("\n")
+		b.WriteString(m.name.View())
+	}
+
+	footer := "Press Enter to open, n to create an organization, m to manage members, Del to delete it, Esc to return to the menu."
+	if m.creating {
+		footer = "Press Enter to create the organization, Esc to cancel."
+	}
+	return lipgloss.JoinVertical(lipgloss.Top,
+		lipgloss.NewStyle().Height(m.bodyHeight).Render(b.String()),
+		styles.FooterStyle.Render(footer),
+	)
+}
+
+func (m vaultsModel) renderItem(i int, name string, current bool) string {
+	cursor := " "
+	if m.cursor == i {
+		cursor = styles.CursorStyle.Render(">")
+	}
+	marker := " "
+	if current {
+		marker = "*"
+	}
+	return fmt.Sprintf("%s %s %s\n", cursor, marker, name)
+}
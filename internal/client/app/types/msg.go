@@ -25,6 +25,13 @@ type BackToMenuMsg struct{}
 
 type AuthMsg ErrMsg
 
+type RegistrationModeMsg struct {
+	Mode models.RegistrationMode
+	Err  error
+}
+
+type AccountDeletedMsg ErrMsg
+
 type RecordsMsg struct {
 	Records []*models.Record
 	Err     error
@@ -46,7 +53,7 @@ type DataMsg struct {
 }
 
 type MetadataMsg struct {
-	Metadata Metadata
+	Metadata models.Metadata
 }
 
 type ConflictMsg struct {
@@ -56,3 +63,111 @@ type ConflictMsg struct {
 type RecordPulledMsg struct {
 	Record *models.Record
 }
+
+type AccessTokensMsg struct {
+	Tokens []*models.AccessToken
+	Err    error
+}
+
+type AccessTokenCreatedMsg struct {
+	Token string
+	Err   error
+}
+
+type AccessTokenRevokedMsg ErrMsg
+
+type ShareSelectedMsg struct {
+	Record *models.Record
+}
+
+type SharesMsg struct {
+	Shares []*models.Share
+	Err    error
+}
+
+type RecordSharedMsg ErrMsg
+
+type ShareRevokedMsg ErrMsg
+
+type SharedRecordsMsg struct {
+	Records []*models.SharedRecord
+	Err     error
+}
+
+type OrgsMsg struct {
+	Orgs []*models.Organization
+	Err  error
+}
+
+type OrgCreatedMsg ErrMsg
+
+type OrgDeletedMsg ErrMsg
+
+type VaultSwitchedMsg ErrMsg
+
+type OrgSelectedMsg struct {
+	Org *models.Organization
+}
+
+type OrgMembersMsg struct {
+	Members []*models.OrgMember
+	Err     error
+}
+
+type OrgMembersChangedMsg struct {
+	// Left is set when the user removed themselves from the organization.
+	Left bool
+	Err  error
+}
+
+type EmergencyMsg struct {
+	Contacts []*models.EmergencyAccess
+	Grants   []*models.EmergencyAccess
+	Err      error
+}
+
+type EmergencyChangedMsg ErrMsg
+
+type EmergencyVaultMsg struct {
+	Login   string
+	Records []*models.Record
+	Err     error
+}
+
+type SendCreatedMsg struct {
+	Link string
+	Err  error
+}
+
+type ExportedMsg struct {
+	Path  string
+	Count int
+	Err   error
+}
+
+type SendOpenedMsg struct {
+	Send *models.OpenedSend
+	Err  error
+}
+
+type SSHAgentToggleMsg struct{}
+
+type SSHAgentMsg struct {
+	// Socket is empty when the agent stopped.
+	Socket string
+	Stop   func()
+	Err    error
+}
+
+// SSHConfirmMsg asks the user whether the SSH agent may use a key.
+type SSHConfirmMsg struct {
+	Name        string
+	Fingerprint string
+	Answer      func(allow bool)
+	// Done is closed once the agent no longer waits for the answer.
+	Done <-chan struct{}
+}
+
+type SSHConfirmDoneMsg struct {
+	Done <-chan struct{}
+}
@@ -10,11 +10,23 @@ import (
 	"github.com/grnsv/GophKeeper/internal/client/app/screens"
 	"github.com/grnsv/GophKeeper/internal/client/app/types"
 	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/transport"
 )
 
 var netErr *net.OpError
 
 func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.pinMismatch {
+		return m.updatePinMismatch(msg)
+	}
+	if m.sshConfirm != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.Type != tea.KeyCtrlC {
+			var cmd tea.Cmd
+			m.sshConfirm, cmd = m.sshConfirm.Update(msg)
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
@@ -26,6 +38,9 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = max(80, msg.Width)
 		msg.Width = m.width
+		if m.sshConfirm != nil {
+			m.sshConfirm, _ = m.sshConfirm.Update(msg)
+		}
 		newScreen, cmd := m.screen.Update(msg)
 		m.screen = newScreen
 		return m, cmd
@@ -56,6 +71,26 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.changeScreen(screen)
 		case "Sync":
 			return m, tea.Batch(m.trySync(), commands.BackToMenu)
+		case screens.MenuExport:
+			return m.changeScreen(screens.NewExport(m.svc))
+		case screens.MenuSharedWithMe:
+			return m.changeScreen(screens.NewShared(m.svc))
+		case screens.MenuVaults:
+			return m.changeScreen(screens.NewVaults(m.svc))
+		case screens.MenuEmergency:
+			return m.changeScreen(screens.NewEmergency(m.svc))
+		case screens.MenuAccessTokens:
+			return m.changeScreen(screens.NewTokens(m.svc))
+		case screens.MenuNewAccessToken:
+			return m.changeScreen(screens.NewCreateToken(m.svc))
+		case screens.MenuSend:
+			return m.changeScreen(screens.NewSend(m.svc))
+		case screens.MenuOpenSend:
+			return m.changeScreen(screens.NewOpenSend(m.svc))
+		case screens.MenuSSHAgent:
+			return m.changeScreen(screens.NewSSHAgent(m.sshAgent.socket))
+		case "Delete account":
+			return m.changeScreen(screens.NewDeleteAccount(m.svc))
 		}
 		return m, nil
 
@@ -72,6 +107,12 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m.changeScreen(screen)
 
+	case types.ShareSelectedMsg:
+		return m.changeScreen(screens.NewShares(m.svc, msg.Record))
+
+	case types.OrgSelectedMsg:
+		return m.changeScreen(screens.NewMembers(m.svc, msg.Org))
+
 	case types.BackToMenuMsg:
 		mode := screens.MenuGuest
 		if m.authenticated {
@@ -87,13 +128,54 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.authenticated = true
 		return m, tea.Batch(commands.SyncTick(), m.trySync(), commands.BackToMenu)
 
+	case types.AccountDeletedMsg:
+		if msg.Err != nil {
+			return m.handleError(msg.Err)
+		}
+		m.authenticated = false
+		m.hasConflicts = false
+		if m.sshAgent.stop != nil {
+			m.sshAgent.stop()
+			m.sshAgent.socket, m.sshAgent.stop = "", nil
+		}
+		return m, commands.BackToMenu
+
 	case types.SyncTickMsg:
+		if !m.authenticated {
+			return m, nil
+		}
 		return m, tea.Batch(commands.SyncTick(), m.trySync())
 
 	case types.SyncMsg:
 		m.hasConflicts = msg.HasConflicts
 		return m.handleError(msg.Err)
 
+	case types.SSHAgentToggleMsg:
+		if m.sshAgent.stop == nil {
+			return m, commands.StartSSHAgent(m.svc, m.sshAgent.confirmations)
+		}
+		m.sshAgent.stop()
+		m.sshAgent.socket, m.sshAgent.stop = "", nil
+		return m.changeScreen(screens.NewSSHAgent(""))
+
+	case types.SSHAgentMsg:
+		if msg.Err != nil {
+			return m.handleError(msg.Err)
+		}
+		m.sshAgent.socket, m.sshAgent.stop = msg.Socket, msg.Stop
+		return m.changeScreen(screens.NewSSHAgent(msg.Socket))
+
+	case types.SSHConfirmMsg:
+		m.sshConfirm = screens.NewSSHConfirm(msg)
+		m.sshConfirmDone = msg.Done
+		return m, tea.Batch(m.sshConfirm.Init(), commands.WaitSSHConfirmDone(msg.Done))
+
+	case types.SSHConfirmDoneMsg:
+		if m.sshConfirmDone == msg.Done {
+			m.sshConfirm, m.sshConfirmDone = nil, nil
+		}
+		return m, commands.WaitSSHConfirm(m.sshAgent.confirmations)
+
 	case types.ErrMsg:
 		return m.handleError(msg.Err)
 
@@ -108,6 +190,26 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updatePinMismatch only lets the pin mismatch screen handle keys and sizes,
+// results of requests still in flight and sync ticks are dropped.
+func (m appModel) updatePinMismatch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = max(80, msg.Width)
+		msg.Width = m.width
+	default:
+		return m, nil
+	}
+
+	newScreen, cmd := m.screen.Update(msg)
+	m.screen = newScreen
+	return m, cmd
+}
+
 func (m appModel) changeScreen(newScreen tea.Model) (tea.Model, tea.Cmd) {
 	m.screen = newScreen
 	return m, m.screen.Init()
@@ -119,6 +221,15 @@ func (m appModel) handleError(err error) (appModel, tea.Cmd) {
 		return m, nil
 	}
 
+	var pinErr *transport.PinMismatchError
+	if errors.As(err, &pinErr) {
+		m.pinMismatch = true
+		m.connected = false
+		m.errMsg = "The server cannot be trusted"
+		m.screen = screens.NewPinMismatch(pinErr)
+		return m, m.screen.Init()
+	}
+
 	m.errMsg = err.Error()
 	if errors.As(err, &netErr) {
 		m.connected = false
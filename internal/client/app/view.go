@@ -7,15 +7,22 @@ import (
 
 func (m appModel) View() string {
 	headerStyle := styles.HeaderStyle.Width(m.width)
+	screen := m.screen
+	if m.sshConfirm != nil {
+		screen = m.sshConfirm
+	}
 	return lipgloss.JoinVertical(lipgloss.Top,
 		headerStyle.Render(m.renderHeader(m.width-headerStyle.GetHorizontalPadding())),
 		styles.ErrorStyle.Width(m.width).Render(m.renderError()),
-		styles.BodyStyle.Width(m.width).Render(m.screen.View()),
+		styles.BodyStyle.Width(m.width).Render(screen.View()),
 	)
 }
 
 func (m appModel) renderHeader(width int) string {
 	title := "GophKeeper"
+	if vault := m.svc.CurrentVault(); m.authenticated && vault != nil {
+		title += " / " + vault.Name
+	}
 
 	var connBadge string
 	if m.connected {
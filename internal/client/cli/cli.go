@@ -0,0 +1,215 @@
+// Package cli implements the non-interactive subcommands of the client,
+// e.g. for scripts. They work with the session created by the login
+// subcommand.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/grnsv/GophKeeper/internal/client/agent"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+)
+
+// Exit codes of the subcommands.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitAuth     = 3
+	ExitNotFound = 4
+	ExitConflict = 5
+)
+
+var (
+	errNoSession = errors.New("not logged in")
+	errConflict  = errors.New("conflict")
+)
+
+// usageError is a wrong invocation of a subcommand.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// session is set for the commands that need a logged in user.
+	session bool
+	run     func(c *CLI, ctx context.Context, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{name: "login", args: "[--password-stdin] LOGIN", summary: "log in and print the session key", run: (*CLI).login},
+	{name: "logout", summary: "forget the saved session", run: (*CLI).logout},
+	{name: "agent", args: "[--idle DURATION]", summary: "run the agent holding the unlocked vault", run: (*CLI).runAgent},
+	{name: "lock", summary: "lock the agent", run: (*CLI).lock},
+	{name: "list", args: "[--type TYPE]", summary: "list the records", session: true, run: (*CLI).list},
+	{name: "get", args: "[--field FIELD] ID|NAME", summary: "print a record or one of its fields", session: true, run: (*CLI).get},
+	{name: "add", args: "[FIELD FLAGS] TYPE", summary: "add a record, secrets are read from stdin", session: true, run: (*CLI).add},
+	{name: "edit", args: "[FIELD FLAGS] ID|NAME", summary: "change a record, secrets are read from stdin", session: true, run: (*CLI).edit},
+	{name: "rm", args: "ID|NAME", summary: "delete a record", session: true, run: (*CLI).rm},
+	{name: "approve", args: "ID|NAME", summary: "approve a login captured by the browser extension and push it", session: true, run: (*CLI).approve},
+	{name: "sync", summary: "synchronize the records with the server", session: true, run: (*CLI).sync},
+	{name: "run", args: "[--env NAME=gk://RECORD/FIELD]... -- COMMAND [ARGS]", summary: "run a command with secrets in its environment", session: true, run: (*CLI).run},
+	{name: "inject", args: "[-i FILE] [-o FILE]", summary: "replace {{ gk://RECORD/FIELD }} in a template with secrets", session: true, run: (*CLI).inject},
+	{name: "git-credential", args: "get|store|erase", summary: "act as a git credential helper, see the README", session: true, run: (*CLI).gitCredential},
+	{name: "docker-credential", args: "get|store|erase|list", summary: "act as a docker credential helper, see the README", run: (*CLI).dockerCredential},
+	{name: "native-host", args: "[ORIGIN]...", summary: "serve the browser extension over native messaging, see the README", run: (*CLI).nativeHost},
+	{name: "ssh-agent", args: "[--socket PATH]", summary: "serve the SSH keys to ssh on SSH_AUTH_SOCK", session: true, run: (*CLI).runSSHAgent},
+	{name: "import", args: "[--format FORMAT] [--dry-run] FILE", summary: "import an archive or the export of another password manager", session: true, run: (*CLI).importRecords},
+	{name: "export", args: "[--format FORMAT] [-o FILE]", summary: "write all records to an encrypted archive, or as JSON or CSV", session: true, run: (*CLI).export},
+}
+
+type CLI struct {
+	svc   interfaces.Service
+	agent *agent.Client
+	// newAgentService returns the service the agent unlocks, which keeps
+	// the cache itself.
+	newAgentService func() interfaces.Service
+	// nativeHostOrigins are the browser extensions native-host serves.
+	nativeHostOrigins []string
+	stdin             io.Reader
+	stdout            io.Writer
+	stderr            io.Writer
+	// json is set by the --json flag of the running command.
+	json bool
+	// resume resumes the session of the running command once its flags are
+	// parsed, so that COMMAND -h works without a session. It is nil for
+	// commands without a session and after it ran.
+	resume func() error
+}
+
+func New(svc interfaces.Service, agentClient *agent.Client, newAgentService func() interfaces.Service, nativeHostOrigins []string) *CLI {
+	return &CLI{
+		svc:               svc,
+		agent:             agentClient,
+		newAgentService:   newAgentService,
+		nativeHostOrigins: nativeHostOrigins,
+		stdin:             os.Stdin,
+		stdout:            os.Stdout,
+		stderr:            os.Stderr,
+	}
+}
+
+// Run runs the subcommand in args[0] and returns the exit code.
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	if args[0] == "help" {
+		PrintCommands(c.stdout)
+		return ExitOK
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(c.stderr, "unknown command %q\n\n", args[0])
+		PrintCommands(c.stderr)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.BoolVar(&c.json, "json", false, "print JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\n", cmd.name, cmd.args)
+		fs.PrintDefaults()
+	}
+
+	c.resume = nil
+	if cmd.session {
+		c.resume = func() error { return c.resumeSession(ctx) }
+	}
+	err := cmd.run(c, ctx, fs, args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	var status exitStatus
+	if errors.As(err, &status) {
+		return int(status)
+	}
+	if errors.Is(err, interfaces.ErrUnauthorized) && cmd.session {
+		err = fmt.Errorf("%w: the session expired, log in again", err)
+	}
+	fmt.Fprintf(c.stderr, "%s: %v\n", cmd.name, err)
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.Is(err, errNoSession), errors.Is(err, interfaces.ErrInvalidSession), errors.Is(err, agent.ErrLocked),
+		errors.Is(err, interfaces.ErrUnauthorized), errors.Is(err, interfaces.ErrAccountDisabled):
+		return ExitAuth
+	case errors.Is(err, interfaces.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, errConflict), errors.Is(err, interfaces.ErrVersionConflict):
+		return ExitConflict
+	default:
+		return ExitError
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// PrintCommands lists the subcommands with the exit codes.
+func PrintCommands(w io.Writer) {
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nEvery command takes --json. Run COMMAND -h for its flags.")
+	fmt.Fprintln(w, "\nExit codes: 0 success, 1 error, 2 usage, 3 not logged in or session expired, 4 not found, 5 conflict.")
+}
+
+// parse parses the flags and checks the number of positional arguments, then
+// resumes the session of a command that needs one.
+func (c *CLI) parse(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return usagef("expected %d argument(s), got %d", nargs, fs.NArg())
+	}
+	return c.startSession()
+}
+
+// startSession runs resume once.
+func (c *CLI) startSession() error {
+	resume := c.resume
+	if resume == nil {
+		return nil
+	}
+	c.resume = nil
+	return resume()
+}
+
+func (c *CLI) printJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// Names the binary is installed under to act as a credential helper or as
+// the native messaging host of browsers.
+const (
+	gitHelperName    = "git-credential-gophkeeper"
+	dockerHelperName = "docker-credential-gophkeeper"
+	nativeHostName   = "gophkeeper-native-host"
+)
+
+// metaHelper is the metadata key marking the credentials a credential
+// helper stored, with the name of the helper. Only those are erased by the
+// helper, records of the user are left alone.
+const (
+	metaHelper   = "credential_helper"
+	helperGit    = "git"
+	helperDocker = "docker"
+)
+
+// errDockerNotFound is the message docker recognizes as missing credentials.
+var errDockerNotFound = errors.New("credentials not found in native keychain")
+
+// HelperCommand returns the command the binary runs when it is installed
+// under the name of a credential helper or of the native messaging host,
+// e.g. as a symlink.
+func HelperCommand(name string) (string, bool) {
+	switch strings.TrimSuffix(filepath.Base(name), ".exe") {
+	case gitHelperName:
+		return "git-credential", true
+	case dockerHelperName:
+		return "docker-credential", true
+	case nativeHostName:
+		return "native-host", true
+	}
+	return "", false
+}
+
+// credentialsFor returns the credentials that may be sent to target, see
+// models.Credentials.Matches. Those stored for its exact host come first,
+// then the others by name.
+func (c *CLI) credentialsFor(target *url.URL) ([]decodedRecord, error) {
+	records, err := c.records()
+	if err != nil {
+		return nil, err
+	}
+	var exact, others []decodedRecord
+	for _, record := range records {
+		if record.Type != models.RecordTypeCredentials {
+			continue
+		}
+		if !(models.Credentials{Resource: string(record.data.Fields["resource"])}).Matches(target) {
+			continue
+		}
+		if onHost(record, target) {
+			exact = append(exact, record)
+		} else {
+			others = append(others, record)
+		}
+	}
+	return append(exact, others...), nil
+}
+
+// onHost reports whether the credentials are stored for the host of target,
+// with the same port.
+func onHost(record decodedRecord, target *url.URL) bool {
+	u, err := models.ResourceURL(string(record.data.Fields["resource"]))
+	return err == nil && strings.EqualFold(u.Host, target.Host)
+}
+
+// newCredentials returns the data of new credentials stored by the helper.
+func newCredentials(helper, resource, login, password string) *exporter.Data {
+	return &exporter.Data{
+		Fields: map[string][]byte{
+			"resource": []byte(resource),
+			"login":    []byte(login),
+			"password": []byte(password),
+		},
+		Metadata: models.Metadata{metaHelper: helper},
+	}
+}
+
+// storedBy reports whether the helper stored the credentials.
+func storedBy(record decodedRecord, helper string) bool {
+	return record.data.Metadata[metaHelper] == helper
+}
+
+// gitCredential implements the protocol of git credential helpers: the
+// request is read from stdin as key=value lines, get prints the username
+// and the password. Nothing is printed when no credentials match, so git
+// asks the next helper or the user.
+func (c *CLI) gitCredential(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	req, err := readGitRequest(c.stdin)
+	if err != nil {
+		return err
+	}
+	// git sends the host with the port, and the protocol, which keeps
+	// credentials of https remotes from being sent over http.
+	target := &url.URL{Scheme: req["protocol"], Host: req["host"]}
+	matches, err := c.credentialsFor(target)
+	if err != nil {
+		return err
+	}
+	var matching []decodedRecord
+	for _, record := range matches {
+		if req["username"] == "" || string(record.data.Fields["login"]) == req["username"] {
+			matching = append(matching, record)
+		}
+	}
+
+	switch fs.Arg(0) {
+	case "get":
+		if len(matching) == 0 {
+			return nil
+		}
+		login, password := matching[0].data.Fields["login"], matching[0].data.Fields["password"]
+		if strings.ContainsAny(string(login)+string(password), "\n\x00") {
+			return errors.New("the credentials contain a newline or a NUL byte, which git cannot read")
+		}
+		_, err = fmt.Fprintf(c.stdout, "username=%s\npassword=%s\n", login, password)
+		return err
+	case "store":
+		if req["host"] == "" || req["username"] == "" || req["password"] == "" {
+			return nil
+		}
+		// Credentials of a parent domain are not changed, new ones are
+		// stored for the remote.
+		if len(matching) > 0 && onHost(matching[0], target) {
+			record := matching[0]
+			if string(record.data.Fields["password"]) == req["password"] {
+				return nil
+			}
+			record.data.Fields["password"] = []byte(req["password"])
+			_, err = c.push(ctx, record.Record, record.data)
+			return err
+		}
+		resource := req["host"]
+		if req["protocol"] != "" {
+			resource = req["protocol"] + "://" + resource
+		}
+		_, err = c.push(ctx, &models.Record{Type: models.RecordTypeCredentials}, newCredentials(helperGit, resource, req["username"], req["password"]))
+		return err
+	case "erase":
+		// git erases on every rejected credential, also on failures that
+		// are not the fault of the password. Only the records the helper
+		// stored are erased, and only with the rejected password, not one
+		// stored since.
+		for _, record := range matching {
+			if !storedBy(record, helperGit) || req["password"] != "" && string(record.data.Fields["password"]) != req["password"] {
+				continue
+			}
+			if err = c.svc.ForgetRecord(ctx, record.Record); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// Unknown operations are ignored as the protocol asks.
+		return nil
+	}
+}
+
+// readGitRequest reads key=value lines up to an empty line or the end.
+func readGitRequest(r io.Reader) (map[string]string, error) {
+	req := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, usagef("invalid line %q, expected key=value", line)
+		}
+		req[key] = value
+	}
+	return req, scanner.Err()
+}
+
+// dockerCredentials is the JSON of docker credential helpers.
+type dockerCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// dockerCredential implements the protocol of docker credential helpers.
+// Docker shows stdout on errors, so errors are printed there.
+func (c *CLI) dockerCredential(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	err := c.resumeSession(ctx)
+	if err == nil {
+		err = c.runDockerCredential(ctx, fs.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintln(c.stdout, err)
+		return exitStatus(ExitError)
+	}
+	return nil
+}
+
+func (c *CLI) runDockerCredential(ctx context.Context, action string) error {
+	switch action {
+	case "get":
+		serverURL, err := c.readServerURL()
+		if err != nil {
+			return err
+		}
+		record, err := c.dockerRecord(serverURL)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(c.stdout).Encode(dockerCredentials{
+			ServerURL: serverURL,
+			Username:  string(record.data.Fields["login"]),
+			Secret:    string(record.data.Fields["password"]),
+		})
+	case "store":
+		var creds dockerCredentials
+		if err := json.NewDecoder(c.stdin).Decode(&creds); err != nil {
+			return fmt.Errorf("invalid credentials: %w", err)
+		}
+		if creds.ServerURL == "" {
+			return errors.New("no server URL")
+		}
+		matches, err := c.dockerCredentialsFor(creds.ServerURL)
+		if err != nil {
+			return err
+		}
+		// Other credentials on the host, e.g. of git, are left alone.
+		i := slices.IndexFunc(matches, func(record decodedRecord) bool {
+			return string(record.data.Fields["resource"]) == creds.ServerURL
+		})
+		if i < 0 {
+			_, err = c.push(ctx, &models.Record{Type: models.RecordTypeCredentials}, newCredentials(helperDocker, creds.ServerURL, creds.Username, creds.Secret))
+			return err
+		}
+		record := matches[i]
+		if string(record.data.Fields["login"]) == creds.Username && string(record.data.Fields["password"]) == creds.Secret {
+			return nil
+		}
+		record.data.Fields["login"], record.data.Fields["password"] = []byte(creds.Username), []byte(creds.Secret)
+		_, err = c.push(ctx, record.Record, record.data)
+		return err
+	case "erase":
+		serverURL, err := c.readServerURL()
+		if err != nil {
+			return err
+		}
+		matches, err := c.dockerCredentialsFor(serverURL)
+		if err != nil {
+			return err
+		}
+		// Only what docker stored is erased.
+		erased := false
+		for _, record := range matches {
+			if !storedBy(record, helperDocker) || string(record.data.Fields["resource"]) != serverURL {
+				continue
+			}
+			if err = c.svc.ForgetRecord(ctx, record.Record); err != nil {
+				return err
+			}
+			erased = true
+		}
+		if !erased {
+			return errDockerNotFound
+		}
+		return nil
+	case "list":
+		records, err := c.records()
+		if err != nil {
+			return err
+		}
+		res := make(map[string]string)
+		for _, record := range records {
+			if resource := string(record.data.Fields["resource"]); record.Type == models.RecordTypeCredentials && resource != "" {
+				res[resource] = string(record.data.Fields["login"])
+			}
+		}
+		return json.NewEncoder(c.stdout).Encode(res)
+	default:
+		return usagef("unknown action %q, expected get, store, erase or list", action)
+	}
+}
+
+func (c *CLI) readServerURL() (string, error) {
+	data, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return "", err
+	}
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return "", errors.New("no server URL")
+	}
+	return serverURL, nil
+}
+
+// dockerCredentialsFor returns the credentials that may be sent to the
+// registry, a URL or a host.
+func (c *CLI) dockerCredentialsFor(serverURL string) ([]decodedRecord, error) {
+	target, err := models.ResourceURL(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	return c.credentialsFor(target)
+}
+
+// dockerRecord returns the credentials of the registry. Credentials stored
+// for the exact server URL come first, then any other credentials on its
+// host.
+func (c *CLI) dockerRecord(serverURL string) (decodedRecord, error) {
+	matches, err := c.dockerCredentialsFor(serverURL)
+	if err != nil {
+		return decodedRecord{}, err
+	}
+	if len(matches) == 0 {
+		return decodedRecord{}, errDockerNotFound
+	}
+	for _, record := range matches {
+		if string(record.data.Fields["resource"]) == serverURL {
+			return record, nil
+		}
+	}
+	return matches[0], nil
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/importer"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// importBatchSize is the number of records saved locally before they are
+// pushed by a sync, so that an interrupted import resumes with the next
+// sync.
+const importBatchSize = 100
+
+// importJSON is an item of an import as printed with --json.
+type importJSON struct {
+	Action      string            `json:"action"`
+	Type        models.RecordType `json:"type"`
+	Name        string            `json:"name,omitempty"`
+	DuplicateOf *uuid.UUID        `json:"duplicate_of,omitempty"`
+	ID          *uuid.UUID        `json:"id,omitempty"`
+}
+
+// importRecords imports the export of another password manager. Entries
+// already in the vault are skipped.
+func (c *CLI) importRecords(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", "", "read the export as `FORMAT`: "+strings.Join(importer.Formats, ", ")+", guessed by default")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without importing")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password of an encrypted export from stdin")
+	keyFile := fs.String("key-file", "", "open a KeePass database with the key file `FILE`")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	if *format != "" && !slices.Contains(importer.Formats, *format) {
+		return usagef("unknown format %q, expected one of %s", *format, strings.Join(importer.Formats, ", "))
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		if *format, err = importer.Detect(data); err != nil {
+			return usagef("%s: %v", fs.Arg(0), err)
+		}
+	}
+	var opts importer.Options
+	if *keyFile != "" {
+		if opts.KeyFile, err = os.ReadFile(*keyFile); err != nil {
+			return err
+		}
+	}
+	if *passwordStdin {
+		if opts.Password, err = c.readLine(); err != nil {
+			return err
+		}
+	}
+	result, err := importer.Parse(*format, data, opts)
+	if errors.Is(err, importer.ErrPasswordRequired) && !*passwordStdin {
+		if opts.Password, err = c.promptPassword("Password of the export: "); err != nil {
+			return err
+		}
+		result, err = importer.Parse(*format, data, opts)
+	}
+	if err != nil {
+		return err
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(c.stderr, "skipped %s\n", skipped)
+	}
+
+	records, err := c.svc.GetRecords()
+	if err != nil {
+		return err
+	}
+	plan := importer.Plan(result.Items, records)
+	ids := make([]uuid.UUID, len(plan))
+	if !*dryRun {
+		if err = c.importPlan(ctx, plan, ids); err != nil {
+			return err
+		}
+	}
+	return c.printImport(plan, ids, *dryRun)
+}
+
+// importPlan saves the new items as pending records and pushes them with a
+// sync every importBatchSize records. Once a sync fails the rest is only
+// saved, to be pushed by a later sync. ids receives the IDs of the records.
+func (c *CLI) importPlan(ctx context.Context, plan []importer.Planned, ids []uuid.UUID) error {
+	pending := 0
+	var syncErr error
+	sync := func() {
+		if pending == 0 || syncErr != nil {
+			return
+		}
+		hasConflicts, err := c.svc.Sync(ctx)
+		switch {
+		case err != nil:
+			syncErr = fmt.Errorf("the imported records are saved locally, run sync to push them: %w", err)
+		case hasConflicts:
+			syncErr = fmt.Errorf("%w: some records were changed on the server, resolve the conflicts in the terminal UI", errConflict)
+		}
+		pending = 0
+	}
+
+	for i, p := range plan {
+		if p.Skip() {
+			continue
+		}
+		record, err := p.Record()
+		if err != nil {
+			return err
+		}
+		if record.ID, err = c.newRecordID(); err != nil {
+			return err
+		}
+		record.Version = 1
+		record.Status = models.RecordStatusPending
+		if err = c.svc.SaveRecord(record); err != nil {
+			return err
+		}
+		ids[i] = record.ID
+		if pending++; pending == importBatchSize {
+			sync()
+		}
+	}
+	sync()
+	return syncErr
+}
+
+// newRecordID returns a random ID no record has.
+func (c *CLI) newRecordID() (uuid.UUID, error) {
+	for {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		exists, err := c.svc.IsRecordExists(id)
+		if err != nil || !exists {
+			return id, err
+		}
+	}
+}
+
+func (c *CLI) printImport(plan []importer.Planned, ids []uuid.UUID, dryRun bool) error {
+	added, skipped := 0, 0
+	for _, p := range plan {
+		if p.Skip() {
+			skipped++
+		} else {
+			added++
+		}
+	}
+
+	if c.json {
+		res := make([]importJSON, 0, len(plan))
+		for i, p := range plan {
+			item := importJSON{Action: "add", Type: p.Type, Name: p.Name()}
+			switch {
+			case p.DuplicateOf != uuid.Nil:
+				item.Action, item.DuplicateOf = "skip", &p.DuplicateOf
+			case p.Repeated:
+				item.Action = "skip"
+			case ids[i] != uuid.Nil:
+				item.ID = &ids[i]
+			}
+			res = append(res, item)
+		}
+		return c.printJSON(res)
+	}
+
+	if dryRun {
+		w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ACTION\tTYPE\tNAME\tDUPLICATE OF")
+		for _, p := range plan {
+			action, duplicate := "add", ""
+			switch {
+			case p.DuplicateOf != uuid.Nil:
+				action, duplicate = "skip", shortID(p.DuplicateOf)
+			case p.Repeated:
+				action, duplicate = "skip", "an earlier entry"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", action, p.Type, p.Name(), duplicate)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "\n%d to import, %d duplicates to skip\n", added, skipped)
+		return nil
+	}
+	fmt.Fprintf(c.stdout, "imported %d records, skipped %d duplicates\n", added, skipped)
+	return nil
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// referencePrefix starts a reference to a field of a record of the form
+// gk://RECORD/FIELD, where RECORD is a name or a prefix of an ID.
+const referencePrefix = "gk://"
+
+// templateReference matches a reference in a template: {{ gk://RECORD/FIELD }}.
+var templateReference = regexp.MustCompile(`\{\{\s*(gk://[^}]+?)\s*\}\}`)
+
+// exitStatus is the exit code of a child process, returned as is.
+type exitStatus int
+
+func (e exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// referenceResolver resolves references against the records of the local
+// cache, so it works offline.
+type referenceResolver struct {
+	records []decodedRecord
+}
+
+func (c *CLI) newReferenceResolver() (*referenceResolver, error) {
+	records, err := c.records()
+	if err != nil {
+		return nil, err
+	}
+	return &referenceResolver{records: records}, nil
+}
+
+func (r *referenceResolver) resolve(ref string) ([]byte, error) {
+	path, ok := strings.CutPrefix(ref, referencePrefix)
+	i := strings.LastIndex(path, "/")
+	if !ok || i <= 0 || i == len(path)-1 {
+		return nil, usagef("reference %q is not of the form %sRECORD/FIELD", ref, referencePrefix)
+	}
+	record, err := findRecord(r.records, path[:i])
+	if err != nil {
+		return nil, err
+	}
+	return record.field(path[i+1:])
+}
+
+func (c *CLI) run(_ context.Context, fs *flag.FlagSet, args []string) error {
+	env := make(map[string]string)
+	fs.Func("env", "set the variable `NAME=gk://RECORD/FIELD` for the command, may be repeated", func(s string) error {
+		name, ref, ok := strings.Cut(s, "=")
+		if !ok || name == "" || !strings.HasPrefix(ref, referencePrefix) {
+			return fmt.Errorf("expected NAME=%sRECORD/FIELD", referencePrefix)
+		}
+		env[name] = ref
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usagef("expected a command")
+	}
+	if err := c.startSession(); err != nil {
+		return err
+	}
+
+	// Variables of the environment whose value is a reference are resolved too.
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if _, ok := env[name]; !ok && strings.HasPrefix(value, referencePrefix) {
+			env[name] = value
+		}
+	}
+	resolver, err := c.newReferenceResolver()
+	if err != nil {
+		return err
+	}
+	// The session key is not passed on, the command gets only the secrets.
+	environ := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, SessionEnv+"=")
+	})
+	for name, ref := range env {
+		value, err := resolver.resolve(ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if bytes.IndexByte(value, 0) >= 0 {
+			return fmt.Errorf("%s: %s contains a NUL byte and cannot be put into the environment", name, ref)
+		}
+		environ = append(environ, name+"="+string(value))
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Env = environ
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = c.stdin, c.stdout, c.stderr
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	// The command gets the signals meant for the client, which waits for it.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitStatus(exitErr.ExitCode())
+	}
+	return err
+}
+
+func (c *CLI) inject(_ context.Context, fs *flag.FlagSet, args []string) error {
+	input := fs.String("i", "", "read the template from `FILE` instead of stdin")
+	output := fs.String("o", "", "write to `FILE` instead of stdout")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var template []byte
+	var err error
+	if *input == "" {
+		template, err = io.ReadAll(c.stdin)
+	} else {
+		template, err = os.ReadFile(*input)
+	}
+	if err != nil {
+		return err
+	}
+	resolver, err := c.newReferenceResolver()
+	if err != nil {
+		return err
+	}
+	// Nothing is written unless every reference resolves.
+	var resolveErr error
+	rendered := templateReference.ReplaceAllFunc(template, func(match []byte) []byte {
+		ref := string(templateReference.FindSubmatch(match)[1])
+		value, err := resolver.resolve(ref)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return value
+	})
+	if resolveErr != nil {
+		return resolveErr
+	}
+
+	if *output == "" {
+		_, err = c.stdout.Write(rendered)
+		return err
+	}
+	return os.WriteFile(*output, rendered, 0600)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/grnsv/GophKeeper/internal/client/agent"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/nativemsg"
+)
+
+// nativeHost serves the browser extension on stdin and stdout. The browser
+// starts it with its own arguments, which are not flags: the origin of the
+// extension for Chrome, the path of the manifest and the ID of the
+// extension for Firefox. The session is resumed on the first request and
+// again once the agent locked, so that the host keeps answering. Only the
+// extensions in native_host.allowed_origins of config.toml are served.
+func (c *CLI) nativeHost(ctx context.Context, _ *flag.FlagSet, args []string) error {
+	origin := ""
+	switch {
+	case len(args) > 0 && strings.Contains(args[0], "://"):
+		origin = args[0]
+	case len(args) > 1:
+		origin = args[1]
+	}
+
+	attached := false
+	open := func() error {
+		if attached {
+			return nil
+		}
+		if err := c.resumeSession(ctx); err != nil {
+			return fmt.Errorf("%w: %w", nativemsg.ErrLocked, err)
+		}
+		attached = true
+		return nil
+	}
+	// locked forgets the session when the agent locked or stopped.
+	locked := func(err error) error {
+		if errors.Is(err, agent.ErrLocked) || errors.Is(err, agent.ErrNotRunning) {
+			attached = false
+			return fmt.Errorf("%w: %w", nativemsg.ErrLocked, err)
+		}
+		return err
+	}
+	records := func() ([]*models.Record, error) {
+		if err := open(); err != nil {
+			return nil, err
+		}
+		records, err := c.svc.GetRecords()
+		return records, locked(err)
+	}
+	// save keeps captured credentials local until the user approves them
+	// with the approve command or in the terminal UI.
+	save := func(_ context.Context, record *models.Record) (*models.Record, error) {
+		if err := open(); err != nil {
+			return nil, err
+		}
+		record, err := c.svc.CaptureRecord(record)
+		return record, locked(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(c.stderr, nil))
+	logger.Info("native messaging host started", slog.String("origin", origin))
+	host := nativemsg.New(origin, c.nativeHostOrigins, records, save, nativemsg.DialogApprove(logger), logger)
+	return host.Serve(ctx, c.stdin, c.stdout)
+}
@@ -0,0 +1,556 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+)
+
+// nameKey is the metadata key holding the name of a record.
+const nameKey = "name"
+
+// fieldSpec is a field of the data of a record type. Secret fields are
+// never taken from flags, only from stdin.
+type fieldSpec struct {
+	name   string
+	secret bool
+}
+
+var recordFields = map[models.RecordType][]fieldSpec{
+	models.RecordTypeCredentials: {{name: "resource"}, {name: "login"}, {name: "password", secret: true}},
+	models.RecordTypeText:        {{name: "text", secret: true}},
+	models.RecordTypeBinary:      {{name: "binary", secret: true}},
+	models.RecordTypeCard:        {{name: "number", secret: true}, {name: "exp"}, {name: "cvv", secret: true}, {name: "holder"}},
+	// The public key and the fingerprint follow from the private key.
+	models.RecordTypeSSHKey: {{name: "private_key", secret: true}, {name: "public_key"}, {name: "fingerprint"}, {name: "comment"}, {name: "confirm"}},
+}
+
+// decodedRecord is a record with its decoded data.
+type decodedRecord struct {
+	*models.Record
+	data *exporter.Data
+}
+
+// records returns the records of the local cache that are not deleted,
+// sorted by name.
+func (c *CLI) records() ([]decodedRecord, error) {
+	records, err := c.svc.GetRecords()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]decodedRecord, 0, len(records))
+	for _, record := range records {
+		if record.Status == models.RecordStatusDeleted {
+			continue
+		}
+		data, err := exporter.Decode(record)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, decodedRecord{Record: record, data: data})
+	}
+	slices.SortFunc(res, func(a, b decodedRecord) int {
+		if n := strings.Compare(a.data.Name(), b.data.Name()); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return res, nil
+}
+
+// resolveRecord finds a record by its name or a prefix of its ID.
+func (c *CLI) resolveRecord(ref string) (decodedRecord, error) {
+	records, err := c.records()
+	if err != nil {
+		return decodedRecord{}, err
+	}
+	return findRecord(records, ref)
+}
+
+func findRecord(records []decodedRecord, ref string) (decodedRecord, error) {
+	var byName, byID []decodedRecord
+	prefix := strings.ToLower(strings.ReplaceAll(ref, "-", ""))
+	for _, record := range records {
+		if record.data.Name() == ref {
+			byName = append(byName, record)
+		}
+		if prefix != "" && strings.HasPrefix(hex.EncodeToString(record.ID[:]), prefix) {
+			byID = append(byID, record)
+		}
+	}
+	matches := byName
+	if len(matches) == 0 {
+		matches = byID
+	}
+	switch len(matches) {
+	case 0:
+		return decodedRecord{}, fmt.Errorf("record %q: %w", ref, interfaces.ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, record := range matches {
+		ids = append(ids, shortID(record.ID))
+	}
+	return decodedRecord{}, fmt.Errorf("record %q is ambiguous, it matches %s", ref, strings.Join(ids, ", "))
+}
+
+// field returns the value of a field, or of the metadata key of the form
+// metadata.KEY.
+func (r decodedRecord) field(name string) ([]byte, error) {
+	value, ok := r.data.Fields[name]
+	if key, isMeta := strings.CutPrefix(name, "metadata."); isMeta {
+		var meta string
+		meta, ok = r.data.Metadata[key]
+		value = []byte(meta)
+	}
+	if !ok {
+		return nil, fmt.Errorf("field %q of a %s record: %w", name, r.Type, interfaces.ErrNotFound)
+	}
+	return value, nil
+}
+
+func shortID(id uuid.UUID) string {
+	return hex.EncodeToString(id[:4])
+}
+
+func (c *CLI) list(_ context.Context, fs *flag.FlagSet, args []string) error {
+	recordType := fs.String("type", "", "list only records of `TYPE`")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if _, ok := recordFields[models.RecordType(*recordType)]; *recordType != "" && !ok {
+		return usagef("unknown record type %q", *recordType)
+	}
+
+	records, err := c.records()
+	if err != nil {
+		return err
+	}
+	records = slices.DeleteFunc(records, func(record decodedRecord) bool {
+		return *recordType != "" && record.Type != models.RecordType(*recordType)
+	})
+
+	if c.json {
+		res := make([]exporter.Entry, 0, len(records))
+		for _, record := range records {
+			res = append(res, exporter.NewEntry(record.Record, record.data, false))
+		}
+		return c.printJSON(res)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tNAME")
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortID(record.ID), record.Type, record.Status, record.data.Name())
+	}
+	return w.Flush()
+}
+
+func (c *CLI) get(_ context.Context, fs *flag.FlagSet, args []string) error {
+	field := fs.String("field", "", "print only `FIELD`, or the metadata key of the form metadata.KEY")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	record, err := c.resolveRecord(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *field != "" {
+		value, err := record.field(*field)
+		if err != nil {
+			return err
+		}
+		if c.json {
+			if record.Type == models.RecordTypeBinary && *field == "binary" {
+				return c.printJSON(map[string]string{*field: base64.StdEncoding.EncodeToString(value)})
+			}
+			return c.printJSON(map[string]string{*field: string(value)})
+		}
+		if _, err = c.stdout.Write(value); err != nil {
+			return err
+		}
+		if record.Type != models.RecordTypeBinary {
+			fmt.Fprintln(c.stdout)
+		}
+		return nil
+	}
+
+	if c.json {
+		return c.printJSON(exporter.NewEntry(record.Record, record.data, true))
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "id:\t%s\ntype:\t%s\nstatus:\t%s\n", record.ID, record.Type, record.Status)
+	for _, spec := range recordFields[record.Type] {
+		value := string(record.data.Fields[spec.name])
+		if record.Type == models.RecordTypeBinary || spec.name == "private_key" {
+			value = fmt.Sprintf("%d bytes", len(record.data.Fields[spec.name]))
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", spec.name, value)
+	}
+	keys := make([]string, 0, len(record.data.Metadata))
+	for key := range record.data.Metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "metadata.%s:\t%s\n", key, record.data.Metadata[key])
+	}
+	return w.Flush()
+}
+
+// recordFlags are the flags of add and edit.
+type recordFlags struct {
+	name    *string
+	meta    map[string]string
+	stdin   *bool
+	file    *string
+	keyType *string
+	fields  map[string]*string
+}
+
+func newRecordFlags(fs *flag.FlagSet) *recordFlags {
+	f := &recordFlags{meta: make(map[string]string), fields: make(map[string]*string)}
+	f.name = fs.String("name", "", "set the name of the record")
+	fs.Func("meta", "set the metadata `KEY=VALUE`, an empty value removes the key, may be repeated", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return errors.New("expected KEY=VALUE")
+		}
+		f.meta[key] = value
+		return nil
+	})
+	f.stdin = fs.Bool("stdin", false, "read the secret fields from stdin: the password, the text, the binary content, the card number and CVV on two lines, or the private key to import")
+	f.file = fs.String("file", "", "read the content of a binary record, or the private key to import, from `FILE`")
+	f.keyType = fs.String("key-type", sshagent.KeyTypeEd25519, "generate a key of `TYPE` for a new SSH key, ed25519 or rsa")
+	f.fields["resource"] = fs.String("resource", "", "set the resource of credentials")
+	f.fields["login"] = fs.String("login", "", "set the login of credentials")
+	f.fields["exp"] = fs.String("exp", "", "set the expiry date of a card")
+	f.fields["holder"] = fs.String("holder", "", "set the holder of a card")
+	f.fields["comment"] = fs.String("comment", "", "set the comment of an SSH key")
+	f.fields["confirm"] = fs.String("confirm", "", "set whether the SSH agent asks before every use of an SSH key, true or false")
+	return f
+}
+
+// apply applies the flags that were set to the data of a record of the type.
+func (f *recordFlags) apply(c *CLI, fs *flag.FlagSet, recordType models.RecordType, data *exporter.Data) error {
+	specs := recordFields[recordType]
+	isField := func(name string) bool {
+		return slices.ContainsFunc(specs, func(spec fieldSpec) bool { return spec.name == name })
+	}
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch {
+		case err != nil:
+		case fl.Name == "name":
+			data.Metadata[nameKey] = *f.name
+		case fl.Name == "file":
+			switch recordType {
+			case models.RecordTypeBinary:
+				data.Fields["binary"], err = os.ReadFile(*f.file)
+			case models.RecordTypeSSHKey:
+			default:
+				err = usagef("--file is only for binary records and SSH keys")
+			}
+		case fl.Name == "key-type":
+			if recordType != models.RecordTypeSSHKey {
+				err = usagef("--key-type is only for SSH keys")
+			} else if *f.keyType != sshagent.KeyTypeEd25519 && *f.keyType != sshagent.KeyTypeRSA {
+				err = usagef("--key-type must be %s or %s", sshagent.KeyTypeEd25519, sshagent.KeyTypeRSA)
+			}
+		case fl.Name == "confirm":
+			var confirm bool
+			if confirm, err = strconv.ParseBool(*f.fields["confirm"]); err != nil {
+				err = usagef("--confirm must be true or false")
+				return
+			}
+			if !isField(fl.Name) {
+				err = usagef("a %s record has no %s", recordType, fl.Name)
+				return
+			}
+			data.Fields["confirm"] = []byte(strconv.FormatBool(confirm))
+		case f.fields[fl.Name] != nil:
+			if !isField(fl.Name) {
+				err = usagef("a %s record has no %s", recordType, fl.Name)
+				return
+			}
+			data.Fields[fl.Name] = []byte(*f.fields[fl.Name])
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for key, value := range f.meta {
+		if value == "" {
+			delete(data.Metadata, key)
+		} else {
+			data.Metadata[key] = value
+		}
+	}
+	if data.Metadata[nameKey] == "" {
+		delete(data.Metadata, nameKey)
+	}
+	if recordType == models.RecordTypeSSHKey {
+		return f.applySSHKey(c, data)
+	}
+	if !*f.stdin {
+		return nil
+	}
+	if *f.file != "" {
+		return usagef("--stdin and --file cannot be used together")
+	}
+
+	input, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return err
+	}
+	switch recordType {
+	case models.RecordTypeBinary:
+		data.Fields["binary"] = input
+	case models.RecordTypeText:
+		data.Fields["text"] = []byte(strings.TrimSuffix(string(input), "\n"))
+	default:
+		lines := strings.Split(strings.TrimRight(string(input), "\r\n"), "\n")
+		var secrets []string
+		for _, spec := range specs {
+			if spec.secret {
+				secrets = append(secrets, spec.name)
+			}
+		}
+		if len(lines) != len(secrets) {
+			return usagef("expected %s on stdin, one per line", strings.Join(secrets, " and "))
+		}
+		for i, name := range secrets {
+			data.Fields[name] = []byte(strings.TrimSuffix(lines[i], "\r"))
+		}
+	}
+	return nil
+}
+
+// applySSHKey imports the private key of --stdin or --file, generates a key
+// for a new record, or else puts the comment into the stored key.
+func (f *recordFlags) applySSHKey(c *CLI, data *exporter.Data) error {
+	var pemBytes []byte
+	var err error
+	switch {
+	case *f.stdin && *f.file != "":
+		return usagef("--stdin and --file cannot be used together")
+	case *f.stdin:
+		pemBytes, err = io.ReadAll(c.stdin)
+	case *f.file != "":
+		pemBytes, err = os.ReadFile(*f.file)
+	}
+	if err != nil {
+		return err
+	}
+
+	comment := string(data.Fields["comment"])
+	var key models.SSHKey
+	switch {
+	case pemBytes != nil:
+		key, err = sshagent.Import(pemBytes, nil, comment)
+		if _, isTerminal := c.terminal(); errors.Is(err, sshagent.ErrPassphraseMissing) {
+			if *f.stdin || !isTerminal {
+				return fmt.Errorf("%w in a terminal, with --file", err)
+			}
+			var passphrase string
+			if passphrase, err = c.promptPassword("Passphrase: "); err == nil {
+				key, err = sshagent.Import(pemBytes, []byte(passphrase), comment)
+			}
+		}
+	case len(data.Fields["private_key"]) == 0:
+		key, err = sshagent.Generate(*f.keyType, comment)
+	default:
+		key, err = sshagent.SetComment(models.SSHKey{PrivateKey: string(data.Fields["private_key"])}, comment)
+	}
+	if err != nil {
+		return err
+	}
+	data.Fields["private_key"], data.Fields["public_key"] = []byte(key.PrivateKey), []byte(key.PublicKey)
+	if len(data.Fields["confirm"]) == 0 {
+		data.Fields["confirm"] = []byte(strconv.FormatBool(false))
+	}
+	return nil
+}
+
+func (c *CLI) add(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	flags := newRecordFlags(fs)
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	recordType := models.RecordType(fs.Arg(0))
+	if _, ok := recordFields[recordType]; !ok {
+		return usagef("unknown record type %q, expected credentials, text, binary, card or ssh_key", recordType)
+	}
+
+	data := &exporter.Data{Fields: make(map[string][]byte), Metadata: make(models.Metadata)}
+	if err := flags.apply(c, fs, recordType, data); err != nil {
+		return err
+	}
+	record := &models.Record{Type: recordType}
+	return c.save(ctx, record, data)
+}
+
+func (c *CLI) edit(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	flags := newRecordFlags(fs)
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	record, err := c.resolveRecord(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err = flags.apply(c, fs, record.Type, record.data); err != nil {
+		return err
+	}
+	return c.save(ctx, record.Record, record.data)
+}
+
+// save pushes the record and prints its ID.
+func (c *CLI) save(ctx context.Context, record *models.Record, data *exporter.Data) error {
+	record, err := c.push(ctx, record, data)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(map[string]string{"id": record.ID.String()})
+	}
+	fmt.Fprintln(c.stdout, record.ID)
+	return nil
+}
+
+// push pushes the record like the terminal UI does. The record is kept
+// locally even if pushing it fails.
+func (c *CLI) push(ctx context.Context, record *models.Record, data *exporter.Data) (*models.Record, error) {
+	var err error
+	if record.Data, err = exporter.Encode(record.Type, data); err != nil {
+		return nil, err
+	}
+	record, err = c.svc.PushRecord(ctx, record)
+	if err != nil {
+		if record.ID != uuid.Nil {
+			return nil, fmt.Errorf("record %s is saved locally but not pushed, run sync later: %w", shortID(record.ID), err)
+		}
+		return nil, err
+	}
+	if record.Status == models.RecordStatusConflict {
+		return nil, fmt.Errorf("%w: record %s was changed on the server, resolve the conflict in the terminal UI", errConflict, shortID(record.ID))
+	}
+	return record, nil
+}
+
+func (c *CLI) rm(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	record, err := c.resolveRecord(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.svc.ForgetRecord(ctx, record.Record)
+}
+
+// approve pushes a record captured by the browser extension, which is kept
+// local until then.
+func (c *CLI) approve(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	record, err := c.resolveRecord(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if record.Status != models.RecordStatusCaptured {
+		return usagef("record %s is %s, only captured records need approval", shortID(record.ID), record.Status)
+	}
+	return c.save(ctx, record.Record, record.data)
+}
+
+func (c *CLI) sync(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	hasConflicts, err := c.svc.Sync(ctx)
+	if err != nil {
+		return err
+	}
+	if hasConflicts {
+		return fmt.Errorf("%w: some records were changed on the server, resolve the conflicts in the terminal UI", errConflict)
+	}
+	return nil
+}
+
+func (c *CLI) export(_ context.Context, fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", exporter.FormatArchive, "write `FORMAT`: archive encrypted with a password, or json or csv in plaintext")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password of the archive from stdin")
+	output := fs.String("o", "", "write to `FILE` instead of stdout")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if !slices.Contains(exporter.Formats, *format) {
+		return usagef("unknown format %q, expected one of %s", *format, strings.Join(exporter.Formats, ", "))
+	}
+	records, err := c.svc.GetRecords()
+	if err != nil {
+		return err
+	}
+
+	password := ""
+	if exporter.Plaintext(*format) {
+		fmt.Fprintln(c.stderr, "warning: the export is not encrypted, keep it safe and delete it when done")
+	} else if password, err = c.archivePassword(*passwordStdin); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = exporter.Write(&buf, *format, records, password); err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = c.stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0600)
+}
+
+// archivePassword reads the password of a new archive, asking twice on the
+// terminal.
+func (c *CLI) archivePassword(passwordStdin bool) (string, error) {
+	if passwordStdin {
+		password, err := c.readLine()
+		if err == nil && password == "" {
+			err = usagef("the password of the archive is empty")
+		}
+		return password, err
+	}
+	password, err := c.promptPassword("Password of the archive: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", usagef("the password of the archive is empty")
+	}
+	again, err := c.promptPassword("Repeat the password: ")
+	if err != nil {
+		return "", err
+	}
+	if again != password {
+		return "", usagef("the passwords do not match")
+	}
+	return password, nil
+}
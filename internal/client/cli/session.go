@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/grnsv/GophKeeper/internal/client/agent"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// SessionEnv is the environment variable holding the session key printed
+// by login. The session file alone does not give access to the records.
+const SessionEnv = "GOPHKEEPER_SESSION"
+
+const sessionKeySize = 32
+
+func (c *CLI) login(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	var password string
+	var err error
+	if *passwordStdin {
+		password, err = c.readLine()
+	} else {
+		password, err = c.promptPassword("Password: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	userID, err := c.svc.Login(ctx, fs.Arg(0), password)
+	if err != nil {
+		return err
+	}
+	sessionKey := make([]byte, sessionKeySize)
+	if _, err = rand.Read(sessionKey); err != nil {
+		return err
+	}
+	session, err := c.svc.Session(sessionKey)
+	if err != nil {
+		return err
+	}
+	if err = saveSession(session); err != nil {
+		return err
+	}
+
+	encodedKey := base64.RawURLEncoding.EncodeToString(sessionKey)
+	if c.json {
+		return c.printJSON(map[string]string{"session": encodedKey, "user_id": userID, "login": session.Login})
+	}
+	fmt.Fprintf(c.stdout, "export %s=%s\n", SessionEnv, encodedKey)
+	return nil
+}
+
+func (c *CLI) logout(_ context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// resumeSession attaches to the agent when it is unlocked, otherwise it
+// opens the saved session with the key in SessionEnv.
+func (c *CLI) resumeSession(ctx context.Context) error {
+	err := c.agent.Attach(ctx, c.svc)
+	if err == nil || !errors.Is(err, agent.ErrNotRunning) && !errors.Is(err, agent.ErrLocked) {
+		return err
+	}
+
+	encodedKey := os.Getenv(SessionEnv)
+	if encodedKey == "" {
+		return fmt.Errorf("%w: run login and set %s", errNoSession, SessionEnv)
+	}
+	sessionKey, err := base64.RawURLEncoding.DecodeString(encodedKey)
+	if err != nil || len(sessionKey) != sessionKeySize {
+		return fmt.Errorf("%w: %s is not a session key", errNoSession, SessionEnv)
+	}
+	session, err := loadSession()
+	if err != nil {
+		return err
+	}
+	return c.svc.ResumeSession(session, sessionKey)
+}
+
+func sessionPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "GophKeeper", "session.json"), nil
+}
+
+func saveSession(session *models.Session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func loadSession() (*models.Session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: run login first", errNoSession)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session models.Session
+	if err = json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("%w: %s is damaged: %w", errNoSession, path, err)
+	}
+	return &session, nil
+}
+
+// promptPassword reads a password from the terminal without echoing it.
+func (c *CLI) promptPassword(prompt string) (string, error) {
+	f, ok := c.terminal()
+	if !ok {
+		return "", usagef("stdin is not a terminal, pass the password with --password-stdin")
+	}
+	fmt.Fprint(c.stderr, prompt)
+	password, err := term.ReadPassword(f.Fd())
+	fmt.Fprintln(c.stderr)
+	return string(password), err
+}
+
+// terminal returns stdin if it is a terminal.
+func (c *CLI) terminal() (*os.File, bool) {
+	f, ok := c.stdin.(*os.File)
+	return f, ok && term.IsTerminal(f.Fd())
+}
+
+// readLine reads the first line of stdin.
+func (c *CLI) readLine() (string, error) {
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *CLI) runAgent(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	idle := fs.Duration("idle", agent.DefaultIdleTimeout, "lock the vault after it was not used for `DURATION`, 0 never locks")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewTextHandler(c.stderr, nil))
+	return agent.New(c.newAgentService, *idle, logger).ListenAndServe(ctx)
+}
+
+func (c *CLI) lock(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	return c.agent.Lock(ctx)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+)
+
+// sshAgentSyncInterval is the interval of the sync of the records while the
+// SSH agent runs, which keeps the cache open.
+const sshAgentSyncInterval = time.Minute
+
+// runSSHAgent serves the SSH keys of the vault until it is interrupted. Keys
+// requiring confirmation are refused, there is nobody to ask.
+func (c *CLI) runSSHAgent(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	socket := fs.String("socket", "", "listen on `PATH` instead of the socket next to the agent")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	path := *socket
+	if path == "" {
+		var err error
+		if path, err = sshagent.DefaultSocket(); err != nil {
+			return err
+		}
+	}
+	listener, err := sshagent.Listen(path)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		err = c.printJSON(map[string]string{"socket": path})
+	} else {
+		_, err = fmt.Fprintf(c.stdout, "export SSH_AUTH_SOCK=%s\n", path)
+	}
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	// The service is not safe for concurrent use, the sync and the
+	// connections take turns.
+	var mu sync.Mutex
+	records := func() ([]*models.Record, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return c.svc.GetRecords()
+	}
+	logger := slog.New(slog.NewTextHandler(c.stderr, nil))
+	go func() {
+		ticker := time.NewTicker(sshAgentSyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			mu.Lock()
+			if _, err := c.svc.Sync(ctx); err != nil {
+				logger.Warn("sync failed", slog.Any("error", err))
+			}
+			mu.Unlock()
+		}
+	}()
+
+	logger.Info("ssh agent started", slog.String("socket", path))
+	return sshagent.New(records, nil, logger).Serve(ctx, listener)
+}
@@ -1,14 +1,67 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/grnsv/GophKeeper/internal/client/transport"
+	"github.com/grnsv/GophKeeper/internal/telemetry"
 	"github.com/pelletier/go-toml/v2"
 )
 
 type Config struct {
 	ServerAddress string `toml:"server_address"`
+	// Proxy overrides the HTTP_PROXY and HTTPS_PROXY environment variables.
+	Proxy      string           `toml:"proxy,omitempty"`
+	TLS        TLSConfig        `toml:"tls"`
+	Tracing    TracingConfig    `toml:"tracing"`
+	NativeHost NativeHostConfig `toml:"native_host"`
+}
+
+// TLSConfig configures how the server is trusted and how the client
+// authenticates to servers requiring mutual TLS.
+type TLSConfig struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system ones.
+	CAFile string `toml:"ca_file,omitempty"`
+	// Pins are SHA-256 hashes of server or CA public keys in the form sha256/BASE64.
+	Pins     []string `toml:"pins,omitempty"`
+	CertFile string   `toml:"cert_file,omitempty"`
+	KeyFile  string   `toml:"key_file,omitempty"`
+}
+
+func (cfg *Config) Transport() transport.Options {
+	return transport.Options{
+		CAFile:   cfg.TLS.CAFile,
+		Pins:     cfg.TLS.Pins,
+		CertFile: cfg.TLS.CertFile,
+		KeyFile:  cfg.TLS.KeyFile,
+		Proxy:    cfg.Proxy,
+	}
+}
+
+// NativeHostConfig lists the browser extensions served by the native-host
+// command: chrome-extension://ID/ origins for Chrome and extension IDs for
+// Firefox, as the browser passes them.
+type NativeHostConfig struct {
+	AllowedOrigins []string `toml:"allowed_origins,omitempty"`
+}
+
+// TracingConfig selects where traces of sync runs and server requests go:
+// none, otlp (an OTLP/HTTP collector at endpoint) or file.
+type TracingConfig struct {
+	Exporter string `toml:"exporter"`
+	Endpoint string `toml:"endpoint,omitempty"`
+	File     string `toml:"file,omitempty"`
+}
+
+func (cfg TracingConfig) Telemetry() telemetry.TracingConfig {
+	return telemetry.TracingConfig{
+		Exporter: cfg.Exporter,
+		Endpoint: cfg.Endpoint,
+		File:     cfg.File,
+	}
 }
 
 func getConfigPath() (string, error) {
@@ -42,6 +95,9 @@ func Parse() (*Config, error) {
 
 	cfg := &Config{
 		ServerAddress: "http://localhost:8080",
+		Tracing: TracingConfig{
+			Exporter: telemetry.ExporterNone,
+		},
 	}
 
 	data, err := os.ReadFile(configFile)
@@ -53,6 +109,15 @@ func Parse() (*Config, error) {
 			return nil, err
 		}
 	}
+	if (cfg.TLS.CertFile == "") != (cfg.TLS.KeyFile == "") {
+		return nil, errors.New("tls: cert_file and key_file must be set together")
+	}
+	if cfg.Tracing.Exporter == telemetry.ExporterStdout {
+		return nil, errors.New("tracing: the stdout exporter would break the terminal UI, use file instead")
+	}
+	if err = cfg.Tracing.Telemetry().Validate(); err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
 	if err = saveConfig(configFile, cfg); err != nil {
 		return nil, err
 	}
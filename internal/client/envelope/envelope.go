@@ -0,0 +1,94 @@
+// Package envelope is the envelope encryption of the vault, shared with the
+// export archives: data is sealed with AES-GCM under a key of its own, which
+// is wrapped with AES-GCM under the key of the vault or the archive.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+// KeySize is the size of every key, AES-256.
+const KeySize = 32
+
+// NewKey returns a random key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NewGCM returns AES-GCM with the key.
+func NewGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WrapKey encrypts key with AES-GCM under kek, the nonce is prepended.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	aesGCM, err := NewGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aesGCM.Seal(nonce, nonce, key, nil), nil
+}
+
+// UnwrapKey decrypts a key wrapped by WrapKey.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	aesGCM, err := NewGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aesGCM.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, sealed := wrapped[:aesGCM.NonceSize()], wrapped[aesGCM.NonceSize():]
+	return aesGCM.Open(nil, nonce, sealed, nil)
+}
+
+// Seal encrypts data with a new key, which is returned wrapped with kek.
+func Seal(kek, data []byte) (wrappedKey, nonce, ciphertext []byte, err error) {
+	key, err := NewKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if wrappedKey, err = WrapKey(kek, key); err != nil {
+		return nil, nil, nil, err
+	}
+	aesGCM, err := NewGCM(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	nonce = make([]byte, aesGCM.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, nil, err
+	}
+	return wrappedKey, nonce, aesGCM.Seal(nil, nonce, data, nil), nil
+}
+
+// Open decrypts data sealed by Seal.
+func Open(kek, wrappedKey, nonce, ciphertext []byte) ([]byte, error) {
+	key, err := UnwrapKey(kek, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aesGCM, err := NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aesGCM.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return aesGCM.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,90 @@
+package envelope_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/envelope"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := envelope.NewKey()
+	if err != nil {
+		t.Fatalf("NewKey: %v", err)
+	}
+	if len(key) != envelope.KeySize {
+		t.Fatalf("key of %d bytes, want %d", len(key), envelope.KeySize)
+	}
+	return key
+}
+
+func TestWrapKey(t *testing.T) {
+	kek, key := newKey(t), newKey(t)
+	wrapped, err := envelope.WrapKey(kek, key)
+	if err != nil {
+		t.Fatalf("WrapKey: %v", err)
+	}
+	if bytes.Contains(wrapped, key) {
+		t.Fatal("the wrapped key holds the key")
+	}
+	got, err := envelope.UnwrapKey(kek, wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKey: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Fatal("UnwrapKey returned another key")
+	}
+
+	if _, err = envelope.UnwrapKey(newKey(t), wrapped); err == nil {
+		t.Fatal("UnwrapKey with another key succeeded")
+	}
+	if _, err = envelope.UnwrapKey(kek, wrapped[:4]); err == nil {
+		t.Fatal("UnwrapKey of a truncated key succeeded")
+	}
+}
+
+func TestSeal(t *testing.T) {
+	kek := newKey(t)
+	data := []byte("secret data")
+	wrappedKey, nonce, ciphertext, err := envelope.Seal(kek, data)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(ciphertext, data) {
+		t.Fatal("the ciphertext holds the data")
+	}
+	got, err := envelope.Open(kek, wrappedKey, nonce, ciphertext)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Open returned %q, want %q", got, data)
+	}
+
+	// Every seal has a key of its own.
+	otherKey, _, _, err := envelope.Seal(kek, data)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Equal(otherKey, wrappedKey) {
+		t.Fatal("two seals share their key")
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[0] ^= 1
+	tests := map[string]func() ([]byte, error){
+		"another key":      func() ([]byte, error) { return envelope.Open(newKey(t), wrappedKey, nonce, ciphertext) },
+		"another data key": func() ([]byte, error) { return envelope.Open(kek, otherKey, nonce, ciphertext) },
+		"tampered data":    func() ([]byte, error) { return envelope.Open(kek, wrappedKey, nonce, tampered) },
+		"truncated nonce":  func() ([]byte, error) { return envelope.Open(kek, wrappedKey, nonce[:4], ciphertext) },
+		"invalid key size": func() ([]byte, error) { return envelope.Open(kek[:5], wrappedKey, nonce, ciphertext) },
+	}
+	for name, open := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := open(); err == nil {
+				t.Fatal("Open succeeded")
+			}
+		})
+	}
+}
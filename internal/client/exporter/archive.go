@@ -0,0 +1,162 @@
+package exporter
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/envelope"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"golang.org/x/crypto/argon2"
+)
+
+// archiveFormat and archiveVersion identify archives. The version is raised
+// whenever the encryption changes, readers refuse versions they do not know.
+const (
+	archiveFormat  = "gophkeeper-archive"
+	archiveVersion = 1
+)
+
+// The key derivation parameters of new archives are those of the vault key.
+// Archives asking for more than the limits are refused as damaged.
+const (
+	archiveKDF        = "argon2id"
+	archiveTime       = 3
+	archiveMemory     = 128 * 1024
+	archiveThreads    = 4
+	archiveMaxTime    = 16
+	archiveMaxMemory  = 2 * 1024 * 1024
+	archiveMaxThreads = 16
+	saltSize          = 16
+)
+
+var (
+	// ErrPasswordRequired is returned for archives without a password.
+	ErrPasswordRequired = errors.New("the archive needs a password")
+	// ErrWrongPassword is returned when the archive cannot be decrypted
+	// with the password.
+	ErrWrongPassword = errors.New("wrong password, or the archive is damaged")
+	errArchive       = errors.New("invalid archive")
+)
+
+// archive is the file of an encrypted export. It is enveloped like the
+// vault, with package envelope: the archive key is wrapped with the key
+// derived from the password, every record has its own key wrapped with the
+// archive key, and the data of the record is encrypted with its key.
+type archive struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	KDF     archiveParams   `json:"kdf"`
+	Key     []byte          `json:"key"`
+	Records []archiveRecord `json:"records"`
+}
+
+type archiveParams struct {
+	Algorithm string `json:"algorithm"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	// Memory is in KiB.
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+type archiveRecord struct {
+	ID      uuid.UUID         `json:"id"`
+	Type    models.RecordType `json:"type"`
+	Version int               `json:"version"`
+	Key     []byte            `json:"key"`
+	Nonce   []byte            `json:"nonce"`
+	Data    []byte            `json:"data"`
+}
+
+// IsArchive reports whether data looks like an archive, whatever its
+// version.
+func IsArchive(data []byte) bool {
+	var probe struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Format == archiveFormat
+}
+
+func writeArchive(w io.Writer, records []*models.Record, password string) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	a := archive{
+		Format:  archiveFormat,
+		Version: archiveVersion,
+		KDF: archiveParams{
+			Algorithm: archiveKDF,
+			Salt:      make([]byte, saltSize),
+			Time:      archiveTime,
+			Memory:    archiveMemory,
+			Threads:   archiveThreads,
+		},
+		Records: make([]archiveRecord, 0, len(records)),
+	}
+	if _, err := rand.Read(a.KDF.Salt); err != nil {
+		return err
+	}
+	archiveKey, err := envelope.NewKey()
+	if err != nil {
+		return err
+	}
+	if a.Key, err = envelope.WrapKey(a.KDF.key(password), archiveKey); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		r := archiveRecord{ID: record.ID, Type: record.Type, Version: record.Version}
+		if r.Key, r.Nonce, r.Data, err = envelope.Seal(archiveKey, record.Data); err != nil {
+			return err
+		}
+		a.Records = append(a.Records, r)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// ReadArchive decrypts an archive. The records hold their decrypted data
+// and keep the ID, type and version they had in the exported vault.
+func ReadArchive(data []byte, password string) ([]*models.Record, error) {
+	var a archive
+	if err := json.Unmarshal(data, &a); err != nil || a.Format != archiveFormat {
+		return nil, fmt.Errorf("%w: not a GophKeeper archive", errArchive)
+	}
+	if a.Version != archiveVersion {
+		return nil, fmt.Errorf("archive version %d is not supported, update GophKeeper", a.Version)
+	}
+	p := a.KDF
+	if p.Algorithm != archiveKDF || len(p.Salt) == 0 ||
+		p.Time == 0 || p.Time > archiveMaxTime ||
+		p.Threads == 0 || p.Threads > archiveMaxThreads ||
+		p.Memory < 8*uint32(p.Threads) || p.Memory > archiveMaxMemory {
+		return nil, fmt.Errorf("%w: invalid key derivation parameters", errArchive)
+	}
+	if password == "" {
+		return nil, ErrPasswordRequired
+	}
+	archiveKey, err := envelope.UnwrapKey(p.key(password), a.Key)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	records := make([]*models.Record, 0, len(a.Records))
+	for _, r := range a.Records {
+		plain, err := envelope.Open(archiveKey, r.Key, r.Nonce, r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %s: %w", errArchive, r.ID, err)
+		}
+		records = append(records, &models.Record{ID: r.ID, Type: r.Type, Version: r.Version, Data: plain})
+	}
+	return records, nil
+}
+
+func (p archiveParams) key(password string) []byte {
+	return argon2.IDKey([]byte(password), p.Salt, p.Time, p.Memory, p.Threads, envelope.KeySize)
+}
@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+)
+
+// nameKey is the metadata key holding the name of a record.
+const nameKey = "name"
+
+// Data is the decrypted data of a record of any type as named fields, the
+// fields of the command-line interface and of the plaintext exports.
+type Data struct {
+	Fields   map[string][]byte
+	Metadata models.Metadata
+}
+
+// Decode returns the fields of the decrypted data of the record. The
+// fingerprint of SSH keys is computed from the private key.
+func Decode(record *models.Record) (*Data, error) {
+	data := &Data{Fields: make(map[string][]byte)}
+	var err error
+	switch record.Type {
+	case models.RecordTypeCredentials:
+		var v models.Credentials
+		err = json.Unmarshal(record.Data, &v)
+		data.Fields["resource"], data.Fields["login"], data.Fields["password"] = []byte(v.Resource), []byte(v.Login), []byte(v.Password)
+		data.Metadata = v.Metadata
+	case models.RecordTypeText:
+		var v models.Text
+		err = json.Unmarshal(record.Data, &v)
+		data.Fields["text"] = []byte(v.Text)
+		data.Metadata = v.Metadata
+	case models.RecordTypeBinary:
+		var v models.Binary
+		err = json.Unmarshal(record.Data, &v)
+		data.Fields["binary"] = v.Binary
+		data.Metadata = v.Metadata
+	case models.RecordTypeCard:
+		var v models.Card
+		err = json.Unmarshal(record.Data, &v)
+		data.Fields["number"], data.Fields["exp"], data.Fields["cvv"], data.Fields["holder"] = []byte(v.CardNumber), []byte(v.EXP), []byte(v.CVV), []byte(v.CardHolder)
+		data.Metadata = v.Metadata
+	case models.RecordTypeSSHKey:
+		var v models.SSHKey
+		if err = json.Unmarshal(record.Data, &v); err == nil {
+			var fingerprint string
+			fingerprint, err = sshagent.Fingerprint(v)
+			data.Fields["fingerprint"] = []byte(fingerprint)
+		}
+		data.Fields["private_key"], data.Fields["public_key"], data.Fields["comment"] = []byte(v.PrivateKey), []byte(v.PublicKey), []byte(v.Comment)
+		data.Fields["confirm"] = []byte(strconv.FormatBool(v.Confirm))
+		data.Metadata = v.Metadata
+	default:
+		return nil, fmt.Errorf("record %s: unknown record type %q", record.ID, record.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("record %s: %w", record.ID, err)
+	}
+	if data.Metadata == nil {
+		data.Metadata = make(models.Metadata)
+	}
+	return data, nil
+}
+
+// Encode returns the data of a record of the type holding the fields.
+// Fields that follow from others, like the fingerprint, are left out.
+func Encode(recordType models.RecordType, data *Data) ([]byte, error) {
+	f := func(name string) string { return string(data.Fields[name]) }
+	switch recordType {
+	case models.RecordTypeCredentials:
+		return json.Marshal(models.Credentials{Resource: f("resource"), Login: f("login"), Password: f("password"), Metadata: data.Metadata})
+	case models.RecordTypeText:
+		return json.Marshal(models.Text{Text: f("text"), Metadata: data.Metadata})
+	case models.RecordTypeBinary:
+		return json.Marshal(models.Binary{Binary: data.Fields["binary"], Metadata: data.Metadata})
+	case models.RecordTypeCard:
+		return json.Marshal(models.Card{CardNumber: f("number"), EXP: f("exp"), CVV: f("cvv"), CardHolder: f("holder"), Metadata: data.Metadata})
+	case models.RecordTypeSSHKey:
+		return json.Marshal(models.SSHKey{PrivateKey: f("private_key"), PublicKey: f("public_key"), Comment: f("comment"), Confirm: f("confirm") == "true", Metadata: data.Metadata})
+	default:
+		return nil, fmt.Errorf("unknown record type %q", recordType)
+	}
+}
+
+// Name returns the name metadata of the record, or the resource of
+// credentials and the comment of SSH keys without one.
+func (d *Data) Name() string {
+	if name := d.Metadata[nameKey]; name != "" {
+		return name
+	}
+	if resource := d.Fields["resource"]; len(resource) > 0 {
+		return string(resource)
+	}
+	return string(d.Fields["comment"])
+}
+
+// Entry is a record as written to plaintext JSON exports and printed by the
+// command-line interface with --json. Binary content is base64 encoded.
+type Entry struct {
+	ID       uuid.UUID           `json:"id"`
+	Type     models.RecordType   `json:"type"`
+	Status   models.RecordStatus `json:"status"`
+	Version  int                 `json:"version"`
+	Name     string              `json:"name,omitempty"`
+	Fields   map[string]string   `json:"fields,omitempty"`
+	Metadata models.Metadata     `json:"metadata,omitempty"`
+}
+
+// NewEntry returns the entry of the record with its decoded data, with the
+// fields only if withFields is set.
+func NewEntry(record *models.Record, data *Data, withFields bool) Entry {
+	e := Entry{
+		ID:       record.ID,
+		Type:     record.Type,
+		Status:   record.Status,
+		Version:  record.Version,
+		Name:     data.Name(),
+		Metadata: data.Metadata,
+	}
+	if withFields {
+		e.Fields = make(map[string]string, len(data.Fields))
+		for name, value := range data.Fields {
+			if record.Type == models.RecordTypeBinary {
+				e.Fields[name] = base64.StdEncoding.EncodeToString(value)
+			} else {
+				e.Fields[name] = string(value)
+			}
+		}
+	}
+	return e
+}
@@ -0,0 +1,127 @@
+// Package exporter writes the records of a vault to a file: an archive
+// encrypted with a password, which the importer reads back into any
+// account, or plaintext JSON and CSV for other password managers.
+package exporter
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// Formats of exports.
+const (
+	// FormatArchive is the encrypted archive.
+	FormatArchive = "archive"
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+)
+
+// Formats lists the formats in the order they are documented.
+var Formats = []string{FormatArchive, FormatJSON, FormatCSV}
+
+// Plaintext reports whether the format leaves the records unencrypted.
+func Plaintext(format string) bool {
+	return format != FormatArchive
+}
+
+// csvFields are the columns of the fields of all record types, between
+// the ID, type and name and the metadata.
+var csvFields = []string{
+	"resource", "login", "password",
+	"text",
+	"binary",
+	"number", "exp", "cvv", "holder",
+	"private_key", "public_key", "fingerprint", "comment", "confirm",
+}
+
+// Write writes the decrypted records in the format, leaving out the deleted
+// ones. The password encrypts the archive and is not used by the other
+// formats.
+func Write(w io.Writer, format string, records []*models.Record, password string) error {
+	records = exported(records)
+	switch format {
+	case FormatArchive:
+		return writeArchive(w, records, password)
+	case FormatJSON:
+		return writeJSON(w, records)
+	case FormatCSV:
+		return writeCSV(w, records)
+	default:
+		return fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// exported returns the records that are not deleted, by name.
+func exported(records []*models.Record) []*models.Record {
+	res := make([]*models.Record, 0, len(records))
+	names := make(map[uuid.UUID]string, len(records))
+	for _, record := range records {
+		if record.Status == models.RecordStatusDeleted {
+			continue
+		}
+		res = append(res, record)
+		if data, err := Decode(record); err == nil {
+			names[record.ID] = data.Name()
+		}
+	}
+	slices.SortFunc(res, func(a, b *models.Record) int {
+		return cmp.Or(cmp.Compare(names[a.ID], names[b.ID]), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return res
+}
+
+func writeJSON(w io.Writer, records []*models.Record) error {
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		data, err := Decode(record)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, NewEntry(record, data, true))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// writeCSV writes a row per record with the columns of every record type.
+// The metadata is a JSON object in the last column.
+func writeCSV(w io.Writer, records []*models.Record) error {
+	cw := csv.NewWriter(w)
+	header := append(append([]string{"id", "type", "name"}, csvFields...), "metadata")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		data, err := Decode(record)
+		if err != nil {
+			return err
+		}
+		e := NewEntry(record, data, true)
+		row := []string{e.ID.String(), string(e.Type), e.Name}
+		for _, field := range csvFields {
+			row = append(row, e.Fields[field])
+		}
+		metadata := ""
+		if len(e.Metadata) > 0 {
+			b, err := json.Marshal(e.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(b)
+		}
+		if err = cw.Write(append(row, metadata)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
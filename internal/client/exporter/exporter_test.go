@@ -0,0 +1,191 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+)
+
+func newRecord(t *testing.T, recordType models.RecordType, status models.RecordStatus, data any) *models.Record {
+	t.Helper()
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.Record{ID: uuid.New(), Type: recordType, Status: status, Version: 3, Data: b}
+}
+
+// testRecords returns a record of every type, by name, and a deleted one.
+func testRecords(t *testing.T) []*models.Record {
+	t.Helper()
+	key, err := sshagent.Generate(sshagent.KeyTypeEd25519, "deploy@ci")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return []*models.Record{
+		newRecord(t, models.RecordTypeCredentials, models.RecordStatusSynced, models.Credentials{Resource: "example.com", Login: "alice", Password: "secret", Metadata: models.Metadata{"name": "a"}}),
+		newRecord(t, models.RecordTypeText, models.RecordStatusPending, models.Text{Text: "note", Metadata: models.Metadata{"name": "b"}}),
+		newRecord(t, models.RecordTypeBinary, models.RecordStatusSynced, models.Binary{Binary: []byte{0, 1, 2}, Metadata: models.Metadata{"name": "c"}}),
+		newRecord(t, models.RecordTypeCard, models.RecordStatusSynced, models.Card{CardNumber: "4111111111111111", EXP: "03/30", CVV: "123", CardHolder: "Alice", Metadata: models.Metadata{"name": "d"}}),
+		newRecord(t, models.RecordTypeSSHKey, models.RecordStatusSynced, key),
+		newRecord(t, models.RecordTypeText, models.RecordStatusDeleted, models.Text{Text: "deleted"}),
+	}
+}
+
+func TestArchive(t *testing.T) {
+	records := testRecords(t)
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatArchive, records, "password"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data := buf.Bytes()
+	if !IsArchive(data) {
+		t.Fatal("IsArchive did not recognize the archive")
+	}
+	for _, record := range records {
+		if bytes.Contains(data, record.Data) {
+			t.Fatalf("the archive holds the data of record %s", record.ID)
+		}
+	}
+
+	got, err := ReadArchive(data, "password")
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	want := records[:len(records)-1]
+	if len(got) != len(want) {
+		t.Fatalf("%d records, want %d", len(got), len(want))
+	}
+	for i, record := range got {
+		w := want[i]
+		if record.ID != w.ID || record.Type != w.Type || record.Version != w.Version || !bytes.Equal(record.Data, w.Data) {
+			t.Fatalf("record %d is %+v, want %+v", i, record, w)
+		}
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		if _, err := ReadArchive(data, "wrong"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("ReadArchive returned %v, want %v", err, ErrWrongPassword)
+		}
+	})
+	t.Run("no password", func(t *testing.T) {
+		if _, err := ReadArchive(data, ""); !errors.Is(err, ErrPasswordRequired) {
+			t.Fatalf("ReadArchive returned %v, want %v", err, ErrPasswordRequired)
+		}
+		if err := Write(&bytes.Buffer{}, FormatArchive, records, ""); !errors.Is(err, ErrPasswordRequired) {
+			t.Fatalf("Write returned %v, want %v", err, ErrPasswordRequired)
+		}
+	})
+
+	change := func(t *testing.T, f func(a *archive)) []byte {
+		t.Helper()
+		var a archive
+		if err := json.Unmarshal(data, &a); err != nil {
+			t.Fatal(err)
+		}
+		f(&a)
+		b, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := ReadArchive(change(t, func(a *archive) { a.Version = archiveVersion + 1 }), "password")
+		if err == nil || !strings.Contains(err.Error(), "not supported") {
+			t.Fatalf("ReadArchive returned %v, want an unsupported version", err)
+		}
+	})
+	t.Run("damaged", func(t *testing.T) {
+		tests := map[string][]byte{
+			"not an archive": []byte(`{"format": "other"}`),
+			"memory":         change(t, func(a *archive) { a.KDF.Memory = archiveMaxMemory + 1 }),
+			"time":           change(t, func(a *archive) { a.KDF.Time = archiveMaxTime + 1 }),
+			"threads":        change(t, func(a *archive) { a.KDF.Threads = 0 }),
+			"algorithm":      change(t, func(a *archive) { a.KDF.Algorithm = "scrypt" }),
+			"record":         change(t, func(a *archive) { a.Records[0].Data[0] ^= 1 }),
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := ReadArchive(data, "password"); !errors.Is(err, errArchive) {
+					t.Fatalf("ReadArchive returned %v, want %v", err, errArchive)
+				}
+			})
+		}
+	})
+}
+
+// TestWriteJSON checks that the export has the fields of Decode, which the
+// command-line interface prints.
+func TestWriteJSON(t *testing.T) {
+	records := testRecords(t)
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, records, ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(entries) != len(records)-1 {
+		t.Fatalf("%d entries, want %d", len(entries), len(records)-1)
+	}
+	for i, e := range entries {
+		data, err := Decode(records[i])
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		// Empty metadata is left out, so the entries are compared as JSON.
+		got, _ := json.Marshal(e)
+		want, _ := json.Marshal(NewEntry(records[i], data, true))
+		if !bytes.Equal(got, want) {
+			t.Fatalf("entry %d is %s, want %s", i, got, want)
+		}
+	}
+	if entries[0].Status != models.RecordStatusSynced || entries[1].Status != models.RecordStatusPending {
+		t.Fatalf("statuses %q and %q", entries[0].Status, entries[1].Status)
+	}
+	if got := entries[2].Fields["binary"]; got != base64.StdEncoding.EncodeToString([]byte{0, 1, 2}) {
+		t.Fatalf("binary %q, want base64", got)
+	}
+	if !strings.HasPrefix(entries[4].Fields["fingerprint"], "SHA256:") {
+		t.Fatalf("SSH key without a fingerprint: %v", entries[4].Fields)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	records := testRecords(t)
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, records, ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(rows) != len(records) {
+		t.Fatalf("%d rows, want a header and %d records", len(rows), len(records)-1)
+	}
+	column := func(name string) int { return slices.Index(rows[0], name) }
+	if rows[1][column("login")] != "alice" || rows[1][column("metadata")] != `{"name":"a"}` {
+		t.Fatalf("credentials row %q", rows[1])
+	}
+	if rows[5][column("fingerprint")] == "" || rows[5][column("name")] != "deploy@ci" {
+		t.Fatalf("SSH key row %q", rows[5])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xml", nil, ""); err == nil {
+		t.Fatal("Write of an unknown format succeeded")
+	}
+}
@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"errors"
+
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// parseArchive reads an encrypted archive of GophKeeper. Its records are
+// imported with new IDs, so that an archive can be imported into another
+// account.
+func parseArchive(data []byte, opts Options) (*Result, error) {
+	records, err := exporter.ReadArchive(data, opts.Password)
+	switch {
+	case errors.Is(err, exporter.ErrPasswordRequired):
+		return nil, ErrPasswordRequired
+	case errors.Is(err, exporter.ErrWrongPassword):
+		return nil, ErrWrongPassword
+	case err != nil:
+		return nil, err
+	}
+
+	res := &Result{}
+	for _, record := range records {
+		var item any
+		switch record.Type {
+		case models.RecordTypeCredentials:
+			item, err = decode[models.Credentials](record.Data)
+		case models.RecordTypeText:
+			item, err = decode[models.Text](record.Data)
+		case models.RecordTypeCard:
+			item, err = decode[models.Card](record.Data)
+		case models.RecordTypeBinary:
+			item, err = decode[models.Binary](record.Data)
+		case models.RecordTypeSSHKey:
+			item, err = decode[models.SSHKey](record.Data)
+		default:
+			res.skip("record %s: unknown record type %q", record.ID, record.Type)
+			continue
+		}
+		if err != nil {
+			res.skip("record %s: %v", record.ID, err)
+			continue
+		}
+		res.add(record.Type, item)
+	}
+	return res, nil
+}
@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Argon2 is implemented here because golang.org/x/crypto/argon2 has no
+// Argon2d, the default key derivation of KeePass, and no secret key or
+// associated data, which KeePass may use. See RFC 9106.
+
+// Types of Argon2.
+const (
+	argon2d  = 0
+	argon2id = 2
+)
+
+// Versions of Argon2, KeePass accepts both.
+const (
+	argon2Version10 = 0x10
+	argon2Version13 = 0x13
+)
+
+const (
+	argon2BlockWords  = 128
+	argon2SyncPoints  = 4
+	argon2AddressesIn = argon2BlockWords
+)
+
+type argon2Block [argon2BlockWords]uint64
+
+type argon2Params struct {
+	mode        int
+	version     uint32
+	time        uint32
+	memory      uint32 // KiB
+	parallelism uint32
+	salt        []byte
+	secret      []byte
+	data        []byte
+	keyLen      uint32
+}
+
+func argon2Key(password []byte, p argon2Params) []byte {
+	h0 := argon2H0(password, p)
+
+	// The memory is rounded down to a multiple of 4 blocks per lane.
+	memory := max(p.memory, 2*argon2SyncPoints*p.parallelism)
+	memory = memory / (argon2SyncPoints * p.parallelism) * (argon2SyncPoints * p.parallelism)
+	laneLen := memory / p.parallelism
+	segmentLen := laneLen / argon2SyncPoints
+	blocks := make([]argon2Block, memory)
+
+	var buf [1024]byte
+	for lane := range p.parallelism {
+		for i := range uint32(2) {
+			binary.LittleEndian.PutUint32(h0[64:], i)
+			binary.LittleEndian.PutUint32(h0[68:], lane)
+			argon2Hash(buf[:], h0[:])
+			b := &blocks[lane*laneLen+i]
+			for j := range b {
+				b[j] = binary.LittleEndian.Uint64(buf[j*8:])
+			}
+		}
+	}
+
+	for pass := range p.time {
+		for slice := range uint32(argon2SyncPoints) {
+			var wg sync.WaitGroup
+			for lane := range p.parallelism {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					argon2FillSegment(blocks, p, memory, laneLen, segmentLen, pass, lane, slice)
+				}()
+			}
+			wg.Wait()
+		}
+	}
+
+	final := blocks[laneLen-1]
+	for lane := uint32(1); lane < p.parallelism; lane++ {
+		last := &blocks[lane*laneLen+laneLen-1]
+		for i := range final {
+			final[i] ^= last[i]
+		}
+	}
+	for i, v := range final {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	key := make([]byte, p.keyLen)
+	argon2Hash(key, buf[:])
+	return key
+}
+
+// argon2H0 returns the initial hash, with room for the block and lane
+// indexes after it.
+func argon2H0(password []byte, p argon2Params) [72]byte {
+	h, _ := blake2b.New512(nil)
+	var n [4]byte
+	put := func(v uint32) {
+		binary.LittleEndian.PutUint32(n[:], v)
+		h.Write(n[:])
+	}
+	put(p.parallelism)
+	put(p.keyLen)
+	put(p.memory)
+	put(p.time)
+	put(p.version)
+	put(uint32(p.mode))
+	for _, b := range [][]byte{password, p.salt, p.secret, p.data} {
+		put(uint32(len(b)))
+		h.Write(b)
+	}
+	var h0 [72]byte
+	h.Sum(h0[:0])
+	return h0
+}
+
+// argon2Hash is the variable length hash function H'.
+func argon2Hash(out, in []byte) {
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(out)))
+	newHash := func(size int) hash.Hash {
+		h, _ := blake2b.New(size, nil)
+		return h
+	}
+	if len(out) <= blake2b.Size {
+		h := newHash(len(out))
+		h.Write(n[:])
+		h.Write(in)
+		h.Sum(out[:0])
+		return
+	}
+
+	h := newHash(blake2b.Size)
+	h.Write(n[:])
+	h.Write(in)
+	var v [blake2b.Size]byte
+	h.Sum(v[:0])
+	copy(out, v[:32])
+	out = out[32:]
+	for len(out) > blake2b.Size {
+		h.Reset()
+		h.Write(v[:])
+		h.Sum(v[:0])
+		copy(out, v[:32])
+		out = out[32:]
+	}
+	h = newHash(len(out))
+	h.Write(v[:])
+	h.Sum(out[:0])
+}
+
+func argon2FillSegment(blocks []argon2Block, p argon2Params, memory, laneLen, segmentLen, pass, lane, slice uint32) {
+	independent := p.mode == argon2id && pass == 0 && slice < argon2SyncPoints/2
+	var address, input, zero argon2Block
+	if independent {
+		input[0] = uint64(pass)
+		input[1] = uint64(lane)
+		input[2] = uint64(slice)
+		input[3] = uint64(memory)
+		input[4] = uint64(p.time)
+		input[5] = uint64(p.mode)
+	}
+	nextAddresses := func() {
+		input[6]++
+		argon2Compress(&address, &zero, &input, false)
+		argon2Compress(&address, &zero, &address, false)
+	}
+
+	start := uint32(0)
+	if pass == 0 && slice == 0 {
+		start = 2
+		if independent {
+			nextAddresses()
+		}
+	}
+	offset := lane*laneLen + slice*segmentLen + start
+	for index := start; index < segmentLen; index, offset = index+1, offset+1 {
+		prev := offset - 1
+		if offset%laneLen == 0 {
+			prev = offset + laneLen - 1
+		}
+
+		var random uint64
+		if independent {
+			if index%argon2AddressesIn == 0 {
+				nextAddresses()
+			}
+			random = address[index%argon2AddressesIn]
+		} else {
+			random = blocks[prev][0]
+		}
+
+		refLane := uint32(random>>32) % p.parallelism
+		if pass == 0 && slice == 0 {
+			refLane = lane
+		}
+		ref := argon2RefIndex(uint32(random), pass, slice, index, refLane == lane, laneLen, segmentLen)
+		xor := pass > 0 && p.version == argon2Version13
+		argon2Compress(&blocks[offset], &blocks[prev], &blocks[refLane*laneLen+ref], xor)
+	}
+}
+
+// argon2RefIndex returns the index in its lane of the block referenced by
+// the current one.
+func argon2RefIndex(random, pass, slice, index uint32, sameLane bool, laneLen, segmentLen uint32) uint32 {
+	var area uint32
+	switch {
+	case pass == 0 && slice == 0:
+		area = index - 1
+	case pass == 0 && sameLane:
+		area = slice*segmentLen + index - 1
+	case pass == 0:
+		area = slice * segmentLen
+		if index == 0 {
+			area--
+		}
+	case sameLane:
+		area = laneLen - segmentLen + index - 1
+	default:
+		area = laneLen - segmentLen
+		if index == 0 {
+			area--
+		}
+	}
+
+	x := uint64(random) * uint64(random) >> 32
+	relative := uint64(area) - 1 - uint64(area)*x>>32
+	startPos := uint64(0)
+	if pass != 0 && slice != argon2SyncPoints-1 {
+		startPos = uint64(slice+1) * uint64(segmentLen)
+	}
+	return uint32((startPos + relative) % uint64(laneLen))
+}
+
+// argon2Compress sets out to G(x, y), XORed with the previous content of out
+// when xor is set.
+func argon2Compress(out, x, y *argon2Block, xor bool) {
+	var r, t argon2Block
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+	t = r
+	if xor {
+		for i := range t {
+			t[i] ^= out[i]
+		}
+	}
+	for i := 0; i < argon2BlockWords; i += 16 {
+		blamkaRound(&r, i, i+1, i+2, i+3, i+4, i+5, i+6, i+7, i+8, i+9, i+10, i+11, i+12, i+13, i+14, i+15)
+	}
+	for i := 0; i < 16; i += 2 {
+		blamkaRound(&r, i, i+1, i+16, i+17, i+32, i+33, i+48, i+49, i+64, i+65, i+80, i+81, i+96, i+97, i+112, i+113)
+	}
+	for i := range out {
+		out[i] = t[i] ^ r[i]
+	}
+}
+
+func blamkaRound(b *argon2Block, v0, v1, v2, v3, v4, v5, v6, v7, v8, v9, v10, v11, v12, v13, v14, v15 int) {
+	blamkaG(b, v0, v4, v8, v12)
+	blamkaG(b, v1, v5, v9, v13)
+	blamkaG(b, v2, v6, v10, v14)
+	blamkaG(b, v3, v7, v11, v15)
+	blamkaG(b, v0, v5, v10, v15)
+	blamkaG(b, v1, v6, v11, v12)
+	blamkaG(b, v2, v7, v8, v13)
+	blamkaG(b, v3, v4, v9, v14)
+}
+
+func blamkaG(v *argon2Block, a, b, c, d int) {
+	fBlaMka := func(x, y uint64) uint64 {
+		return x + y + 2*uint64(uint32(x))*uint64(uint32(y))
+	}
+	v[a] = fBlaMka(v[a], v[b])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = fBlaMka(v[c], v[d])
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = fBlaMka(v[a], v[b])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = fBlaMka(v[c], v[d])
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestArgon2RFC9106 checks the test vectors of RFC 9106, section 5.
+func TestArgon2RFC9106(t *testing.T) {
+	tests := []struct {
+		name string
+		mode int
+		tag  string
+	}{
+		{name: "Argon2d", mode: argon2d, tag: "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb"},
+		{name: "Argon2id", mode: argon2id, tag: "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := argon2Key(bytes.Repeat([]byte{0x01}, 32), argon2Params{
+				mode:        tt.mode,
+				version:     argon2Version13,
+				time:        3,
+				memory:      32,
+				parallelism: 4,
+				salt:        bytes.Repeat([]byte{0x02}, 16),
+				secret:      bytes.Repeat([]byte{0x03}, 8),
+				data:        bytes.Repeat([]byte{0x04}, 12),
+				keyLen:      32,
+			})
+			if hex.EncodeToString(got) != tt.tag {
+				t.Fatalf("tag %x, want %s", got, tt.tag)
+			}
+		})
+	}
+}
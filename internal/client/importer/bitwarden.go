@@ -0,0 +1,295 @@
+package importer
+
+import (
+	"crypto/aes"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+	"golang.org/x/crypto/argon2"
+)
+
+// Types of Bitwarden items.
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+	bitwardenSSHKey   = 5
+)
+
+// Key derivation functions of password protected Bitwarden exports.
+const (
+	bitwardenPBKDF2   = 0
+	bitwardenArgon2id = 1
+)
+
+// Upper bounds of the key derivation parameters, which are used before the
+// password can be checked. They are those Bitwarden accepts for accounts,
+// a damaged export must not exhaust the memory or run for hours.
+const (
+	bitwardenMaxPBKDF2Iterations = 2_000_000
+	bitwardenMaxArgon2Time       = 10
+	bitwardenMaxArgon2Memory     = 1024 // MiB
+	bitwardenMaxArgon2Threads    = 16
+)
+
+// bitwardenLinked is the type of custom fields linked to another field,
+// which hold no value of their own.
+const bitwardenLinked = 3
+
+// bitwardenAESCBCHMAC is the type of encrypted strings of exports.
+const bitwardenAESCBCHMAC = "2"
+
+var errBitwardenAccountKey = errors.New("the export is encrypted with the key of the Bitwarden account, export the vault again as an unencrypted file or with a password")
+
+type bitwardenExport struct {
+	Encrypted         bool   `json:"encrypted"`
+	PasswordProtected bool   `json:"passwordProtected"`
+	Salt              string `json:"salt"`
+	KdfType           int    `json:"kdfType"`
+	KdfIterations     uint64 `json:"kdfIterations"`
+	KdfMemory         uint64 `json:"kdfMemory"` // MiB
+	KdfParallelism    uint64 `json:"kdfParallelism"`
+	Validation        string `json:"encKeyValidation_DO_NOT_EDIT"`
+	Data              string `json:"data"`
+
+	Folders []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	FolderID string `json:"folderId"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		URIs []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]*string `json:"identity"`
+	SSHKey   *struct {
+		PrivateKey string `json:"privateKey"`
+		PublicKey  string `json:"publicKey"`
+	} `json:"sshKey"`
+}
+
+// bitwardenIdentityFields orders the fields of identities as Bitwarden
+// shows them.
+var bitwardenIdentityFields = []struct{ key, label string }{
+	{"title", "Title"}, {"firstName", "First name"}, {"middleName", "Middle name"}, {"lastName", "Last name"},
+	{"username", "Username"}, {"company", "Company"}, {"ssn", "Social security number"},
+	{"passportNumber", "Passport number"}, {"licenseNumber", "License number"}, {"email", "Email"}, {"phone", "Phone"},
+	{"address1", "Address 1"}, {"address2", "Address 2"}, {"address3", "Address 3"}, {"city", "City"},
+	{"state", "State"}, {"postalCode", "Postal code"}, {"country", "Country"},
+}
+
+// parseBitwarden reads a JSON export of Bitwarden, unencrypted or protected
+// with a password.
+func parseBitwarden(data []byte, opts Options) (*Result, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid Bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		if !export.PasswordProtected {
+			return nil, errBitwardenAccountKey
+		}
+		if opts.Password == "" {
+			return nil, ErrPasswordRequired
+		}
+		plain, err := export.decrypt(opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		return parseBitwarden(plain, Options{})
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+	res := &Result{}
+	for _, item := range export.Items {
+		item.add(res, folders[item.FolderID])
+	}
+	return res, nil
+}
+
+func (item bitwardenItem) add(res *Result, folder string) {
+	m := newMeta(item.Name)
+	m.set(metaFolder, folder)
+	for _, field := range item.Fields {
+		if field.Type != bitwardenLinked {
+			m.set(field.Name, field.Value)
+		}
+	}
+
+	switch {
+	case item.Type == bitwardenLogin && item.Login != nil:
+		resource := ""
+		for i, uri := range item.Login.URIs {
+			if i == 0 {
+				resource = uri.URI
+				continue
+			}
+			m.set(metaURL, uri.URI)
+		}
+		m.set(metaTOTP, item.Login.TOTP)
+		m.set(metaNotes, item.Notes)
+		res.add(models.RecordTypeCredentials, models.Credentials{
+			Resource: resource,
+			Login:    item.Login.Username,
+			Password: item.Login.Password,
+			Metadata: models.Metadata(m),
+		})
+	case item.Type == bitwardenCard && item.Card != nil:
+		m.set("brand", item.Card.Brand)
+		m.set(metaNotes, item.Notes)
+		res.add(models.RecordTypeCard, models.Card{
+			CardNumber: item.Card.Number,
+			EXP:        expiry(item.Card.ExpMonth, item.Card.ExpYear),
+			CVV:        item.Card.Code,
+			CardHolder: item.Card.CardholderName,
+			Metadata:   models.Metadata(m),
+		})
+	case item.Type == bitwardenSSHKey && item.SSHKey != nil:
+		m.set(metaNotes, item.Notes)
+		addSSHKey(res, item.SSHKey.PrivateKey, item.SSHKey.PublicKey, m)
+	case item.Type == bitwardenNote:
+		res.add(models.RecordTypeText, models.Text{Text: item.Notes, Metadata: models.Metadata(m)})
+	case item.Type == bitwardenIdentity:
+		var text strings.Builder
+		for _, field := range bitwardenIdentityFields {
+			if value := item.Identity[field.key]; value != nil && *value != "" {
+				fmt.Fprintf(&text, "%s: %s\n", field.label, *value)
+			}
+		}
+		if item.Notes != "" {
+			fmt.Fprintf(&text, "\n%s\n", item.Notes)
+		}
+		res.add(models.RecordTypeText, models.Text{Text: text.String(), Metadata: models.Metadata(m)})
+	default:
+		res.skip("%q: unknown Bitwarden item type %d", item.Name, item.Type)
+	}
+}
+
+// addSSHKey adds the key as stored by the SSH key records, with the comment
+// of the public key. Keys that cannot be read are skipped.
+func addSSHKey(res *Result, privateKey, publicKey string, m meta) {
+	comment := ""
+	if fields := strings.Fields(publicKey); len(fields) > 2 {
+		comment = strings.Join(fields[2:], " ")
+	}
+	key, err := sshagent.Import([]byte(privateKey), nil, comment)
+	if err != nil {
+		res.skip("%q: %v", m[metaName], err)
+		return
+	}
+	key.Metadata = models.Metadata(m)
+	res.add(models.RecordTypeSSHKey, key)
+}
+
+// decrypt returns the unencrypted export inside a password protected one.
+// The key is derived from the password like the PIN key of Bitwarden and
+// stretched into an encryption and a MAC key.
+func (e bitwardenExport) decrypt(password string) ([]byte, error) {
+	var key []byte
+	var err error
+	switch e.KdfType {
+	case bitwardenPBKDF2:
+		if e.KdfIterations == 0 || e.KdfIterations > bitwardenMaxPBKDF2Iterations {
+			return nil, errors.New("invalid Bitwarden export: invalid PBKDF2 iterations")
+		}
+		key, err = pbkdf2.Key(sha256.New, password, []byte(e.Salt), int(e.KdfIterations), 32)
+	case bitwardenArgon2id:
+		// The memory is checked in MiB, before it is converted to the KiB of
+		// Argon2, so that it cannot wrap around.
+		if e.KdfIterations == 0 || e.KdfIterations > bitwardenMaxArgon2Time ||
+			e.KdfMemory == 0 || e.KdfMemory > bitwardenMaxArgon2Memory ||
+			e.KdfParallelism == 0 || e.KdfParallelism > bitwardenMaxArgon2Threads {
+			return nil, errors.New("invalid Bitwarden export: invalid Argon2 parameters")
+		}
+		salt := sha256.Sum256([]byte(e.Salt))
+		key = argon2.IDKey([]byte(password), salt[:], uint32(e.KdfIterations), uint32(e.KdfMemory*1024), uint8(e.KdfParallelism), 32)
+	default:
+		return nil, fmt.Errorf("invalid Bitwarden export: unknown key derivation function %d", e.KdfType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	encKey, err := hkdf.Expand(sha256.New, key, "enc", 32)
+	if err != nil {
+		return nil, err
+	}
+	macKey, err := hkdf.Expand(sha256.New, key, "mac", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = decryptEncString(e.Validation, encKey, macKey); err != nil {
+		return nil, err
+	}
+	return decryptEncString(e.Data, encKey, macKey)
+}
+
+// decryptEncString decrypts a string of Bitwarden of type 2: AES-256-CBC
+// with HMAC-SHA256, written as 2.IV|DATA|MAC in base64.
+func decryptEncString(s string, encKey, macKey []byte) ([]byte, error) {
+	encType, rest, ok := strings.Cut(s, ".")
+	if !ok || encType != bitwardenAESCBCHMAC {
+		return nil, fmt.Errorf("invalid Bitwarden export: unsupported encryption %q", encType)
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid Bitwarden export: invalid encrypted string")
+	}
+	var decoded [3][]byte
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = base64.StdEncoding.DecodeString(part); err != nil {
+			return nil, errors.New("invalid Bitwarden export: invalid encrypted string")
+		}
+	}
+	iv, data, tag := decoded[0], decoded[1], decoded[2]
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return nil, ErrWrongPassword
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	return decryptCBC(block, iv, data)
+}
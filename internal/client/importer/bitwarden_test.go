@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"golang.org/x/crypto/argon2"
+)
+
+const bitwardenPlain = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Work"}],
+  "items": [
+    {"type": 1, "name": "Example", "notes": "n", "folderId": "f1",
+     "fields": [{"name": "pin", "value": "1234", "type": 0}, {"name": "linked", "value": null, "type": 3}],
+     "login": {"uris": [{"uri": "https://example.com"}, {"uri": "https://www.example.com"}], "username": "alice", "password": "secret", "totp": "otpauth://totp/x"}},
+    {"type": 2, "name": "Note", "notes": "text"},
+    {"type": 3, "name": "Visa", "card": {"cardholderName": "Alice", "brand": "Visa", "number": "4111111111111111", "expMonth": "3", "expYear": "2030", "code": "123"}},
+    {"type": 4, "name": "Me", "identity": {"firstName": "Alice", "lastName": "Smith", "email": null}},
+    {"type": 9, "name": "Unknown"}
+  ]
+}`
+
+func TestParseBitwarden(t *testing.T) {
+	if format, err := Detect([]byte(bitwardenPlain)); err != nil || format != FormatBitwarden {
+		t.Fatalf("Detect = %q, %v, want %q", format, err, FormatBitwarden)
+	}
+	res, err := Parse(FormatBitwarden, []byte(bitwardenPlain), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Item{
+		{Type: models.RecordTypeCredentials, Data: models.Credentials{
+			Resource: "https://example.com", Login: "alice", Password: "secret",
+			Metadata: models.Metadata{"name": "Example", "folder": "Work", "pin": "1234", "url": "https://www.example.com", "totp": "otpauth://totp/x", "notes": "n"},
+		}},
+		{Type: models.RecordTypeText, Data: models.Text{Text: "text", Metadata: models.Metadata{"name": "Note"}}},
+		{Type: models.RecordTypeCard, Data: models.Card{
+			CardNumber: "4111111111111111", EXP: "03/30", CVV: "123", CardHolder: "Alice",
+			Metadata: models.Metadata{"name": "Visa", "brand": "Visa"},
+		}},
+		{Type: models.RecordTypeText, Data: models.Text{Text: "First name: Alice\nLast name: Smith\n", Metadata: models.Metadata{"name": "Me"}}},
+	}
+	if !reflect.DeepEqual(res.Items, want) {
+		t.Fatalf("items\n%#v\nwant\n%#v", res.Items, want)
+	}
+	if len(res.Skipped) != 1 {
+		t.Fatalf("skipped %q, want the unknown item", res.Skipped)
+	}
+}
+
+// encryptBitwarden protects the export like Bitwarden does with a password.
+func encryptBitwarden(t *testing.T, e bitwardenExport, plain, password string) []byte {
+	t.Helper()
+	var key []byte
+	if e.KdfType == bitwardenArgon2id {
+		salt := sha256.Sum256([]byte(e.Salt))
+		key = argon2.IDKey([]byte(password), salt[:], uint32(e.KdfIterations), uint32(e.KdfMemory*1024), uint8(e.KdfParallelism), 32)
+	} else {
+		var err error
+		if key, err = pbkdf2.Key(sha256.New, password, []byte(e.Salt), int(e.KdfIterations), 32); err != nil {
+			t.Fatal(err)
+		}
+	}
+	encKey, _ := hkdf.Expand(sha256.New, key, "enc", 32)
+	macKey, _ := hkdf.Expand(sha256.New, key, "mac", 32)
+	encString := func(data []byte) string {
+		block, _ := aes.NewCipher(encKey)
+		iv := bytes.Repeat([]byte{0x07}, aes.BlockSize)
+		pad := aes.BlockSize - len(data)%aes.BlockSize
+		data = append(data, bytes.Repeat([]byte{byte(pad)}, pad)...)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+		mac := hmac.New(sha256.New, macKey)
+		mac.Write(iv)
+		mac.Write(data)
+		enc := base64.StdEncoding.EncodeToString
+		return bitwardenAESCBCHMAC + "." + enc(iv) + "|" + enc(data) + "|" + enc(mac.Sum(nil))
+	}
+	e.Encrypted, e.PasswordProtected = true, true
+	e.Validation = encString([]byte("validation"))
+	e.Data = encString([]byte(plain))
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseBitwardenEncrypted(t *testing.T) {
+	exports := map[string]bitwardenExport{
+		"PBKDF2":   {Salt: "salt", KdfType: bitwardenPBKDF2, KdfIterations: 1000},
+		"Argon2id": {Salt: "salt", KdfType: bitwardenArgon2id, KdfIterations: 1, KdfMemory: 1, KdfParallelism: 1},
+	}
+	for name, e := range exports {
+		t.Run(name, func(t *testing.T) {
+			data := encryptBitwarden(t, e, bitwardenPlain, "password")
+			res, err := Parse(FormatBitwarden, data, Options{Password: "password"})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(res.Items) != 4 {
+				t.Fatalf("%d items, want 4", len(res.Items))
+			}
+			if _, err = Parse(FormatBitwarden, data, Options{Password: "wrong"}); !errors.Is(err, ErrWrongPassword) {
+				t.Fatalf("wrong password: %v, want %v", err, ErrWrongPassword)
+			}
+			if _, err = Parse(FormatBitwarden, data, Options{}); !errors.Is(err, ErrPasswordRequired) {
+				t.Fatalf("no password: %v, want %v", err, ErrPasswordRequired)
+			}
+		})
+	}
+
+	_, err := Parse(FormatBitwarden, []byte(`{"encrypted": true, "items": []}`), Options{Password: "password"})
+	if !errors.Is(err, errBitwardenAccountKey) {
+		t.Fatalf("account key: %v, want %v", err, errBitwardenAccountKey)
+	}
+}
+
+// TestBitwardenKDFBounds checks that parameters which would exhaust the
+// memory or run for hours are refused before any work is done.
+func TestBitwardenKDFBounds(t *testing.T) {
+	tests := map[string]bitwardenExport{
+		"no PBKDF2 iterations":       {KdfType: bitwardenPBKDF2},
+		"PBKDF2 iterations":          {KdfType: bitwardenPBKDF2, KdfIterations: 4_000_000_000},
+		"no Argon2 iterations":       {KdfType: bitwardenArgon2id, KdfMemory: 64, KdfParallelism: 4},
+		"Argon2 iterations":          {KdfType: bitwardenArgon2id, KdfIterations: 1 << 32, KdfMemory: 64, KdfParallelism: 4},
+		"no memory":                  {KdfType: bitwardenArgon2id, KdfIterations: 3, KdfParallelism: 4},
+		"memory":                     {KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: bitwardenMaxArgon2Memory + 1, KdfParallelism: 4},
+		"memory wrapping to 32 bits": {KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 1<<22 + 64, KdfParallelism: 4},
+		"no parallelism":             {KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 64},
+		"parallelism":                {KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 64, KdfParallelism: 256},
+		"unknown function":           {KdfType: 7, KdfIterations: 3},
+	}
+	for name, e := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := e.decrypt("password"); err == nil || errors.Is(err, ErrWrongPassword) {
+				t.Fatalf("decrypt returned %v, want invalid parameters", err)
+			}
+		})
+	}
+}
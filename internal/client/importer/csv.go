@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// csvColumns maps the names of the columns of the exports of Chrome,
+// Firefox and 1Password to the fields they hold.
+var csvColumns = map[string]string{
+	"name":              metaName,
+	"title":             metaName,
+	"url":               metaURL,
+	"website":           metaURL,
+	"login_uri":         metaURL,
+	"username":          "login",
+	"login":             "login",
+	"login_username":    "login",
+	"password":          "password",
+	"login_password":    "password",
+	"note":              metaNotes,
+	"notes":             metaNotes,
+	"otpauth":           metaTOTP,
+	"login_totp":        metaTOTP,
+	"one-time password": metaTOTP,
+	"tags":              metaTags,
+	"folder":            metaFolder,
+}
+
+// utf8BOM starts the CSV files written by some spreadsheets.
+var utf8BOM = []byte("\ufeff")
+
+// csvIgnored are the columns of bookkeeping of the browsers and 1Password.
+var csvIgnored = map[string]bool{
+	"guid":                true,
+	"httprealm":           true,
+	"formactionorigin":    true,
+	"timecreated":         true,
+	"timelastused":        true,
+	"timepasswordchanged": true,
+	"favorite":            true,
+	"archived":            true,
+}
+
+// csvFlavor returns the fields of the columns of the header if it is the
+// header of a password export, with the URL or the name, the login and the
+// password.
+func csvFlavor(data []byte) ([]string, bool) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	header, err := r.Read()
+	if err != nil {
+		return nil, false
+	}
+	fields := make([]string, len(header))
+	has := make(map[string]bool)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if field, ok := csvColumns[column]; ok {
+			fields[i] = field
+			has[field] = true
+		} else if !csvIgnored[column] {
+			fields[i] = strings.TrimSpace(header[i])
+		}
+	}
+	return fields, (has[metaURL] || has[metaName]) && has["login"] && has["password"]
+}
+
+// parseCSV reads the password CSV of Chrome, Firefox or 1Password. Rows
+// without a URL, a login and a password become texts of their notes.
+func parseCSV(data []byte) (*Result, error) {
+	fields, ok := csvFlavor(data)
+	if !ok {
+		return nil, fmt.Errorf("%w: the CSV has no name or url, username and password columns", errUnknownFormat)
+	}
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if _, err := r.Read(); err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		values := make(map[string]string)
+		m := make(meta)
+		for i, value := range row {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			switch fields[i] {
+			case "login", "password", metaURL, metaNotes:
+				values[fields[i]] = value
+			default:
+				m.set(fields[i], value)
+			}
+		}
+
+		switch {
+		case values[metaURL] != "" || values["login"] != "" || values["password"] != "":
+			m.set(metaNotes, values[metaNotes])
+			res.add(models.RecordTypeCredentials, models.Credentials{
+				Resource: values[metaURL],
+				Login:    values["login"],
+				Password: values["password"],
+				Metadata: models.Metadata(m),
+			})
+		case values[metaNotes] != "":
+			res.add(models.RecordTypeText, models.Text{Text: values[metaNotes], Metadata: models.Metadata(m)})
+		default:
+			line, _ := r.FieldPos(0)
+			res.skip("line %d: no URL, login, password or notes", line)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Item
+	}{
+		{
+			name: "Chrome",
+			data: "\ufeffname,url,username,password,note\n" +
+				"Example,https://example.com,alice,secret,n\n" +
+				"Note,,,,text\n",
+			want: []Item{
+				{Type: models.RecordTypeCredentials, Data: models.Credentials{
+					Resource: "https://example.com", Login: "alice", Password: "secret",
+					Metadata: models.Metadata{"name": "Example", "notes": "n"},
+				}},
+				{Type: models.RecordTypeText, Data: models.Text{Text: "text", Metadata: models.Metadata{"name": "Note"}}},
+			},
+		},
+		{
+			name: "Firefox",
+			data: `"url","username","password","httpRealm","formActionOrigin","guid","timeCreated","timeLastUsed","timePasswordChanged"` + "\n" +
+				`"https://example.com","alice","secret",,"https://example.com","{1}","1","2","3"` + "\n",
+			want: []Item{
+				{Type: models.RecordTypeCredentials, Data: models.Credentials{
+					Resource: "https://example.com", Login: "alice", Password: "secret",
+					Metadata: models.Metadata{},
+				}},
+			},
+		},
+		{
+			name: "1Password",
+			data: "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes,Custom\n" +
+				"Example,https://example.com,alice,secret,otpauth://totp/x,false,false,a,n,c\n",
+			want: []Item{
+				{Type: models.RecordTypeCredentials, Data: models.Credentials{
+					Resource: "https://example.com", Login: "alice", Password: "secret",
+					Metadata: models.Metadata{"name": "Example", "totp": "otpauth://totp/x", "tags": "a", "Custom": "c", "notes": "n"},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if format, err := Detect([]byte(tt.data)); err != nil || format != FormatCSV {
+				t.Fatalf("Detect = %q, %v, want %q", format, err, FormatCSV)
+			}
+			res, err := Parse(FormatCSV, []byte(tt.data), Options{})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(res.Items, tt.want) {
+				t.Fatalf("items\n%#v\nwant\n%#v", res.Items, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVSkipped(t *testing.T) {
+	res, err := Parse(FormatCSV, []byte("name,url,username,password\nEmpty,,,\n"), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(res.Items) != 0 || len(res.Skipped) != 1 {
+		t.Fatalf("items %v, skipped %q, want the empty row skipped", res.Items, res.Skipped)
+	}
+
+	if _, err = Parse(FormatCSV, []byte("a,b\n1,2\n"), Options{}); err == nil {
+		t.Fatal("Parse of a CSV without password columns succeeded")
+	}
+}
@@ -0,0 +1,336 @@
+// Package importer reads the exports of other password managers and the
+// archives of GophKeeper, and maps their entries to records: logins to
+// credentials, notes and other entries to texts, cards to cards, SSH keys to
+// SSH keys and attached files to binaries. The fields the records have no
+// place for are kept in the metadata.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/exporter"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// Formats of exports.
+const (
+	FormatKeePass   = "keepass"
+	FormatBitwarden = "bitwarden"
+	Format1PUX      = "1pux"
+	// FormatCSV is the password CSV of Chrome, Firefox or 1Password, told
+	// apart by their columns.
+	FormatCSV = "csv"
+	// FormatGophKeeper is the encrypted archive written by the exporter.
+	FormatGophKeeper = "gophkeeper"
+)
+
+// Formats lists the formats in the order they are documented.
+var Formats = []string{FormatGophKeeper, FormatKeePass, FormatBitwarden, Format1PUX, FormatCSV}
+
+// Metadata keys set by the importers besides the name.
+const (
+	metaName   = "name"
+	metaNotes  = "notes"
+	metaFolder = "folder"
+	metaTags   = "tags"
+	metaTOTP   = "totp"
+	metaURL    = "url"
+	// metaFilename is the key the terminal UI gives binaries read from files.
+	metaFilename = "filename"
+)
+
+var (
+	// ErrPasswordRequired is returned for encrypted exports when no
+	// password was given.
+	ErrPasswordRequired = errors.New("the export is encrypted, give its password")
+	// ErrWrongPassword is returned when the export cannot be decrypted with
+	// the password.
+	ErrWrongPassword = errors.New("wrong password or key file, or the export is damaged")
+	errUnknownFormat = errors.New("unknown export format")
+)
+
+// Options are the secrets opening an encrypted export.
+type Options struct {
+	Password string
+	// KeyFile is the content of the key file of a KeePass database.
+	KeyFile []byte
+}
+
+// Item is an entry of an export mapped to the data of a record.
+type Item struct {
+	Type models.RecordType
+	// Data is a models.Credentials, models.Text, models.Card, models.Binary or
+	// models.SSHKey.
+	Data any
+}
+
+// Result is what Parse found in an export.
+type Result struct {
+	Items []Item
+	// Skipped describes the entries that were not imported.
+	Skipped []string
+}
+
+func (r *Result) add(recordType models.RecordType, data any) {
+	r.Items = append(r.Items, Item{Type: recordType, Data: data})
+}
+
+func (r *Result) skip(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+// Detect guesses the format of the export from its content.
+func Detect(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, kdbxSignature):
+		return FormatKeePass, nil
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		if r, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err == nil && slices.ContainsFunc(r.File, func(f *zip.File) bool { return f.Name == onePUXData }) {
+			return Format1PUX, nil
+		}
+	case exporter.IsArchive(data):
+		return FormatGophKeeper, nil
+	case json.Valid(data):
+		var probe struct {
+			Encrypted *bool           `json:"encrypted"`
+			Items     json.RawMessage `json:"items"`
+		}
+		if json.Unmarshal(data, &probe) == nil && (probe.Encrypted != nil || probe.Items != nil) {
+			return FormatBitwarden, nil
+		}
+	default:
+		if _, ok := csvFlavor(data); ok {
+			return FormatCSV, nil
+		}
+	}
+	return "", fmt.Errorf("%w, give one of %s", errUnknownFormat, strings.Join(Formats, ", "))
+}
+
+// Parse reads an export of the format.
+func Parse(format string, data []byte, opts Options) (*Result, error) {
+	switch format {
+	case FormatGophKeeper:
+		return parseArchive(data, opts)
+	case FormatKeePass:
+		return parseKeePass(data, opts)
+	case FormatBitwarden:
+		return parseBitwarden(data, opts)
+	case Format1PUX:
+		return parse1PUX(data)
+	case FormatCSV:
+		return parseCSV(data)
+	default:
+		return nil, fmt.Errorf("%w %q, expected one of %s", errUnknownFormat, format, strings.Join(Formats, ", "))
+	}
+}
+
+// Name returns the name metadata of the item, or what the lists show for
+// records without one.
+func (it Item) Name() string {
+	meta := it.Metadata()
+	if name := meta[metaName]; name != "" {
+		return name
+	}
+	switch data := it.Data.(type) {
+	case models.Credentials:
+		return data.Resource
+	case models.SSHKey:
+		return data.Comment
+	}
+	return meta[metaFilename]
+}
+
+func (it Item) Metadata() models.Metadata {
+	switch data := it.Data.(type) {
+	case models.Credentials:
+		return data.Metadata
+	case models.Text:
+		return data.Metadata
+	case models.Card:
+		return data.Metadata
+	case models.Binary:
+		return data.Metadata
+	case models.SSHKey:
+		return data.Metadata
+	}
+	return nil
+}
+
+// Record returns a new record holding the item.
+func (it Item) Record() (*models.Record, error) {
+	data, err := json.Marshal(it.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Record{Type: it.Type, Data: data}, nil
+}
+
+// Planned is an item with what importing it does.
+type Planned struct {
+	Item
+	// DuplicateOf is the record already holding the item, or uuid.Nil.
+	DuplicateOf uuid.UUID
+	// Repeated is set for an item found earlier in the export.
+	Repeated bool
+}
+
+// Skip reports whether the item is a duplicate, which is not imported.
+func (p Planned) Skip() bool {
+	return p.DuplicateOf != uuid.Nil || p.Repeated
+}
+
+// Plan finds the items already held by the records, or found twice in the
+// export. Credentials are duplicates when their host, login and password
+// are the same, cards when their number and expiry are, other records when
+// their name and content are.
+func Plan(items []Item, records []*models.Record) []Planned {
+	existing := make(map[string]uuid.UUID, len(records))
+	for _, record := range records {
+		if record.Status == models.RecordStatusDeleted {
+			continue
+		}
+		if k := recordKey(record); k != "" {
+			existing[k] = record.ID
+		}
+	}
+
+	seen := make(map[string]bool, len(items))
+	res := make([]Planned, 0, len(items))
+	for _, item := range items {
+		p := Planned{Item: item}
+		k := itemKey(item)
+		p.DuplicateOf = existing[k]
+		p.Repeated = p.DuplicateOf == uuid.Nil && seen[k]
+		seen[k] = true
+		res = append(res, p)
+	}
+	return res
+}
+
+func recordKey(record *models.Record) string {
+	var data any
+	var err error
+	switch record.Type {
+	case models.RecordTypeCredentials:
+		data, err = decode[models.Credentials](record.Data)
+	case models.RecordTypeText:
+		data, err = decode[models.Text](record.Data)
+	case models.RecordTypeCard:
+		data, err = decode[models.Card](record.Data)
+	case models.RecordTypeBinary:
+		data, err = decode[models.Binary](record.Data)
+	case models.RecordTypeSSHKey:
+		data, err = decode[models.SSHKey](record.Data)
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	return itemKey(Item{Type: record.Type, Data: data})
+}
+
+func decode[T any](data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// itemKey returns what two items have in common when one duplicates the
+// other.
+func itemKey(item Item) string {
+	var parts []string
+	switch data := item.Data.(type) {
+	case models.Credentials:
+		parts = []string{resourceHost(data.Resource), data.Login, data.Password}
+	case models.Text:
+		parts = []string{data.Metadata[metaName], data.Text}
+	case models.Card:
+		parts = []string{digits(data.CardNumber), data.EXP}
+	case models.Binary:
+		sum := sha256.Sum256(data.Binary)
+		parts = []string{data.Metadata[metaName], hex.EncodeToString(sum[:])}
+	case models.SSHKey:
+		fields := strings.Fields(data.PublicKey)
+		parts = append(parts, fields[:min(len(fields), 2)]...)
+	}
+	return string(item.Type) + "\x00" + strings.Join(parts, "\x00")
+}
+
+// resourceHost returns the host of a resource, which is a URL or a host
+// optionally followed by a path, without the port and www.
+func resourceHost(resource string) string {
+	if !strings.Contains(resource, "://") {
+		resource = "//" + resource
+	}
+	u, err := url.Parse(resource)
+	if err != nil {
+		return strings.ToLower(resource)
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// meta builds the metadata of an item, leaving out empty values and never
+// replacing a value already set: a second field of the same name gets a
+// number.
+type meta models.Metadata
+
+func newMeta(name string) meta {
+	m := make(meta)
+	m.set(metaName, name)
+	return m
+}
+
+func (m meta) set(key, value string) {
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if key == "" || value == "" {
+		return
+	}
+	unique := key
+	for i := 2; ; i++ {
+		old, ok := m[unique]
+		if !ok {
+			break
+		}
+		if old == value {
+			return
+		}
+		unique = key + " " + strconv.Itoa(i)
+	}
+	m[unique] = value
+}
+
+// expiry formats the expiry of a card as MM/YY.
+func expiry(month, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
@@ -0,0 +1,657 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/salsa20/salsa"
+	"golang.org/x/crypto/twofish"
+)
+
+// kdbxSignature starts every KeePass 2 database.
+var kdbxSignature = []byte{0x03, 0xd9, 0xa2, 0x9a, 0x67, 0xfb, 0x4b, 0xb5}
+
+const kdbxMajorVersion = 4
+
+// Fields of the outer header.
+const (
+	kdbxEndOfHeader    = 0
+	kdbxCipherID       = 2
+	kdbxCompression    = 3
+	kdbxMasterSeed     = 4
+	kdbxEncryptionIV   = 7
+	kdbxKdfParameters  = 11
+	kdbxInnerStreamID  = 1
+	kdbxInnerStreamKey = 2
+	kdbxInnerBinary    = 3
+)
+
+// Upper bounds of the key derivation parameters, which are used before the
+// password can be checked. They are far above what KeePass and KeePassXC
+// choose, a damaged database must not exhaust the memory or run for hours.
+const (
+	kdbxMaxAESRounds     = 100_000_000
+	kdbxMaxArgon2Time    = 100
+	kdbxMaxArgon2Memory  = 2 * 1024 * 1024 // KiB
+	kdbxMaxArgon2Threads = 64
+)
+
+// Stream ciphers protecting the values in the XML.
+const (
+	kdbxStreamSalsa20  = 2
+	kdbxStreamChaCha20 = 3
+)
+
+var (
+	kdbxCipherAES      = uuid.MustParse("31c1f2e6-bf71-4350-be58-05216afc5aff")
+	kdbxCipherChaCha20 = uuid.MustParse("d6038a2b-8b6f-4cb5-a524-339a31dbb59a")
+	kdbxCipherTwofish  = uuid.MustParse("ad68f29f-576f-4bb9-a36a-d47af965346c")
+	kdbxKdfAES         = uuid.MustParse("c9d9f39a-628a-4460-bf74-0d08c18a4fea")
+	kdbxKdfAES4        = uuid.MustParse("7c02bb82-79a7-4ac0-927d-114a00648238")
+	kdbxKdfArgon2d     = uuid.MustParse("ef636ddf-8c29-444b-91f7-a9a403e30a0c")
+	kdbxKdfArgon2id    = uuid.MustParse("9e298b19-56db-4773-b23d-fc3ec6f0a1e6")
+	kdbxSalsa20Nonce   = []byte{0xe8, 0x30, 0x09, 0x4b, 0x97, 0x20, 0x5d, 0x2a}
+)
+
+var errKDBX = errors.New("invalid KeePass database")
+
+// parseKeePass reads a KDBX 4 database. Entries of the recycle bin and the
+// history of entries are left out.
+func parseKeePass(data []byte, opts Options) (*Result, error) {
+	if opts.Password == "" && len(opts.KeyFile) == 0 {
+		return nil, ErrPasswordRequired
+	}
+	r := bytes.NewReader(data)
+	var sig [8]byte
+	var version uint32
+	if _, err := io.ReadFull(r, sig[:]); err != nil || !bytes.Equal(sig[:], kdbxSignature) {
+		return nil, fmt.Errorf("%w: no KeePass signature", errKDBX)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("%w: %w", errKDBX, err)
+	}
+	if major := version >> 16; major != kdbxMajorVersion {
+		return nil, fmt.Errorf("KDBX %d is not supported, save the database with KeePass 2.35 or later in the KDBX 4 format", major)
+	}
+
+	header, err := readKDBXFields(r)
+	if err != nil {
+		return nil, err
+	}
+	headerLen := len(data) - r.Len()
+	var headerHash, headerMAC [32]byte
+	if _, err = io.ReadFull(r, headerHash[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", errKDBX, err)
+	}
+	if _, err = io.ReadFull(r, headerMAC[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", errKDBX, err)
+	}
+	if sum := sha256.Sum256(data[:headerLen]); !hmac.Equal(sum[:], headerHash[:]) {
+		return nil, fmt.Errorf("%w: the header is damaged", errKDBX)
+	}
+
+	compositeKey, err := kdbxCompositeKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	transformedKey, err := kdbxTransformKey(compositeKey, header[kdbxKdfParameters])
+	if err != nil {
+		return nil, err
+	}
+	seed := header[kdbxMasterSeed]
+	if len(seed) != 32 {
+		return nil, fmt.Errorf("%w: invalid master seed", errKDBX)
+	}
+	encryptionKey := sha256.Sum256(append(append([]byte{}, seed...), transformedKey...))
+	hmacKey := sha512.Sum512(append(append(append([]byte{}, seed...), transformedKey...), 1))
+	if mac := kdbxHMAC(hmacKey[:], ^uint64(0), data[:headerLen]); !hmac.Equal(mac, headerMAC[:]) {
+		return nil, ErrWrongPassword
+	}
+
+	encrypted, err := readKDBXBlocks(r, hmacKey[:])
+	if err != nil {
+		return nil, err
+	}
+	payload, err := kdbxDecrypt(header, encryptionKey[:], encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if compression := header[kdbxCompression]; len(compression) == 4 && binary.LittleEndian.Uint32(compression) == 1 {
+		gz, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+		if payload, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+	}
+
+	// The inner header holds the key of the protected values and the
+	// attachments.
+	pr := bytes.NewReader(payload)
+	var binaries [][]byte
+	inner, err := readKDBXFields(pr, func(id byte, value []byte) {
+		if id == kdbxInnerBinary && len(value) > 0 {
+			binaries = append(binaries, value[1:])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	stream, err := kdbxInnerStream(inner)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := unprotectXML(payload[len(payload)-pr.Len():], stream)
+	if err != nil {
+		return nil, err
+	}
+
+	var file kdbxFile
+	if err = xml.Unmarshal(doc, &file); err != nil {
+		return nil, fmt.Errorf("%w: %w", errKDBX, err)
+	}
+	res := &Result{}
+	recycleBin := ""
+	if strings.EqualFold(file.Meta.RecycleBinEnabled, "true") {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+	for _, group := range file.Root.Groups {
+		// The root group is not a folder of its own.
+		group.addEntries(res, "", recycleBin, binaries)
+	}
+	return res, nil
+}
+
+// readKDBXFields reads the fields of a header up to its end: a byte of type,
+// 4 bytes of length and the value. Every field is also passed to each, as
+// some are repeated.
+func readKDBXFields(r *bytes.Reader, each ...func(id byte, value []byte)) (map[byte][]byte, error) {
+	fields := make(map[byte][]byte)
+	for {
+		id, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated header", errKDBX)
+		}
+		var size uint32
+		if err = binary.Read(r, binary.LittleEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+			return nil, fmt.Errorf("%w: truncated header", errKDBX)
+		}
+		value := make([]byte, size)
+		if _, err = io.ReadFull(r, value); err != nil {
+			return nil, fmt.Errorf("%w: truncated header", errKDBX)
+		}
+		if id == kdbxEndOfHeader {
+			return fields, nil
+		}
+		fields[id] = value
+		for _, f := range each {
+			f(id, value)
+		}
+	}
+}
+
+// kdbxCompositeKey hashes the password and the key file together.
+func kdbxCompositeKey(opts Options) ([]byte, error) {
+	var composite []byte
+	if opts.Password != "" || len(opts.KeyFile) == 0 {
+		sum := sha256.Sum256([]byte(opts.Password))
+		composite = append(composite, sum[:]...)
+	}
+	if len(opts.KeyFile) > 0 {
+		key, err := keyFileKey(opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		composite = append(composite, key...)
+	}
+	sum := sha256.Sum256(composite)
+	return sum[:], nil
+}
+
+// keyFileKey reads a key file: the XML files of KeePass, 32 bytes, 64 hex
+// digits, or any other file, which is hashed.
+func keyFileKey(data []byte) ([]byte, error) {
+	var keyFile struct {
+		Meta struct {
+			Version string `xml:"Version"`
+		} `xml:"Meta"`
+		Key struct {
+			Data string `xml:"Data"`
+		} `xml:"Key"`
+	}
+	if bytes.Contains(data, []byte("<KeyFile>")) && xml.Unmarshal(data, &keyFile) == nil {
+		keyData := strings.Join(strings.Fields(keyFile.Key.Data), "")
+		var key []byte
+		var err error
+		if strings.HasPrefix(keyFile.Meta.Version, "2.") {
+			key, err = hex.DecodeString(keyData)
+		} else {
+			key, err = base64.StdEncoding.DecodeString(keyData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file: %w", err)
+		}
+		return key, nil
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+	if len(data) == 64 {
+		if key, err := hex.DecodeString(string(data)); err == nil {
+			return key, nil
+		}
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// kdbxTransformKey derives the key with the key derivation function of
+// the header, stored as a variant dictionary.
+func kdbxTransformKey(compositeKey, params []byte) ([]byte, error) {
+	dict, err := readVariantDictionary(params)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.FromBytes(dict["$UUID"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: no key derivation function", errKDBX)
+	}
+	u32 := func(name string) uint32 {
+		if v := dict[name]; len(v) == 4 {
+			return binary.LittleEndian.Uint32(v)
+		}
+		return 0
+	}
+	u64 := func(name string) uint64 {
+		if v := dict[name]; len(v) == 8 {
+			return binary.LittleEndian.Uint64(v)
+		}
+		return 0
+	}
+
+	switch id {
+	case kdbxKdfAES, kdbxKdfAES4:
+		block, err := aes.NewCipher(dict["S"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+		rounds := u64("R")
+		if rounds > kdbxMaxAESRounds {
+			return nil, fmt.Errorf("%w: too many AES-KDF rounds: %d", errKDBX, rounds)
+		}
+		key := append([]byte{}, compositeKey...)
+		for range rounds {
+			block.Encrypt(key[:16], key[:16])
+			block.Encrypt(key[16:], key[16:])
+		}
+		sum := sha256.Sum256(key)
+		return sum[:], nil
+	case kdbxKdfArgon2d, kdbxKdfArgon2id:
+		mode := argon2d
+		if id == kdbxKdfArgon2id {
+			mode = argon2id
+		}
+		// The sizes are checked before they are narrowed to 32 bits.
+		version, iterations, memory, parallelism := u32("V"), u64("I"), u64("M")/1024, u32("P")
+		if version != argon2Version10 && version != argon2Version13 ||
+			iterations == 0 || iterations > kdbxMaxArgon2Time ||
+			parallelism == 0 || parallelism > kdbxMaxArgon2Threads ||
+			memory < 8*uint64(parallelism) || memory > kdbxMaxArgon2Memory {
+			return nil, fmt.Errorf("%w: invalid Argon2 parameters", errKDBX)
+		}
+		return argon2Key(compositeKey, argon2Params{
+			mode:        mode,
+			version:     version,
+			time:        uint32(iterations),
+			memory:      uint32(memory),
+			parallelism: parallelism,
+			salt:        dict["S"],
+			secret:      dict["K"],
+			data:        dict["A"],
+			keyLen:      32,
+		}), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown key derivation function %s", errKDBX, id)
+	}
+}
+
+// readVariantDictionary reads the typed key-value pairs of the KDF
+// parameters, keeping the raw values.
+func readVariantDictionary(data []byte) (map[string][]byte, error) {
+	r := bytes.NewReader(data)
+	var version uint16
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil || version>>8 != 1 {
+		return nil, fmt.Errorf("%w: invalid key derivation parameters", errKDBX)
+	}
+	dict := make(map[string][]byte)
+	read := func() ([]byte, error) {
+		var size uint32
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+			return nil, fmt.Errorf("%w: invalid key derivation parameters", errKDBX)
+		}
+		b := make([]byte, size)
+		_, err := io.ReadFull(r, b)
+		return b, err
+	}
+	for {
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid key derivation parameters", errKDBX)
+		}
+		if kind == 0 {
+			return dict, nil
+		}
+		name, err := read()
+		if err != nil {
+			return nil, err
+		}
+		value, err := read()
+		if err != nil {
+			return nil, err
+		}
+		dict[string(name)] = value
+	}
+}
+
+// kdbxHMAC returns the HMAC of a block, or of the header with the index
+// ^uint64(0).
+func kdbxHMAC(hmacKey []byte, index uint64, data ...[]byte) []byte {
+	var i [8]byte
+	binary.LittleEndian.PutUint64(i[:], index)
+	blockKey := sha512.Sum512(append(i[:], hmacKey...))
+	mac := hmac.New(sha256.New, blockKey[:])
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// readKDBXBlocks reads the encrypted payload, checking the HMAC of every
+// block.
+func readKDBXBlocks(r *bytes.Reader, hmacKey []byte) ([]byte, error) {
+	var payload []byte
+	for index := uint64(0); ; index++ {
+		var mac [32]byte
+		var size [4]byte
+		if _, err := io.ReadFull(r, mac[:]); err != nil {
+			return nil, fmt.Errorf("%w: truncated block", errKDBX)
+		}
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil, fmt.Errorf("%w: truncated block", errKDBX)
+		}
+		n := binary.LittleEndian.Uint32(size[:])
+		if int64(n) > int64(r.Len()) {
+			return nil, fmt.Errorf("%w: truncated block", errKDBX)
+		}
+		block := make([]byte, n)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, fmt.Errorf("%w: truncated block", errKDBX)
+		}
+		var i [8]byte
+		binary.LittleEndian.PutUint64(i[:], index)
+		if !hmac.Equal(kdbxHMAC(hmacKey, index, i[:], size[:], block), mac[:]) {
+			return nil, fmt.Errorf("%w: block %d is damaged", errKDBX, index)
+		}
+		if n == 0 {
+			return payload, nil
+		}
+		payload = append(payload, block...)
+	}
+}
+
+func kdbxDecrypt(header map[byte][]byte, key, data []byte) ([]byte, error) {
+	id, err := uuid.FromBytes(header[kdbxCipherID])
+	if err != nil {
+		return nil, fmt.Errorf("%w: no cipher", errKDBX)
+	}
+	iv := header[kdbxEncryptionIV]
+	var block cipher.Block
+	switch id {
+	case kdbxCipherChaCha20:
+		c, err := chacha20.NewUnauthenticatedCipher(key, iv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+		out := make([]byte, len(data))
+		c.XORKeyStream(out, data)
+		return out, nil
+	case kdbxCipherAES:
+		block, err = aes.NewCipher(key)
+	case kdbxCipherTwofish:
+		block, err = twofish.NewCipher(key)
+	default:
+		return nil, fmt.Errorf("%w: unknown cipher %s", errKDBX, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errKDBX, err)
+	}
+	return decryptCBC(block, iv, data)
+}
+
+// decryptCBC decrypts data padded with PKCS #7.
+func decryptCBC(block cipher.Block, iv, data []byte) ([]byte, error) {
+	size := block.BlockSize()
+	if len(iv) != size || len(data) == 0 || len(data)%size != 0 {
+		return nil, ErrWrongPassword
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > size || !bytes.Equal(out[len(out)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, ErrWrongPassword
+	}
+	return out[:len(out)-pad], nil
+}
+
+// kdbxInnerStream returns the cipher of the protected values.
+func kdbxInnerStream(inner map[byte][]byte) (cipher.Stream, error) {
+	key := inner[kdbxInnerStreamKey]
+	id := inner[kdbxInnerStreamID]
+	if len(id) != 4 {
+		return nil, fmt.Errorf("%w: no inner stream", errKDBX)
+	}
+	switch binary.LittleEndian.Uint32(id) {
+	case kdbxStreamChaCha20:
+		sum := sha512.Sum512(key)
+		return chacha20.NewUnauthenticatedCipher(sum[:32], sum[32:44])
+	case kdbxStreamSalsa20:
+		sum := sha256.Sum256(key)
+		return newSalsa20Stream(sum, kdbxSalsa20Nonce), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown inner stream %d", errKDBX, binary.LittleEndian.Uint32(id))
+	}
+}
+
+// salsa20Stream is Salsa20 as a cipher.Stream, which salsa20 does not
+// provide: the key stream goes on across calls.
+type salsa20Stream struct {
+	key     [32]byte
+	counter [16]byte
+	block   [64]byte
+	used    int
+}
+
+func newSalsa20Stream(key [32]byte, nonce []byte) *salsa20Stream {
+	s := &salsa20Stream{key: key, used: len(salsa20Stream{}.block)}
+	copy(s.counter[:8], nonce)
+	return s
+}
+
+func (s *salsa20Stream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if s.used == len(s.block) {
+			var zero [64]byte
+			salsa.XORKeyStream(s.block[:], zero[:], &s.counter, &s.key)
+			binary.LittleEndian.PutUint64(s.counter[8:], binary.LittleEndian.Uint64(s.counter[8:])+1)
+			s.used = 0
+		}
+		dst[i] = src[i] ^ s.block[s.used]
+		s.used++
+	}
+}
+
+// unprotectXML replaces the protected values by their plaintext. They are
+// encrypted with a single key stream in the order of the document, so the
+// values of the history are decrypted too.
+func unprotectXML(doc []byte, stream cipher.Stream) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	var out bytes.Buffer
+	enc := xml.NewEncoder(&out)
+	protected := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			protected = false
+			for i, attr := range t.Attr {
+				if attr.Name.Local == "Protected" && strings.EqualFold(attr.Value, "true") {
+					protected = true
+					t.Attr = append(t.Attr[:i:i], t.Attr[i+1:]...)
+					break
+				}
+			}
+			tok = t
+		case xml.CharData:
+			if protected {
+				value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(t)))
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid protected value", errKDBX)
+				}
+				stream.XORKeyStream(value, value)
+				tok = xml.CharData(value)
+			}
+		case xml.EndElement:
+			protected = false
+		case xml.ProcInst:
+			// The declaration is not needed and xml.Encoder refuses
+			// to write one after other tokens.
+			continue
+		}
+		if err = enc.EncodeToken(tok); err != nil {
+			return nil, fmt.Errorf("%w: %w", errKDBX, err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+type kdbxFile struct {
+	Meta struct {
+		RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []kdbxGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type kdbxGroup struct {
+	UUID    string      `xml:"UUID"`
+	Name    string      `xml:"Name"`
+	Entries []kdbxEntry `xml:"Entry"`
+	Groups  []kdbxGroup `xml:"Group"`
+}
+
+type kdbxEntry struct {
+	Tags    string `xml:"Tags"`
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref int `xml:"Ref,attr"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+}
+
+func (g kdbxGroup) addEntries(res *Result, folder, recycleBin string, binaries [][]byte) {
+	if recycleBin != "" && g.UUID == recycleBin {
+		return
+	}
+	for _, entry := range g.Entries {
+		entry.add(res, folder, binaries)
+	}
+	for _, group := range g.Groups {
+		sub := group.Name
+		if folder != "" {
+			sub = folder + "/" + group.Name
+		}
+		group.addEntries(res, sub, recycleBin, binaries)
+	}
+}
+
+// add maps the entry to credentials, or to a text when it holds only notes.
+// Its attachments become binaries named after the entry.
+func (e kdbxEntry) add(res *Result, folder string, binaries [][]byte) {
+	fields := make(map[string]string, len(e.Strings))
+	for _, s := range e.Strings {
+		fields[s.Key] = s.Value
+	}
+	title := fields["Title"]
+	m := newMeta(title)
+	m.set(metaFolder, folder)
+	m.set(metaTags, strings.Join(strings.FieldsFunc(e.Tags, func(r rune) bool { return r == ';' || r == ',' }), ", "))
+	for _, s := range e.Strings {
+		switch s.Key {
+		case "Title", "UserName", "Password", "URL", "Notes":
+		case "otp", "TimeOtp-Secret-Base32":
+			m.set(metaTOTP, s.Value)
+		default:
+			m.set(s.Key, s.Value)
+		}
+	}
+
+	empty := fields["UserName"] == "" && fields["Password"] == "" && fields["URL"] == ""
+	switch {
+	case empty && fields["Notes"] != "":
+		res.add(models.RecordTypeText, models.Text{Text: fields["Notes"], Metadata: models.Metadata(m)})
+	case empty && len(e.Binaries) > 0:
+		// The entry only holds its attachments.
+	default:
+		m.set(metaNotes, fields["Notes"])
+		res.add(models.RecordTypeCredentials, models.Credentials{
+			Resource: fields["URL"],
+			Login:    fields["UserName"],
+			Password: fields["Password"],
+			Metadata: models.Metadata(m),
+		})
+	}
+
+	for _, b := range e.Binaries {
+		if b.Value.Ref < 0 || b.Value.Ref >= len(binaries) {
+			res.skip("attachment %q of %q: missing in the database", b.Key, title)
+			continue
+		}
+		bm := newMeta(title + "/" + b.Key)
+		bm.set(metaFilename, b.Key)
+		bm.set(metaFolder, folder)
+		res.add(models.RecordTypeBinary, models.Binary{Binary: binaries[b.Value.Ref], Metadata: models.Metadata(bm)})
+	}
+}
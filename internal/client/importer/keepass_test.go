@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// variantDictionary encodes KDF parameters, all as byte arrays since only
+// the raw values are read.
+func variantDictionary(kdf uuid.UUID, values map[string][]byte) []byte {
+	buf := binary.LittleEndian.AppendUint16(nil, 0x0100)
+	values["$UUID"] = kdf[:]
+	for name, value := range values {
+		buf = append(buf, 0x42)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(name)))
+		buf = append(buf, name...)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
+		buf = append(buf, value...)
+	}
+	return append(buf, 0)
+}
+
+func u32(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+func u64(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+
+func argon2Dictionary(version uint32, iterations, memory uint64, parallelism uint32) []byte {
+	return variantDictionary(kdbxKdfArgon2d, map[string][]byte{
+		"V": u32(version),
+		"I": u64(iterations),
+		"M": u64(memory),
+		"P": u32(parallelism),
+		"S": bytes.Repeat([]byte{0x02}, 32),
+	})
+}
+
+func TestKDBXTransformKey(t *testing.T) {
+	compositeKey := bytes.Repeat([]byte{0x01}, 32)
+	for name, params := range map[string][]byte{
+		"AES-KDF": variantDictionary(kdbxKdfAES, map[string][]byte{"R": u64(1000), "S": bytes.Repeat([]byte{0x02}, 32)}),
+		"Argon2d": argon2Dictionary(argon2Version13, 2, 64*1024, 2),
+	} {
+		t.Run(name, func(t *testing.T) {
+			key, err := kdbxTransformKey(compositeKey, params)
+			if err != nil {
+				t.Fatalf("kdbxTransformKey: %v", err)
+			}
+			if len(key) != 32 {
+				t.Fatalf("key of %d bytes, want 32", len(key))
+			}
+		})
+	}
+}
+
+// TestKDBXTransformKeyBounds checks that parameters which would exhaust the
+// memory or run for hours are refused before any work is done.
+func TestKDBXTransformKeyBounds(t *testing.T) {
+	tests := map[string][]byte{
+		"AES-KDF rounds":                 variantDictionary(kdbxKdfAES, map[string][]byte{"R": u64(1 << 62), "S": bytes.Repeat([]byte{0x02}, 32)}),
+		"no iterations":                  argon2Dictionary(argon2Version13, 0, 64*1024, 2),
+		"iterations":                     argon2Dictionary(argon2Version13, kdbxMaxArgon2Time+1, 64*1024, 2),
+		"iterations wrapping to 32 bits": argon2Dictionary(argon2Version13, 1<<32+1, 64*1024, 2),
+		"memory":                         argon2Dictionary(argon2Version13, 2, (kdbxMaxArgon2Memory+1)*1024, 2),
+		"memory wrapping to 32 bits":     argon2Dictionary(argon2Version13, 2, (1<<32+64)*1024, 2),
+		"memory below 8 blocks per lane": argon2Dictionary(argon2Version13, 2, 15*1024, 2),
+		"no parallelism":                 argon2Dictionary(argon2Version13, 2, 64*1024, 0),
+		"parallelism":                    argon2Dictionary(argon2Version13, 2, 64*1024, kdbxMaxArgon2Threads+1),
+		"parallelism overflowing memory": argon2Dictionary(argon2Version13, 2, 64*1024, 1<<30),
+		"version":                        argon2Dictionary(0x12, 2, 64*1024, 2),
+	}
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := kdbxTransformKey(nil, params); !errors.Is(err, errKDBX) {
+				t.Fatalf("kdbxTransformKey returned %v, want %v", err, errKDBX)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// onePUXData is the file of a 1PUX archive holding the items, the attached
+// files are in onePUXFiles.
+const (
+	onePUXData  = "export.data"
+	onePUXFiles = "files/"
+)
+
+// Categories of 1Password items.
+const (
+	onePasswordLogin    = "001"
+	onePasswordCard     = "002"
+	onePasswordNote     = "003"
+	onePasswordPassword = "005"
+	onePasswordDocument = "006"
+)
+
+type onePUXExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePUXItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePUXItem struct {
+	Trashed      bool   `json:"trashed"`
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Details      struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Title  string `json:"title"`
+			Fields []struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+		DocumentAttributes *struct {
+			FileName   string `json:"fileName"`
+			DocumentID string `json:"documentId"`
+		} `json:"documentAttributes"`
+	} `json:"details"`
+	Overview struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+		Tags []string `json:"tags"`
+	} `json:"overview"`
+}
+
+// onePUXField is a field of a section with its value as text.
+type onePUXField struct {
+	id, title, kind, value string
+	// privateKey and publicKey are set for SSH keys.
+	privateKey, publicKey string
+}
+
+// parse1PUX reads a 1PUX archive of 1Password 8. Items in the trash are left
+// out, archived items are imported.
+func parse1PUX(data []byte) (*Result, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid 1PUX archive: %w", err)
+	}
+	files := make(map[string]*zip.File)
+	var export onePUXExport
+	found := false
+	for _, f := range archive.File {
+		switch {
+		case f.Name == onePUXData:
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if err = json.Unmarshal(content, &export); err != nil {
+				return nil, fmt.Errorf("invalid 1PUX archive: %w", err)
+			}
+			found = true
+		case strings.HasPrefix(f.Name, onePUXFiles):
+			files[path.Base(f.Name)] = f
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid 1PUX archive: no %s", onePUXData)
+	}
+
+	res := &Result{}
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				if item.Trashed || item.State == "deleted" {
+					continue
+				}
+				if err = item.add(res, vault.Attrs.Name, files); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (item onePUXItem) add(res *Result, vault string, files map[string]*zip.File) error {
+	title := item.Overview.Title
+	m := newMeta(title)
+	m.set(metaFolder, vault)
+	m.set(metaTags, strings.Join(item.Overview.Tags, ", "))
+	for _, u := range item.Overview.URLs {
+		if u.URL != item.Overview.URL {
+			m.set(metaURL, u.URL)
+		}
+	}
+	if item.State == "archived" {
+		m.set("archived", "true")
+	}
+
+	var fields []onePUXField
+	for _, section := range item.Details.Sections {
+		for _, field := range section.Fields {
+			f := onePUXField{id: field.ID, title: field.Title}
+			for kind, raw := range field.Value {
+				f.kind = kind
+				f.value, f.privateKey, f.publicKey = onePUXValue(kind, raw)
+			}
+			if f.title == "" {
+				f.title = f.id
+			}
+			fields = append(fields, f)
+		}
+	}
+	// setFields keeps the fields not mapped to the record in the metadata.
+	setFields := func(mapped ...string) {
+		for _, f := range fields {
+			if !slices.Contains(mapped, f.id) && f.privateKey == "" {
+				if f.kind == "totp" {
+					m.set(metaTOTP, f.value)
+				} else {
+					m.set(f.title, f.value)
+				}
+			}
+		}
+	}
+	field := func(id string) string {
+		for _, f := range fields {
+			if f.id == id {
+				return f.value
+			}
+		}
+		return ""
+	}
+
+	switch item.CategoryUUID {
+	case onePasswordCard:
+		setFields("cardholder", "ccnum", "cvv", "expiry")
+		m.set(metaNotes, item.Details.NotesPlain)
+		res.add(models.RecordTypeCard, models.Card{
+			CardNumber: field("ccnum"),
+			EXP:        field("expiry"),
+			CVV:        field("cvv"),
+			CardHolder: field("cardholder"),
+			Metadata:   models.Metadata(m),
+		})
+		return nil
+	case onePasswordNote:
+		setFields()
+		res.add(models.RecordTypeText, models.Text{Text: item.Details.NotesPlain, Metadata: models.Metadata(m)})
+		return nil
+	case onePasswordDocument:
+		setFields()
+		m.set(metaNotes, item.Details.NotesPlain)
+		doc := item.Details.DocumentAttributes
+		if doc == nil {
+			res.skip("%q: a document without a file", title)
+			return nil
+		}
+		return addOnePUXFile(res, m, doc.DocumentID, doc.FileName, files)
+	}
+
+	for _, f := range fields {
+		if f.privateKey != "" {
+			setFields(f.id)
+			m.set(metaNotes, item.Details.NotesPlain)
+			addSSHKey(res, f.privateKey, f.publicKey, m)
+			return nil
+		}
+	}
+
+	var login, password string
+	for _, f := range item.Details.LoginFields {
+		switch f.Designation {
+		case "username":
+			login = f.Value
+		case "password":
+			password = f.Value
+		default:
+			m.set(f.Name, f.Value)
+		}
+	}
+	if password == "" {
+		password = item.Details.Password
+	}
+	if item.CategoryUUID == onePasswordLogin || item.CategoryUUID == onePasswordPassword || login != "" || password != "" {
+		setFields()
+		m.set(metaNotes, item.Details.NotesPlain)
+		res.add(models.RecordTypeCredentials, models.Credentials{
+			Resource: item.Overview.URL,
+			Login:    login,
+			Password: password,
+			Metadata: models.Metadata(m),
+		})
+		return nil
+	}
+
+	// Identities, software licenses, bank accounts and the other
+	// categories become texts listing their fields.
+	var text strings.Builder
+	for _, f := range fields {
+		if f.value != "" {
+			fmt.Fprintf(&text, "%s: %s\n", f.title, f.value)
+		}
+	}
+	if item.Details.NotesPlain != "" {
+		fmt.Fprintf(&text, "\n%s\n", item.Details.NotesPlain)
+	}
+	res.add(models.RecordTypeText, models.Text{Text: text.String(), Metadata: models.Metadata(m)})
+	return nil
+}
+
+// addOnePUXFile adds the attached file, stored as files/ID__NAME.
+func addOnePUXFile(res *Result, m meta, id, name string, files map[string]*zip.File) error {
+	f := files[id+"__"+name]
+	if f == nil && id != "" {
+		for base, candidate := range files {
+			if strings.HasPrefix(base, id) {
+				f = candidate
+				break
+			}
+		}
+	}
+	if f == nil {
+		res.skip("%q: the file %q is not in the archive", m[metaName], name)
+		return nil
+	}
+	content, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	m.set(metaFilename, name)
+	res.add(models.RecordTypeBinary, models.Binary{Binary: content, Metadata: models.Metadata(m)})
+	return nil
+}
+
+// onePUXValue returns a value of a field as text. Months are written as
+// MM/YY like the expiry of cards, dates as YYYY-MM-DD.
+func onePUXValue(kind string, raw json.RawMessage) (value, privateKey, publicKey string) {
+	switch kind {
+	case "monthYear":
+		var n int
+		if json.Unmarshal(raw, &n) == nil && n > 0 {
+			return expiry(strconv.Itoa(n%100), strconv.Itoa(n/100)), "", ""
+		}
+		return "", "", ""
+	case "date":
+		var n int64
+		if json.Unmarshal(raw, &n) == nil && n != 0 {
+			return time.Unix(n, 0).UTC().Format(time.DateOnly), "", ""
+		}
+		return "", "", ""
+	case "email":
+		var email struct {
+			Address string `json:"email_address"`
+		}
+		if json.Unmarshal(raw, &email) == nil {
+			return email.Address, "", ""
+		}
+	case "address":
+		var address struct {
+			Street, City, State, Zip, Country string
+		}
+		if json.Unmarshal(raw, &address) == nil {
+			parts := []string{address.Street, address.City, address.State, address.Zip, address.Country}
+			return strings.Join(nonEmpty(parts), ", "), "", ""
+		}
+	case "sshKey":
+		var key struct {
+			PrivateKey string `json:"privateKey"`
+			Metadata   struct {
+				PublicKey string `json:"publicKey"`
+			} `json:"metadata"`
+		}
+		if json.Unmarshal(raw, &key) == nil {
+			return "", key.PrivateKey, key.Metadata.PublicKey
+		}
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, "", ""
+	}
+	// Other values are kept as they are written.
+	return string(raw), "", ""
+}
+
+func nonEmpty(values []string) []string {
+	var res []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const onePUXExportData = `{"accounts": [{"vaults": [{"attrs": {"name": "Private"}, "items": [
+  {"categoryUuid": "001", "state": "active",
+   "overview": {"title": "Example", "url": "https://example.com", "urls": [{"url": "https://example.com"}, {"url": "https://example.org"}], "tags": ["a", "b"]},
+   "details": {"loginFields": [{"value": "alice", "designation": "username"}, {"value": "secret", "designation": "password"}], "notesPlain": "n",
+     "sections": [{"fields": [{"title": "one-time password", "id": "otp", "value": {"totp": "otpauth://totp/x"}}]}]}},
+  {"categoryUuid": "002", "state": "archived", "overview": {"title": "Visa"},
+   "details": {"sections": [{"fields": [
+     {"id": "cardholder", "value": {"string": "Alice"}}, {"id": "ccnum", "value": {"creditCardNumber": "4111111111111111"}},
+     {"id": "cvv", "value": {"concealed": "123"}}, {"id": "expiry", "value": {"monthYear": 203003}}]}]}},
+  {"categoryUuid": "003", "overview": {"title": "Note"}, "details": {"notesPlain": "text"}},
+  {"categoryUuid": "006", "overview": {"title": "Doc"}, "details": {"documentAttributes": {"fileName": "a.txt", "documentId": "doc1"}}},
+  {"categoryUuid": "004", "overview": {"title": "Me"}, "details": {"sections": [{"fields": [
+     {"title": "email", "id": "email", "value": {"email": {"email_address": "alice@example.com"}}},
+     {"title": "birth date", "id": "birth", "value": {"date": 0}}]}]}},
+  {"categoryUuid": "001", "trashed": true, "overview": {"title": "Trashed"}}
+]}]}]}`
+
+// onePUX zips the files into a 1PUX archive.
+func onePUX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse1PUX(t *testing.T) {
+	data := onePUX(t, map[string]string{
+		onePUXData:                  onePUXExportData,
+		onePUXFiles + "doc1__a.txt": "content",
+	})
+	if format, err := Detect(data); err != nil || format != Format1PUX {
+		t.Fatalf("Detect = %q, %v, want %q", format, err, Format1PUX)
+	}
+	res, err := Parse(Format1PUX, data, Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Item{
+		{Type: models.RecordTypeCredentials, Data: models.Credentials{
+			Resource: "https://example.com", Login: "alice", Password: "secret",
+			Metadata: models.Metadata{"name": "Example", "folder": "Private", "tags": "a, b", "url": "https://example.org", "totp": "otpauth://totp/x", "notes": "n"},
+		}},
+		{Type: models.RecordTypeCard, Data: models.Card{
+			CardNumber: "4111111111111111", EXP: "03/30", CVV: "123", CardHolder: "Alice",
+			Metadata: models.Metadata{"name": "Visa", "folder": "Private", "archived": "true"},
+		}},
+		{Type: models.RecordTypeText, Data: models.Text{Text: "text", Metadata: models.Metadata{"name": "Note", "folder": "Private"}}},
+		{Type: models.RecordTypeBinary, Data: models.Binary{Binary: []byte("content"), Metadata: models.Metadata{"name": "Doc", "folder": "Private", "filename": "a.txt"}}},
+		{Type: models.RecordTypeText, Data: models.Text{Text: "email: alice@example.com\n", Metadata: models.Metadata{"name": "Me", "folder": "Private"}}},
+	}
+	if !reflect.DeepEqual(res.Items, want) {
+		t.Fatalf("items\n%#v\nwant\n%#v", res.Items, want)
+	}
+}
+
+func TestParse1PUXMissingFile(t *testing.T) {
+	res, err := Parse(Format1PUX, onePUX(t, map[string]string{onePUXData: onePUXExportData}), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(res.Items) != 4 || len(res.Skipped) != 1 {
+		t.Fatalf("%d items, skipped %q, want 4 items and the document skipped", len(res.Items), res.Skipped)
+	}
+
+	if _, err = Parse(Format1PUX, onePUX(t, map[string]string{"other": "{}"}), Options{}); err == nil {
+		t.Fatal("Parse of an archive without export.data succeeded")
+	}
+}
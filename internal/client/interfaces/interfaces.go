@@ -10,31 +10,80 @@ import (
 )
 
 var (
-	ErrLoginTaken      = errors.New("login already exists")
-	ErrNotFound        = errors.New("not found")
-	ErrUnauthorized    = errors.New("unauthorized")
-	ErrVersionConflict = errors.New("version conflict")
-	ErrBadRequest      = errors.New("bad request")
-	ErrUnexpected      = errors.New("unexpected response")
+	ErrLoginTaken         = errors.New("login already exists")
+	ErrNotFound           = errors.New("not found")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrVersionConflict    = errors.New("version conflict")
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnexpected         = errors.New("unexpected response")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrInvalidInvite      = errors.New("invite code is missing or invalid")
+	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidAccessToken = errors.New("invalid access token")
+	ErrInvalidShare       = errors.New("unknown user or record cannot be shared")
+	ErrInvalidOrg         = errors.New("invalid organization request")
+	ErrLastOrgOwner       = errors.New("you are the only owner of an organization, make another member an owner or delete it first")
+	ErrInvalidEmergency   = errors.New("unknown user, user without a key pair or invalid waiting period")
+	ErrEmergencyState     = errors.New("emergency access is not in a state that allows this")
+	ErrInvalidSend        = errors.New("invalid view limit or expiry")
+	ErrInvalidSendLink    = errors.New("invalid send link")
+	ErrSendPassword       = errors.New("the send needs a password or the password is wrong")
+	ErrInvalidSession     = errors.New("the saved session does not match the session key or the server")
 )
 
 type SecuritySource interface {
 	api.SecuritySource
 	SetToken(token string)
+	Token() string
 }
 
 type Service interface {
 	AuthService
 	CryptoService
 	SyncService
+	TokenService
+	ShareService
+	OrgService
+	EmergencyService
+	SendService
 	Storage
+	// SwitchVault makes the records of the organization the records the
+	// service works with, nil switches back to the personal vault.
+	SwitchVault(ctx context.Context, org *models.Organization) error
+	// CurrentVault returns the organization whose records are in use, nil
+	// for the personal vault.
+	CurrentVault() *models.Organization
+	// ShareRecord shares the record with the user with the login, giving it
+	// a record key first if it has none.
+	ShareRecord(ctx context.Context, record *models.Record, login string) error
+	// CaptureRecord saves a new record in the local cache as captured, for
+	// the user to approve. Approving pushes it with PushRecord.
+	CaptureRecord(record *models.Record) (*models.Record, error)
+	// CreateAccessToken returns the full token including its key part.
+	CreateAccessToken(ctx context.Context, req models.AccessTokenRequest) (token string, err error)
+	// LoginWithAccessToken authenticates a headless client, which reads the
+	// records from the server without a local cache.
+	LoginWithAccessToken(ctx context.Context, token string) error
+	// Session returns the session of the logged in user with the vault key
+	// wrapped with sessionKey, so that ResumeSession can restore it without
+	// the password.
+	Session(sessionKey []byte) (*models.Session, error)
+	// ResumeSession restores a session returned by Session and opens the
+	// local cache of its records. The server is not contacted.
+	ResumeSession(session *models.Session, sessionKey []byte) error
 	FetchServerVersion(ctx context.Context) (versionInfo models.VersionInfo, err error)
+	FetchRegistrationMode(ctx context.Context) (models.RegistrationMode, error)
 }
 
 type NewAuthService func(client api.Invoker, security SecuritySource) AuthService
 type AuthService interface {
-	Register(ctx context.Context, login, password string) (userID string, err error)
+	Register(ctx context.Context, login, password, inviteCode string) (userID string, err error)
 	Login(ctx context.Context, login, password string) (userID string, err error)
+	DeleteAccount(ctx context.Context, password string) error
+	// AuthenticateAccessToken authenticates with the token without its key
+	// part and returns the keys wrapped for it.
+	AuthenticateAccessToken(ctx context.Context, token string) (*models.AccessTokenKeys, error)
 }
 
 type NewCryptoService func(newCryptoStorage NewCryptoStorage) CryptoService
@@ -42,6 +91,23 @@ type CryptoService interface {
 	InitCrypto(userID, login, password string) (Storage, error)
 	EncryptRecord(record *models.Record) error
 	DecryptRecord(record *models.Record) error
+	InitAccessTokenCrypto(tokenKey []byte, keys *models.AccessTokenKeys) error
+	InitSessionCrypto(userID string, sessionKey, wrappedVaultKey []byte) (Storage, error)
+	WrapRecordKey(tokenKey []byte, record *models.Record) ([]byte, error)
+	WrapVaultKey(tokenKey []byte) ([]byte, error)
+	GenerateKeyPair() (publicKey, wrappedPrivateKey []byte, err error)
+	InitKeyPair(wrappedPrivateKey []byte) error
+	WrapRecordKeyFor(publicKey []byte, record *models.Record) ([]byte, error)
+	DecryptSharedRecord(record *models.Record) error
+	NewOrgKey() ([]byte, error)
+	WrapOrgKeyFor(publicKey, orgKey []byte) ([]byte, error)
+	UnwrapOrgKey(wrapped []byte) ([]byte, error)
+	EncryptOrgRecord(orgKey []byte, record *models.Record) error
+	DecryptOrgRecord(orgKey []byte, record *models.Record) error
+	WrapVaultKeyFor(publicKey []byte) ([]byte, error)
+	DecryptEmergencyRecords(wrappedVaultKey []byte, records []*models.Record) error
+	EncryptSend(data []byte) (key, nonce, ciphertext []byte, err error)
+	DecryptSend(key, nonce, ciphertext []byte) ([]byte, error)
 }
 
 type NewSyncService func(client api.Invoker, storage Storage, crypto CryptoService) SyncService
@@ -50,11 +116,87 @@ type SyncService interface {
 	PullRecord(ctx context.Context, id uuid.UUID) (*models.Record, error)
 	ForgetRecord(ctx context.Context, record *models.Record) error
 	Sync(ctx context.Context) (hasConflicts bool, err error)
+	// FetchRecords returns the decrypted records on the server.
+	FetchRecords(ctx context.Context) ([]*models.Record, error)
+}
+
+type NewTokenService func(client api.Invoker, crypto CryptoService) TokenService
+type TokenService interface {
+	// CreateTokenForRecords creates a token limited to records when records
+	// is not nil. The records must have record keys.
+	CreateTokenForRecords(ctx context.Context, req models.AccessTokenRequest, records []*models.Record) (token string, err error)
+	ListAccessTokens(ctx context.Context) ([]*models.AccessToken, error)
+	RevokeAccessToken(ctx context.Context, id uuid.UUID) error
+}
+
+type NewShareService func(client api.Invoker, crypto CryptoService) ShareService
+type ShareService interface {
+	// EnsureKeyPair loads the key pair records are shared with, generating
+	// and publishing it on first use.
+	EnsureKeyPair(ctx context.Context) error
+	// ShareRecordWith wraps the record key for the public key of the user.
+	// The record must have a record key.
+	ShareRecordWith(ctx context.Context, record *models.Record, login string) error
+	ListShares(ctx context.Context, recordID uuid.UUID) ([]*models.Share, error)
+	RevokeShare(ctx context.Context, recordID, userID uuid.UUID) error
+	FetchSharedRecords(ctx context.Context) ([]*models.SharedRecord, error)
+}
+
+type NewOrgService func(client api.Invoker, crypto CryptoService) OrgService
+type OrgService interface {
+	// ListOrgs returns the organizations of the user with their keys unwrapped.
+	ListOrgs(ctx context.Context) ([]*models.Organization, error)
+	CreateOrg(ctx context.Context, name string) (*models.Organization, error)
+	DeleteOrg(ctx context.Context, org *models.Organization) error
+	ListOrgMembers(ctx context.Context, org *models.Organization) ([]*models.OrgMember, error)
+	// InviteOrgMember wraps the organization key for the public key of the
+	// user with the login and adds them with the role.
+	InviteOrgMember(ctx context.Context, org *models.Organization, login string, role models.OrgRole) error
+	SetOrgMemberRole(ctx context.Context, org *models.Organization, userID uuid.UUID, role models.OrgRole) error
+	RemoveOrgMember(ctx context.Context, org *models.Organization, userID uuid.UUID) error
+	// RekeyOrg replaces the organization key, wrapping the new one for every
+	// member and encrypting every record with it.
+	RekeyOrg(ctx context.Context, org *models.Organization) error
+	// FetchOrgRecords returns the decrypted records of the organization.
+	FetchOrgRecords(ctx context.Context, org *models.Organization) ([]*models.Record, error)
+	// SaveOrgRecord encrypts and saves the record, its Version must be the
+	// saved version plus one.
+	SaveOrgRecord(ctx context.Context, org *models.Organization, record *models.Record) error
+	DeleteOrgRecord(ctx context.Context, org *models.Organization, id uuid.UUID) error
+}
+
+type NewEmergencyService func(client api.Invoker, crypto CryptoService) EmergencyService
+type EmergencyService interface {
+	// AddEmergencyContact wraps the vault key for the public key of the user
+	// with the login and makes them a trusted contact.
+	AddEmergencyContact(ctx context.Context, login string, waitDays int) error
+	ListEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error)
+	RemoveEmergencyContact(ctx context.Context, id uuid.UUID) error
+	ApproveEmergencyAccess(ctx context.Context, id uuid.UUID) error
+	RejectEmergencyAccess(ctx context.Context, id uuid.UUID) error
+	ListEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error)
+	RequestEmergencyAccess(ctx context.Context, id uuid.UUID) error
+	// FetchEmergencyVault returns the decrypted records of the grantor once
+	// access is approved, ErrForbidden before.
+	FetchEmergencyVault(ctx context.Context, id uuid.UUID) ([]*models.Record, error)
+}
+
+// NewSendService takes the address of the server to put into the links.
+type NewSendService func(client api.Invoker, crypto CryptoService, serverURL string) SendService
+type SendService interface {
+	// CreateSend encrypts the data with a new key and returns the link to
+	// the send. The key is in the fragment of the link, it never reaches
+	// the server.
+	CreateSend(ctx context.Context, req *models.SendRequest) (link string, err error)
+	// OpenSend counts a view of the send behind the link and decrypts it.
+	// No account is needed.
+	OpenSend(ctx context.Context, link, password string) (*models.OpenedSend, error)
 }
 
 type NewCryptoStorage func(userID string, encryptionKey []byte) (Storage, error)
 type Storage interface {
 	Close() error
+	Destroy() error
 	GetRecords() ([]*models.Record, error)
 	SaveRecord(record *models.Record) error
 	GetRecord(id uuid.UUID) (*models.Record, error)
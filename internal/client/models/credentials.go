@@ -0,0 +1,35 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ResourceURL parses the resource of credentials, a URL or a host optionally
+// followed by a path. Resources without a scheme have an empty one.
+func ResourceURL(resource string) (*url.URL, error) {
+	if !strings.Contains(resource, "://") {
+		resource = "//" + resource
+	}
+	return url.Parse(resource)
+}
+
+// Matches reports whether the credentials may be used at target, a web page
+// or a server asking for them. They match target on the host of their
+// resource and its subdomains, with any port. A resource with a scheme
+// matches only that scheme, except that http credentials may be sent over
+// https; https credentials are never sent over http. A target without a
+// scheme matches any resource on its host.
+func (c Credentials) Matches(target *url.URL) bool {
+	u, err := ResourceURL(c.Resource)
+	if err != nil || u.Hostname() == "" || target.Hostname() == "" {
+		return false
+	}
+	if u.Scheme != "" && target.Scheme != "" && !strings.EqualFold(u.Scheme, target.Scheme) &&
+		!(strings.EqualFold(u.Scheme, "http") && strings.EqualFold(target.Scheme, "https")) {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	targetHost := strings.ToLower(target.Hostname())
+	return targetHost == host || strings.HasSuffix(targetHost, "."+host)
+}
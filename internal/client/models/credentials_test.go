@@ -0,0 +1,38 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCredentialsMatches(t *testing.T) {
+	tests := []struct {
+		resource, target string
+		want             bool
+	}{
+		{"https://example.com", "https://example.com/login", true},
+		{"https://example.com", "https://example.com:8443", true},
+		{"https://example.com", "https://git.example.com", true},
+		{"https://www.example.com", "https://example.com", true},
+		{"example.com/path", "https://example.com", true},
+		{"example.com", "http://example.com", true},
+		{"http://example.com", "https://example.com", true},
+		{"https://example.com", "http://example.com", false},
+		{"ssh://example.com", "https://example.com", false},
+		{"https://example.com", "//example.com", true},
+		{"https://example.com", "https://example.org", false},
+		{"https://example.com", "https://badexample.com", false},
+		{"https://git.example.com", "https://example.com", false},
+		{"", "https://example.com", false},
+		{"https://example.com", "https://", false},
+	}
+	for _, tt := range tests {
+		target, err := url.Parse(tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := (Credentials{Resource: tt.resource}).Matches(target); got != tt.want {
+			t.Errorf("credentials of %q match %q: %v, want %v", tt.resource, tt.target, got, tt.want)
+		}
+	}
+}
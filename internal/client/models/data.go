@@ -1,4 +1,4 @@
-package types
+package models
 
 type Metadata map[string]string
 
@@ -27,6 +27,17 @@ type Card struct {
 	Metadata   Metadata
 }
 
+type SSHKey struct {
+	// PrivateKey is in the OpenSSH format, PublicKey in the authorized_keys
+	// format.
+	PrivateKey string
+	PublicKey  string
+	Comment    string
+	// Confirm asks before every use of the key by the SSH agent.
+	Confirm  bool
+	Metadata Metadata
+}
+
 type Data interface {
-	Credentials | Text | Binary | Card
+	Credentials | Text | Binary | Card | SSHKey
 }
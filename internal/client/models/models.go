@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/api"
 )
@@ -68,6 +70,9 @@ const (
 	RecordStatusSynced   RecordStatus = "synced"
 	RecordStatusConflict RecordStatus = "conflict"
 	RecordStatusDeleted  RecordStatus = "deleted"
+	// RecordStatusCaptured is a record captured outside the vault, e.g. by
+	// the browser extension. It stays local until the user approves it.
+	RecordStatusCaptured RecordStatus = "captured"
 )
 
 type RecordType api.RecordType
@@ -77,6 +82,15 @@ const (
 	RecordTypeText        RecordType = RecordType(api.RecordTypeText)
 	RecordTypeBinary      RecordType = RecordType(api.RecordTypeBinary)
 	RecordTypeCard        RecordType = RecordType(api.RecordTypeCard)
+	RecordTypeSSHKey      RecordType = RecordType(api.RecordTypeSSHKey)
+)
+
+type RegistrationMode api.RegistrationMode
+
+const (
+	RegistrationOpen       RegistrationMode = RegistrationMode(api.RegistrationModeOpen)
+	RegistrationInviteOnly RegistrationMode = RegistrationMode(api.RegistrationModeInviteOnly)
+	RegistrationClosed     RegistrationMode = RegistrationMode(api.RegistrationModeClosed)
 )
 
 type Record struct {
@@ -86,4 +100,146 @@ type Record struct {
 	Nonce   []byte
 	Version int
 	Status  RecordStatus
+	// Key is the record key wrapped with the vault key. Records without it
+	// are encrypted with the vault key itself.
+	Key []byte
+	// OrgID is the organization of a record of a team vault, uuid.Nil for
+	// records of the personal vault. Team records are not cached locally.
+	OrgID uuid.UUID
+}
+
+// AccessTokenRequest describes a personal access token to create. Without
+// records and tags the token is not limited to records.
+type AccessTokenRequest struct {
+	Name      string
+	ReadOnly  bool
+	ExpiresAt time.Time
+	RecordIDs []uuid.UUID
+	// Tags select the records whose metadata has the key, or the key with
+	// the value for "key=value" tags.
+	Tags []string
+}
+
+type AccessToken struct {
+	ID       uuid.UUID
+	Name     string
+	ReadOnly bool
+	// Records the token is limited to, nil for a token to all records.
+	Records    []uuid.UUID
+	Tags       []string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// AccessTokenKeys are the keys wrapped for the access token in use.
+type AccessTokenKeys struct {
+	ReadOnly   bool
+	WrappedKey []byte
+	RecordKeys map[uuid.UUID][]byte
+}
+
+// Share is a user a record is shared with.
+type Share struct {
+	UserID    uuid.UUID
+	Login     string
+	CreatedAt time.Time
+}
+
+// SharedRecord is a decrypted record another user shared. It is read-only.
+type SharedRecord struct {
+	Record
+	Owner    string
+	SharedAt time.Time
+}
+
+type OrgRole api.OrgRole
+
+const (
+	OrgRoleOwner  OrgRole = OrgRole(api.OrgRoleOwner)
+	OrgRoleAdmin  OrgRole = OrgRole(api.OrgRoleAdmin)
+	OrgRoleWriter OrgRole = OrgRole(api.OrgRoleWriter)
+	OrgRoleReader OrgRole = OrgRole(api.OrgRoleReader)
+)
+
+// OrgRoles lists the roles from the most to the least privileged.
+var OrgRoles = []OrgRole{OrgRoleOwner, OrgRoleAdmin, OrgRoleWriter, OrgRoleReader}
+
+// CanWrite reports whether the role may change the records of the team vault.
+func (r OrgRole) CanWrite() bool {
+	return r != OrgRoleReader
+}
+
+// CanManage reports whether the role may manage the members.
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+// Organization is a team vault the user is a member of.
+type Organization struct {
+	ID   uuid.UUID
+	Name string
+	Role OrgRole
+	// Key is the unwrapped organization key of KeyVersion.
+	Key        []byte
+	KeyVersion int64
+	// RekeyNeeded is set once a member was removed until the key is replaced.
+	RekeyNeeded bool
+	CreatedAt   time.Time
+}
+
+type OrgMember struct {
+	UserID    uuid.UUID
+	Login     string
+	Role      OrgRole
+	PublicKey []byte
+	CreatedAt time.Time
+}
+
+type EmergencyStatus api.EmergencyStatus
+
+const (
+	EmergencyGranted   EmergencyStatus = EmergencyStatus(api.EmergencyStatusGranted)
+	EmergencyRequested EmergencyStatus = EmergencyStatus(api.EmergencyStatusRequested)
+	EmergencyApproved  EmergencyStatus = EmergencyStatus(api.EmergencyStatusApproved)
+)
+
+// EmergencyAccess is a trusted contact of the user, or a user who made the
+// user their trusted contact. Login is the user on the other side.
+type EmergencyAccess struct {
+	ID       uuid.UUID
+	Login    string
+	WaitDays int
+	Status   EmergencyStatus
+	// RequestedAt and AvailableAt are zero unless access was requested.
+	RequestedAt time.Time
+	AvailableAt time.Time
+	CreatedAt   time.Time
+}
+
+// SendRequest describes a one-time send. An empty Password leaves it
+// unprotected.
+type SendRequest struct {
+	Data      []byte
+	MaxViews  int
+	ExpiresAt time.Time
+	Password  string
+}
+
+// OpenedSend is the decrypted data of a send.
+type OpenedSend struct {
+	Data      []byte
+	ViewsLeft int
+	ExpiresAt time.Time
+}
+
+// Session is a login saved by the command-line interface. The vault key is
+// wrapped with a session key that is not saved along with it.
+type Session struct {
+	ServerAddress string    `json:"server_address"`
+	UserID        string    `json:"user_id"`
+	Login         string    `json:"login"`
+	Token         string    `json:"token"`
+	WrappedKey    []byte    `json:"wrapped_key"`
+	CreatedAt     time.Time `json:"created_at"`
 }
@@ -0,0 +1,86 @@
+package nativemsg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// ApproveTimeout is how long the dialog waits for an answer before the
+// request is refused.
+const ApproveTimeout = time.Minute
+
+// approvalEnv passes the text of the dialog to PowerShell, which would
+// otherwise need it quoted.
+const approvalEnv = "GOPHKEEPER_APPROVAL"
+
+var errNoDialog = errors.New("no dialog program found, install zenity or kdialog")
+
+// DialogApprove asks the user with a dialog of the desktop: zenity or kdialog
+// on Linux and BSD, AppleScript on macOS and PowerShell on Windows. The
+// browser owns stdin and stdout of the host, there is no terminal to ask on.
+func DialogApprove(logger *slog.Logger) ApproveFunc {
+	return func(ctx context.Context, req Approval) bool {
+		ctx, cancel := context.WithTimeout(ctx, ApproveTimeout)
+		defer cancel()
+		cmd, allowed, err := dialog(ctx, req.text())
+		if err != nil {
+			logger.Warn("cannot ask to fill in credentials", slog.Any("error", err))
+			return false
+		}
+		out, err := cmd.Output()
+		return ctx.Err() == nil && allowed(out, err)
+	}
+}
+
+func (a Approval) text() string {
+	return fmt.Sprintf(`The browser extension %s asks for the password of
+
+    %s (%s)
+
+to fill it in on %s.
+
+Allow it only if you just asked to fill in a login form there.`, a.Origin, a.Name, a.Login, a.Page)
+}
+
+// dialog returns the command showing a dialog with the text and the function
+// telling from its output whether the user allowed the request.
+func dialog(ctx context.Context, text string) (*exec.Cmd, func(out []byte, err error) bool, error) {
+	exited := func(_ []byte, err error) bool { return err == nil }
+	timeout := fmt.Sprint(int(ApproveTimeout.Seconds()))
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd := exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", `display dialog (item 1 of argv) with title "GophKeeper" buttons {"Deny", "Allow"} default button "Deny" giving up after `+timeout,
+			"-e", "end run",
+			text)
+		return cmd, func(out []byte, err error) bool {
+			return err == nil && bytes.Contains(out, []byte("button returned:Allow")) && !bytes.Contains(out, []byte("gave up:true"))
+		}, nil
+	case "windows":
+		cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"Add-Type -AssemblyName PresentationFramework; [System.Windows.MessageBox]::Show($env:"+approvalEnv+", 'GophKeeper', 'YesNo', 'Question')")
+		cmd.Env = append(os.Environ(), approvalEnv+"="+text)
+		return cmd, func(out []byte, err error) bool {
+			return err == nil && bytes.Equal(bytes.TrimSpace(out), []byte("Yes"))
+		}, nil
+	}
+
+	if path, err := exec.LookPath("zenity"); err == nil {
+		return exec.CommandContext(ctx, path, "--question", "--no-markup", "--title=GophKeeper", "--text="+text,
+			"--ok-label=Allow", "--cancel-label=Deny", "--timeout="+timeout), exited, nil
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		return exec.CommandContext(ctx, path, "--title", "GophKeeper", "--yesno", text,
+			"--yes-label", "Allow", "--no-label", "Deny"), exited, nil
+	}
+	return nil, nil, errNoDialog
+}
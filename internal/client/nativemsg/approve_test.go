@@ -0,0 +1,79 @@
+package nativemsg_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/nativemsg"
+)
+
+var approval = nativemsg.Approval{Origin: origin, Page: "https://example.com", Name: "Example", Login: "alice"}
+
+// fakeDialog puts a zenity on PATH that exits with the code and writes its
+// arguments to the returned file.
+func fakeDialog(t *testing.T, code string) string {
+	t.Helper()
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the dialog is not zenity on", runtime.GOOS)
+	}
+	dir := t.TempDir()
+	args := filepath.Join(dir, "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + args + "'\nexit " + code + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "zenity"), []byte(script), 0o700); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Setenv("PATH", dir)
+	return args
+}
+
+func dialogApprove() nativemsg.ApproveFunc {
+	return nativemsg.DialogApprove(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestDialogApprove(t *testing.T) {
+	args := fakeDialog(t, "0")
+	if !dialogApprove()(context.Background(), approval) {
+		t.Fatal("the request was refused although the user allowed it")
+	}
+	data, err := os.ReadFile(args)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, want := range []string{approval.Origin, approval.Page, approval.Name, approval.Login} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("the dialog does not show %q: %s", want, data)
+		}
+	}
+}
+
+func TestDialogApproveDenied(t *testing.T) {
+	fakeDialog(t, "1")
+	if dialogApprove()(context.Background(), approval) {
+		t.Fatal("the request was allowed although the user denied it")
+	}
+}
+
+func TestDialogApproveCanceled(t *testing.T) {
+	fakeDialog(t, "0")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if dialogApprove()(ctx, approval) {
+		t.Fatal("the request was allowed after the context was canceled")
+	}
+}
+
+func TestDialogApproveNoDialog(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the dialog is always available on", runtime.GOOS)
+	}
+	t.Setenv("PATH", t.TempDir())
+	if dialogApprove()(context.Background(), approval) {
+		t.Fatal("the request was allowed without a dialog")
+	}
+}
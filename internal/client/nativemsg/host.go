@@ -0,0 +1,347 @@
+package nativemsg
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// Types of requests.
+const (
+	RequestStatus = "status"
+	RequestSearch = "search"
+	RequestGet    = "get"
+	RequestSave   = "save"
+)
+
+// Codes of errors sent to the extension.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeLocked         = "locked"
+	CodeNotFound       = "not_found"
+	CodeRefused        = "refused"
+	CodeForbidden      = "forbidden"
+	CodeError          = "error"
+)
+
+// ErrLocked is returned by the records and save functions when there is no
+// session to read the vault with.
+var ErrLocked = errors.New("the vault is locked")
+
+var (
+	errNotFound = errors.New("no such credentials for the page")
+	errRefused  = errors.New("the user refused to fill in the credentials")
+	errOrigin   = errors.New("the extension is not allowed, add its origin to native_host.allowed_origins in config.toml")
+)
+
+// invalidRequestError is a request the extension should not have sent.
+type invalidRequestError struct {
+	msg string
+}
+
+func (e invalidRequestError) Error() string {
+	return e.msg
+}
+
+func invalidf(format string, args ...any) error {
+	return invalidRequestError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Request is a message of the extension. ID is sent back in the response,
+// so that the extension can match them.
+type Request struct {
+	ID       json.RawMessage `json:"id,omitempty"`
+	Type     string          `json:"type"`
+	URL      string          `json:"url,omitempty"`
+	Record   string          `json:"record,omitempty"`
+	Login    string          `json:"login,omitempty"`
+	Password string          `json:"password,omitempty"`
+}
+
+// Response answers a request with a result, or an error if OK is not set.
+type Response struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	OK     bool            `json:"ok"`
+	Result any             `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// StatusResult tells whether the vault can be read.
+type StatusResult struct {
+	Unlocked bool `json:"unlocked"`
+}
+
+// Item is credentials found by a search, without the password.
+type Item struct {
+	Record   string `json:"record"`
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	Login    string `json:"login"`
+}
+
+type SearchResult struct {
+	Items []Item `json:"items"`
+}
+
+type GetResult struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// SaveResult is the captured record holding the saved credentials.
+type SaveResult struct {
+	Record string              `json:"record"`
+	Status models.RecordStatus `json:"status"`
+}
+
+// Approval is a request to fill in credentials as shown to the user.
+type Approval struct {
+	Origin string
+	Page   string
+	Name   string
+	Login  string
+}
+
+// ApproveFunc reports whether the password may be sent to the extension. A
+// nil ApproveFunc refuses every request.
+type ApproveFunc func(ctx context.Context, req Approval) bool
+
+// Host answers the requests of one extension, one at a time. Records are
+// read on every request so that changes apply at once.
+type Host struct {
+	origin  string
+	allowed bool
+	records func() ([]*models.Record, error)
+	save    func(ctx context.Context, record *models.Record) (*models.Record, error)
+	approve ApproveFunc
+	logger  *slog.Logger
+}
+
+// New returns the host for the extension of origin, as passed by the
+// browser. Only origins in allowedOrigins are served, every request of
+// another extension is refused. Captured credentials are stored with save,
+// which must keep them for the user to approve.
+func New(origin string, allowedOrigins []string, records func() ([]*models.Record, error), save func(ctx context.Context, record *models.Record) (*models.Record, error), approve ApproveFunc, logger *slog.Logger) *Host {
+	return &Host{
+		origin:  origin,
+		allowed: origin != "" && slices.Contains(allowedOrigins, origin),
+		records: records,
+		save:    save,
+		approve: approve,
+		logger:  logger,
+	}
+}
+
+// Serve answers the messages read from r until the browser closes it. Bad
+// requests are answered with an error, only a broken stream stops Serve.
+func (h *Host) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	for ctx.Err() == nil {
+		msg, err := ReadMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req Request
+		var res Response
+		if err = json.Unmarshal(msg, &req); err != nil {
+			res = h.respond(nil, nil, invalidf("invalid JSON: %v", err))
+		} else {
+			result, err := h.handle(ctx, req)
+			res = h.respond(req.ID, result, err)
+		}
+		if err = WriteMessage(w, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Host) respond(id json.RawMessage, result any, err error) Response {
+	if err == nil {
+		return Response{ID: id, OK: true, Result: result}
+	}
+	var invalid invalidRequestError
+	code := CodeError
+	switch {
+	case errors.As(err, &invalid):
+		code = CodeInvalidRequest
+	case errors.Is(err, ErrLocked):
+		code = CodeLocked
+	case errors.Is(err, errNotFound):
+		code = CodeNotFound
+	case errors.Is(err, errRefused):
+		code = CodeRefused
+	case errors.Is(err, errOrigin):
+		code = CodeForbidden
+	default:
+		h.logger.Warn("native messaging request failed", slog.Any("error", err))
+	}
+	return Response{ID: id, Error: &Error{Code: code, Message: err.Error()}}
+}
+
+func (h *Host) handle(ctx context.Context, req Request) (any, error) {
+	if !h.allowed {
+		h.logger.Warn("request of an extension that is not allowed", slog.String("origin", h.origin))
+		return nil, fmt.Errorf("%w: %q", errOrigin, h.origin)
+	}
+	switch req.Type {
+	case RequestStatus:
+		_, err := h.records()
+		if errors.Is(err, ErrLocked) {
+			return StatusResult{}, nil
+		}
+		return StatusResult{Unlocked: err == nil}, err
+	case RequestSearch:
+		return h.search(req)
+	case RequestGet:
+		return h.get(ctx, req)
+	case RequestSave:
+		return h.store(ctx, req)
+	default:
+		return nil, invalidf("unknown request type %q, expected %s, %s, %s or %s", req.Type, RequestStatus, RequestSearch, RequestGet, RequestSave)
+	}
+}
+
+func (h *Host) search(req Request) (SearchResult, error) {
+	page, err := parsePage(req.URL)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	found, err := h.credentialsFor(page)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	items := make([]Item, 0, len(found))
+	for _, c := range found {
+		items = append(items, Item{Record: c.id.String(), Name: c.name(), Resource: c.data.Resource, Login: c.data.Login})
+	}
+	return SearchResult{Items: items}, nil
+}
+
+// get returns the credentials once the user allowed it. Credentials of
+// other sites are not found, whatever the extension asks for.
+func (h *Host) get(ctx context.Context, req Request) (GetResult, error) {
+	page, err := parsePage(req.URL)
+	if err != nil {
+		return GetResult{}, err
+	}
+	id, err := uuid.Parse(req.Record)
+	if err != nil {
+		return GetResult{}, invalidf("invalid record %q", req.Record)
+	}
+	found, err := h.credentialsFor(page)
+	if err != nil {
+		return GetResult{}, err
+	}
+	i := slices.IndexFunc(found, func(c credentials) bool { return c.id == id })
+	if i < 0 {
+		return GetResult{}, errNotFound
+	}
+	c := found[i]
+
+	approval := Approval{Origin: h.origin, Page: page.Scheme + "://" + page.Host, Name: c.name(), Login: c.data.Login}
+	if h.approve == nil || !h.approve(ctx, approval) {
+		h.logger.Info("credentials refused", slog.String("record", c.id.String()), slog.String("page", approval.Page))
+		return GetResult{}, errRefused
+	}
+	h.logger.Info("credentials filled", slog.String("record", c.id.String()), slog.String("page", approval.Page))
+	return GetResult{Login: c.data.Login, Password: c.data.Password}, nil
+}
+
+// store saves credentials captured on a page as a new record for the user
+// to approve. They are never compared with the records of the vault: the
+// answer would tell the extension whether a password is right without the
+// approval get asks for. Existing records are never changed.
+func (h *Host) store(ctx context.Context, req Request) (SaveResult, error) {
+	page, err := parsePage(req.URL)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	if req.Login == "" || req.Password == "" {
+		return SaveResult{}, invalidf("login and password are required")
+	}
+
+	data, err := json.Marshal(models.Credentials{
+		Resource: page.Scheme + "://" + page.Host,
+		Login:    req.Login,
+		Password: req.Password,
+		Metadata: make(models.Metadata),
+	})
+	if err != nil {
+		return SaveResult{}, err
+	}
+	record, err := h.save(ctx, &models.Record{Type: models.RecordTypeCredentials, Data: data})
+	if err != nil {
+		return SaveResult{}, err
+	}
+	h.logger.Info("credentials captured", slog.String("record", record.ID.String()), slog.String("page", page.Scheme+"://"+page.Host))
+	return SaveResult{Record: record.ID.String(), Status: record.Status}, nil
+}
+
+// credentials is a credentials record.
+type credentials struct {
+	id     uuid.UUID
+	status models.RecordStatus
+	data   models.Credentials
+}
+
+func (c credentials) name() string {
+	if name := c.data.Metadata["name"]; name != "" {
+		return name
+	}
+	return c.data.Resource
+}
+
+// credentialsFor returns the credentials that may be filled in on the page,
+// sorted by name.
+func (h *Host) credentialsFor(page *url.URL) ([]credentials, error) {
+	records, err := h.records()
+	if err != nil {
+		return nil, err
+	}
+	var res []credentials
+	for _, record := range records {
+		if record.Type != models.RecordTypeCredentials || record.Status == models.RecordStatusDeleted {
+			continue
+		}
+		var data models.Credentials
+		if err = json.Unmarshal(record.Data, &data); err != nil {
+			h.logger.Warn("skipping damaged credentials", slog.String("id", record.ID.String()), slog.Any("error", err))
+			continue
+		}
+		if data.Matches(page) {
+			res = append(res, credentials{id: record.ID, status: record.Status, data: data})
+		}
+	}
+	slices.SortFunc(res, func(a, b credentials) int {
+		return cmp.Or(strings.Compare(a.name(), b.name()), strings.Compare(a.id.String(), b.id.String()))
+	})
+	return res, nil
+}
+
+// parsePage parses the URL of a page. Only web pages are served.
+func parsePage(raw string) (*url.URL, error) {
+	page, err := url.Parse(raw)
+	if err != nil || page.Hostname() == "" || page.Scheme != "http" && page.Scheme != "https" {
+		return nil, invalidf("invalid page URL %q, expected an http or https URL", raw)
+	}
+	return page, nil
+}
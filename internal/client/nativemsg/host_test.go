@@ -0,0 +1,310 @@
+package nativemsg_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/nativemsg"
+)
+
+const origin = "chrome-extension://abcdefghijklmnop/"
+
+// vault is the records behind a host.
+type vault struct {
+	records []*models.Record
+	locked  bool
+	reads   int
+}
+
+func (v *vault) read() ([]*models.Record, error) {
+	v.reads++
+	if v.locked {
+		return nil, nativemsg.ErrLocked
+	}
+	return v.records, nil
+}
+
+func (v *vault) capture(_ context.Context, record *models.Record) (*models.Record, error) {
+	record.ID = uuid.New()
+	record.Status = models.RecordStatusCaptured
+	v.records = append(v.records, record)
+	return record, nil
+}
+
+func (v *vault) add(t *testing.T, data models.Credentials) uuid.UUID {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	id := uuid.New()
+	v.records = append(v.records, &models.Record{ID: id, Type: models.RecordTypeCredentials, Data: raw, Status: models.RecordStatusSynced})
+	return id
+}
+
+// conn is the side of the browser of a host served over pipes.
+type conn struct {
+	t *testing.T
+	w *io.PipeWriter
+	r *io.PipeReader
+}
+
+// serve serves the host over pipes until the test ends.
+func serve(t *testing.T, host *nativemsg.Host) *conn {
+	t.Helper()
+	reqR, reqW := io.Pipe()
+	resR, resW := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		err := host.Serve(context.Background(), reqR, resW)
+		resW.CloseWithError(io.ErrClosedPipe)
+		served <- err
+	}()
+	t.Cleanup(func() {
+		reqW.Close()
+		if err := <-served; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	return &conn{t: t, w: reqW, r: resR}
+}
+
+func newHost(v *vault, allowed []string, approve nativemsg.ApproveFunc) *nativemsg.Host {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return nativemsg.New(origin, allowed, v.read, v.capture, approve, logger)
+}
+
+// response is a response with its result still encoded.
+type response struct {
+	ID     json.RawMessage  `json:"id"`
+	OK     bool             `json:"ok"`
+	Result json.RawMessage  `json:"result"`
+	Error  *nativemsg.Error `json:"error"`
+}
+
+func (c *conn) send(req nativemsg.Request) response {
+	c.t.Helper()
+	if err := nativemsg.WriteMessage(c.w, req); err != nil {
+		c.t.Fatalf("WriteMessage: %v", err)
+	}
+	return c.receive()
+}
+
+func (c *conn) receive() response {
+	c.t.Helper()
+	msg, err := nativemsg.ReadMessage(c.r)
+	if err != nil {
+		c.t.Fatalf("ReadMessage: %v", err)
+	}
+	var res response
+	if err = json.Unmarshal(msg, &res); err != nil {
+		c.t.Fatalf("Unmarshal %q: %v", msg, err)
+	}
+	return res
+}
+
+// result decodes the result of a successful response.
+func result[T any](t *testing.T, res response) T {
+	t.Helper()
+	if !res.OK {
+		t.Fatalf("request failed: %+v", res.Error)
+	}
+	var v T
+	if err := json.Unmarshal(res.Result, &v); err != nil {
+		t.Fatalf("Unmarshal result %q: %v", res.Result, err)
+	}
+	return v
+}
+
+func wantError(t *testing.T, res response, code string) {
+	t.Helper()
+	if res.OK || res.Error == nil {
+		t.Fatalf("request succeeded, want the error %s", code)
+	}
+	if res.Error.Code != code {
+		t.Fatalf("error %s (%s), want %s", res.Error.Code, res.Error.Message, code)
+	}
+}
+
+func TestHostOriginAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+	}{
+		{name: "empty allowlist"},
+		{name: "other origin", allowed: []string{"chrome-extension://ponmlkjihgfedcba/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &vault{}
+			v.add(t, models.Credentials{Resource: "example.com", Login: "alice", Password: "secret"})
+			approved := false
+			c := serve(t, newHost(v, tt.allowed, func(context.Context, nativemsg.Approval) bool {
+				approved = true
+				return true
+			}))
+
+			for _, req := range []nativemsg.Request{
+				{Type: nativemsg.RequestStatus},
+				{Type: nativemsg.RequestSearch, URL: "https://example.com/"},
+				{Type: nativemsg.RequestGet, URL: "https://example.com/", Record: v.records[0].ID.String()},
+				{Type: nativemsg.RequestSave, URL: "https://example.com/", Login: "mallory", Password: "x"},
+			} {
+				wantError(t, c.send(req), nativemsg.CodeForbidden)
+			}
+			if v.reads != 0 || approved || len(v.records) != 1 {
+				t.Fatalf("the vault was used for an extension that is not allowed: %d reads, approved %t, %d records", v.reads, approved, len(v.records))
+			}
+		})
+	}
+
+	t.Run("no origin", func(t *testing.T) {
+		v := &vault{}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		c := serve(t, nativemsg.New("", []string{""}, v.read, v.capture, nil, logger))
+		wantError(t, c.send(nativemsg.Request{Type: nativemsg.RequestStatus}), nativemsg.CodeForbidden)
+	})
+}
+
+func TestHostStatus(t *testing.T) {
+	v := &vault{}
+	c := serve(t, newHost(v, []string{origin}, nil))
+	if status := result[nativemsg.StatusResult](t, c.send(nativemsg.Request{Type: nativemsg.RequestStatus})); !status.Unlocked {
+		t.Fatal("status of an unlocked vault is locked")
+	}
+	v.locked = true
+	if status := result[nativemsg.StatusResult](t, c.send(nativemsg.Request{Type: nativemsg.RequestStatus})); status.Unlocked {
+		t.Fatal("status of a locked vault is unlocked")
+	}
+	wantError(t, c.send(nativemsg.Request{Type: nativemsg.RequestSearch, URL: "https://example.com/"}), nativemsg.CodeLocked)
+}
+
+func TestHostSearch(t *testing.T) {
+	v := &vault{}
+	v.add(t, models.Credentials{Resource: "https://example.com", Login: "alice", Password: "secret", Metadata: models.Metadata{"name": "Example"}})
+	v.add(t, models.Credentials{Resource: "example.org", Login: "bob", Password: "secret"})
+	c := serve(t, newHost(v, []string{origin}, nil))
+
+	res := c.send(nativemsg.Request{ID: json.RawMessage(`7`), Type: nativemsg.RequestSearch, URL: "https://login.example.com:8443/form"})
+	if string(res.ID) != "7" {
+		t.Fatalf("response ID %s, want 7", res.ID)
+	}
+	if string(res.Result) != `{"items":[{"record":"`+v.records[0].ID.String()+`","name":"Example","resource":"https://example.com","login":"alice"}]}` {
+		t.Fatalf("search returned %s", res.Result)
+	}
+	if found := result[nativemsg.SearchResult](t, c.send(nativemsg.Request{Type: nativemsg.RequestSearch, URL: "http://example.com/"})); len(found.Items) != 0 {
+		t.Fatalf("search of an http page found https credentials: %+v", found.Items)
+	}
+}
+
+func TestHostGet(t *testing.T) {
+	v := &vault{}
+	id := v.add(t, models.Credentials{Resource: "example.com", Login: "alice", Password: "secret"})
+	other := v.add(t, models.Credentials{Resource: "example.org", Login: "bob", Password: "secret"})
+	get := nativemsg.Request{Type: nativemsg.RequestGet, URL: "https://example.com/login", Record: id.String()}
+
+	t.Run("approved", func(t *testing.T) {
+		var asked nativemsg.Approval
+		c := serve(t, newHost(v, []string{origin}, func(_ context.Context, req nativemsg.Approval) bool {
+			asked = req
+			return true
+		}))
+		got := result[nativemsg.GetResult](t, c.send(get))
+		if got.Login != "alice" || got.Password != "secret" {
+			t.Fatalf("get returned %+v", got)
+		}
+		want := nativemsg.Approval{Origin: origin, Page: "https://example.com", Name: "example.com", Login: "alice"}
+		if asked != want {
+			t.Fatalf("approval asked for %+v, want %+v", asked, want)
+		}
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		c := serve(t, newHost(v, []string{origin}, func(context.Context, nativemsg.Approval) bool { return false }))
+		res := c.send(get)
+		wantError(t, res, nativemsg.CodeRefused)
+		if len(res.Result) != 0 {
+			t.Fatalf("a refused get returned %s", res.Result)
+		}
+	})
+
+	t.Run("no approval", func(t *testing.T) {
+		c := serve(t, newHost(v, []string{origin}, nil))
+		wantError(t, c.send(get), nativemsg.CodeRefused)
+	})
+
+	t.Run("other site", func(t *testing.T) {
+		approved := false
+		c := serve(t, newHost(v, []string{origin}, func(context.Context, nativemsg.Approval) bool {
+			approved = true
+			return true
+		}))
+		wantError(t, c.send(nativemsg.Request{Type: nativemsg.RequestGet, URL: "https://example.com/", Record: other.String()}), nativemsg.CodeNotFound)
+		if approved {
+			t.Fatal("the user was asked for credentials of another site")
+		}
+	})
+}
+
+func TestHostSave(t *testing.T) {
+	v := &vault{}
+	existing := v.add(t, models.Credentials{Resource: "example.com", Login: "alice", Password: "secret"})
+	c := serve(t, newHost(v, []string{origin}, nil))
+
+	// Saving the stored password answers like saving any other, so that
+	// the extension cannot test passwords against the vault.
+	for i, password := range []string{"secret", "new"} {
+		saved := result[nativemsg.SaveResult](t, c.send(nativemsg.Request{Type: nativemsg.RequestSave, URL: "https://example.com/login", Login: "alice", Password: password}))
+		if saved.Record == existing.String() || saved.Status != models.RecordStatusCaptured || len(v.records) != i+2 {
+			t.Fatalf("saving %q returned %+v with %d records", password, saved, len(v.records))
+		}
+	}
+	var data models.Credentials
+	if err := json.Unmarshal(v.records[2].Data, &data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if data.Resource != "https://example.com" || data.Login != "alice" || data.Password != "new" {
+		t.Fatalf("captured %+v", data)
+	}
+
+	wantError(t, c.send(nativemsg.Request{Type: nativemsg.RequestSave, URL: "https://example.com/", Login: "alice"}), nativemsg.CodeInvalidRequest)
+}
+
+func TestHostInvalidRequests(t *testing.T) {
+	v := &vault{}
+	c := serve(t, newHost(v, []string{origin}, nil))
+
+	for _, req := range []nativemsg.Request{
+		{Type: "delete"},
+		{Type: nativemsg.RequestSearch, URL: "file:///etc/passwd"},
+		{Type: nativemsg.RequestGet, URL: "https://example.com/", Record: "not an id"},
+	} {
+		wantError(t, c.send(req), nativemsg.CodeInvalidRequest)
+	}
+
+	if _, err := c.w.Write(append(binary.NativeEndian.AppendUint32(nil, 3), "{]}"...)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	wantError(t, c.receive(), nativemsg.CodeInvalidRequest)
+}
+
+func TestHostOversizeRequest(t *testing.T) {
+	v := &vault{}
+	reqR, reqW := io.Pipe()
+	served := make(chan error, 1)
+	go func() { served <- newHost(v, []string{origin}, nil).Serve(context.Background(), reqR, io.Discard) }()
+
+	go func() { _, _ = reqW.Write(binary.NativeEndian.AppendUint32(nil, nativemsg.MaxMessageSize+1)) }()
+	err := <-served
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("Serve of an oversize request returned %v, want an error", err)
+	}
+	reqR.Close()
+}
@@ -0,0 +1,57 @@
+// Package nativemsg serves the credentials of the vault to a browser
+// extension with the native messaging protocol of Chrome and Firefox: the
+// browser starts the host and exchanges JSON messages with it over stdin and
+// stdout, each preceded by its length.
+package nativemsg
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxMessageSize is the largest message sent to the browser, which refuses
+// larger ones. Requests are limited to the same size, they are much smaller.
+const MaxMessageSize = 1 << 20
+
+var errTooLarge = errors.New("the message is too large")
+
+// ReadMessage reads a message: its length as a 32-bit unsigned integer in
+// the native byte order, then the JSON. io.EOF is returned when the browser
+// closed the pipe between messages.
+func ReadMessage(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.NativeEndian, &size); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("truncated message length: %w", err)
+		}
+		return nil, err
+	}
+	if size > MaxMessageSize {
+		return nil, fmt.Errorf("%w: %d bytes", errTooLarge, size)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("truncated message: %w", err)
+	}
+	return msg, nil
+}
+
+// WriteMessage writes v as JSON preceded by its length in a single write.
+func WriteMessage(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes", errTooLarge, len(data))
+	}
+	msg := binary.NativeEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+	_, err = w.Write(append(msg, data...))
+	return err
+}
@@ -0,0 +1,98 @@
+package nativemsg_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/client/nativemsg"
+)
+
+// frame returns the message as the browser sends it.
+func frame(msg string) []byte {
+	return append(binary.NativeEndian.AppendUint32(nil, uint32(len(msg))), msg...)
+}
+
+func TestReadMessage(t *testing.T) {
+	r := bytes.NewReader(append(frame(`{"type":"status"}`), frame(`{}`)...))
+	for _, want := range []string{`{"type":"status"}`, `{}`} {
+		msg, err := nativemsg.ReadMessage(r)
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		if string(msg) != want {
+			t.Fatalf("ReadMessage returned %q, want %q", msg, want)
+		}
+	}
+	if _, err := nativemsg.ReadMessage(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadMessage after the last message returned %v, want io.EOF", err)
+	}
+}
+
+func TestReadMessageInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated length", data: []byte{1, 0}},
+		{name: "truncated message", data: frame(`{"type":"status"}`)[:10]},
+		{name: "missing message", data: binary.NativeEndian.AppendUint32(nil, 2)},
+		{name: "too large", data: binary.NativeEndian.AppendUint32(nil, nativemsg.MaxMessageSize+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := nativemsg.ReadMessage(bytes.NewReader(tt.data))
+			if err == nil {
+				t.Fatalf("ReadMessage returned %q, want an error", msg)
+			}
+			if errors.Is(err, io.EOF) {
+				t.Fatalf("ReadMessage returned io.EOF, which ends the stream cleanly")
+			}
+		})
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := nativemsg.WriteMessage(&buf, map[string]bool{"ok": true}); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if want := frame(`{"ok":true}`); !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("WriteMessage wrote %q, want %q", buf.Bytes(), want)
+	}
+
+	buf.Reset()
+	if err := nativemsg.WriteMessage(&buf, strings.Repeat("x", nativemsg.MaxMessageSize)); err == nil {
+		t.Fatal("WriteMessage of a message larger than MaxMessageSize succeeded")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("WriteMessage wrote %d bytes of a message that is too large", buf.Len())
+	}
+}
+
+func TestMessagePipe(t *testing.T) {
+	r, w := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := nativemsg.WriteMessage(w, nativemsg.Request{Type: nativemsg.RequestSearch, URL: "https://example.com/"})
+		w.Close()
+		written <- err
+	}()
+
+	msg, err := nativemsg.ReadMessage(r)
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if want := `{"type":"search","url":"https://example.com/"}`; string(msg) != want {
+		t.Fatalf("ReadMessage returned %q, want %q", msg, want)
+	}
+	if err = <-written; err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if _, err = nativemsg.ReadMessage(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadMessage after close returned %v, want io.EOF", err)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/api"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
 )
 
 type authService struct {
@@ -20,8 +22,12 @@ func NewAuthService(client api.Invoker, security interfaces.SecuritySource) inte
 	}
 }
 
-func (s *authService) Register(ctx context.Context, login, password string) (string, error) {
-	res, err := s.client.RegisterPost(ctx, &api.UserCredentials{Login: login, Password: password})
+func (s *authService) Register(ctx context.Context, login, password, inviteCode string) (string, error) {
+	req := &api.UserCredentials{Login: login, Password: password}
+	if inviteCode != "" {
+		req.InviteCode = api.NewOptString(inviteCode)
+	}
+	res, err := s.client.RegisterPost(ctx, req)
 	if err != nil {
 		return "", err
 	}
@@ -32,6 +38,10 @@ func (s *authService) Register(ctx context.Context, login, password string) (str
 		return "", interfaces.ErrBadRequest
 	case *api.RegisterPostConflict:
 		return "", interfaces.ErrLoginTaken
+	case *api.RegisterPostForbidden:
+		return "", interfaces.ErrRegistrationClosed
+	case *api.RegisterPostUnprocessableEntity:
+		return "", interfaces.ErrInvalidInvite
 	default:
 		return "", interfaces.ErrUnexpected
 	}
@@ -49,11 +59,62 @@ func (s *authService) Login(ctx context.Context, login, password string) (string
 		return "", interfaces.ErrBadRequest
 	case *api.Unauthorized:
 		return "", interfaces.ErrUnauthorized
+	case *api.LoginPostForbidden:
+		return "", interfaces.ErrAccountDisabled
 	default:
 		return "", interfaces.ErrUnexpected
 	}
 }
 
+func (s *authService) DeleteAccount(ctx context.Context, password string) error {
+	res, err := s.client.AccountDelete(ctx, &api.PasswordConfirmation{Password: password})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.AccountDeleteNoContent:
+		s.security.SetToken("")
+		return nil
+	case *api.AccountDeleteBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.AccountDeleteConflict:
+		return interfaces.ErrLastOrgOwner
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *authService) AuthenticateAccessToken(ctx context.Context, token string) (*models.AccessTokenKeys, error) {
+	s.security.SetToken(token)
+	res, err := s.client.TokensCurrentGet(ctx)
+	if err != nil {
+		s.security.SetToken("")
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.AccessTokenKeys:
+		keys := &models.AccessTokenKeys{ReadOnly: res.ReadOnly, WrappedKey: res.WrappedKey}
+		if len(res.WrappedKey) == 0 {
+			keys.RecordKeys = make(map[uuid.UUID][]byte, len(res.RecordKeys))
+			for _, rk := range res.RecordKeys {
+				keys.RecordKeys[rk.RecordID] = rk.Key
+			}
+		}
+		return keys, nil
+	case *api.Unauthorized:
+		s.security.SetToken("")
+		return nil, interfaces.ErrUnauthorized
+	case *api.TokensCurrentGetForbidden:
+		s.security.SetToken("")
+		return nil, interfaces.ErrInvalidAccessToken
+	default:
+		s.security.SetToken("")
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
 func (s *authService) handleAuth(res *api.AuthToken, login, password string) (string, error) {
 	s.security.SetToken(res.Token)
 	return s.getUserID(res)
@@ -3,18 +3,39 @@ package service
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
 	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/envelope"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
 	"github.com/grnsv/GophKeeper/internal/client/models"
 	"golang.org/x/crypto/argon2"
 )
 
+const (
+	keySize = envelope.KeySize
+	// shareInfo, orgInfo and emergencyInfo bind the keys derived for
+	// shares, organization keys and emergency access to their purpose.
+	shareInfo     = "GophKeeper record share"
+	orgInfo       = "GophKeeper organization key"
+	emergencyInfo = "GophKeeper emergency access"
+)
+
 type cryptoService struct {
 	encryptionKey    []byte
 	newCryptoStorage interfaces.NewCryptoStorage
 	block            cipher.Block
+	// recordKeys are the unwrapped keys of an access token limited to
+	// records. Such a token has no vault key, so block is nil.
+	recordKeys map[uuid.UUID][]byte
+	// privateKey opens the record keys shared with the user.
+	privateKey *ecdh.PrivateKey
 }
 
 func NewCryptoService(newCryptoStorage interfaces.NewCryptoStorage) interfaces.CryptoService {
@@ -22,22 +43,307 @@ func NewCryptoService(newCryptoStorage interfaces.NewCryptoStorage) interfaces.C
 }
 
 func (s *cryptoService) InitCrypto(userID, login, password string) (interfaces.Storage, error) {
-	s.encryptionKey = s.generateKey(userID, login, password)
-	block, err := aes.NewCipher(s.encryptionKey)
+	if err := s.setVaultKey(s.generateKey(userID, login, password)); err != nil {
+		return nil, err
+	}
+	s.recordKeys = nil
+	s.privateKey = nil
+	return s.newCryptoStorage(userID, s.encryptionKey)
+}
+
+// InitSessionCrypto unwraps the vault key of a saved session and opens the
+// local cache of the user.
+func (s *cryptoService) InitSessionCrypto(userID string, sessionKey, wrappedVaultKey []byte) (interfaces.Storage, error) {
+	vaultKey, err := envelope.UnwrapKey(sessionKey, wrappedVaultKey)
 	if err != nil {
+		return nil, interfaces.ErrInvalidSession
+	}
+	if err = s.setVaultKey(vaultKey); err != nil {
 		return nil, err
 	}
-	s.block = block
+	s.recordKeys = nil
+	s.privateKey = nil
 	return s.newCryptoStorage(userID, s.encryptionKey)
 }
 
+// InitAccessTokenCrypto unwraps the keys of an access token with its key
+// part. A token to all records carries the vault key, a token limited to
+// records carries only their record keys.
+func (s *cryptoService) InitAccessTokenCrypto(tokenKey []byte, keys *models.AccessTokenKeys) error {
+	if len(keys.WrappedKey) > 0 {
+		vaultKey, err := envelope.UnwrapKey(tokenKey, keys.WrappedKey)
+		if err != nil {
+			return fmt.Errorf("unwrap vault key: %w", err)
+		}
+		s.recordKeys = nil
+		return s.setVaultKey(vaultKey)
+	}
+
+	recordKeys := make(map[uuid.UUID][]byte, len(keys.RecordKeys))
+	for id, wrapped := range keys.RecordKeys {
+		key, err := envelope.UnwrapKey(tokenKey, wrapped)
+		if err != nil {
+			return fmt.Errorf("unwrap key of record %s: %w", id, err)
+		}
+		recordKeys[id] = key
+	}
+	s.encryptionKey, s.block = nil, nil
+	s.recordKeys = recordKeys
+	return nil
+}
+
+// GenerateKeyPair generates the X25519 key pair records are shared with
+// and returns its public key and its private key wrapped with the vault key.
+func (s *cryptoService) GenerateKeyPair() (publicKey, wrappedPrivateKey []byte, err error) {
+	if s.block == nil {
+		return nil, nil, errors.New("vault key is not available")
+	}
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := envelope.WrapKey(s.encryptionKey, privateKey.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	s.privateKey = privateKey
+	return privateKey.PublicKey().Bytes(), wrapped, nil
+}
+
+// InitKeyPair unwraps the private key of the key pair records are shared with.
+func (s *cryptoService) InitKeyPair(wrappedPrivateKey []byte) error {
+	if s.block == nil {
+		return errors.New("vault key is not available")
+	}
+	raw, err := envelope.UnwrapKey(s.encryptionKey, wrappedPrivateKey)
+	if err != nil {
+		return fmt.Errorf("unwrap private key: %w", err)
+	}
+	privateKey, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return err
+	}
+	s.privateKey = privateKey
+	return nil
+}
+
+// WrapRecordKeyFor wraps the key of the record for the X25519 public key
+// of a recipient.
+func (s *cryptoService) WrapRecordKeyFor(publicKey []byte, record *models.Record) ([]byte, error) {
+	key, err := s.recordKey(record)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("record %s has no record key", record.ID)
+	}
+	return sealKeyFor(publicKey, key, shareInfo)
+}
+
+// DecryptSharedRecord decrypts a record shared with the user, its Key is
+// wrapped for their public key.
+func (s *cryptoService) DecryptSharedRecord(record *models.Record) error {
+	key, err := s.openSealedKey(record.Key, shareInfo)
+	if err != nil {
+		return fmt.Errorf("unwrap shared key: %w", err)
+	}
+	aesGCM, err := envelope.NewGCM(key)
+	if err != nil {
+		return err
+	}
+	record.Data, err = aesGCM.Open(nil, record.Nonce, record.Data, nil)
+	return err
+}
+
+// NewOrgKey generates a key for the records of an organization.
+func (s *cryptoService) NewOrgKey() ([]byte, error) {
+	return envelope.NewKey()
+}
+
+// WrapOrgKeyFor wraps an organization key for the X25519 public key of a member.
+func (s *cryptoService) WrapOrgKeyFor(publicKey, orgKey []byte) ([]byte, error) {
+	return sealKeyFor(publicKey, orgKey, orgInfo)
+}
+
+// UnwrapOrgKey unwraps the organization key wrapped for the public key of the user.
+func (s *cryptoService) UnwrapOrgKey(wrapped []byte) ([]byte, error) {
+	key, err := s.openSealedKey(wrapped, orgInfo)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap organization key: %w", err)
+	}
+	return key, nil
+}
+
+// EncryptOrgRecord encrypts the data of an organization record with the
+// organization key. Organization records have no record keys.
+func (s *cryptoService) EncryptOrgRecord(orgKey []byte, record *models.Record) error {
+	aesGCM, err := envelope.NewGCM(orgKey)
+	if err != nil {
+		return err
+	}
+	record.Key = nil
+	record.Nonce = make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, record.Nonce); err != nil {
+		return err
+	}
+	record.Data = aesGCM.Seal(nil, record.Nonce, record.Data, nil)
+	return nil
+}
+
+func (s *cryptoService) DecryptOrgRecord(orgKey []byte, record *models.Record) error {
+	aesGCM, err := envelope.NewGCM(orgKey)
+	if err != nil {
+		return err
+	}
+	record.Data, err = aesGCM.Open(nil, record.Nonce, record.Data, nil)
+	return err
+}
+
+// WrapVaultKeyFor wraps the vault key for the X25519 public key of a
+// trusted contact.
+func (s *cryptoService) WrapVaultKeyFor(publicKey []byte) ([]byte, error) {
+	if s.block == nil {
+		return nil, errors.New("vault key is not available")
+	}
+	return sealKeyFor(publicKey, s.encryptionKey, emergencyInfo)
+}
+
+// DecryptEmergencyRecords decrypts the records of another user with their
+// vault key, wrapped for the public key of the user by WrapVaultKeyFor.
+func (s *cryptoService) DecryptEmergencyRecords(wrappedVaultKey []byte, records []*models.Record) error {
+	vaultKey, err := s.openSealedKey(wrappedVaultKey, emergencyInfo)
+	if err != nil {
+		return fmt.Errorf("unwrap vault key: %w", err)
+	}
+	for _, record := range records {
+		key := vaultKey
+		if len(record.Key) > 0 {
+			if key, err = envelope.UnwrapKey(vaultKey, record.Key); err != nil {
+				return fmt.Errorf("unwrap key of record %s: %w", record.ID, err)
+			}
+		}
+		aesGCM, err := envelope.NewGCM(key)
+		if err != nil {
+			return err
+		}
+		if record.Data, err = aesGCM.Open(nil, record.Nonce, record.Data, nil); err != nil {
+			return fmt.Errorf("decrypt record %s: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+// EncryptSend encrypts the data of a send with a new key. The key is not
+// related to the vault, it is handed out in the link to the send.
+func (s *cryptoService) EncryptSend(data []byte) (key, nonce, ciphertext []byte, err error) {
+	if key, err = envelope.NewKey(); err != nil {
+		return nil, nil, nil, err
+	}
+	aesGCM, err := envelope.NewGCM(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	nonce = make([]byte, aesGCM.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, nil, err
+	}
+	return key, nonce, aesGCM.Seal(nil, nonce, data, nil), nil
+}
+
+func (s *cryptoService) DecryptSend(key, nonce, ciphertext []byte) ([]byte, error) {
+	aesGCM, err := envelope.NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aesGCM.Open(nil, nonce, ciphertext, nil)
+}
+
+// sealKeyFor wraps key for the X25519 public key of a recipient: an
+// ephemeral key pair is generated, the key encryption key is derived from
+// their shared secret and the ephemeral public key is prepended to the
+// wrapped key.
+func sealKeyFor(publicKey, key []byte, info string) ([]byte, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := sealKEK(ephemeral, recipient, ephemeral.PublicKey(), recipient, info)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := envelope.WrapKey(kek, key)
+	if err != nil {
+		return nil, err
+	}
+	return append(ephemeral.PublicKey().Bytes(), wrapped...), nil
+}
+
+// openSealedKey unwraps a key sealed by sealKeyFor for the public key of the user.
+func (s *cryptoService) openSealedKey(sealed []byte, info string) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, errors.New("private key is not available")
+	}
+	ephemeralSize := len(s.privateKey.PublicKey().Bytes())
+	if len(sealed) < ephemeralSize {
+		return nil, errors.New("sealed key is too short")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:ephemeralSize])
+	if err != nil {
+		return nil, err
+	}
+	kek, err := sealKEK(s.privateKey, ephemeral, ephemeral, s.privateKey.PublicKey(), info)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.UnwrapKey(kek, sealed[ephemeralSize:])
+}
+
+// sealKEK derives the key encryption key of a sealed key from the X25519
+// shared secret of private and peer, salted with the ephemeral and recipient
+// public keys and bound to its purpose by info.
+func sealKEK(private *ecdh.PrivateKey, peer, ephemeral, recipient *ecdh.PublicKey, info string) ([]byte, error) {
+	secret, err := private.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(ephemeral.Bytes(), recipient.Bytes()...)
+	return hkdf.Key(sha256.New, secret, salt, info, keySize)
+}
+
+func (s *cryptoService) setVaultKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	s.encryptionKey = key
+	s.block = block
+	return nil
+}
+
 func (s *cryptoService) generateKey(userID, login, password string) []byte {
 	salt := []byte(login + userID)
-	return argon2.IDKey([]byte(password), salt, 3, 128*1024, 4, 32)
+	return argon2.IDKey([]byte(password), salt, 3, 128*1024, 4, keySize)
 }
 
+// EncryptRecord encrypts the data with the record key, generating one
+// wrapped with the vault key for records that have none yet.
 func (s *cryptoService) EncryptRecord(record *models.Record) error {
-	aesGCM, err := s.newGCM()
+	if len(record.Key) == 0 && s.block != nil {
+		key, err := envelope.NewKey()
+		if err != nil {
+			return err
+		}
+		wrapped, err := envelope.WrapKey(s.encryptionKey, key)
+		if err != nil {
+			return err
+		}
+		record.Key = wrapped
+	}
+	aesGCM, err := s.recordGCM(record)
 	if err != nil {
 		return err
 	}
@@ -50,7 +356,7 @@ func (s *cryptoService) EncryptRecord(record *models.Record) error {
 }
 
 func (s *cryptoService) DecryptRecord(record *models.Record) error {
-	aesGCM, err := s.newGCM()
+	aesGCM, err := s.recordGCM(record)
 	if err != nil {
 		return err
 	}
@@ -61,6 +367,60 @@ func (s *cryptoService) DecryptRecord(record *models.Record) error {
 	return nil
 }
 
+// WrapRecordKey returns the key of the record wrapped with the key part of
+// an access token.
+func (s *cryptoService) WrapRecordKey(tokenKey []byte, record *models.Record) ([]byte, error) {
+	key, err := s.recordKey(record)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("record %s has no record key", record.ID)
+	}
+	return envelope.WrapKey(tokenKey, key)
+}
+
+// WrapVaultKey returns the vault key wrapped with the key part of an access token.
+func (s *cryptoService) WrapVaultKey(tokenKey []byte) ([]byte, error) {
+	if s.block == nil {
+		return nil, errors.New("vault key is not available")
+	}
+	return envelope.WrapKey(tokenKey, s.encryptionKey)
+}
+
+// recordKey returns the unwrapped key of the record, nil for records
+// encrypted with the vault key.
+func (s *cryptoService) recordKey(record *models.Record) ([]byte, error) {
+	if s.recordKeys != nil {
+		key, ok := s.recordKeys[record.ID]
+		if !ok {
+			return nil, fmt.Errorf("access token has no key for record %s", record.ID)
+		}
+		return key, nil
+	}
+	if len(record.Key) == 0 {
+		return nil, nil
+	}
+	if s.block == nil {
+		return nil, errors.New("vault key is not available")
+	}
+	return envelope.UnwrapKey(s.encryptionKey, record.Key)
+}
+
+func (s *cryptoService) recordGCM(record *models.Record) (cipher.AEAD, error) {
+	key, err := s.recordKey(record)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return s.newGCM()
+	}
+	return envelope.NewGCM(key)
+}
+
 func (s *cryptoService) newGCM() (cipher.AEAD, error) {
+	if s.block == nil {
+		return nil, errors.New("vault key is not available")
+	}
 	return cipher.NewGCM(s.block)
 }
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+type emergencyService struct {
+	client api.Invoker
+	crypto interfaces.CryptoService
+}
+
+func NewEmergencyService(client api.Invoker, crypto interfaces.CryptoService) interfaces.EmergencyService {
+	return &emergencyService{client: client, crypto: crypto}
+}
+
+func (s *emergencyService) AddEmergencyContact(ctx context.Context, login string, waitDays int) error {
+	keyRes, err := s.client.UsersLoginPublicKeyGet(ctx, api.UsersLoginPublicKeyGetParams{Login: login})
+	if err != nil {
+		return err
+	}
+	var publicKey []byte
+	switch keyRes := keyRes.(type) {
+	case *api.PublicKey:
+		publicKey = keyRes.PublicKey
+	case *api.UsersLoginPublicKeyGetNotFound:
+		return interfaces.ErrInvalidEmergency
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+
+	key, err := s.crypto.WrapVaultKeyFor(publicKey)
+	if err != nil {
+		return err
+	}
+	res, err := s.client.EmergencyContactsPost(ctx, &api.EmergencyContactRequest{Login: login, WaitDays: waitDays, Key: key})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.EmergencyAccess:
+		return nil
+	case *api.EmergencyContactsPostConflict:
+		return fmt.Errorf("%w: %s is already a trusted contact", interfaces.ErrEmergencyState, login)
+	case *api.EmergencyContactsPostUnprocessableEntity:
+		return interfaces.ErrInvalidEmergency
+	case *api.EmergencyContactsPostBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) ListEmergencyContacts(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	res, err := s.client.EmergencyContactsGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.EmergencyContactsGetOKApplicationJSON:
+		return convertEmergencyAccesses(*res), nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) RemoveEmergencyContact(ctx context.Context, id uuid.UUID) error {
+	res, err := s.client.EmergencyContactsIDDelete(ctx, api.EmergencyContactsIDDeleteParams{ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.EmergencyContactsIDDeleteNoContent:
+		return nil
+	case *api.EmergencyContactsIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) ApproveEmergencyAccess(ctx context.Context, id uuid.UUID) error {
+	res, err := s.client.EmergencyContactsIDApprovePost(ctx, api.EmergencyContactsIDApprovePostParams{ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.EmergencyContactsIDApprovePostNoContent:
+		return nil
+	case *api.EmergencyContactsIDApprovePostNotFound:
+		return interfaces.ErrNotFound
+	case *api.EmergencyContactsIDApprovePostConflict:
+		return interfaces.ErrEmergencyState
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) RejectEmergencyAccess(ctx context.Context, id uuid.UUID) error {
+	res, err := s.client.EmergencyContactsIDRejectPost(ctx, api.EmergencyContactsIDRejectPostParams{ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.EmergencyContactsIDRejectPostNoContent:
+		return nil
+	case *api.EmergencyContactsIDRejectPostNotFound:
+		return interfaces.ErrNotFound
+	case *api.EmergencyContactsIDRejectPostConflict:
+		return interfaces.ErrEmergencyState
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) ListEmergencyGrants(ctx context.Context) ([]*models.EmergencyAccess, error) {
+	res, err := s.client.EmergencyGrantsGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.EmergencyGrantsGetOKApplicationJSON:
+		return convertEmergencyAccesses(*res), nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) RequestEmergencyAccess(ctx context.Context, id uuid.UUID) error {
+	res, err := s.client.EmergencyGrantsIDRequestPost(ctx, api.EmergencyGrantsIDRequestPostParams{ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.EmergencyGrantsIDRequestPostNoContent:
+		return nil
+	case *api.EmergencyGrantsIDRequestPostNotFound:
+		return interfaces.ErrNotFound
+	case *api.EmergencyGrantsIDRequestPostConflict:
+		return interfaces.ErrEmergencyState
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *emergencyService) FetchEmergencyVault(ctx context.Context, id uuid.UUID) ([]*models.Record, error) {
+	res, err := s.client.EmergencyGrantsIDVaultGet(ctx, api.EmergencyGrantsIDVaultGetParams{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.EmergencyVault:
+		records := make([]*models.Record, 0, len(res.Records))
+		for _, rec := range res.Records {
+			records = append(records, &models.Record{
+				ID:      rec.ID,
+				Type:    models.RecordType(rec.Type),
+				Data:    rec.Data,
+				Nonce:   rec.Nonce,
+				Version: rec.Version,
+				Status:  models.RecordStatusSynced,
+				Key:     rec.Key,
+			})
+		}
+		if err := s.crypto.DecryptEmergencyRecords(res.Key, records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	case *api.EmergencyGrantsIDVaultGetForbidden:
+		return nil, interfaces.ErrForbidden
+	case *api.EmergencyGrantsIDVaultGetNotFound:
+		return nil, interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func convertEmergencyAccesses(res []api.EmergencyAccess) []*models.EmergencyAccess {
+	accesses := make([]*models.EmergencyAccess, 0, len(res))
+	for _, access := range res {
+		accesses = append(accesses, &models.EmergencyAccess{
+			ID:          access.ID,
+			Login:       access.Login,
+			WaitDays:    access.WaitDays,
+			Status:      models.EmergencyStatus(access.Status),
+			RequestedAt: access.RequestedAt.Or(time.Time{}),
+			AvailableAt: access.AvailableAt.Or(time.Time{}),
+			CreatedAt:   access.CreatedAt,
+		})
+	}
+	return accesses
+}
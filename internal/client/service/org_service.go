@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+type orgService struct {
+	client api.Invoker
+	crypto interfaces.CryptoService
+}
+
+func NewOrgService(client api.Invoker, crypto interfaces.CryptoService) interfaces.OrgService {
+	return &orgService{client: client, crypto: crypto}
+}
+
+func (s *orgService) ListOrgs(ctx context.Context) ([]*models.Organization, error) {
+	res, err := s.client.OrgsGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.OrgsGetOKApplicationJSON:
+		orgs := make([]*models.Organization, 0, len(*res))
+		for _, org := range *res {
+			converted, err := s.convertOrg(&org)
+			if err != nil {
+				return nil, err
+			}
+			orgs = append(orgs, converted)
+		}
+		return orgs, nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+// CreateOrg generates the organization key and wraps it for the public key
+// of the user, who becomes the owner.
+func (s *orgService) CreateOrg(ctx context.Context, name string) (*models.Organization, error) {
+	orgKey, err := s.crypto.NewOrgKey()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := s.ownPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := s.crypto.WrapOrgKeyFor(publicKey, orgKey)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.OrgsPost(ctx, &api.OrganizationRequest{Name: name, Key: wrapped})
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.Organization:
+		return s.convertOrg(res)
+	case *api.OrgsPostUnprocessableEntity:
+		return nil, interfaces.ErrInvalidOrg
+	case *api.OrgsPostBadRequest:
+		return nil, interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) ownPublicKey(ctx context.Context) ([]byte, error) {
+	res, err := s.client.AccountKeysGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.UserKeys:
+		return res.PublicKey, nil
+	case *api.AccountKeysGetNotFound:
+		return nil, interfaces.ErrInvalidOrg
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) DeleteOrg(ctx context.Context, org *models.Organization) error {
+	res, err := s.client.OrgsOrgIDDelete(ctx, api.OrgsOrgIDDeleteParams{OrgID: org.ID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDDeleteNoContent:
+		return nil
+	case *api.OrgsOrgIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDDeleteForbidden:
+		return interfaces.ErrForbidden
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) ListOrgMembers(ctx context.Context, org *models.Organization) ([]*models.OrgMember, error) {
+	res, err := s.client.OrgsOrgIDMembersGet(ctx, api.OrgsOrgIDMembersGetParams{OrgID: org.ID})
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.OrgsOrgIDMembersGetOKApplicationJSON:
+		members := make([]*models.OrgMember, 0, len(*res))
+		for _, member := range *res {
+			members = append(members, &models.OrgMember{
+				UserID:    member.UserID,
+				Login:     member.Login,
+				Role:      models.OrgRole(member.Role),
+				PublicKey: member.PublicKey,
+				CreatedAt: member.CreatedAt,
+			})
+		}
+		return members, nil
+	case *api.OrgsOrgIDMembersGetNotFound:
+		return nil, interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) InviteOrgMember(ctx context.Context, org *models.Organization, login string, role models.OrgRole) error {
+	keyRes, err := s.client.UsersLoginPublicKeyGet(ctx, api.UsersLoginPublicKeyGetParams{Login: login})
+	if err != nil {
+		return err
+	}
+	var publicKey []byte
+	switch keyRes := keyRes.(type) {
+	case *api.PublicKey:
+		publicKey = keyRes.PublicKey
+	case *api.UsersLoginPublicKeyGetNotFound:
+		return interfaces.ErrInvalidOrg
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+
+	key, err := s.crypto.WrapOrgKeyFor(publicKey, org.Key)
+	if err != nil {
+		return err
+	}
+	res, err := s.client.OrgsOrgIDMembersPost(ctx, &api.OrgInvite{
+		Login:      login,
+		Role:       api.OrgRole(role),
+		Key:        key,
+		KeyVersion: org.KeyVersion,
+	}, api.OrgsOrgIDMembersPostParams{OrgID: org.ID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgMember:
+		return nil
+	case *api.OrgsOrgIDMembersPostNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDMembersPostForbidden:
+		return interfaces.ErrForbidden
+	case *api.OrgsOrgIDMembersPostConflict:
+		return fmt.Errorf("%w: already a member or the organization was re-keyed", interfaces.ErrVersionConflict)
+	case *api.OrgsOrgIDMembersPostUnprocessableEntity:
+		return interfaces.ErrInvalidOrg
+	case *api.OrgsOrgIDMembersPostBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) SetOrgMemberRole(ctx context.Context, org *models.Organization, userID uuid.UUID, role models.OrgRole) error {
+	res, err := s.client.OrgsOrgIDMembersUserIDPut(ctx, &api.OrgRoleRequest{Role: api.OrgRole(role)},
+		api.OrgsOrgIDMembersUserIDPutParams{OrgID: org.ID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDMembersUserIDPutNoContent:
+		return nil
+	case *api.OrgsOrgIDMembersUserIDPutNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDMembersUserIDPutForbidden:
+		return interfaces.ErrForbidden
+	case *api.OrgsOrgIDMembersUserIDPutUnprocessableEntity:
+		return interfaces.ErrInvalidOrg
+	case *api.OrgsOrgIDMembersUserIDPutBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) RemoveOrgMember(ctx context.Context, org *models.Organization, userID uuid.UUID) error {
+	res, err := s.client.OrgsOrgIDMembersUserIDDelete(ctx, api.OrgsOrgIDMembersUserIDDeleteParams{OrgID: org.ID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDMembersUserIDDeleteNoContent:
+		org.RekeyNeeded = true
+		return nil
+	case *api.OrgsOrgIDMembersUserIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDMembersUserIDDeleteForbidden:
+		return interfaces.ErrForbidden
+	case *api.OrgsOrgIDMembersUserIDDeleteUnprocessableEntity:
+		return interfaces.ErrInvalidOrg
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+// RekeyOrg replaces the organization key. The server rejects the new key if
+// the members or records changed meanwhile, the re-key can then be retried.
+func (s *orgService) RekeyOrg(ctx context.Context, org *models.Organization) error {
+	members, err := s.ListOrgMembers(ctx, org)
+	if err != nil {
+		return err
+	}
+	records, err := s.FetchOrgRecords(ctx, org)
+	if err != nil {
+		return err
+	}
+	orgKey, err := s.crypto.NewOrgKey()
+	if err != nil {
+		return err
+	}
+
+	req := &api.OrgRekey{
+		KeyVersion: org.KeyVersion + 1,
+		Keys:       make([]api.OrgMemberKey, 0, len(members)),
+		Records:    make([]api.RecordWithId, 0, len(records)),
+	}
+	for _, member := range members {
+		key, err := s.crypto.WrapOrgKeyFor(member.PublicKey, orgKey)
+		if err != nil {
+			return err
+		}
+		req.Keys = append(req.Keys, api.OrgMemberKey{UserID: member.UserID, Key: key})
+	}
+	for _, record := range records {
+		if err := s.crypto.EncryptOrgRecord(orgKey, record); err != nil {
+			return err
+		}
+		req.Records = append(req.Records, api.RecordWithId{
+			ID:      record.ID,
+			Type:    api.RecordType(record.Type),
+			Data:    record.Data,
+			Nonce:   record.Nonce,
+			Version: record.Version + 1,
+		})
+	}
+
+	res, err := s.client.OrgsOrgIDRekeyPost(ctx, req, api.OrgsOrgIDRekeyPostParams{OrgID: org.ID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDRekeyPostNoContent:
+		org.Key = orgKey
+		org.KeyVersion = req.KeyVersion
+		org.RekeyNeeded = false
+		return nil
+	case *api.OrgsOrgIDRekeyPostNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDRekeyPostForbidden:
+		return interfaces.ErrForbidden
+	case *api.OrgsOrgIDRekeyPostConflict:
+		return interfaces.ErrVersionConflict
+	case *api.OrgsOrgIDRekeyPostUnprocessableEntity:
+		return interfaces.ErrInvalidOrg
+	case *api.OrgsOrgIDRekeyPostBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) FetchOrgRecords(ctx context.Context, org *models.Organization) ([]*models.Record, error) {
+	res, err := s.client.OrgsOrgIDRecordsGet(ctx, api.OrgsOrgIDRecordsGetParams{OrgID: org.ID})
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.OrgsOrgIDRecordsGetOKApplicationJSON:
+		records := make([]*models.Record, 0, len(*res))
+		for _, rec := range *res {
+			record := &models.Record{
+				ID:      rec.ID,
+				Type:    models.RecordType(rec.Type),
+				Data:    rec.Data,
+				Nonce:   rec.Nonce,
+				Version: rec.Version,
+				Status:  models.RecordStatusSynced,
+				OrgID:   org.ID,
+			}
+			if err := s.crypto.DecryptOrgRecord(org.Key, record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	case *api.OrgsOrgIDRecordsGetNotFound:
+		return nil, interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) SaveOrgRecord(ctx context.Context, org *models.Organization, record *models.Record) error {
+	encrypted := *record
+	if err := s.crypto.EncryptOrgRecord(org.Key, &encrypted); err != nil {
+		return err
+	}
+	res, err := s.client.OrgsOrgIDRecordsIDPut(ctx, &api.OrgRecord{
+		ID:         api.NewOptUUID(encrypted.ID),
+		Type:       api.RecordType(encrypted.Type),
+		Data:       encrypted.Data,
+		Nonce:      encrypted.Nonce,
+		Version:    encrypted.Version,
+		KeyVersion: org.KeyVersion,
+	}, api.OrgsOrgIDRecordsIDPutParams{OrgID: org.ID, ID: encrypted.ID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDRecordsIDPutNoContent:
+		record.Status = models.RecordStatusSynced
+		return nil
+	case *api.OrgsOrgIDRecordsIDPutNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDRecordsIDPutForbidden:
+		return interfaces.ErrForbidden
+	case *api.OrgsOrgIDRecordsIDPutConflict:
+		// The record was changed or the key replaced meanwhile.
+		return interfaces.ErrVersionConflict
+	case *api.OrgsOrgIDRecordsIDPutBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) DeleteOrgRecord(ctx context.Context, org *models.Organization, id uuid.UUID) error {
+	res, err := s.client.OrgsOrgIDRecordsIDDelete(ctx, api.OrgsOrgIDRecordsIDDeleteParams{OrgID: org.ID, ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.OrgsOrgIDRecordsIDDeleteNoContent:
+		return nil
+	case *api.OrgsOrgIDRecordsIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.OrgsOrgIDRecordsIDDeleteForbidden:
+		return interfaces.ErrForbidden
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *orgService) convertOrg(org *api.Organization) (*models.Organization, error) {
+	key, err := s.crypto.UnwrapOrgKey(org.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Organization{
+		ID:          org.ID,
+		Name:        org.Name,
+		Role:        models.OrgRole(org.Role),
+		Key:         key,
+		KeyVersion:  org.KeyVersion,
+		RekeyNeeded: org.RekeyNeeded,
+		CreatedAt:   org.CreatedAt,
+	}, nil
+}
@@ -35,3 +35,10 @@ func (s *securitySource) SetToken(token string) {
 
 	s.token = token
 }
+
+func (s *securitySource) Token() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token
+}
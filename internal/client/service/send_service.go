@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// sendPath is the path of a send below the server address, in the API and
+// in links.
+const sendPath = "/sends/"
+
+type sendService struct {
+	client    api.Invoker
+	crypto    interfaces.CryptoService
+	serverURL string
+}
+
+func NewSendService(client api.Invoker, crypto interfaces.CryptoService, serverURL string) interfaces.SendService {
+	return &sendService{client: client, crypto: crypto, serverURL: strings.TrimRight(serverURL, "/")}
+}
+
+func (s *sendService) CreateSend(ctx context.Context, req *models.SendRequest) (string, error) {
+	key, nonce, data, err := s.crypto.EncryptSend(req.Data)
+	if err != nil {
+		return "", err
+	}
+	body := &api.SendRequest{
+		Data:      data,
+		Nonce:     nonce,
+		MaxViews:  req.MaxViews,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.Password != "" {
+		body.Password = api.NewOptString(req.Password)
+	}
+	res, err := s.client.SendsPost(ctx, body)
+	if err != nil {
+		return "", err
+	}
+	switch res := res.(type) {
+	case *api.Send:
+		return s.serverURL + sendPath + res.ID.String() + "#" + base64.RawURLEncoding.EncodeToString(key), nil
+	case *api.SendsPostUnprocessableEntity:
+		return "", interfaces.ErrInvalidSend
+	case *api.SendsPostBadRequest:
+		return "", interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return "", interfaces.ErrUnauthorized
+	default:
+		return "", interfaces.ErrUnexpected
+	}
+}
+
+// OpenSend asks the server in the link, which need not be the configured one.
+func (s *sendService) OpenSend(ctx context.Context, link, password string) (*models.OpenedSend, error) {
+	server, id, key, err := parseSendLink(link)
+	if err != nil {
+		return nil, err
+	}
+	params := api.SendsIDGetParams{ID: id}
+	if password != "" {
+		params.XSendPassword = api.NewOptString(password)
+	}
+	res, err := s.client.SendsIDGet(api.WithServerURL(ctx, server), params)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.SendData:
+		data, err := s.crypto.DecryptSend(key, res.Nonce, res.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: the key does not match", interfaces.ErrInvalidSendLink)
+		}
+		return &models.OpenedSend{Data: data, ViewsLeft: res.ViewsLeft, ExpiresAt: res.ExpiresAt}, nil
+	case *api.SendsIDGetForbidden:
+		return nil, interfaces.ErrSendPassword
+	case *api.SendsIDGetNotFound:
+		return nil, fmt.Errorf("%w: the send expired or has no views left", interfaces.ErrNotFound)
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+// parseSendLink splits a link of the form SERVER/sends/ID#KEY.
+func parseSendLink(link string) (server *url.URL, id uuid.UUID, key []byte, err error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, uuid.Nil, nil, interfaces.ErrInvalidSendLink
+	}
+	i := strings.LastIndex(u.Path, sendPath)
+	if i < 0 {
+		return nil, uuid.Nil, nil, interfaces.ErrInvalidSendLink
+	}
+	if id, err = uuid.Parse(u.Path[i+len(sendPath):]); err != nil {
+		return nil, uuid.Nil, nil, interfaces.ErrInvalidSendLink
+	}
+	if key, err = base64.RawURLEncoding.DecodeString(u.Fragment); err != nil || len(key) != keySize {
+		return nil, uuid.Nil, nil, fmt.Errorf("%w: the key is missing or malformed", interfaces.ErrInvalidSendLink)
+	}
+	server = &url.URL{Scheme: u.Scheme, User: u.User, Host: u.Host, Path: u.Path[:i]}
+	return server, id, key, nil
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/api"
@@ -16,22 +19,52 @@ type service struct {
 	interfaces.AuthService
 	interfaces.CryptoService
 	interfaces.SyncService
+	interfaces.TokenService
+	interfaces.ShareService
+	interfaces.OrgService
+	interfaces.EmergencyService
+	interfaces.SendService
 	interfaces.Storage
 	client         api.Invoker
+	security       interfaces.SecuritySource
+	serverURL      string
 	newSyncService interfaces.NewSyncService
+
+	// userID and login are the logged in user, empty for an access token.
+	userID string
+	login  string
+
+	// vault is the organization whose records are in use, nil for the
+	// personal vault. Its records are kept in vaultRecords.
+	vaultMu      sync.RWMutex
+	vault        *models.Organization
+	vaultRecords map[uuid.UUID]*models.Record
 }
 
-func New(client api.Invoker, security interfaces.SecuritySource,
+func New(client api.Invoker, security interfaces.SecuritySource, serverURL string,
 	newAuthService interfaces.NewAuthService,
 	newCryptoService interfaces.NewCryptoService,
 	newSyncService interfaces.NewSyncService,
+	newTokenService interfaces.NewTokenService,
+	newShareService interfaces.NewShareService,
+	newOrgService interfaces.NewOrgService,
+	newEmergencyService interfaces.NewEmergencyService,
+	newSendService interfaces.NewSendService,
 	newCryptoStorage interfaces.NewCryptoStorage,
 ) interfaces.Service {
+	crypto := newCryptoService(newCryptoStorage)
 	return &service{
-		AuthService:    newAuthService(client, security),
-		CryptoService:  newCryptoService(newCryptoStorage),
-		client:         client,
-		newSyncService: newSyncService,
+		AuthService:      newAuthService(client, security),
+		CryptoService:    crypto,
+		TokenService:     newTokenService(client, crypto),
+		ShareService:     newShareService(client, crypto),
+		OrgService:       newOrgService(client, crypto),
+		EmergencyService: newEmergencyService(client, crypto),
+		SendService:      newSendService(client, crypto, serverURL),
+		client:           client,
+		security:         security,
+		serverURL:        serverURL,
+		newSyncService:   newSyncService,
 	}
 }
 
@@ -49,30 +82,220 @@ func (s *service) FetchServerVersion(ctx context.Context) (versionInfo models.Ve
 	return
 }
 
-func (s *service) Register(ctx context.Context, login, password string) (string, error) {
-	userID, err := s.AuthService.Register(ctx, login, password)
-	return s.handleAuth(userID, login, password, err)
+func (s *service) FetchRegistrationMode(ctx context.Context) (models.RegistrationMode, error) {
+	res, err := s.client.RegistrationGet(ctx)
+	if err != nil {
+		return "", err
+	}
+	return models.RegistrationMode(res.Mode), nil
+}
+
+func (s *service) Register(ctx context.Context, login, password, inviteCode string) (string, error) {
+	userID, err := s.AuthService.Register(ctx, login, password, inviteCode)
+	return s.handleAuth(ctx, userID, login, password, err)
 }
 
 func (s *service) Login(ctx context.Context, login, password string) (string, error) {
 	userID, err := s.AuthService.Login(ctx, login, password)
-	return s.handleAuth(userID, login, password, err)
+	return s.handleAuth(ctx, userID, login, password, err)
 }
 
-func (s *service) handleAuth(userID, login, password string, err error) (string, error) {
+func (s *service) handleAuth(ctx context.Context, userID, login, password string, err error) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	s.setVault(nil, nil)
 	s.Storage, err = s.CryptoService.InitCrypto(userID, login, password)
 	if err != nil {
 		return "", err
 	}
 	s.SyncService = s.newSyncService(s.client, s.Storage, s.CryptoService)
+	if err = s.ShareService.EnsureKeyPair(ctx); err != nil {
+		return "", fmt.Errorf("key pair: %w", err)
+	}
+	s.userID, s.login = userID, login
 
 	return userID, nil
 }
 
+func (s *service) Session(sessionKey []byte) (*models.Session, error) {
+	if s.userID == "" {
+		return nil, interfaces.ErrUnauthorized
+	}
+	wrappedKey, err := s.CryptoService.WrapVaultKey(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Session{
+		ServerAddress: s.serverURL,
+		UserID:        s.userID,
+		Login:         s.login,
+		Token:         s.security.Token(),
+		WrappedKey:    wrappedKey,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// ResumeSession does not load the key pair, which needs the server, so
+// records shared with the user are not available in a resumed session.
+func (s *service) ResumeSession(session *models.Session, sessionKey []byte) error {
+	if session.ServerAddress != s.serverURL {
+		return fmt.Errorf("%w: it belongs to %s", interfaces.ErrInvalidSession, session.ServerAddress)
+	}
+	storage, err := s.CryptoService.InitSessionCrypto(session.UserID, sessionKey, session.WrappedKey)
+	if err != nil {
+		return err
+	}
+	s.setVault(nil, nil)
+	s.Storage = storage
+	s.SyncService = s.newSyncService(s.client, s.Storage, s.CryptoService)
+	s.security.SetToken(session.Token)
+	s.userID, s.login = session.UserID, session.Login
+	return nil
+}
+
+// Close closes the local cache, if a user is logged in.
+func (s *service) Close() error {
+	if s.Storage == nil {
+		return nil
+	}
+	return s.Storage.Close()
+}
+
+// LoginWithAccessToken authenticates with a personal access token. The
+// records are read from the server, nothing is cached locally.
+func (s *service) LoginWithAccessToken(ctx context.Context, token string) error {
+	token, tokenKey, err := splitAccessToken(token)
+	if err != nil {
+		return err
+	}
+	keys, err := s.AuthService.AuthenticateAccessToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err = s.CryptoService.InitAccessTokenCrypto(tokenKey, keys); err != nil {
+		return err
+	}
+	s.SyncService = s.newSyncService(s.client, nil, s.CryptoService)
+	return nil
+}
+
+// CreateAccessToken resolves the records and tags of the request to the
+// records the token is limited to. Records still encrypted with the vault
+// key are pushed first, which gives them a record key.
+func (s *service) CreateAccessToken(ctx context.Context, req models.AccessTokenRequest) (string, error) {
+	if len(req.RecordIDs) == 0 && len(req.Tags) == 0 {
+		return s.TokenService.CreateTokenForRecords(ctx, req, nil)
+	}
+
+	selected := make(map[uuid.UUID]*models.Record)
+	for _, id := range req.RecordIDs {
+		record, err := s.Storage.GetRecord(id)
+		if err != nil {
+			return "", fmt.Errorf("record %s: %w", id, err)
+		}
+		selected[id] = record
+	}
+	if len(req.Tags) > 0 {
+		records, err := s.Storage.GetRecords()
+		if err != nil {
+			return "", err
+		}
+		for _, record := range records {
+			if matchesTags(record, req.Tags) {
+				selected[record.ID] = record
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return "", fmt.Errorf("%w: no records match the tags", interfaces.ErrInvalidAccessToken)
+	}
+
+	records := make([]*models.Record, 0, len(selected))
+	for _, record := range selected {
+		record, err := s.ensureRecordKey(ctx, record, interfaces.ErrInvalidAccessToken)
+		if err != nil {
+			return "", err
+		}
+		records = append(records, record)
+	}
+	return s.TokenService.CreateTokenForRecords(ctx, req, records)
+}
+
+// ShareRecord shares the record with the user with the login. A record
+// still encrypted with the vault key is pushed first to give it a record key.
+func (s *service) ShareRecord(ctx context.Context, record *models.Record, login string) error {
+	if record.OrgID != uuid.Nil {
+		return fmt.Errorf("%w: records of a team vault are shared with its members", interfaces.ErrInvalidShare)
+	}
+	record, err := s.ensureRecordKey(ctx, record, interfaces.ErrInvalidShare)
+	if err != nil {
+		return err
+	}
+	return s.ShareService.ShareRecordWith(ctx, record, login)
+}
+
+// RevokeShare revokes the share and rotates the record key: the former
+// recipient may have kept the old one, so the record is encrypted with a new
+// key, which is wrapped again for the remaining recipients. Access tokens
+// limited to the record can no longer decrypt it and must be recreated.
+func (s *service) RevokeShare(ctx context.Context, recordID, userID uuid.UUID) error {
+	if err := s.ShareService.RevokeShare(ctx, recordID, userID); err != nil {
+		return err
+	}
+	record, err := s.Storage.GetRecord(recordID)
+	if err != nil {
+		return fmt.Errorf("the share is revoked, but the record key was not rotated: %w", err)
+	}
+	record.Key = nil
+	if record, err = s.PushRecord(ctx, record); err != nil {
+		return fmt.Errorf("the share is revoked, but the record key was not rotated: %w", err)
+	}
+	if record.Status != models.RecordStatusSynced {
+		return fmt.Errorf("the share is revoked, but the record key was not rotated: record %s is %s", record.ID, record.Status)
+	}
+
+	shares, err := s.ShareService.ListShares(ctx, recordID)
+	if err != nil {
+		return fmt.Errorf("the record key was rotated, share the record again with the remaining users: %w", err)
+	}
+	var errs []error
+	for _, share := range shares {
+		if err = s.ShareService.ShareRecordWith(ctx, record, share.Login); err != nil {
+			errs = append(errs, fmt.Errorf("share the record again with %s: %w", share.Login, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ensureRecordKey returns the record with a record key, pushing a synced
+// record that has none. Records with local changes must be synced first,
+// otherwise notSynced is returned.
+func (s *service) ensureRecordKey(ctx context.Context, record *models.Record, notSynced error) (*models.Record, error) {
+	if record.Status == models.RecordStatusSynced && len(record.Key) == 0 {
+		var err error
+		if record, err = s.PushRecord(ctx, record); err != nil {
+			return nil, err
+		}
+	}
+	if record.Status != models.RecordStatusSynced {
+		return nil, fmt.Errorf("%w: record %s is %s, sync it first", notSynced, record.ID, record.Status)
+	}
+	return record, nil
+}
+
+// DeleteAccount deletes the account on the server and destroys the local cache of its records.
+func (s *service) DeleteAccount(ctx context.Context, password string) error {
+	if err := s.AuthService.DeleteAccount(ctx, password); err != nil {
+		return err
+	}
+	return s.Storage.Destroy()
+}
+
 func (s *service) PushRecord(ctx context.Context, record *models.Record) (*models.Record, error) {
+	if org := s.CurrentVault(); org != nil && (record.ID == uuid.Nil || record.OrgID == org.ID) {
+		return s.pushOrgRecord(ctx, org, record)
+	}
 	if record.ID == uuid.Nil {
 		id, err := s.newUniqueID()
 		if err != nil {
@@ -91,6 +314,21 @@ func (s *service) PushRecord(ctx context.Context, record *models.Record) (*model
 	return s.SyncService.PushRecord(ctx, record)
 }
 
+// CaptureRecord saves a new record in the local cache without pushing it.
+// Sync skips it too, until the user approves it with PushRecord.
+func (s *service) CaptureRecord(record *models.Record) (*models.Record, error) {
+	id, err := s.newUniqueID()
+	if err != nil {
+		return nil, err
+	}
+	record.ID = id
+	record.Status = models.RecordStatusCaptured
+	if err = s.Storage.SaveRecord(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
 func (s *service) newUniqueID() (uuid.UUID, error) {
 	for range maxUniqueIDAttempts {
 		id, err := uuid.NewRandom()
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+type shareService struct {
+	client api.Invoker
+	crypto interfaces.CryptoService
+}
+
+func NewShareService(client api.Invoker, crypto interfaces.CryptoService) interfaces.ShareService {
+	return &shareService{client: client, crypto: crypto}
+}
+
+func (s *shareService) EnsureKeyPair(ctx context.Context) error {
+	res, err := s.client.AccountKeysGet(ctx)
+	if err != nil {
+		return err
+	}
+	switch res := res.(type) {
+	case *api.UserKeys:
+		return s.crypto.InitKeyPair(res.WrappedPrivateKey)
+	case *api.AccountKeysGetNotFound:
+		return s.publishKeyPair(ctx)
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *shareService) publishKeyPair(ctx context.Context) error {
+	publicKey, wrappedPrivateKey, err := s.crypto.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	res, err := s.client.AccountKeysPut(ctx, &api.UserKeys{PublicKey: publicKey, WrappedPrivateKey: wrappedPrivateKey})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.AccountKeysPutNoContent:
+		return nil
+	case *api.AccountKeysPutConflict:
+		// Another device published its key pair first, use that one.
+		return s.EnsureKeyPair(ctx)
+	case *api.AccountKeysPutBadRequest, *api.AccountKeysPutUnprocessableEntity:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *shareService) ShareRecordWith(ctx context.Context, record *models.Record, login string) error {
+	res, err := s.client.UsersLoginPublicKeyGet(ctx, api.UsersLoginPublicKeyGetParams{Login: login})
+	if err != nil {
+		return err
+	}
+	var publicKey []byte
+	switch res := res.(type) {
+	case *api.PublicKey:
+		publicKey = res.PublicKey
+	case *api.UsersLoginPublicKeyGetNotFound:
+		return interfaces.ErrInvalidShare
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+
+	key, err := s.crypto.WrapRecordKeyFor(publicKey, record)
+	if err != nil {
+		return err
+	}
+	shareRes, err := s.client.RecordsIDSharesPost(ctx, &api.ShareRequest{Login: login, Key: key}, api.RecordsIDSharesPostParams{ID: record.ID})
+	if err != nil {
+		return err
+	}
+	switch shareRes.(type) {
+	case *api.Share:
+		return nil
+	case *api.RecordsIDSharesPostNotFound:
+		return interfaces.ErrNotFound
+	case *api.RecordsIDSharesPostUnprocessableEntity:
+		return interfaces.ErrInvalidShare
+	case *api.RecordsIDSharesPostBadRequest:
+		return interfaces.ErrBadRequest
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+func (s *shareService) ListShares(ctx context.Context, recordID uuid.UUID) ([]*models.Share, error) {
+	res, err := s.client.RecordsIDSharesGet(ctx, api.RecordsIDSharesGetParams{ID: recordID})
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.RecordsIDSharesGetOKApplicationJSON:
+		shares := make([]*models.Share, 0, len(*res))
+		for _, share := range *res {
+			shares = append(shares, &models.Share{UserID: share.UserID, Login: share.Login, CreatedAt: share.CreatedAt})
+		}
+		return shares, nil
+	case *api.RecordsIDSharesGetNotFound:
+		// The record is not synced yet, so it is not shared with anyone.
+		return nil, nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *shareService) RevokeShare(ctx context.Context, recordID, userID uuid.UUID) error {
+	res, err := s.client.RecordsIDSharesUserIDDelete(ctx, api.RecordsIDSharesUserIDDeleteParams{ID: recordID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.RecordsIDSharesUserIDDeleteNoContent:
+		return nil
+	case *api.RecordsIDSharesUserIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+// FetchSharedRecords returns the records shared with the user decrypted.
+// They are not cached locally, so they are only available online.
+func (s *shareService) FetchSharedRecords(ctx context.Context) ([]*models.SharedRecord, error) {
+	res, err := s.client.SharedGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.SharedGetOKApplicationJSON:
+		records := make([]*models.SharedRecord, 0, len(*res))
+		for _, rec := range *res {
+			record := &models.SharedRecord{
+				Record: models.Record{
+					ID:      rec.ID,
+					Type:    models.RecordType(rec.Type),
+					Data:    rec.Data,
+					Nonce:   rec.Nonce,
+					Version: rec.Version,
+					Status:  models.RecordStatusSynced,
+					Key:     rec.Key,
+				},
+				Owner:    rec.Owner,
+				SharedAt: rec.SharedAt,
+			}
+			if err := s.crypto.DecryptSharedRecord(&record.Record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
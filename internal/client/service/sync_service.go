@@ -8,12 +8,17 @@ import (
 	"github.com/grnsv/GophKeeper/internal/api"
 	"github.com/grnsv/GophKeeper/internal/client/interfaces"
 	"github.com/grnsv/GophKeeper/internal/client/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type syncService struct {
 	client  api.Invoker
 	storage interfaces.Storage
 	crypto  interfaces.CryptoService
+	tracer  trace.Tracer
 }
 
 func NewSyncService(client api.Invoker, storage interfaces.Storage, crypto interfaces.CryptoService) interfaces.SyncService {
@@ -21,10 +26,34 @@ func NewSyncService(client api.Invoker, storage interfaces.Storage, crypto inter
 		client:  client,
 		storage: storage,
 		crypto:  crypto,
+		tracer:  otel.Tracer("github.com/grnsv/GophKeeper/internal/client/service"),
 	}
 }
 
-func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (*models.Record, error) {
+func (s *syncService) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func recordAttrs(record *models.Record) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("record.id", record.ID.String()),
+		attribute.Int("record.version", record.Version),
+		attribute.String("record.status", string(record.Status)),
+	}
+}
+
+func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (_ *models.Record, err error) {
+	ctx, span := s.startSpan(ctx, "PushRecord", recordAttrs(record)...)
+	defer func() { endSpan(span, err) }()
+
 	encrypted := *record
 	if err := s.crypto.EncryptRecord(&encrypted); err != nil {
 		return record, err
@@ -36,6 +65,7 @@ func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (*m
 		Data:    encrypted.Data,
 		Nonce:   encrypted.Nonce,
 		Version: encrypted.Version,
+		Key:     encrypted.Key,
 	}, api.RecordsIDPutParams{
 		ID: encrypted.ID,
 	})
@@ -43,6 +73,9 @@ func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (*m
 		return record, err
 	}
 
+	// Keep the record key generated for a record encrypted with the vault
+	// key, access tokens may have been given it.
+	record.Key = encrypted.Key
 	switch res.(type) {
 	case *api.RecordsIDPutNoContent:
 		record.Status = models.RecordStatusSynced
@@ -50,6 +83,8 @@ func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (*m
 		return record, interfaces.ErrBadRequest
 	case *api.Unauthorized:
 		return record, interfaces.ErrUnauthorized
+	case *api.RecordsIDPutForbidden:
+		return record, interfaces.ErrForbidden
 	case *api.RecordsIDPutConflict:
 		record.Status = models.RecordStatusConflict
 	default:
@@ -58,7 +93,10 @@ func (s *syncService) PushRecord(ctx context.Context, record *models.Record) (*m
 	return record, s.storage.SaveRecord(record)
 }
 
-func (s *syncService) PullRecord(ctx context.Context, id uuid.UUID) (*models.Record, error) {
+func (s *syncService) PullRecord(ctx context.Context, id uuid.UUID) (_ *models.Record, err error) {
+	ctx, span := s.startSpan(ctx, "PullRecord", attribute.String("record.id", id.String()))
+	defer func() { endSpan(span, err) }()
+
 	res, err := s.client.RecordsIDGet(ctx, api.RecordsIDGetParams{ID: id})
 	if err != nil {
 		return nil, err
@@ -72,6 +110,7 @@ func (s *syncService) PullRecord(ctx context.Context, id uuid.UUID) (*models.Rec
 			Nonce:   rec.Nonce,
 			Version: rec.Version,
 			Status:  models.RecordStatusSynced,
+			Key:     rec.Key,
 		}
 		if err := s.crypto.DecryptRecord(record); err != nil {
 			return nil, err
@@ -86,7 +125,14 @@ func (s *syncService) PullRecord(ctx context.Context, id uuid.UUID) (*models.Rec
 	}
 }
 
-func (s *syncService) ForgetRecord(ctx context.Context, record *models.Record) error {
+func (s *syncService) ForgetRecord(ctx context.Context, record *models.Record) (err error) {
+	ctx, span := s.startSpan(ctx, "ForgetRecord", recordAttrs(record)...)
+	defer func() { endSpan(span, err) }()
+
+	// A captured record never reached the server.
+	if record.Status == models.RecordStatusCaptured {
+		return s.storage.DeleteRecord(record.ID)
+	}
 	if record.Status != models.RecordStatusDeleted {
 		record.Status = models.RecordStatusDeleted
 		if err := s.storage.SaveRecord(record); err != nil {
@@ -104,23 +150,35 @@ func (s *syncService) ForgetRecord(ctx context.Context, record *models.Record) e
 		return s.storage.DeleteRecord(record.ID)
 	case *api.Unauthorized:
 		return interfaces.ErrUnauthorized
+	case *api.RecordsIDDeleteForbidden:
+		return interfaces.ErrForbidden
 	default:
 		return interfaces.ErrUnexpected
 	}
 }
 
 func (s *syncService) Sync(ctx context.Context) (hasConflicts bool, err error) {
+	ctx, span := s.startSpan(ctx, "Sync")
+	defer func() {
+		span.SetAttributes(attribute.Bool("sync.has_conflicts", hasConflicts))
+		endSpan(span, err)
+	}()
+
 	serverRecords, err := s.fetchRecords(ctx)
 	if err != nil {
 		return
 	}
-	if err = s.pull(serverRecords); err != nil {
+	span.SetAttributes(attribute.Int("sync.server_records", len(serverRecords)))
+	if err = s.pull(ctx, serverRecords); err != nil {
 		return
 	}
 	return s.push(ctx, serverRecords)
 }
 
-func (s *syncService) fetchRecords(ctx context.Context) (map[uuid.UUID]*models.Record, error) {
+func (s *syncService) fetchRecords(ctx context.Context) (_ map[uuid.UUID]*models.Record, err error) {
+	ctx, span := s.startSpan(ctx, "fetchRecords")
+	defer func() { endSpan(span, err) }()
+
 	res, err := s.client.RecordsGet(ctx)
 	if err != nil {
 		return nil, err
@@ -135,6 +193,19 @@ func (s *syncService) fetchRecords(ctx context.Context) (map[uuid.UUID]*models.R
 	}
 }
 
+func (s *syncService) FetchRecords(ctx context.Context) ([]*models.Record, error) {
+	records, err := s.fetchRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.Record, 0, len(records))
+	for _, record := range records {
+		record.Status = models.RecordStatusSynced
+		out = append(out, record)
+	}
+	return out, nil
+}
+
 func (s *syncService) decryptFetchedRecords(res *api.RecordsGetOKApplicationJSON) (map[uuid.UUID]*models.Record, error) {
 	records := make(map[uuid.UUID]*models.Record, len(*res)*8/7+1)
 	for _, rec := range *res {
@@ -144,6 +215,7 @@ func (s *syncService) decryptFetchedRecords(res *api.RecordsGetOKApplicationJSON
 			Data:    rec.Data,
 			Nonce:   rec.Nonce,
 			Version: rec.Version,
+			Key:     rec.Key,
 		}
 		if err := s.crypto.DecryptRecord(record); err != nil {
 			return nil, err
@@ -153,7 +225,10 @@ func (s *syncService) decryptFetchedRecords(res *api.RecordsGetOKApplicationJSON
 	return records, nil
 }
 
-func (s *syncService) pull(serverRecords map[uuid.UUID]*models.Record) error {
+func (s *syncService) pull(ctx context.Context, serverRecords map[uuid.UUID]*models.Record) (err error) {
+	_, span := s.startSpan(ctx, "pull")
+	defer func() { endSpan(span, err) }()
+
 	for _, serverRecord := range serverRecords {
 		localRecord, err := s.storage.GetRecord(serverRecord.ID)
 		if err != nil {
@@ -187,6 +262,9 @@ func (s *syncService) syncRecord(localRecord *models.Record, serverRecord *model
 }
 
 func (s *syncService) push(ctx context.Context, serverRecords map[uuid.UUID]*models.Record) (hasConflicts bool, err error) {
+	ctx, span := s.startSpan(ctx, "push")
+	defer func() { endSpan(span, err) }()
+
 	localRecords, err := s.storage.GetRecords()
 	if err != nil {
 		return
@@ -197,6 +275,8 @@ func (s *syncService) push(ctx context.Context, serverRecords map[uuid.UUID]*mod
 			localRecord, err = s.PushRecord(ctx, localRecord)
 		case models.RecordStatusDeleted:
 			err = s.ForgetRecord(ctx, localRecord)
+		case models.RecordStatusCaptured:
+			continue
 		case models.RecordStatusConflict, models.RecordStatusSynced:
 			_, exists := serverRecords[localRecord.ID]
 			if !exists {
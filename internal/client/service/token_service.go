@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+const accessTokenPrefix = "gkp_"
+
+type tokenService struct {
+	client api.Invoker
+	crypto interfaces.CryptoService
+}
+
+func NewTokenService(client api.Invoker, crypto interfaces.CryptoService) interfaces.TokenService {
+	return &tokenService{client: client, crypto: crypto}
+}
+
+// CreateTokenForRecords wraps the keys for the token with a new key part
+// and appends it to the token the server returns, so only the holder of the
+// token can unwrap them.
+func (s *tokenService) CreateTokenForRecords(ctx context.Context, req models.AccessTokenRequest, records []*models.Record) (string, error) {
+	tokenKey := make([]byte, keySize)
+	if _, err := rand.Read(tokenKey); err != nil {
+		return "", err
+	}
+	apiReq := &api.AccessTokenRequest{
+		Name:      req.Name,
+		ReadOnly:  api.NewOptBool(req.ReadOnly),
+		ExpiresAt: req.ExpiresAt,
+		Tags:      req.Tags,
+	}
+	if records == nil {
+		wrapped, err := s.crypto.WrapVaultKey(tokenKey)
+		if err != nil {
+			return "", err
+		}
+		apiReq.WrappedKey = wrapped
+	} else {
+		apiReq.RecordKeys = make([]api.RecordKey, 0, len(records))
+		for _, record := range records {
+			wrapped, err := s.crypto.WrapRecordKey(tokenKey, record)
+			if err != nil {
+				return "", err
+			}
+			apiReq.RecordKeys = append(apiReq.RecordKeys, api.RecordKey{RecordID: record.ID, Key: wrapped})
+		}
+	}
+
+	res, err := s.client.TokensPost(ctx, apiReq)
+	if err != nil {
+		return "", err
+	}
+	switch res := res.(type) {
+	case *api.CreatedAccessToken:
+		return res.Token + "_" + hex.EncodeToString(tokenKey), nil
+	case *api.TokensPostBadRequest:
+		return "", interfaces.ErrBadRequest
+	case *api.TokensPostUnprocessableEntity:
+		return "", interfaces.ErrInvalidAccessToken
+	case *api.Unauthorized:
+		return "", interfaces.ErrUnauthorized
+	default:
+		return "", interfaces.ErrUnexpected
+	}
+}
+
+func (s *tokenService) ListAccessTokens(ctx context.Context) ([]*models.AccessToken, error) {
+	res, err := s.client.TokensGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch res := res.(type) {
+	case *api.TokensGetOKApplicationJSON:
+		tokens := make([]*models.AccessToken, 0, len(*res))
+		for _, token := range *res {
+			tokens = append(tokens, &models.AccessToken{
+				ID:         token.ID,
+				Name:       token.Name,
+				ReadOnly:   token.ReadOnly,
+				Records:    token.Records,
+				Tags:       token.Tags,
+				ExpiresAt:  token.ExpiresAt,
+				LastUsedAt: token.LastUsedAt.Or(time.Time{}),
+				CreatedAt:  token.CreatedAt,
+			})
+		}
+		return tokens, nil
+	case *api.Unauthorized:
+		return nil, interfaces.ErrUnauthorized
+	default:
+		return nil, interfaces.ErrUnexpected
+	}
+}
+
+func (s *tokenService) RevokeAccessToken(ctx context.Context, id uuid.UUID) error {
+	res, err := s.client.TokensIDDelete(ctx, api.TokensIDDeleteParams{ID: id})
+	if err != nil {
+		return err
+	}
+	switch res.(type) {
+	case *api.TokensIDDeleteNoContent:
+		return nil
+	case *api.TokensIDDeleteNotFound:
+		return interfaces.ErrNotFound
+	case *api.Unauthorized:
+		return interfaces.ErrUnauthorized
+	default:
+		return interfaces.ErrUnexpected
+	}
+}
+
+// splitAccessToken separates "gkp_<id>_<secret>_<key>" into the part sent
+// to the server and the key the wrapped keys are unwrapped with.
+func splitAccessToken(token string) (string, []byte, error) {
+	i := strings.LastIndex(token, "_")
+	if !strings.HasPrefix(token, accessTokenPrefix) || strings.Count(token, "_") != 3 {
+		return "", nil, fmt.Errorf("%w: expected gkp_<id>_<secret>_<key>", interfaces.ErrInvalidAccessToken)
+	}
+	key, err := hex.DecodeString(token[i+1:])
+	if err != nil || len(key) != keySize {
+		return "", nil, fmt.Errorf("%w: malformed key part", interfaces.ErrInvalidAccessToken)
+	}
+	return token[:i], key, nil
+}
+
+// matchesTags tells whether the metadata of the decrypted record has one of
+// the tags, a metadata key or a "key=value" pair.
+func matchesTags(record *models.Record, tags []string) bool {
+	var data struct {
+		Metadata map[string]string
+	}
+	if err := json.Unmarshal(record.Data, &data); err != nil {
+		return false
+	}
+	for _, tag := range tags {
+		key, value, hasValue := strings.Cut(tag, "=")
+		v, ok := data.Metadata[key]
+		if ok && (!hasValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/interfaces"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+)
+
+// SwitchVault fetches the records of the organization and makes them the
+// records the service works with. They are kept in memory only.
+func (s *service) SwitchVault(ctx context.Context, org *models.Organization) error {
+	if org == nil {
+		s.setVault(nil, nil)
+		return nil
+	}
+	records, err := s.OrgService.FetchOrgRecords(ctx, org)
+	if err != nil {
+		return err
+	}
+	cache := make(map[uuid.UUID]*models.Record, len(records))
+	for _, record := range records {
+		cache[record.ID] = record
+	}
+	s.setVault(org, cache)
+	return nil
+}
+
+func (s *service) CurrentVault() *models.Organization {
+	s.vaultMu.RLock()
+	defer s.vaultMu.RUnlock()
+	return s.vault
+}
+
+func (s *service) setVault(org *models.Organization, records map[uuid.UUID]*models.Record) {
+	s.vaultMu.Lock()
+	defer s.vaultMu.Unlock()
+	s.vault = org
+	s.vaultRecords = records
+}
+
+// GetRecords returns the records of the current vault.
+func (s *service) GetRecords() ([]*models.Record, error) {
+	s.vaultMu.RLock()
+	defer s.vaultMu.RUnlock()
+	if s.vault == nil {
+		return s.Storage.GetRecords()
+	}
+	records := make([]*models.Record, 0, len(s.vaultRecords))
+	for _, record := range s.vaultRecords {
+		clone := *record
+		records = append(records, &clone)
+	}
+	return records, nil
+}
+
+// pushOrgRecord saves a record of the team vault on the server. Unlike
+// personal records it is not saved when the server cannot be reached.
+func (s *service) pushOrgRecord(ctx context.Context, org *models.Organization, record *models.Record) (*models.Record, error) {
+	if !org.Role.CanWrite() {
+		return record, interfaces.ErrForbidden
+	}
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+	record.OrgID = org.ID
+	record.Version++
+	if err := s.OrgService.SaveOrgRecord(ctx, org, record); err != nil {
+		record.Version--
+		return record, err
+	}
+
+	clone := *record
+	s.vaultMu.Lock()
+	if s.vault == org {
+		s.vaultRecords[record.ID] = &clone
+	}
+	s.vaultMu.Unlock()
+	return record, nil
+}
+
+func (s *service) ForgetRecord(ctx context.Context, record *models.Record) error {
+	if record.OrgID == uuid.Nil {
+		return s.SyncService.ForgetRecord(ctx, record)
+	}
+	org := s.CurrentVault()
+	if org == nil || org.ID != record.OrgID {
+		return fmt.Errorf("%w: switch to the team vault of the record first", interfaces.ErrNotFound)
+	}
+	if err := s.OrgService.DeleteOrgRecord(ctx, org, record.ID); err != nil {
+		return err
+	}
+
+	s.vaultMu.Lock()
+	if s.vault == org {
+		delete(s.vaultRecords, record.ID)
+	}
+	s.vaultMu.Unlock()
+	return nil
+}
+
+// Sync syncs the personal vault and refreshes the records of the team vault
+// in use, picking up a replaced key or a changed role.
+func (s *service) Sync(ctx context.Context) (bool, error) {
+	hasConflicts, err := s.SyncService.Sync(ctx)
+	if err != nil {
+		return hasConflicts, err
+	}
+	if org := s.CurrentVault(); org != nil {
+		return hasConflicts, s.refreshVault(ctx, org.ID)
+	}
+	return hasConflicts, nil
+}
+
+func (s *service) refreshVault(ctx context.Context, orgID uuid.UUID) error {
+	orgs, err := s.OrgService.ListOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		if org.ID == orgID {
+			return s.SwitchVault(ctx, org)
+		}
+	}
+	s.setVault(nil, nil)
+	return fmt.Errorf("%w: no longer a member of the organization, switched to the personal vault", interfaces.ErrForbidden)
+}
@@ -0,0 +1,306 @@
+// Package sshagent serves the SSH key records of the vault with the
+// ssh-agent protocol, so that ssh finds them through SSH_AUTH_SOCK and the
+// private keys never touch the disk.
+package sshagent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/client/agent"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	socketName = "ssh-agent.sock"
+	// ConfirmTimeout is how long a confirmation waits for an answer before
+	// the use of the key is refused.
+	ConfirmTimeout = 30 * time.Second
+)
+
+var (
+	errReadOnly = errors.New("the keys are managed in GophKeeper")
+	errNoKey    = errors.New("no such key")
+	errRefused  = errors.New("the use of the key was refused")
+)
+
+// DefaultSocket returns the socket path next to the socket of the agent
+// holding the vault.
+func DefaultSocket() (string, error) {
+	dir, err := agent.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketName), nil
+}
+
+// Key is a key as shown to the user when it asks for confirmation.
+type Key struct {
+	Name        string
+	Fingerprint string
+}
+
+// ConfirmFunc reports whether the key may be used. It is called for the keys
+// requiring confirmation, a nil ConfirmFunc refuses them.
+type ConfirmFunc func(ctx context.Context, key Key) bool
+
+// Agent implements the ssh-agent protocol on top of the records of the
+// vault, which are read on every request so that changes apply at once. It
+// is read-only: keys are added and removed by editing the records.
+type Agent struct {
+	records func() ([]*models.Record, error)
+	confirm ConfirmFunc
+	logger  *slog.Logger
+}
+
+func New(records func() ([]*models.Record, error), confirm ConfirmFunc, logger *slog.Logger) *Agent {
+	return &Agent{records: records, confirm: confirm, logger: logger}
+}
+
+// Listen creates the socket at path, which only the user may use. A socket
+// left behind by an agent that is gone is replaced.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("an SSH agent is already running on %s", path)
+	}
+	_ = os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Serve serves the connections of listener until ctx is done, then it
+// closes listener, which removes the socket.
+func (a *Agent) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := sshagent.ServeAgent(&session{Agent: a, ctx: ctx}, conn); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				a.logger.Debug("ssh agent connection closed", slog.Any("error", err))
+			}
+		}()
+	}
+}
+
+// ListenAndServe is Listen followed by Serve.
+func (a *Agent) ListenAndServe(ctx context.Context, path string) error {
+	listener, err := Listen(path)
+	if err != nil {
+		return err
+	}
+	a.logger.Info("ssh agent started", slog.String("socket", path))
+	return a.Serve(ctx, listener)
+}
+
+// key is an SSH key record.
+type key struct {
+	name      string
+	data      models.SSHKey
+	publicKey ssh.PublicKey
+}
+
+func (a *Agent) keys() ([]key, error) {
+	records, err := a.records()
+	if err != nil {
+		return nil, err
+	}
+	var keys []key
+	for _, record := range records {
+		if record.Type != models.RecordTypeSSHKey || record.Status == models.RecordStatusDeleted {
+			continue
+		}
+		var data models.SSHKey
+		if err = json.Unmarshal(record.Data, &data); err != nil {
+			a.logger.Warn("skipping a damaged SSH key", slog.String("id", record.ID.String()), slog.Any("error", err))
+			continue
+		}
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(data.PublicKey))
+		if err != nil {
+			a.logger.Warn("skipping an SSH key with an invalid public key", slog.String("id", record.ID.String()), slog.Any("error", err))
+			continue
+		}
+		name := data.Metadata["name"]
+		if name == "" {
+			name = data.Comment
+		}
+		keys = append(keys, key{name: name, data: data, publicKey: publicKey})
+	}
+	return keys, nil
+}
+
+// session is the agent serving one connection.
+type session struct {
+	*Agent
+	ctx context.Context
+}
+
+func (s *session) List() ([]*sshagent.Key, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*sshagent.Key, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, &sshagent.Key{
+			Format:  key.publicKey.Type(),
+			Blob:    key.publicKey.Marshal(),
+			Comment: key.data.Comment,
+		})
+	}
+	return res, nil
+}
+
+func (s *session) Sign(publicKey ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return s.SignWithFlags(publicKey, data, 0)
+}
+
+func (s *session) SignWithFlags(publicKey ssh.PublicKey, data []byte, flags sshagent.SignatureFlags) (*ssh.Signature, error) {
+	signer, err := s.signer(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+	switch {
+	case flags&sshagent.SignatureFlagRsaSha256 != 0 && ok:
+		return algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA256)
+	case flags&sshagent.SignatureFlagRsaSha512 != 0 && ok:
+		return algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	default:
+		return signer.Sign(rand.Reader, data)
+	}
+}
+
+// signer returns the signer of the key, asking for confirmation if the key
+// requires it.
+func (s *session) signer(publicKey ssh.PublicKey) (ssh.Signer, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return nil, err
+	}
+	wanted := publicKey.Marshal()
+	for _, key := range keys {
+		if !bytes.Equal(key.publicKey.Marshal(), wanted) {
+			continue
+		}
+		fingerprint := ssh.FingerprintSHA256(key.publicKey)
+		if key.data.Confirm && !s.confirmed(Key{Name: key.name, Fingerprint: fingerprint}) {
+			s.logger.Info("ssh key use refused", slog.String("key", key.name), slog.String("fingerprint", fingerprint))
+			return nil, errRefused
+		}
+		s.logger.Info("ssh key used", slog.String("key", key.name), slog.String("fingerprint", fingerprint))
+		return ssh.ParsePrivateKey([]byte(key.data.PrivateKey))
+	}
+	return nil, errNoKey
+}
+
+func (s *session) confirmed(key Key) bool {
+	if s.confirm == nil {
+		s.logger.Warn("the key requires confirmation, serve it from the terminal UI", slog.String("key", key.Name))
+		return false
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, ConfirmTimeout)
+	defer cancel()
+	return s.confirm(ctx, key)
+}
+
+// ConfirmRequest is a confirmation sent to a channel by ChannelConfirm.
+type ConfirmRequest struct {
+	Key    Key
+	ctx    context.Context
+	answer chan bool
+}
+
+// Answer allows or refuses the use of the key. Answers after the request
+// was done are ignored.
+func (r *ConfirmRequest) Answer(allow bool) {
+	select {
+	case r.answer <- allow:
+	default:
+	}
+}
+
+// Done is closed when the request no longer waits for an answer.
+func (r *ConfirmRequest) Done() <-chan struct{} {
+	return r.ctx.Done()
+}
+
+// ChannelConfirm sends the confirmations to requests, for a user interface
+// to answer them.
+func ChannelConfirm(requests chan<- *ConfirmRequest) ConfirmFunc {
+	return func(ctx context.Context, key Key) bool {
+		req := &ConfirmRequest{Key: key, ctx: ctx, answer: make(chan bool, 1)}
+		select {
+		case requests <- req:
+		case <-ctx.Done():
+			return false
+		}
+		select {
+		case allow := <-req.answer:
+			return allow
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (s *session) Signers() ([]ssh.Signer, error) {
+	return nil, errReadOnly
+}
+
+func (s *session) Add(sshagent.AddedKey) error {
+	return errReadOnly
+}
+
+func (s *session) Remove(ssh.PublicKey) error {
+	return errReadOnly
+}
+
+func (s *session) RemoveAll() error {
+	return errReadOnly
+}
+
+func (s *session) Lock([]byte) error {
+	return errReadOnly
+}
+
+func (s *session) Unlock([]byte) error {
+	return errReadOnly
+}
+
+func (s *session) Extension(string, []byte) ([]byte, error) {
+	return nil, sshagent.ErrExtensionUnsupported
+}
@@ -0,0 +1,200 @@
+package sshagent_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"github.com/grnsv/GophKeeper/internal/client/sshagent"
+	"golang.org/x/crypto/ssh"
+	sshagentlib "golang.org/x/crypto/ssh/agent"
+)
+
+// serve serves the records on a socket in a temporary directory and returns
+// a client connected to it.
+func serve(t *testing.T, records []*models.Record, confirm sshagent.ConfirmFunc) sshagentlib.ExtendedAgent {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a := sshagent.New(func() ([]*models.Record, error) { return records, nil }, confirm, logger)
+
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := sshagent.Listen(path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- a.Serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return sshagentlib.NewClient(conn)
+}
+
+func newRecord(t *testing.T, key models.SSHKey, status models.RecordStatus) *models.Record {
+	t.Helper()
+	data, err := json.Marshal(key)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return &models.Record{ID: uuid.New(), Type: models.RecordTypeSSHKey, Data: data, Status: status}
+}
+
+func generate(t *testing.T, keyType, comment string, confirm bool) (models.SSHKey, ssh.PublicKey) {
+	t.Helper()
+	key, err := sshagent.Generate(keyType, comment)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	key.Confirm = confirm
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	if err != nil {
+		t.Fatalf("ParseAuthorizedKey: %v", err)
+	}
+	return key, publicKey
+}
+
+func TestList(t *testing.T) {
+	key, publicKey := generate(t, sshagent.KeyTypeEd25519, "work", false)
+	deleted, _ := generate(t, sshagent.KeyTypeEd25519, "deleted", false)
+	text, err := json.Marshal(models.Text{Text: "not a key"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	client := serve(t, []*models.Record{
+		newRecord(t, key, models.RecordStatusSynced),
+		newRecord(t, deleted, models.RecordStatusDeleted),
+		{ID: uuid.New(), Type: models.RecordTypeText, Data: text, Status: models.RecordStatusSynced},
+	}, nil)
+
+	keys, err := client.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("List returned %d keys, want 1", len(keys))
+	}
+	if string(keys[0].Marshal()) != string(publicKey.Marshal()) || keys[0].Comment != "work" {
+		t.Fatalf("List returned %s %q, want the key of the record", keys[0].Format, keys[0].Comment)
+	}
+}
+
+func TestSign(t *testing.T) {
+	ed25519Key, ed25519Public := generate(t, sshagent.KeyTypeEd25519, "ed25519", false)
+	rsaKey, rsaPublic := generate(t, sshagent.KeyTypeRSA, "rsa", false)
+	client := serve(t, []*models.Record{
+		newRecord(t, ed25519Key, models.RecordStatusSynced),
+		newRecord(t, rsaKey, models.RecordStatusPending),
+	}, nil)
+	data := []byte("session data")
+
+	tests := []struct {
+		name      string
+		publicKey ssh.PublicKey
+		flags     sshagentlib.SignatureFlags
+		format    string
+	}{
+		{name: "ed25519", publicKey: ed25519Public, format: ssh.KeyAlgoED25519},
+		{name: "rsa-sha2-256", publicKey: rsaPublic, flags: sshagentlib.SignatureFlagRsaSha256, format: ssh.KeyAlgoRSASHA256},
+		{name: "rsa-sha2-512", publicKey: rsaPublic, flags: sshagentlib.SignatureFlagRsaSha512, format: ssh.KeyAlgoRSASHA512},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, err := client.SignWithFlags(tt.publicKey, data, tt.flags)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if signature.Format != tt.format {
+				t.Fatalf("signature format %s, want %s", signature.Format, tt.format)
+			}
+			if err = tt.publicKey.Verify(data, signature); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+		})
+	}
+
+	_, otherPublic := generate(t, sshagent.KeyTypeEd25519, "other", false)
+	if _, err := client.Sign(otherPublic, data); err == nil {
+		t.Fatal("Sign with a key that is not in the vault succeeded")
+	}
+}
+
+func TestSignConfirm(t *testing.T) {
+	key, publicKey := generate(t, sshagent.KeyTypeEd25519, "confirm", true)
+	records := []*models.Record{newRecord(t, key, models.RecordStatusSynced)}
+	data := []byte("session data")
+
+	t.Run("allowed", func(t *testing.T) {
+		var asked sshagent.Key
+		client := serve(t, records, func(_ context.Context, key sshagent.Key) bool {
+			asked = key
+			return true
+		})
+		signature, err := client.Sign(publicKey, data)
+		if err != nil {
+			t.Fatalf("Sign: %v", err)
+		}
+		if err = publicKey.Verify(data, signature); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		if asked.Name != "confirm" || asked.Fingerprint != ssh.FingerprintSHA256(publicKey) {
+			t.Fatalf("confirmation asked for %+v", asked)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		client := serve(t, records, func(context.Context, sshagent.Key) bool { return false })
+		if _, err := client.Sign(publicKey, data); err == nil {
+			t.Fatal("Sign succeeded although the confirmation was refused")
+		}
+	})
+
+	t.Run("no confirmation", func(t *testing.T) {
+		client := serve(t, records, nil)
+		if _, err := client.Sign(publicKey, data); err == nil {
+			t.Fatal("Sign succeeded without a way to confirm")
+		}
+	})
+
+	t.Run("channel refused", func(t *testing.T) {
+		requests := make(chan *sshagent.ConfirmRequest)
+		go func() {
+			req := <-requests
+			req.Answer(false)
+		}()
+		client := serve(t, records, sshagent.ChannelConfirm(requests))
+		if _, err := client.Sign(publicKey, data); err == nil {
+			t.Fatal("Sign succeeded although the confirmation was refused")
+		}
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	key, _ := generate(t, sshagent.KeyTypeEd25519, "work", false)
+	client := serve(t, []*models.Record{newRecord(t, key, models.RecordStatusSynced)}, nil)
+	if err := client.RemoveAll(); err == nil {
+		t.Fatal("RemoveAll succeeded")
+	}
+	keys, err := client.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("List returned %d keys after RemoveAll, want 1", len(keys))
+	}
+}
@@ -0,0 +1,102 @@
+package sshagent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grnsv/GophKeeper/internal/client/models"
+	"golang.org/x/crypto/ssh"
+)
+
+// Types of generated keys.
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeRSA     = "rsa"
+)
+
+const rsaBits = 3072
+
+var ErrPassphraseMissing = errors.New("the key is encrypted, give its passphrase")
+
+// Generate returns a new key of the type.
+func Generate(keyType, comment string) (models.SSHKey, error) {
+	var key any
+	var err error
+	switch keyType {
+	case KeyTypeEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case KeyTypeRSA:
+		key, err = rsa.GenerateKey(rand.Reader, rsaBits)
+	default:
+		return models.SSHKey{}, fmt.Errorf("unknown key type %q, expected %s or %s", keyType, KeyTypeEd25519, KeyTypeRSA)
+	}
+	if err != nil {
+		return models.SSHKey{}, err
+	}
+	return newKey(key, comment)
+}
+
+// Import reads a private key in any format written by ssh-keygen. The
+// passphrase is only used if the key is encrypted, the stored key is not.
+func Import(pemBytes, passphrase []byte, comment string) (models.SSHKey, error) {
+	key, err := ssh.ParseRawPrivateKey(pemBytes)
+	var missingErr *ssh.PassphraseMissingError
+	if errors.As(err, &missingErr) {
+		if len(passphrase) == 0 {
+			return models.SSHKey{}, ErrPassphraseMissing
+		}
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+	}
+	if err != nil {
+		return models.SSHKey{}, fmt.Errorf("invalid private key: %w", err)
+	}
+	return newKey(key, comment)
+}
+
+// SetComment returns the key with the comment, which is also part of the
+// private key.
+func SetComment(key models.SSHKey, comment string) (models.SSHKey, error) {
+	res, err := Import([]byte(key.PrivateKey), nil, comment)
+	if err != nil {
+		return models.SSHKey{}, err
+	}
+	res.Confirm = key.Confirm
+	res.Metadata = key.Metadata
+	return res, nil
+}
+
+func newKey(key any, comment string) (models.SSHKey, error) {
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return models.SSHKey{}, err
+	}
+	block, err := ssh.MarshalPrivateKey(key, comment)
+	if err != nil {
+		return models.SSHKey{}, err
+	}
+	publicKey := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(signer.PublicKey())), "\n")
+	if comment != "" {
+		publicKey += " " + comment
+	}
+	return models.SSHKey{
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		PublicKey:  publicKey,
+		Comment:    comment,
+		Metadata:   make(models.Metadata),
+	}, nil
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key as printed by
+// ssh-keygen -l.
+func Fingerprint(key models.SSHKey) (string, error) {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	return ssh.FingerprintSHA256(publicKey), nil
+}
@@ -13,7 +13,8 @@ import (
 )
 
 type storage struct {
-	db *badger.DB
+	db   *badger.DB
+	path string
 }
 
 func New(userID string, encryptionKey []byte) (interfaces.Storage, error) {
@@ -28,7 +29,7 @@ func New(userID string, encryptionKey []byte) (interfaces.Storage, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &storage{db: db}, nil
+	return &storage{db: db, path: path}, nil
 }
 
 func getDBPath(userID string) (string, error) {
@@ -49,6 +50,14 @@ func (s *storage) Close() error {
 	return s.db.Close()
 }
 
+// Destroy closes the database and removes its files.
+func (s *storage) Destroy() error {
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.path)
+}
+
 func (s *storage) GetRecords() ([]*models.Record, error) {
 	var records []*models.Record
 	err := s.db.View(func(txn *badger.Txn) error {
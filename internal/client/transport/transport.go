@@ -0,0 +1,140 @@
+// Package transport builds the HTTP transport the client talks to the server with.
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+const pinPrefix = "sha256/"
+
+type Options struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system ones.
+	CAFile string
+	// Pins are SHA-256 hashes of SubjectPublicKeyInfo in the form sha256/BASE64.
+	// If set, one of the certificates of the verified chain must match one of them.
+	Pins []string
+	// CertFile and KeyFile are the client certificate for servers requiring mutual TLS.
+	CertFile string
+	KeyFile  string
+	// Proxy is the URL of the proxy to use instead of the one from the environment.
+	Proxy string
+}
+
+// PinMismatchError means the server presented a valid certificate whose
+// public key is not pinned. Either the server key was changed or the
+// connection is intercepted.
+type PinMismatchError struct {
+	Host string
+	// Pin is the pin of the public key the server presented.
+	Pin string
+}
+
+func (e *PinMismatchError) Error() string {
+	return fmt.Sprintf("public key of %s (%s) does not match any pinned key", e.Host, e.Pin)
+}
+
+func New(opts Options) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CAFile != "" {
+		pool, err := loadCAs(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca file: %w", err)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if len(opts.Pins) > 0 {
+		pins, err := parsePins(opts.Pins)
+		if err != nil {
+			return nil, err
+		}
+		cfg.VerifyConnection = verifyPins(pins)
+	}
+	t.TLSClientConfig = cfg
+
+	return t, nil
+}
+
+func loadCAs(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM certificates found in " + path)
+	}
+	return pool, nil
+}
+
+func parsePins(pins []string) ([][]byte, error) {
+	hashes := make([][]byte, 0, len(pins))
+	for _, pin := range pins {
+		encoded, ok := strings.CutPrefix(pin, pinPrefix)
+		if !ok {
+			return nil, fmt.Errorf("pin %q: must start with %q", pin, pinPrefix)
+		}
+		hash, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("pin %q: must be a base64 encoded SHA-256 hash", pin)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// Pin returns the pin of the public key of cert.
+func Pin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pinPrefix + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// verifyPins runs after the usual chain verification, so pinning a CA key
+// accepts any certificate that CA issues for the server.
+func verifyPins(pins [][]byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		for _, chain := range cs.VerifiedChains {
+			for _, cert := range chain {
+				hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				if slices.ContainsFunc(pins, func(pin []byte) bool { return bytes.Equal(pin, hash[:]) }) {
+					return nil
+				}
+			}
+		}
+		err := &PinMismatchError{Host: cs.ServerName}
+		if len(cs.PeerCertificates) > 0 {
+			err.Pin = Pin(cs.PeerCertificates[0])
+		}
+		return err
+	}
+}
@@ -0,0 +1,193 @@
+package transport_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/client/transport"
+)
+
+// server is a TLS server with a certificate issued by a CA of its own.
+type server struct {
+	*httptest.Server
+	ca, leaf *x509.Certificate
+	// caFile is the CA certificate as PEM.
+	caFile string
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	caKey := newKey(t)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey := newKey(t)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "server"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{leafDER}, PrivateKey: leafKey}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return &server{Server: srv, ca: ca, leaf: leaf, caFile: caFile}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+// get requests the server through a transport with the options.
+func (s *server) get(t *testing.T, opts transport.Options) error {
+	t.Helper()
+	tr, err := transport.New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(tr.CloseIdleConnections)
+	resp, err := (&http.Client{Transport: tr}).Get(s.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestPins(t *testing.T) {
+	s := newServer(t)
+	otherPin := func() string {
+		key := newKey(t)
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := sha256.Sum256(der)
+		return "sha256/" + base64.StdEncoding.EncodeToString(hash[:])
+	}()
+
+	tests := []struct {
+		name string
+		pins []string
+	}{
+		{name: "no pins"},
+		{name: "leaf pin", pins: []string{transport.Pin(s.leaf)}},
+		// A pinned CA key accepts any certificate the CA issues.
+		{name: "CA pin", pins: []string{otherPin, transport.Pin(s.ca)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.get(t, transport.Options{CAFile: s.caFile, Pins: tt.pins}); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+		})
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		err := s.get(t, transport.Options{CAFile: s.caFile, Pins: []string{otherPin}})
+		var mismatch *transport.PinMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("Get returned %v, want a %T", err, mismatch)
+		}
+		if mismatch.Pin != transport.Pin(s.leaf) {
+			t.Fatalf("the error names the pin %s, want the pin of the leaf %s", mismatch.Pin, transport.Pin(s.leaf))
+		}
+	})
+
+	// Pins are checked after the chain, a pinned key of an untrusted
+	// server is refused.
+	t.Run("untrusted", func(t *testing.T) {
+		err := s.get(t, transport.Options{Pins: []string{transport.Pin(s.leaf)}})
+		var unknown x509.UnknownAuthorityError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("Get returned %v, want an unknown authority", err)
+		}
+	})
+}
+
+func TestInvalidPins(t *testing.T) {
+	for _, pin := range []string{
+		"sha1/AAAA",
+		"sha256/not base64",
+		"sha256/" + base64.StdEncoding.EncodeToString([]byte("short")),
+	} {
+		if _, err := transport.New(transport.Options{Pins: []string{pin}}); err == nil {
+			t.Errorf("New accepted the pin %q", pin)
+		}
+	}
+}
+
+// TestPinsSelfSigned pins the self-signed certificate of httptest, which is
+// the whole verified chain.
+func TestPinsSelfSigned(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &server{Server: srv, leaf: srv.Certificate()}
+
+	if err := s.get(t, transport.Options{CAFile: caFile, Pins: []string{transport.Pin(s.leaf)}}); err != nil {
+		t.Fatalf("Get with the pin of the certificate: %v", err)
+	}
+	other := newServer(t)
+	err := s.get(t, transport.Options{CAFile: caFile, Pins: []string{transport.Pin(other.leaf)}})
+	var mismatch *transport.PinMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Pin != transport.Pin(s.leaf) {
+		t.Fatalf("Get returned %v, want a pin mismatch naming the pin of the certificate", err)
+	}
+}
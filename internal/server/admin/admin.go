@@ -0,0 +1,84 @@
+// Package admin implements the operator commands of the server,
+// working directly on interfaces.Storage.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+)
+
+const usage = `usage: goph-keeper-server admin [config flags] <command> [flags] [args]
+
+Commands:
+  users list [-search TEXT] [-json]   list users, optionally filtered by login substring
+  users show [-json] LOGIN            show a user with record count and size
+  users disable LOGIN                 forbid the user to log in and revoke their sessions
+  users enable LOGIN                  allow a disabled user to log in again
+  users revoke-sessions LOGIN         invalidate all tokens issued to the user so far
+  users delete -yes LOGIN             delete the user with all their records
+  invites create [-uses N] [-expires DURATION] [-json]
+                                      create a registration invite, -uses 0 allows unlimited registrations
+  invites list [-json]                list registration invites
+  invites delete ID                   delete a registration invite
+  stats [-json]                       print server-wide statistics`
+
+var ErrUsage = errors.New(usage)
+
+type Admin struct {
+	storage interfaces.Storage
+	out     io.Writer
+}
+
+func New(storage interfaces.Storage, out io.Writer) *Admin {
+	return &Admin{storage: storage, out: out}
+}
+
+func (a *Admin) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch args[0] {
+	case "users":
+		return a.runUsers(ctx, args[1:])
+	case "invites":
+		return a.runInvites(ctx, args[1:])
+	case "stats":
+		return a.stats(ctx, args[1:])
+	default:
+		return ErrUsage
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseLogin parses flags followed by exactly one login argument.
+func parseLogin(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", fmt.Errorf("%w\n\n%s", err, usage)
+	}
+	if fs.NArg() != 1 {
+		return "", ErrUsage
+	}
+	return fs.Arg(0), nil
+}
+
+func (a *Admin) writeJSON(v any) error {
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (a *Admin) newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+}
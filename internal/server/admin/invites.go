@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/models"
+	"github.com/grnsv/GophKeeper/internal/server/service"
+)
+
+type InviteInfo struct {
+	ID        string     `json:"id"`
+	Code      string     `json:"code,omitempty"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newInviteInfo(invite *models.Invite) InviteInfo {
+	info := InviteInfo{
+		ID:        invite.ID,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		CreatedAt: invite.CreatedAt,
+	}
+	if !invite.ExpiresAt.IsZero() {
+		info.ExpiresAt = &invite.ExpiresAt
+	}
+	return info
+}
+
+func (a *Admin) runInvites(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch args[0] {
+	case "create":
+		return a.createInvite(ctx, args[1:])
+	case "list":
+		return a.listInvites(ctx, args[1:])
+	case "delete":
+		return a.deleteInvite(ctx, args[1:])
+	default:
+		return ErrUsage
+	}
+}
+
+func (a *Admin) createInvite(ctx context.Context, args []string) error {
+	fs := newFlagSet("invites create")
+	uses := fs.Int("uses", 1, "number of registrations allowed, 0 for unlimited")
+	expires := fs.Duration("expires", 0, "lifetime of the invite, 0 for no expiry")
+	asJSON := fs.Bool("json", false, "JSON output")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 || *uses < 0 || *expires < 0 {
+		return ErrUsage
+	}
+
+	code := service.NewInviteCode()
+	invite := &models.Invite{
+		CodeHash: service.HashInviteCode(code),
+		MaxUses:  *uses,
+	}
+	if *expires > 0 {
+		invite.ExpiresAt = time.Now().Add(*expires)
+	}
+	if err := a.storage.CreateInvite(ctx, invite); err != nil {
+		return err
+	}
+
+	info := newInviteInfo(invite)
+	info.Code = code
+	if *asJSON {
+		return a.writeJSON(info)
+	}
+
+	fmt.Fprintf(a.out, "Invite code: %s\n", code)
+	fmt.Fprintln(a.out, "The code is not stored and cannot be shown again.")
+	return nil
+}
+
+func (a *Admin) listInvites(ctx context.Context, args []string) error {
+	fs := newFlagSet("invites list")
+	asJSON := fs.Bool("json", false, "JSON output")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	invites, err := a.storage.ListInvites(ctx)
+	if err != nil {
+		return err
+	}
+	infos := make([]InviteInfo, len(invites))
+	for i, invite := range invites {
+		infos[i] = newInviteInfo(invite)
+	}
+	if *asJSON {
+		return a.writeJSON(infos)
+	}
+
+	tw := a.newTable()
+	fmt.Fprintln(tw, "ID\tCREATED\tEXPIRES\tUSES")
+	for _, info := range infos {
+		expires := "never"
+		if info.ExpiresAt != nil {
+			expires = info.ExpiresAt.Local().Format(time.DateTime)
+		}
+		maxUses := "unlimited"
+		if info.MaxUses > 0 {
+			maxUses = fmt.Sprint(info.MaxUses)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%s\n", info.ID, info.CreatedAt.Format(time.DateTime), expires, info.Uses, maxUses)
+	}
+	return tw.Flush()
+}
+
+func (a *Admin) deleteInvite(ctx context.Context, args []string) error {
+	fs := newFlagSet("invites delete")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return ErrUsage
+	}
+	id := fs.Arg(0)
+	if err := a.storage.DeleteInvite(ctx, id); err != nil {
+		return fmt.Errorf("invite %s: %w", id, err)
+	}
+
+	fmt.Fprintf(a.out, "Invite %s is deleted\n", id)
+	return nil
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+)
+
+type ServerStats struct {
+	Users         int   `json:"users"`
+	DisabledUsers int   `json:"disabled_users"`
+	Records       int   `json:"records"`
+	Bytes         int64 `json:"bytes"`
+}
+
+func (a *Admin) stats(ctx context.Context, args []string) error {
+	fs := newFlagSet("stats")
+	asJSON := fs.Bool("json", false, "JSON output")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	users, err := a.storage.ListUsers(ctx, "")
+	if err != nil {
+		return err
+	}
+	recordStats, err := a.storage.GetRecordStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	stats := ServerStats{Users: len(users)}
+	for _, user := range users {
+		if user.Disabled {
+			stats.DisabledUsers++
+		}
+	}
+	for _, userStats := range recordStats {
+		stats.Records += userStats.Count
+		stats.Bytes += userStats.Bytes
+	}
+	if *asJSON {
+		return a.writeJSON(stats)
+	}
+
+	tw := a.newTable()
+	fmt.Fprintf(tw, "Users:\t%d\n", stats.Users)
+	fmt.Fprintf(tw, "Disabled users:\t%d\n", stats.DisabledUsers)
+	fmt.Fprintf(tw, "Records:\t%d\n", stats.Records)
+	fmt.Fprintf(tw, "Bytes:\t%d\n", stats.Bytes)
+	return tw.Flush()
+}
@@ -0,0 +1,203 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type UserInfo struct {
+	ID                string     `json:"id"`
+	Login             string     `json:"login"`
+	CreatedAt         time.Time  `json:"created_at"`
+	Disabled          bool       `json:"disabled"`
+	SessionsRevokedAt *time.Time `json:"sessions_revoked_at,omitempty"`
+	Records           int        `json:"records"`
+	Bytes             int64      `json:"bytes"`
+}
+
+func newUserInfo(user *models.User, stats models.RecordStats) UserInfo {
+	info := UserInfo{
+		ID:        user.ID,
+		Login:     user.Login,
+		CreatedAt: user.CreatedAt,
+		Disabled:  user.Disabled,
+		Records:   stats.Count,
+		Bytes:     stats.Bytes,
+	}
+	if !user.SessionsRevokedAt.IsZero() {
+		info.SessionsRevokedAt = &user.SessionsRevokedAt
+	}
+	return info
+}
+
+func (a *Admin) runUsers(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch args[0] {
+	case "list":
+		return a.listUsers(ctx, args[1:])
+	case "show":
+		return a.showUser(ctx, args[1:])
+	case "disable":
+		return a.setUserDisabled(ctx, args[1:], true)
+	case "enable":
+		return a.setUserDisabled(ctx, args[1:], false)
+	case "revoke-sessions":
+		return a.revokeSessions(ctx, args[1:])
+	case "delete":
+		return a.deleteUser(ctx, args[1:])
+	default:
+		return ErrUsage
+	}
+}
+
+func (a *Admin) listUsers(ctx context.Context, args []string) error {
+	fs := newFlagSet("users list")
+	search := fs.String("search", "", "login substring")
+	asJSON := fs.Bool("json", false, "JSON output")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	users, err := a.storage.ListUsers(ctx, *search)
+	if err != nil {
+		return err
+	}
+	stats, err := a.storage.GetRecordStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	infos := make([]UserInfo, len(users))
+	for i, user := range users {
+		infos[i] = newUserInfo(user, stats[user.ID])
+	}
+	if *asJSON {
+		return a.writeJSON(infos)
+	}
+
+	tw := a.newTable()
+	fmt.Fprintln(tw, "ID\tLOGIN\tCREATED\tSTATUS\tRECORDS\tBYTES")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n",
+			info.ID, info.Login, info.CreatedAt.Format(time.DateTime), status(info.Disabled), info.Records, info.Bytes)
+	}
+	return tw.Flush()
+}
+
+func (a *Admin) showUser(ctx context.Context, args []string) error {
+	fs := newFlagSet("users show")
+	asJSON := fs.Bool("json", false, "JSON output")
+	login, err := parseLogin(fs, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.findUser(ctx, login)
+	if err != nil {
+		return err
+	}
+	stats, err := a.storage.GetRecordStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	info := newUserInfo(user, stats[user.ID])
+	if *asJSON {
+		return a.writeJSON(info)
+	}
+
+	revoked := "never"
+	if info.SessionsRevokedAt != nil {
+		revoked = info.SessionsRevokedAt.Format(time.DateTime)
+	}
+	tw := a.newTable()
+	fmt.Fprintf(tw, "ID:\t%s\n", info.ID)
+	fmt.Fprintf(tw, "Login:\t%s\n", info.Login)
+	fmt.Fprintf(tw, "Created:\t%s\n", info.CreatedAt.Format(time.DateTime))
+	fmt.Fprintf(tw, "Status:\t%s\n", status(info.Disabled))
+	fmt.Fprintf(tw, "Sessions revoked:\t%s\n", revoked)
+	fmt.Fprintf(tw, "Records:\t%d\n", info.Records)
+	fmt.Fprintf(tw, "Bytes:\t%d\n", info.Bytes)
+	return tw.Flush()
+}
+
+func (a *Admin) setUserDisabled(ctx context.Context, args []string, disabled bool) error {
+	login, err := parseLogin(newFlagSet("users disable"), args)
+	if err != nil {
+		return err
+	}
+	user, err := a.findUser(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = a.storage.SetUserDisabled(ctx, user.ID, disabled); err != nil {
+		return err
+	}
+	if disabled {
+		if err = a.storage.RevokeUserSessions(ctx, user.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(a.out, "User %s is %s\n", login, status(disabled))
+	return nil
+}
+
+func (a *Admin) revokeSessions(ctx context.Context, args []string) error {
+	login, err := parseLogin(newFlagSet("users revoke-sessions"), args)
+	if err != nil {
+		return err
+	}
+	user, err := a.findUser(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = a.storage.RevokeUserSessions(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Sessions of user %s are revoked\n", login)
+	return nil
+}
+
+func (a *Admin) deleteUser(ctx context.Context, args []string) error {
+	fs := newFlagSet("users delete")
+	yes := fs.Bool("yes", false, "confirm deletion")
+	login, err := parseLogin(fs, args)
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		return fmt.Errorf("deleting user %s removes all their records irrecoverably, pass -yes to confirm", login)
+	}
+	user, err := a.findUser(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = a.storage.DeleteUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "User %s is deleted\n", login)
+	return nil
+}
+
+func (a *Admin) findUser(ctx context.Context, login string) (*models.User, error) {
+	user, err := a.storage.FindUserByLogin(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("user %s: %w", login, err)
+	}
+	return user, nil
+}
+
+func status(disabled bool) string {
+	if disabled {
+		return "disabled"
+	}
+	return "active"
+}
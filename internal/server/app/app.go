@@ -3,68 +3,182 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/grnsv/GophKeeper/internal/api"
 	"github.com/grnsv/GophKeeper/internal/server/config"
 	"github.com/grnsv/GophKeeper/internal/server/handlers"
+	"github.com/grnsv/GophKeeper/internal/server/health"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/jwtkeys"
+	"github.com/grnsv/GophKeeper/internal/server/logging"
+	"github.com/grnsv/GophKeeper/internal/server/metrics"
 	"github.com/grnsv/GophKeeper/internal/server/service"
 	"github.com/grnsv/GophKeeper/internal/server/storage"
+	"github.com/grnsv/GophKeeper/internal/server/tlsconfig"
+	"github.com/grnsv/GophKeeper/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type application struct {
-	Config     *config.Config
-	Storage    interfaces.Storage
+	Config  *config.Config
+	Logger  *slog.Logger
+	Storage interfaces.Storage
+	// JWTKeys is nil if tokens are signed with JWT_SECRET.
+	JWTKeys    *jwtkeys.KeySet
 	JWTService interfaces.JWTService
 	Service    interfaces.Service
+	Metrics    *metrics.Metrics
+	Tracing    *sdktrace.TracerProvider
 	Server     *http.Server
+	// TLS is nil if the server is not configured to serve HTTPS.
+	TLS *tlsconfig.Reloader
+	// AdminServer serves metrics and health checks, it is nil if ADMIN_ADDRESS is off.
+	AdminServer *http.Server
 }
 
-func New(ctx context.Context, buildVersion, buildDate string) (app *application, err error) {
-	app = &application{}
-	if app.Config, err = config.Parse(); err != nil {
+func New(ctx context.Context, cfg *config.Config, buildVersion, buildDate string) (app *application, err error) {
+	app = &application{Config: cfg}
+	if err = app.Config.CheckJWT(service.TokenLifetime); err != nil {
 		return nil, fmt.Errorf("config: %w", err)
 	}
-	if app.Storage, err = storage.New(ctx, app.Config.DatabaseDSN, app.Config.MigrationsPath); err != nil {
+	if app.Logger, err = logging.New(os.Stderr, app.Config.LogLevel, app.Config.LogFormat); err != nil {
+		return nil, fmt.Errorf("logging: %w", err)
+	}
+	slog.SetDefault(app.Logger)
+	if app.Tracing, err = telemetry.NewTracerProvider(ctx, app.Config.Tracing(), "gophkeeper-server", buildVersion); err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+	otel.SetTracerProvider(app.Tracing)
+	if app.Config.AutoMigrate {
+		if err = storage.Migrate(app.Config.DatabaseDSN); err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+	}
+	if app.Storage, err = storage.New(ctx, app.Config.DatabaseDSN); err != nil {
 		return nil, fmt.Errorf("storage: %w", err)
 	}
-	app.JWTService = service.NewJWTService(app.Config.JWTSecret)
-	if app.Service, err = service.New(app.Storage, app.JWTService, buildVersion, buildDate); err != nil {
+	app.Storage = storage.WithTracing(app.Storage, app.Config.DatabaseDSN, app.Tracing)
+	if app.Metrics, err = metrics.New(); err != nil {
+		return nil, fmt.Errorf("metrics: %w", err)
+	}
+	if app.Config.JWTKeysDir != "" {
+		if app.JWTKeys, err = jwtkeys.Open(app.Config.JWTKeys()); err != nil {
+			return nil, fmt.Errorf("jwt keys: %w", err)
+		}
+		go app.JWTKeys.Run(ctx, time.Minute)
+		app.JWTService = service.NewKeySetJWTService(app.JWTKeys)
+	} else {
+		app.JWTService = service.NewJWTService(app.Config.JWTSecret)
+	}
+	if app.Service, err = service.New(app.Storage, app.JWTService, app.Config.RegistrationMode, buildVersion, buildDate); err != nil {
 		return nil, fmt.Errorf("service: %w", err)
 	}
+	app.Service = metrics.WrapService(app.Service, app.Metrics)
 	server, err := api.NewServer(
 		handlers.NewHandler(app.Service),
-		handlers.NewSecurityHandler(app.JWTService),
+		handlers.NewSecurityHandler(app.Service),
 		api.WithErrorHandler(handlers.ErrorHandler),
+		api.WithMeterProvider(app.Metrics.MeterProvider()),
+		api.WithTracerProvider(app.Tracing),
+		api.WithMiddleware(handlers.Middleware),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("server: %w", err)
 	}
 	app.Server = &http.Server{
 		Addr:         app.Config.RunAddress,
-		Handler:      server,
+		Handler:      telemetry.Handler(handlers.AccessLog(app.Logger, server, server)),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
+	if app.Config.TLSCertFile != "" {
+		app.TLS, err = tlsconfig.NewReloader(app.Config.TLSCertFile, app.Config.TLSKeyFile, app.Config.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		app.Server.TLSConfig = app.TLS.TLSConfig()
+	}
+	if app.Config.AdminAddress != config.AdminAddressOff {
+		app.AdminServer = app.newAdminServer()
+	}
 
 	return
 }
 
+func (app *application) newAdminServer() *http.Server {
+	health := health.NewHandler(app.Storage, app.Config.DatabaseDSN)
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", app.Metrics.Handler())
+	mux.HandleFunc("GET /healthz", health.Liveness)
+	mux.HandleFunc("GET /readyz", health.Readiness)
+
+	return &http.Server{
+		Addr:         app.Config.AdminAddress,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  15 * time.Second,
+	}
+}
+
 func (app *application) Run() {
-	log.Printf("Starting server at %s", app.Server.Addr)
-	if err := app.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed: %v", err)
+	if app.AdminServer != nil {
+		go func() {
+			app.Logger.Info("Starting admin server", "address", app.AdminServer.Addr)
+			if err := app.AdminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				app.Logger.Error("Admin server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	var err error
+	if app.TLS != nil {
+		app.Logger.Info("Starting server", "address", app.Server.Addr, "tls", true, "mtls", app.TLS.MutualTLS())
+		err = app.Server.ListenAndServeTLS("", "")
+	} else {
+		app.Logger.Info("Starting server", "address", app.Server.Addr)
+		err = app.Server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		app.Logger.Error("Server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// ReloadTLS reads the TLS certificate, key and client CA files again.
+func (app *application) ReloadTLS() {
+	if app.TLS == nil {
+		return
+	}
+	if err := app.TLS.Reload(); err != nil {
+		app.Logger.Error("TLS reload failed, keeping the previous certificates", "error", err)
+		return
 	}
+	app.Logger.Info("TLS certificates reloaded")
 }
 
 func (app *application) Shutdown(ctx context.Context) error {
 	if err := app.Server.Shutdown(ctx); err != nil {
 		return fmt.Errorf("shutdown server: %w", err)
 	}
+	if app.AdminServer != nil {
+		if err := app.AdminServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("shutdown admin server: %w", err)
+		}
+	}
+	if err := app.Metrics.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown metrics: %w", err)
+	}
+	if err := app.Tracing.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown tracing: %w", err)
+	}
 	if err := app.Storage.Close(); err != nil {
 		return fmt.Errorf("close storage: %w", err)
 	}
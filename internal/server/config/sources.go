@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// secretTag marks options that are redacted when printed and may be read
+	// from the file named by NAME_FILE. Its value is secretFull or secretURL.
+	secretTag  = "secret"
+	secretFull = "true"
+	// secretURL redacts only the password of a URL such as a DSN. Values
+	// without user info, like keyword DSNs (host=... password=...), are
+	// redacted whole.
+	secretURL = "url"
+
+	redacted = "[REDACTED]"
+)
+
+// option is a Config field settable from every source: the environment
+// variable JWT_SECRET is jwt_secret in the config file and --jwt-secret on
+// the command line.
+type option struct {
+	index  int
+	env    string
+	key    string
+	flag   string
+	bool   bool
+	secret string
+}
+
+func options() []option {
+	t := reflect.TypeFor[Config]()
+	var opts []option
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if name == "" {
+			continue
+		}
+		opts = append(opts, option{
+			index:  i,
+			env:    name,
+			key:    strings.ToLower(name),
+			flag:   strings.ReplaceAll(strings.ToLower(name), "_", "-"),
+			bool:   field.Type.Kind() == reflect.Bool,
+			secret: field.Tag.Get(secretTag),
+		})
+	}
+	return opts
+}
+
+// Parse reads the configuration from, in increasing order of precedence,
+// the defaults, the config file, environment variables and command line
+// flags. The config file is given by --config or CONFIG_FILE.
+func Parse(args []string) (*Config, error) {
+	cfg, rest, err := ParseCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", rest[0])
+	}
+	return cfg, nil
+}
+
+// ParseCommand is Parse for subcommands: the flags end at the first
+// argument that is not a flag, it returns the arguments from there on.
+func ParseCommand(args []string) (*Config, []string, error) {
+	opts := options()
+
+	flags := flag.NewFlagSet("goph-keeper-server", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "TOML or YAML config `file`")
+	printConfig := flags.Bool("print-config", false, "print the configuration with secrets redacted and exit")
+	fromFlags := make(map[string]string)
+	for _, opt := range opts {
+		set := func(value string) error {
+			fromFlags[opt.env] = value
+			return nil
+		}
+		if opt.bool {
+			flags.BoolFunc(opt.flag, "overrides "+opt.env, set)
+		} else {
+			flags.Func(opt.flag, "overrides "+opt.env, set)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[string]string)
+	if *configFile != "" {
+		fromFile, err := readFile(*configFile, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		merge(values, fromFile)
+	}
+	fromEnv, err := readEnv(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	merge(values, fromEnv)
+	merge(values, fromFlags)
+
+	cfg := &Config{ConfigFile: *configFile, PrintConfig: *printConfig}
+	if err := env.ParseWithOptions(cfg, env.Options{Environment: values}); err != nil {
+		return nil, nil, describeParseError(err, opts)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+func merge(dst, src map[string]string) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+// readFile reads a config file. Its keys are the environment variable names
+// in lower case, unknown keys are rejected to catch typos.
+func readFile(path string, opts []option) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+	raw := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("config file %s: unknown format, use .toml, .yaml or .yml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	keys := make(map[string]option, len(opts))
+	for _, opt := range opts {
+		keys[opt.key] = opt
+	}
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		opt, ok := keys[key]
+		if !ok {
+			return nil, fmt.Errorf("config file %s: unknown option %q", path, key)
+		}
+		switch value.(type) {
+		case string, bool, int, int64, uint64, float64:
+			values[opt.env] = fmt.Sprint(value)
+		default:
+			return nil, fmt.Errorf("config file %s: option %q must be a string, number or boolean", path, key)
+		}
+	}
+	return values, nil
+}
+
+// readEnv reads the environment variables of the options. A secret may
+// instead be read from the file named by NAME_FILE, as Docker and
+// Kubernetes secrets are mounted.
+func readEnv(opts []option) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, opt := range opts {
+		value, ok := os.LookupEnv(opt.env)
+		if ok {
+			values[opt.env] = value
+		}
+		if opt.secret == "" {
+			continue
+		}
+		path, ok := os.LookupEnv(opt.env + "_FILE")
+		if !ok {
+			continue
+		}
+		if _, set := values[opt.env]; set {
+			return nil, fmt.Errorf("%s and %s_FILE must not be set together", opt.env, opt.env)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s_FILE: %w", opt.env, err)
+		}
+		values[opt.env] = strings.TrimRight(string(data), "\r\n")
+	}
+	return values, nil
+}
+
+// describeParseError names the option instead of the Go field in errors of
+// values that cannot be parsed.
+func describeParseError(err error, opts []option) error {
+	var aggregate env.AggregateError
+	if !errors.As(err, &aggregate) {
+		return err
+	}
+	fields := make(map[string]option, len(opts))
+	t := reflect.TypeFor[Config]()
+	for _, opt := range opts {
+		fields[t.Field(opt.index).Name] = opt
+	}
+	errs := make([]error, 0, len(aggregate.Errors))
+	for _, err := range aggregate.Errors {
+		var parseErr env.ParseError
+		if errors.As(err, &parseErr) {
+			if opt, ok := fields[parseErr.Name]; ok {
+				err = fmt.Errorf("%s: %w", opt.env, parseErr.Err)
+			}
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Print writes the configuration as TOML that can be used as a config file.
+// Secrets are redacted.
+func (cfg *Config) Print(w io.Writer) error {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, opt := range options() {
+		field := v.Field(opt.index)
+		var value string
+		switch {
+		case opt.bool:
+			value = strconv.FormatBool(field.Bool())
+		default:
+			value = strconv.Quote(redact(fmt.Sprint(field.Interface()), opt.secret))
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s\n", opt.key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func redact(value, secret string) string {
+	switch {
+	case value == "" || secret == "":
+		return value
+	case secret == secretURL:
+		if u, err := url.Parse(value); err == nil && u.User != nil {
+			return u.Redacted()
+		}
+	}
+	return redacted
+}
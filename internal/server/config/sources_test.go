@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// clearEnv unsets the variables of every option for the test, so that the
+// environment of the test run does not leak in.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	names := []string{"CONFIG_FILE"}
+	for _, opt := range options() {
+		names = append(names, opt.env, opt.env+"_FILE")
+	}
+	for _, name := range names {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPrecedence(t *testing.T) {
+	files := map[string]string{
+		"config.toml": "run_address = \":1\"\nadmin_address = \":2\"\nlog_format = \"text\"\nlog_level = \"debug\"\nauto_migrate = false\n",
+		"config.yaml": "run_address: \":1\"\nadmin_address: \":2\"\nlog_format: text\nlog_level: debug\nauto_migrate: false\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("CONFIG_FILE", writeFile(t, name, content))
+			t.Setenv("RUN_ADDRESS", ":3")
+			t.Setenv("LOG_FORMAT", "json")
+
+			cfg, rest, err := ParseCommand([]string{"--run-address", ":4", "migrate", "--run-address", ":5"})
+			if err != nil {
+				t.Fatalf("ParseCommand: %v", err)
+			}
+			if !slices.Equal(rest, []string{"migrate", "--run-address", ":5"}) {
+				t.Fatalf("rest %q, want the arguments from the subcommand on", rest)
+			}
+			// The flag wins over the environment, which wins over the file,
+			// which wins over the defaults.
+			if cfg.RunAddress != ":4" {
+				t.Errorf("RUN_ADDRESS %q, want the flag", cfg.RunAddress)
+			}
+			if cfg.LogFormat != "json" {
+				t.Errorf("LOG_FORMAT %q, want the environment", cfg.LogFormat)
+			}
+			if cfg.AdminAddress != ":2" || cfg.LogLevel != slog.LevelDebug || cfg.AutoMigrate {
+				t.Errorf("ADMIN_ADDRESS %q, LOG_LEVEL %v and AUTO_MIGRATE %v, want the file", cfg.AdminAddress, cfg.LogLevel, cfg.AutoMigrate)
+			}
+			if cfg.TracesExporter != "none" {
+				t.Errorf("TRACES_EXPORTER %q, want the default", cfg.TracesExporter)
+			}
+		})
+	}
+
+	t.Run("--config over CONFIG_FILE", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, "env.toml", "run_address = \":1\"\n"))
+		path := writeFile(t, "flag.toml", "run_address = \":2\"\n")
+		cfg, err := Parse([]string{"--config", path})
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		if cfg.RunAddress != ":2" || cfg.ConfigFile != path {
+			t.Fatalf("RUN_ADDRESS %q from %s, want the file of --config", cfg.RunAddress, cfg.ConfigFile)
+		}
+	})
+}
+
+func TestConfigFileRejects(t *testing.T) {
+	tests := map[string]struct {
+		name, content, err string
+	}{
+		"unknown key":       {"config.toml", "jwt_secrets = \"x\"\n", `unknown option "jwt_secrets"`},
+		"config key":        {"config.yaml", "config: other.yaml\n", `unknown option "config"`},
+		"table":             {"config.toml", "[run_address]\nport = 1\n", "must be a string, number or boolean"},
+		"unknown extension": {"config.json", "{}", "unknown format"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			_, err := Parse([]string{"--config", writeFile(t, tt.name, tt.content)})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("Parse returned %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSecretFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt_secret", "from file\n"))
+	cfg, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cfg.JWTSecret != "from file" {
+		t.Fatalf("JWT_SECRET %q, want the file without the trailing newline", cfg.JWTSecret)
+	}
+
+	t.Setenv("JWT_SECRET", "from env")
+	if _, err = Parse(nil); err == nil || !strings.Contains(err.Error(), "must not be set together") {
+		t.Fatalf("Parse with JWT_SECRET and JWT_SECRET_FILE returned %v", err)
+	}
+
+	// Only secrets are read from files.
+	clearEnv(t)
+	t.Setenv("RUN_ADDRESS_FILE", writeFile(t, "run_address", ":1"))
+	if cfg, err = Parse(nil); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cfg.RunAddress != ":8080" {
+		t.Fatalf("RUN_ADDRESS %q read from RUN_ADDRESS_FILE", cfg.RunAddress)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	tests := map[string]struct {
+		dsn, want string
+	}{
+		"URL":                  {"postgres://user:hunter2@db:5432/keeper", `database_dsn = "postgres://user:xxxxx@db:5432/keeper"`},
+		"URL without password": {"postgres://db:5432/keeper", `database_dsn = "[REDACTED]"`},
+		"password parameter":   {"postgres://db/keeper?password=hunter2", `database_dsn = "[REDACTED]"`},
+		"keyword DSN":          {"host=db user=user password=hunter2 dbname=keeper", `database_dsn = "[REDACTED]"`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			cfg, err := Parse([]string{"--print-config", "--database-dsn", tt.dsn, "--jwt-secret", "hunter2"})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !cfg.PrintConfig {
+				t.Fatal("PrintConfig is not set")
+			}
+			var buf bytes.Buffer
+			if err = cfg.Print(&buf); err != nil {
+				t.Fatalf("Print: %v", err)
+			}
+			out := buf.String()
+			if strings.Contains(out, "hunter2") {
+				t.Fatalf("the printed configuration holds a secret:\n%s", out)
+			}
+			for _, line := range []string{tt.want, `jwt_secret = "[REDACTED]"`, `run_address = ":8080"`, "auto_migrate = true"} {
+				if !strings.Contains(out, line+"\n") {
+					t.Errorf("the printed configuration lacks %s:\n%s", line, out)
+				}
+			}
+
+			// The output is a config file.
+			clearEnv(t)
+			printed, err := Parse([]string{"--config", writeFile(t, "printed.toml", out)})
+			if err != nil {
+				t.Fatalf("Parse of the printed configuration: %v", err)
+			}
+			if printed.JWTKeyRotation != cfg.JWTKeyRotation || printed.LogLevel != cfg.LogLevel || printed.RegistrationMode != cfg.RegistrationMode {
+				t.Fatalf("the printed configuration reads back as %+v", printed)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/logging"
+	"github.com/ogen-go/ogen/middleware"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+type RouteFinder interface {
+	FindPath(method string, u *url.URL) (api.Route, bool)
+}
+
+// AccessLog gives every request an ID, taken from a well-formed X-Request-ID
+// header or generated, returns it in the response and logs the request
+// once it has been served.
+func AccessLog(logger *slog.Logger, routes RouteFinder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		req := &logging.Request{
+			ID:        r.Header.Get(RequestIDHeader),
+			RemoteIP:  remoteIP(r),
+			UserAgent: r.UserAgent(),
+		}
+		if !validRequestID(req.ID) {
+			req.ID = uuid.NewString()
+		}
+		if route, ok := routes.FindPath(r.Method, r.URL); ok {
+			req.Operation = route.Name()
+		}
+		w.Header().Set(RequestIDHeader, req.ID)
+
+		ctx := logging.WithRequest(r.Context(), req)
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		logger.LogAttrs(ctx, slog.LevelInfo, "Request served",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("operation", req.Operation),
+			slog.String("user_id", req.UserID),
+			slog.String("remote_ip", req.RemoteIP),
+			slog.Int("status", recorder.Status()),
+			slog.Float64("latency_ms", float64(time.Since(start))/float64(time.Millisecond)),
+		)
+	})
+}
+
+// Middleware records the operation and the authenticated user of a request for AccessLog.
+func Middleware(req middleware.Request, next middleware.Next) (middleware.Response, error) {
+	if info, ok := logging.RequestFromContext(req.Context); ok {
+		info.Operation = req.OperationName
+		if userID, err := getUserID(req.Context); err == nil {
+			info.UserID = userID
+		}
+	}
+	return next(req)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+)
+
+type AccountHandler struct {
+	service interfaces.Service
+}
+
+func NewAccountHandler(s interfaces.Service) *AccountHandler {
+	return &AccountHandler{service: s}
+}
+
+func (h *AccountHandler) AccountDelete(ctx context.Context, req *api.PasswordConfirmation) (api.AccountDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.DeleteAccount(ctx, userID, req.Password); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrUnauthorized):
+			return &api.Unauthorized{}, nil
+		case errors.Is(err, interfaces.ErrLastOwner):
+			return &api.AccountDeleteConflict{}, nil
+		}
+		return nil, err
+	}
+	return &api.AccountDeleteNoContent{}, nil
+}
+
+func (h *AccountHandler) AccountAuditGet(ctx context.Context, params api.AccountAuditGetParams) (api.AccountAuditGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := h.service.GetAuditEvents(ctx, userID, params.Limit.Or(0))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.AccountAuditGetOKApplicationJSON, 0, len(events))
+	for _, event := range events {
+		res = append(res, api.AuditEvent{
+			ID:        event.ID,
+			Type:      api.AuditEventType(event.Type),
+			Details:   optString(event.Details),
+			IP:        optString(event.IP),
+			UserAgent: optString(event.UserAgent),
+			RequestID: optString(event.RequestID),
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return &res, nil
+}
+
+func optString(s string) api.OptString {
+	if s == "" {
+		return api.OptString{}
+	}
+	return api.NewOptString(s)
+}
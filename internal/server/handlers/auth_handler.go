@@ -17,10 +17,15 @@ func NewAuthHandler(s interfaces.Service) *AuthHandler {
 }
 
 func (h *AuthHandler) RegisterPost(ctx context.Context, req *api.UserCredentials) (api.RegisterPostRes, error) {
-	token, err := h.service.Register(ctx, req.Login, req.Password)
+	token, err := h.service.Register(ctx, req.Login, req.Password, req.InviteCode.Or(""))
 	if err != nil {
-		if errors.Is(err, interfaces.ErrLoginTaken) {
+		switch {
+		case errors.Is(err, interfaces.ErrLoginTaken):
 			return &api.RegisterPostConflict{}, nil
+		case errors.Is(err, interfaces.ErrRegistrationClosed):
+			return &api.RegisterPostForbidden{}, nil
+		case errors.Is(err, interfaces.ErrInvalidInvite):
+			return &api.RegisterPostUnprocessableEntity{}, nil
 		}
 		return nil, err
 	}
@@ -33,6 +38,9 @@ func (h *AuthHandler) LoginPost(ctx context.Context, req *api.UserCredentials) (
 		if errors.Is(err, interfaces.ErrUnauthorized) {
 			return &api.Unauthorized{}, nil
 		}
+		if errors.Is(err, interfaces.ErrAccountDisabled) {
+			return &api.LoginPostForbidden{}, nil
+		}
 		return nil, err
 	}
 	return &api.AuthToken{Token: token}, nil
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type EmergencyHandler struct {
+	service interfaces.Service
+}
+
+func NewEmergencyHandler(s interfaces.Service) *EmergencyHandler {
+	return &EmergencyHandler{service: s}
+}
+
+func (h *EmergencyHandler) EmergencyContactsGet(ctx context.Context) (api.EmergencyContactsGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := h.service.ListEmergencyContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.EmergencyContactsGetOKApplicationJSON, 0, len(contacts))
+	for _, access := range contacts {
+		res = append(res, *convertEmergencyAccess(access, access.GranteeLogin))
+	}
+	return &res, nil
+}
+
+func (h *EmergencyHandler) EmergencyContactsPost(ctx context.Context, req *api.EmergencyContactRequest) (api.EmergencyContactsPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	access := &models.EmergencyAccess{GrantorID: userID, WaitDays: req.WaitDays, Key: req.Key}
+	if err = h.service.AddEmergencyContact(ctx, access, req.Login); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrContactExists):
+			return &api.EmergencyContactsPostConflict{}, nil
+		case errors.Is(err, interfaces.ErrInvalidEmergency):
+			return &api.EmergencyContactsPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return convertEmergencyAccess(access, access.GranteeLogin), nil
+}
+
+func (h *EmergencyHandler) EmergencyContactsIDDelete(ctx context.Context, params api.EmergencyContactsIDDeleteParams) (api.EmergencyContactsIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RemoveEmergencyContact(ctx, userID, params.ID); err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.EmergencyContactsIDDeleteNotFound{}, nil
+		}
+		return nil, err
+	}
+	return &api.EmergencyContactsIDDeleteNoContent{}, nil
+}
+
+func (h *EmergencyHandler) EmergencyContactsIDApprovePost(ctx context.Context, params api.EmergencyContactsIDApprovePostParams) (api.EmergencyContactsIDApprovePostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.ApproveEmergencyAccess(ctx, userID, params.ID); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.EmergencyContactsIDApprovePostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrEmergencyState):
+			return &api.EmergencyContactsIDApprovePostConflict{}, nil
+		}
+		return nil, err
+	}
+	return &api.EmergencyContactsIDApprovePostNoContent{}, nil
+}
+
+func (h *EmergencyHandler) EmergencyContactsIDRejectPost(ctx context.Context, params api.EmergencyContactsIDRejectPostParams) (api.EmergencyContactsIDRejectPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RejectEmergencyAccess(ctx, userID, params.ID); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.EmergencyContactsIDRejectPostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrEmergencyState):
+			return &api.EmergencyContactsIDRejectPostConflict{}, nil
+		}
+		return nil, err
+	}
+	return &api.EmergencyContactsIDRejectPostNoContent{}, nil
+}
+
+func (h *EmergencyHandler) EmergencyGrantsGet(ctx context.Context) (api.EmergencyGrantsGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := h.service.ListEmergencyGrants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.EmergencyGrantsGetOKApplicationJSON, 0, len(grants))
+	for _, access := range grants {
+		res = append(res, *convertEmergencyAccess(access, access.GrantorLogin))
+	}
+	return &res, nil
+}
+
+func (h *EmergencyHandler) EmergencyGrantsIDRequestPost(ctx context.Context, params api.EmergencyGrantsIDRequestPostParams) (api.EmergencyGrantsIDRequestPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RequestEmergencyAccess(ctx, userID, params.ID); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.EmergencyGrantsIDRequestPostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrEmergencyState):
+			return &api.EmergencyGrantsIDRequestPostConflict{}, nil
+		}
+		return nil, err
+	}
+	return &api.EmergencyGrantsIDRequestPostNoContent{}, nil
+}
+
+func (h *EmergencyHandler) EmergencyGrantsIDVaultGet(ctx context.Context, params api.EmergencyGrantsIDVaultGetParams) (api.EmergencyGrantsIDVaultGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	access, records, err := h.service.GetEmergencyVault(ctx, userID, params.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.EmergencyGrantsIDVaultGetNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.EmergencyGrantsIDVaultGetForbidden{}, nil
+		}
+		return nil, err
+	}
+
+	res := &api.EmergencyVault{
+		Login:   access.GrantorLogin,
+		Key:     access.Key,
+		Records: make([]api.RecordWithId, 0, len(records)),
+	}
+	for _, rec := range records {
+		res.Records = append(res.Records, api.RecordWithId{
+			ID:      rec.ID,
+			Type:    api.RecordType(rec.Type),
+			Data:    rec.Data,
+			Nonce:   rec.Nonce,
+			Version: rec.Version,
+			Key:     rec.Key,
+		})
+	}
+	return res, nil
+}
+
+// convertEmergencyAccess converts the access as seen by one side, login is
+// the user on the other side.
+func convertEmergencyAccess(access *models.EmergencyAccess, login string) *api.EmergencyAccess {
+	res := &api.EmergencyAccess{
+		ID:        access.ID,
+		Login:     login,
+		WaitDays:  access.WaitDays,
+		Status:    api.EmergencyStatus(access.Status),
+		CreatedAt: access.CreatedAt,
+	}
+	if !access.RequestedAt.IsZero() {
+		res.RequestedAt = api.NewOptDateTime(access.RequestedAt)
+		res.AvailableAt = api.NewOptDateTime(access.AvailableAt())
+	}
+	return res
+}
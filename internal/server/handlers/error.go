@@ -2,15 +2,20 @@ package handlers
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/go-faster/jx"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
 	"github.com/ogen-go/ogen/ogenerrors"
 )
 
 func ErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
 	code := ogenerrors.ErrorCode(err)
+	if errors.Is(err, interfaces.ErrForbidden) {
+		code = http.StatusForbidden
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 
@@ -18,7 +23,7 @@ func ErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, e
 	e.ObjStart()
 	e.FieldStart("error_message")
 	if code == http.StatusInternalServerError {
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal server error", "error", err)
 		e.StrEscape("Internal Server Error")
 	} else {
 		e.StrEscape(err.Error())
@@ -12,15 +12,27 @@ var ErrUserIDNotFound = errors.New("user ID not found in context")
 
 type Handler struct {
 	*AuthHandler
+	*AccountHandler
 	*RecordHandler
+	*TokenHandler
+	*ShareHandler
+	*OrgHandler
+	*EmergencyHandler
+	*SendHandler
 	*InfoHandler
 }
 
 func NewHandler(s interfaces.Service) api.Invoker {
 	return &Handler{
-		AuthHandler:   NewAuthHandler(s),
-		RecordHandler: NewRecordHandler(s),
-		InfoHandler:   NewInfoHandler(s),
+		AuthHandler:      NewAuthHandler(s),
+		AccountHandler:   NewAccountHandler(s),
+		RecordHandler:    NewRecordHandler(s),
+		TokenHandler:     NewTokenHandler(s),
+		ShareHandler:     NewShareHandler(s),
+		OrgHandler:       NewOrgHandler(s),
+		EmergencyHandler: NewEmergencyHandler(s),
+		SendHandler:      NewSendHandler(s),
+		InfoHandler:      NewInfoHandler(s),
 	}
 }
 
@@ -27,3 +27,28 @@ func (h *InfoHandler) VersionGet(ctx context.Context) (*api.VersionInfo, error)
 
 	return res, nil
 }
+
+func (h *InfoHandler) RegistrationGet(ctx context.Context) (*api.RegistrationInfo, error) {
+	return &api.RegistrationInfo{Mode: api.RegistrationMode(h.service.GetRegistrationMode(ctx))}, nil
+}
+
+func (h *InfoHandler) WellKnownJwksJSONGet(ctx context.Context) (*api.JWKSet, error) {
+	keys := h.service.GetJWKS(ctx)
+	res := &api.JWKSet{Keys: make([]api.JWK, 0, len(keys))}
+	for _, key := range keys {
+		jwk := api.JWK{
+			Kty: api.JWKKty(key.KeyType),
+			Kid: key.KeyID,
+			Alg: api.JWKAlg(key.Algorithm),
+			Use: api.JWKUseSig,
+			Crv: api.JWKCrv(key.Curve),
+			X:   key.X,
+		}
+		if key.Y != "" {
+			jwk.Y = api.NewOptString(key.Y)
+		}
+		res.Keys = append(res.Keys, jwk)
+	}
+
+	return res, nil
+}
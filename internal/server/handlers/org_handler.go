@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type OrgHandler struct {
+	service interfaces.Service
+}
+
+func NewOrgHandler(s interfaces.Service) *OrgHandler {
+	return &OrgHandler{service: s}
+}
+
+func (h *OrgHandler) OrgsGet(ctx context.Context) (api.OrgsGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orgs, err := h.service.ListOrgs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.OrgsGetOKApplicationJSON, 0, len(orgs))
+	for _, org := range orgs {
+		res = append(res, *convertOrg(org))
+	}
+	return &res, nil
+}
+
+func (h *OrgHandler) OrgsPost(ctx context.Context, req *api.OrganizationRequest) (api.OrgsPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	org := &models.Organization{Name: req.Name, Key: req.Key}
+	if err = h.service.CreateOrg(ctx, userID, org); err != nil {
+		if errors.Is(err, interfaces.ErrInvalidOrg) {
+			return &api.OrgsPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return convertOrg(org), nil
+}
+
+func (h *OrgHandler) OrgsOrgIDDelete(ctx context.Context, params api.OrgsOrgIDDeleteParams) (api.OrgsOrgIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.DeleteOrg(ctx, userID, params.OrgID); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDDeleteNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDDeleteForbidden{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDDeleteNoContent{}, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDMembersGet(ctx context.Context, params api.OrgsOrgIDMembersGetParams) (api.OrgsOrgIDMembersGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := h.service.ListOrgMembers(ctx, userID, params.OrgID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.OrgsOrgIDMembersGetNotFound{}, nil
+		}
+		return nil, err
+	}
+
+	res := make(api.OrgsOrgIDMembersGetOKApplicationJSON, 0, len(members))
+	for _, member := range members {
+		res = append(res, *convertOrgMember(member))
+	}
+	return &res, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDMembersPost(ctx context.Context, req *api.OrgInvite, params api.OrgsOrgIDMembersPostParams) (api.OrgsOrgIDMembersPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	member := &models.OrgMember{OrgID: params.OrgID, Role: models.OrgRole(req.Role), Key: req.Key}
+	if err = h.service.InviteOrgMember(ctx, userID, member, req.Login, req.KeyVersion); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDMembersPostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDMembersPostForbidden{}, nil
+		case errors.Is(err, interfaces.ErrMemberExists), errors.Is(err, interfaces.ErrVersionConflict):
+			return &api.OrgsOrgIDMembersPostConflict{}, nil
+		case errors.Is(err, interfaces.ErrInvalidOrg):
+			return &api.OrgsOrgIDMembersPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return convertOrgMember(member), nil
+}
+
+func (h *OrgHandler) OrgsOrgIDMembersUserIDPut(ctx context.Context, req *api.OrgRoleRequest, params api.OrgsOrgIDMembersUserIDPutParams) (api.OrgsOrgIDMembersUserIDPutRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.SetOrgMemberRole(ctx, userID, params.OrgID, params.UserID.String(), models.OrgRole(req.Role)); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDMembersUserIDPutNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDMembersUserIDPutForbidden{}, nil
+		case errors.Is(err, interfaces.ErrInvalidOrg):
+			return &api.OrgsOrgIDMembersUserIDPutUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDMembersUserIDPutNoContent{}, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDMembersUserIDDelete(ctx context.Context, params api.OrgsOrgIDMembersUserIDDeleteParams) (api.OrgsOrgIDMembersUserIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RemoveOrgMember(ctx, userID, params.OrgID, params.UserID.String()); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDMembersUserIDDeleteNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDMembersUserIDDeleteForbidden{}, nil
+		case errors.Is(err, interfaces.ErrInvalidOrg):
+			return &api.OrgsOrgIDMembersUserIDDeleteUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDMembersUserIDDeleteNoContent{}, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDRekeyPost(ctx context.Context, req *api.OrgRekey, params api.OrgsOrgIDRekeyPostParams) (api.OrgsOrgIDRekeyPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string][]byte, len(req.Keys))
+	for _, key := range req.Keys {
+		keys[key.UserID.String()] = key.Key
+	}
+	records := make([]*models.OrgRecord, 0, len(req.Records))
+	for _, rec := range req.Records {
+		records = append(records, &models.OrgRecord{
+			ID:      rec.ID,
+			Type:    string(rec.Type),
+			Data:    rec.Data,
+			Nonce:   rec.Nonce,
+			Version: rec.Version,
+		})
+	}
+	if err = h.service.RekeyOrg(ctx, userID, params.OrgID, req.KeyVersion, keys, records); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDRekeyPostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDRekeyPostForbidden{}, nil
+		case errors.Is(err, interfaces.ErrVersionConflict):
+			return &api.OrgsOrgIDRekeyPostConflict{}, nil
+		case errors.Is(err, interfaces.ErrInvalidOrg):
+			return &api.OrgsOrgIDRekeyPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDRekeyPostNoContent{}, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDRecordsGet(ctx context.Context, params api.OrgsOrgIDRecordsGetParams) (api.OrgsOrgIDRecordsGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := h.service.GetOrgRecords(ctx, userID, params.OrgID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.OrgsOrgIDRecordsGetNotFound{}, nil
+		}
+		return nil, err
+	}
+
+	res := make(api.OrgsOrgIDRecordsGetOKApplicationJSON, 0, len(records))
+	for _, rec := range records {
+		res = append(res, api.RecordWithId{
+			ID:      rec.ID,
+			Type:    api.RecordType(rec.Type),
+			Data:    rec.Data,
+			Nonce:   rec.Nonce,
+			Version: rec.Version,
+		})
+	}
+	return &res, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDRecordsIDPut(ctx context.Context, req *api.OrgRecord, params api.OrgsOrgIDRecordsIDPutParams) (api.OrgsOrgIDRecordsIDPutRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rec := &models.OrgRecord{
+		ID:      params.ID,
+		OrgID:   params.OrgID,
+		Type:    string(req.Type),
+		Data:    req.Data,
+		Nonce:   req.Nonce,
+		Version: req.Version,
+	}
+	if err = h.service.SaveOrgRecord(ctx, userID, rec, req.KeyVersion); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDRecordsIDPutNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDRecordsIDPutForbidden{}, nil
+		case errors.Is(err, interfaces.ErrVersionConflict):
+			return &api.OrgsOrgIDRecordsIDPutConflict{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDRecordsIDPutNoContent{}, nil
+}
+
+func (h *OrgHandler) OrgsOrgIDRecordsIDDelete(ctx context.Context, params api.OrgsOrgIDRecordsIDDeleteParams) (api.OrgsOrgIDRecordsIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.DeleteOrgRecord(ctx, userID, params.OrgID, params.ID); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.OrgsOrgIDRecordsIDDeleteNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.OrgsOrgIDRecordsIDDeleteForbidden{}, nil
+		}
+		return nil, err
+	}
+	return &api.OrgsOrgIDRecordsIDDeleteNoContent{}, nil
+}
+
+func convertOrg(org *models.Organization) *api.Organization {
+	return &api.Organization{
+		ID:          org.ID,
+		Name:        org.Name,
+		Role:        api.OrgRole(org.Role),
+		Key:         org.Key,
+		KeyVersion:  org.KeyVersion,
+		RekeyNeeded: org.RekeyNeeded,
+		CreatedAt:   org.CreatedAt,
+	}
+}
+
+func convertOrgMember(member *models.OrgMember) *api.OrgMember {
+	return &api.OrgMember{
+		UserID:    uuid.MustParse(member.UserID),
+		Login:     member.Login,
+		Role:      api.OrgRole(member.Role),
+		PublicKey: member.PublicKey,
+		CreatedAt: member.CreatedAt,
+	}
+}
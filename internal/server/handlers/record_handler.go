@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/api"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
 	"github.com/grnsv/GophKeeper/internal/server/models"
@@ -24,6 +25,7 @@ func (h *RecordHandler) convertRecordToApiRecord(rec *models.Record) *api.Record
 		Data:    rec.Data,
 		Nonce:   rec.Nonce,
 		Version: rec.Version,
+		Key:     rec.Key,
 	}
 }
 
@@ -37,10 +39,13 @@ func (h *RecordHandler) RecordsGet(ctx context.Context) (api.RecordsGetRes, erro
 		return nil, err
 	}
 
-	length := len(records)
-	out := make(api.RecordsGetOKApplicationJSON, length)
-	for k, rec := range records {
-		out[k] = *h.convertRecordToApiRecord(rec)
+	accessToken := getAccessToken(ctx)
+	out := make(api.RecordsGetOKApplicationJSON, 0, len(records))
+	for _, rec := range records {
+		if accessToken != nil && !accessToken.CanRead(rec.ID) {
+			continue
+		}
+		out = append(out, *h.convertRecordToApiRecord(rec))
 	}
 	return &out, nil
 }
@@ -50,6 +55,9 @@ func (h *RecordHandler) RecordsIDPut(ctx context.Context, req *api.Record, param
 	if err != nil {
 		return nil, err
 	}
+	if !canWrite(ctx, params.ID) {
+		return &api.RecordsIDPutForbidden{}, nil
+	}
 	rec := &models.Record{
 		ID:      params.ID,
 		UserID:  userID,
@@ -57,6 +65,7 @@ func (h *RecordHandler) RecordsIDPut(ctx context.Context, req *api.Record, param
 		Data:    req.Data,
 		Nonce:   req.Nonce,
 		Version: req.Version,
+		Key:     req.Key,
 	}
 	if err = h.service.SaveRecord(ctx, rec); err != nil {
 		if errors.Is(err, interfaces.ErrVersionConflict) {
@@ -72,6 +81,9 @@ func (h *RecordHandler) RecordsIDGet(ctx context.Context, params api.RecordsIDGe
 	if err != nil {
 		return nil, err
 	}
+	if accessToken := getAccessToken(ctx); accessToken != nil && !accessToken.CanRead(params.ID) {
+		return &api.RecordsIDGetNotFound{}, nil
+	}
 	rec, err := h.service.GetRecord(ctx, userID, params.ID)
 	if err != nil {
 		if errors.Is(err, interfaces.ErrNotFound) {
@@ -87,8 +99,18 @@ func (h *RecordHandler) RecordsIDDelete(ctx context.Context, params api.RecordsI
 	if err != nil {
 		return nil, err
 	}
+	if !canWrite(ctx, params.ID) {
+		return &api.RecordsIDDeleteForbidden{}, nil
+	}
 	if err = h.service.DeleteRecord(ctx, userID, params.ID); err != nil {
 		return nil, err
 	}
 	return &api.RecordsIDDeleteNoContent{}, nil
 }
+
+// canWrite tells whether the personal access token of the request, if any,
+// may change the record.
+func canWrite(ctx context.Context, id uuid.UUID) bool {
+	accessToken := getAccessToken(ctx)
+	return accessToken == nil || accessToken.CanWrite(id)
+}
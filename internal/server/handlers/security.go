@@ -2,26 +2,44 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/grnsv/GophKeeper/internal/api"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
 	"github.com/ogen-go/ogen/ogenerrors"
 )
 
 type contextKey string
 
-const userIDContextKey contextKey = "userID"
+const (
+	userIDContextKey      contextKey = "userID"
+	accessTokenContextKey contextKey = "accessToken"
+)
+
+// accessTokenOperations are the operations a personal access token may
+// call. Managing tokens and the account needs a session.
+var accessTokenOperations = map[api.OperationName]bool{
+	api.RecordsGetOperation:       true,
+	api.RecordsIDGetOperation:     true,
+	api.RecordsIDPutOperation:     true,
+	api.RecordsIDDeleteOperation:  true,
+	api.TokensCurrentGetOperation: true,
+}
 
 type SecurityHandler struct {
-	jwts interfaces.JWTService
+	service interfaces.Service
 }
 
-func NewSecurityHandler(jwts interfaces.JWTService) api.SecurityHandler {
-	return &SecurityHandler{jwts: jwts}
+func NewSecurityHandler(s interfaces.Service) api.SecurityHandler {
+	return &SecurityHandler{service: s}
 }
 
 func (h *SecurityHandler) HandleBearerAuth(ctx context.Context, operationName api.OperationName, t api.BearerAuth) (context.Context, error) {
-	userID, err := h.jwts.ParseJWT(t.GetToken())
+	userID, accessToken, err := h.service.Authenticate(ctx, t.GetToken())
+	if err == nil && accessToken != nil && !accessTokenOperations[operationName] {
+		err = fmt.Errorf("%w: not allowed with an access token", interfaces.ErrForbidden)
+	}
 	if err != nil {
 		return ctx, &ogenerrors.SecurityError{
 			OperationContext: ogenerrors.OperationContext{Name: operationName},
@@ -30,5 +48,16 @@ func (h *SecurityHandler) HandleBearerAuth(ctx context.Context, operationName ap
 		}
 	}
 
-	return context.WithValue(ctx, userIDContextKey, userID), nil
+	ctx = context.WithValue(ctx, userIDContextKey, userID)
+	if accessToken != nil {
+		ctx = context.WithValue(ctx, accessTokenContextKey, accessToken)
+	}
+	return ctx, nil
+}
+
+// getAccessToken returns the personal access token the request is
+// authenticated with, nil for a session.
+func getAccessToken(ctx context.Context) *models.AccessToken {
+	accessToken, _ := ctx.Value(accessTokenContextKey).(*models.AccessToken)
+	return accessToken
 }
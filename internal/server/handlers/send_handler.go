@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type SendHandler struct {
+	service interfaces.Service
+}
+
+func NewSendHandler(s interfaces.Service) *SendHandler {
+	return &SendHandler{service: s}
+}
+
+func (h *SendHandler) SendsPost(ctx context.Context, req *api.SendRequest) (api.SendsPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	send := &models.Send{
+		UserID:    userID,
+		Data:      req.Data,
+		Nonce:     req.Nonce,
+		MaxViews:  req.MaxViews,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err = h.service.CreateSend(ctx, send, req.Password.Or("")); err != nil {
+		if errors.Is(err, interfaces.ErrInvalidSend) {
+			return &api.SendsPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.Send{
+		ID:                send.ID,
+		MaxViews:          send.MaxViews,
+		ExpiresAt:         send.ExpiresAt,
+		PasswordProtected: send.PasswordHash != "",
+		CreatedAt:         send.CreatedAt,
+	}, nil
+}
+
+func (h *SendHandler) SendsIDGet(ctx context.Context, params api.SendsIDGetParams) (api.SendsIDGetRes, error) {
+	send, err := h.service.OpenSend(ctx, params.ID, params.XSendPassword.Or(""))
+	if err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.SendsIDGetNotFound{}, nil
+		case errors.Is(err, interfaces.ErrForbidden):
+			return &api.SendsIDGetForbidden{}, nil
+		}
+		return nil, err
+	}
+	return &api.SendData{
+		Data:      send.Data,
+		Nonce:     send.Nonce,
+		ViewsLeft: send.ViewsLeft,
+		ExpiresAt: send.ExpiresAt,
+	}, nil
+}
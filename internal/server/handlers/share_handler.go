@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type ShareHandler struct {
+	service interfaces.Service
+}
+
+func NewShareHandler(s interfaces.Service) *ShareHandler {
+	return &ShareHandler{service: s}
+}
+
+func (h *ShareHandler) AccountKeysGet(ctx context.Context) (api.AccountKeysGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := h.service.GetUserKeys(ctx, userID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.AccountKeysGetNotFound{}, nil
+		}
+		return nil, err
+	}
+	return &api.UserKeys{PublicKey: keys.PublicKey, WrappedPrivateKey: keys.WrappedPrivateKey}, nil
+}
+
+func (h *ShareHandler) AccountKeysPut(ctx context.Context, req *api.UserKeys) (api.AccountKeysPutRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = h.service.SetUserKeys(ctx, &models.UserKeys{
+		UserID:            userID,
+		PublicKey:         req.PublicKey,
+		WrappedPrivateKey: req.WrappedPrivateKey,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrKeysExist):
+			return &api.AccountKeysPutConflict{}, nil
+		case errors.Is(err, interfaces.ErrInvalidShare):
+			return &api.AccountKeysPutUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.AccountKeysPutNoContent{}, nil
+}
+
+func (h *ShareHandler) UsersLoginPublicKeyGet(ctx context.Context, params api.UsersLoginPublicKeyGetParams) (api.UsersLoginPublicKeyGetRes, error) {
+	keys, err := h.service.GetPublicKey(ctx, params.Login)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.UsersLoginPublicKeyGetNotFound{}, nil
+		}
+		return nil, err
+	}
+	return &api.PublicKey{Login: params.Login, PublicKey: keys.PublicKey}, nil
+}
+
+func (h *ShareHandler) RecordsIDSharesGet(ctx context.Context, params api.RecordsIDSharesGetParams) (api.RecordsIDSharesGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := h.service.ListShares(ctx, userID, params.ID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.RecordsIDSharesGetNotFound{}, nil
+		}
+		return nil, err
+	}
+
+	res := make(api.RecordsIDSharesGetOKApplicationJSON, 0, len(shares))
+	for _, share := range shares {
+		res = append(res, *convertShare(share))
+	}
+	return &res, nil
+}
+
+func (h *ShareHandler) RecordsIDSharesPost(ctx context.Context, req *api.ShareRequest, params api.RecordsIDSharesPostParams) (api.RecordsIDSharesPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	share := &models.Share{RecordID: params.ID, OwnerID: userID, Key: req.Key}
+	if err = h.service.ShareRecord(ctx, share, req.Login); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrNotFound):
+			return &api.RecordsIDSharesPostNotFound{}, nil
+		case errors.Is(err, interfaces.ErrInvalidShare):
+			return &api.RecordsIDSharesPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return convertShare(share), nil
+}
+
+func (h *ShareHandler) RecordsIDSharesUserIDDelete(ctx context.Context, params api.RecordsIDSharesUserIDDeleteParams) (api.RecordsIDSharesUserIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RevokeShare(ctx, userID, params.ID, params.UserID.String()); err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.RecordsIDSharesUserIDDeleteNotFound{}, nil
+		}
+		return nil, err
+	}
+	return &api.RecordsIDSharesUserIDDeleteNoContent{}, nil
+}
+
+func (h *ShareHandler) SharedGet(ctx context.Context) (api.SharedGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := h.service.GetSharedRecords(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.SharedGetOKApplicationJSON, 0, len(records))
+	for _, rec := range records {
+		res = append(res, api.SharedRecord{
+			ID:       rec.ID,
+			Type:     api.RecordType(rec.Type),
+			Data:     rec.Data,
+			Nonce:    rec.Nonce,
+			Version:  rec.Version,
+			Key:      rec.Key,
+			Owner:    rec.OwnerLogin,
+			SharedAt: rec.SharedAt,
+		})
+	}
+	return &res, nil
+}
+
+func convertShare(share *models.Share) *api.Share {
+	return &api.Share{
+		UserID:    uuid.MustParse(share.RecipientID),
+		Login:     share.RecipientLogin,
+		CreatedAt: share.CreatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/api"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type TokenHandler struct {
+	service interfaces.Service
+}
+
+func NewTokenHandler(s interfaces.Service) *TokenHandler {
+	return &TokenHandler{service: s}
+}
+
+func (h *TokenHandler) TokensPost(ctx context.Context, req *api.AccessTokenRequest) (api.TokensPostRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token := &models.AccessToken{
+		UserID:     userID,
+		Name:       req.Name,
+		ReadOnly:   req.ReadOnly.Or(false),
+		Tags:       req.Tags,
+		WrappedKey: req.WrappedKey,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if req.RecordKeys != nil {
+		token.Records = make(map[uuid.UUID][]byte, len(req.RecordKeys))
+		for _, rk := range req.RecordKeys {
+			token.Records[rk.RecordID] = rk.Key
+		}
+	}
+
+	secret, err := h.service.CreateAccessToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrInvalidAccessToken) {
+			return &api.TokensPostUnprocessableEntity{}, nil
+		}
+		return nil, err
+	}
+	return &api.CreatedAccessToken{ID: uuid.MustParse(token.ID), Token: secret}, nil
+}
+
+func (h *TokenHandler) TokensGet(ctx context.Context) (api.TokensGetRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := h.service.ListAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.TokensGetOKApplicationJSON, 0, len(tokens))
+	for _, token := range tokens {
+		out := api.AccessToken{
+			ID:        uuid.MustParse(token.ID),
+			Name:      token.Name,
+			ReadOnly:  token.ReadOnly,
+			Tags:      token.Tags,
+			ExpiresAt: token.ExpiresAt,
+			CreatedAt: token.CreatedAt,
+		}
+		if token.Records != nil {
+			out.Records = make([]uuid.UUID, 0, len(token.Records))
+			for id := range token.Records {
+				out.Records = append(out.Records, id)
+			}
+		}
+		if !token.LastUsedAt.IsZero() {
+			out.LastUsedAt = api.NewOptDateTime(token.LastUsedAt)
+		}
+		res = append(res, out)
+	}
+	return &res, nil
+}
+
+func (h *TokenHandler) TokensIDDelete(ctx context.Context, params api.TokensIDDeleteParams) (api.TokensIDDeleteRes, error) {
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.service.RevokeAccessToken(ctx, userID, params.ID.String()); err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return &api.TokensIDDeleteNotFound{}, nil
+		}
+		return nil, err
+	}
+	return &api.TokensIDDeleteNoContent{}, nil
+}
+
+// TokensCurrentGet gives a headless client the keys wrapped for its token.
+func (h *TokenHandler) TokensCurrentGet(ctx context.Context) (api.TokensCurrentGetRes, error) {
+	accessToken := getAccessToken(ctx)
+	if accessToken == nil {
+		return &api.TokensCurrentGetForbidden{}, nil
+	}
+	res := &api.AccessTokenKeys{
+		ReadOnly:   accessToken.ReadOnly,
+		WrappedKey: accessToken.WrappedKey,
+	}
+	for id, key := range accessToken.Records {
+		res.RecordKeys = append(res.RecordKeys, api.RecordKey{RecordID: id, Key: key})
+	}
+	return res, nil
+}
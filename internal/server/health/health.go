@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/storage"
+)
+
+const checkTimeout = 3 * time.Second
+
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type Handler struct {
+	storage interfaces.Storage
+	dsn     string
+}
+
+func NewHandler(storage interfaces.Storage, dsn string) *Handler {
+	return &Handler{storage: storage, dsn: dsn}
+}
+
+// Liveness reports that the process is up and serving requests.
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: "ok"})
+}
+
+// Readiness reports whether the database is reachable and its schema is up to date.
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	resp := Response{Status: "ok", Checks: make(map[string]string)}
+	code := http.StatusOK
+	checks := map[string]func(context.Context) error{
+		"database":   h.storage.Ping,
+		"migrations": h.checkMigrations,
+	}
+	for name, check := range checks {
+		if err := check(ctx); err != nil {
+			resp.Checks[name] = err.Error()
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+
+	writeResponse(w, code, resp)
+}
+
+// checkMigrations compares the version the storage reports with the latest
+// embedded migration. It reads the version table through the open storage,
+// so a probe does not wait for a migration holding the migrate lock.
+func (h *Handler) checkMigrations(ctx context.Context) error {
+	latest, err := storage.LatestMigration(h.dsn)
+	if err != nil {
+		if errors.Is(err, storage.ErrNoMigrations) {
+			return nil
+		}
+		return err
+	}
+
+	status := storage.MigrationStatus{Latest: latest}
+	if status.Version, status.Dirty, err = h.storage.SchemaVersion(ctx); err != nil {
+		return err
+	}
+	if !status.UpToDate() {
+		return fmt.Errorf("schema version %d (dirty: %t), expected %d", status.Version, status.Dirty, status.Latest)
+	}
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,30 +11,111 @@ import (
 )
 
 var (
-	ErrLoginTaken      = errors.New("login already exists")
-	ErrNotFound        = errors.New("not found")
-	ErrUnauthorized    = errors.New("unauthorized")
-	ErrVersionConflict = errors.New("version conflict")
+	ErrLoginTaken         = errors.New("login already exists")
+	ErrNotFound           = errors.New("not found")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrVersionConflict    = errors.New("version conflict")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrInvalidInvite      = errors.New("invite code is missing or invalid")
+	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidAccessToken = errors.New("access token request is invalid")
+	ErrKeysExist          = errors.New("key pair already exists")
+	ErrInvalidShare       = errors.New("share request is invalid")
+	ErrInvalidOrg         = errors.New("organization request is invalid")
+	ErrMemberExists       = errors.New("user is already a member")
+	// ErrLastOwner is returned when an organization would be left without an owner.
+	ErrLastOwner        = fmt.Errorf("%w: an organization needs an owner", ErrInvalidOrg)
+	ErrInvalidEmergency = errors.New("emergency access request is invalid")
+	ErrContactExists    = errors.New("user is already a trusted contact")
+	ErrEmergencyState   = errors.New("emergency access is not in a state that allows this")
+	ErrInvalidSend      = errors.New("send request is invalid")
 )
 
 type Service interface {
-	Register(ctx context.Context, login, password string) (token string, err error)
+	Register(ctx context.Context, login, password, inviteCode string) (token string, err error)
 	Login(ctx context.Context, login, password string) (token string, err error)
+	DeleteAccount(ctx context.Context, userID, password string) error
 	GetRecords(ctx context.Context, userID string) ([]*models.Record, error)
 	SaveRecord(ctx context.Context, rec *models.Record) error
 	GetRecord(ctx context.Context, userID string, id uuid.UUID) (*models.Record, error)
 	DeleteRecord(ctx context.Context, userID string, id uuid.UUID) error
 	GetVersion(ctx context.Context) (buildVersion string, buildDate time.Time)
+	GetRegistrationMode(ctx context.Context) models.RegistrationMode
+	GetJWKS(ctx context.Context) []models.JWK
+	// Authenticate accepts session tokens and personal access tokens, accessToken is nil for the former.
+	Authenticate(ctx context.Context, token string) (userID string, accessToken *models.AccessToken, err error)
+	GetAuditEvents(ctx context.Context, userID string, limit int) ([]*models.AuditEvent, error)
+	// CreateAccessToken stores the token and returns the part of it the client authenticates with.
+	CreateAccessToken(ctx context.Context, token *models.AccessToken) (secret string, err error)
+	ListAccessTokens(ctx context.Context, userID string) ([]*models.AccessToken, error)
+	RevokeAccessToken(ctx context.Context, userID, id string) error
+	// SetUserKeys stores the key pair of the user once, replacing it would
+	// make the records shared with them unreadable.
+	SetUserKeys(ctx context.Context, keys *models.UserKeys) error
+	GetUserKeys(ctx context.Context, userID string) (*models.UserKeys, error)
+	GetPublicKey(ctx context.Context, login string) (*models.UserKeys, error)
+	ShareRecord(ctx context.Context, share *models.Share, recipientLogin string) error
+	ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) ([]*models.Share, error)
+	RevokeShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) error
+	GetSharedRecords(ctx context.Context, userID string) ([]*models.SharedRecord, error)
+	CreateOrg(ctx context.Context, userID string, org *models.Organization) error
+	ListOrgs(ctx context.Context, userID string) ([]*models.Organization, error)
+	DeleteOrg(ctx context.Context, userID string, orgID uuid.UUID) error
+	ListOrgMembers(ctx context.Context, userID string, orgID uuid.UUID) ([]*models.OrgMember, error)
+	// InviteOrgMember adds the user with the login, member.Key must be the
+	// organization key of keyVersion wrapped for their public key.
+	InviteOrgMember(ctx context.Context, userID string, member *models.OrgMember, login string, keyVersion int64) error
+	SetOrgMemberRole(ctx context.Context, userID string, orgID uuid.UUID, memberID string, role models.OrgRole) error
+	// RemoveOrgMember removes a member, any member may remove themselves.
+	RemoveOrgMember(ctx context.Context, userID string, orgID uuid.UUID, memberID string) error
+	RekeyOrg(ctx context.Context, userID string, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) error
+	GetOrgRecords(ctx context.Context, userID string, orgID uuid.UUID) ([]*models.OrgRecord, error)
+	SaveOrgRecord(ctx context.Context, userID string, rec *models.OrgRecord, keyVersion int64) error
+	DeleteOrgRecord(ctx context.Context, userID string, orgID, id uuid.UUID) error
+	// AddEmergencyContact makes the user with the login a trusted contact of
+	// the grantor, access.Key must be the vault key wrapped for their public key.
+	AddEmergencyContact(ctx context.Context, access *models.EmergencyAccess, granteeLogin string) error
+	ListEmergencyContacts(ctx context.Context, grantorID string) ([]*models.EmergencyAccess, error)
+	RemoveEmergencyContact(ctx context.Context, grantorID string, id uuid.UUID) error
+	ApproveEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error
+	// RejectEmergencyAccess rejects a request or withdraws an approved
+	// access, the contact may request again.
+	RejectEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error
+	ListEmergencyGrants(ctx context.Context, granteeID string) ([]*models.EmergencyAccess, error)
+	RequestEmergencyAccess(ctx context.Context, granteeID string, id uuid.UUID) error
+	// GetEmergencyVault returns the access with the wrapped vault key and
+	// the records of the grantor, ErrForbidden until access is approved.
+	GetEmergencyVault(ctx context.Context, granteeID string, id uuid.UUID) (*models.EmergencyAccess, []*models.Record, error)
+	// CreateSend stores the send with the hash of the password, an empty
+	// password leaves it unprotected.
+	CreateSend(ctx context.Context, send *models.Send, password string) error
+	// OpenSend counts a view of the send, ErrForbidden if the password is
+	// wrong, ErrNotFound if the send expired or has no views left.
+	OpenSend(ctx context.Context, id uuid.UUID, password string) (*models.Send, error)
 }
 
 type JWTService interface {
 	BuildJWT(userID string) (token string, err error)
-	ParseJWT(token string) (userID string, err error)
+	ParseJWT(token string) (userID string, issuedAt time.Time, err error)
+	// PublicKeys returns the keys tokens can be verified with, none for symmetric signing.
+	PublicKeys() []models.JWK
 }
 
 type Storage interface {
 	UserRepository
 	RecordRepository
+	InviteRepository
+	AuditRepository
+	AccessTokenRepository
+	ShareRepository
+	OrgRepository
+	EmergencyRepository
+	SendRepository
+	Ping(ctx context.Context) error
+	// SchemaVersion reports the applied schema migration, version 0 if none
+	// was applied or the storage has no migrations.
+	SchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
 }
 
 type UserRepository interface {
@@ -41,6 +123,109 @@ type UserRepository interface {
 	IsLoginExists(ctx context.Context, login string) (bool, error)
 	CreateUser(ctx context.Context, user *models.User) error
 	FindUserByLogin(ctx context.Context, login string) (*models.User, error)
+	FindUserByID(ctx context.Context, id string) (*models.User, error)
+	ListUsers(ctx context.Context, search string) ([]*models.User, error)
+	SetUserDisabled(ctx context.Context, id string, disabled bool) error
+	RevokeUserSessions(ctx context.Context, id string, at time.Time) error
+	DeleteUser(ctx context.Context, id string) error
+}
+
+type InviteRepository interface {
+	Close() error
+	CreateInvite(ctx context.Context, invite *models.Invite) error
+	ListInvites(ctx context.Context) ([]*models.Invite, error)
+	DeleteInvite(ctx context.Context, id string) error
+	// IsInviteValid reports whether there is a valid invite, without using it.
+	IsInviteValid(ctx context.Context, codeHash string, now time.Time) (bool, error)
+	// UseInvite counts a use of a valid invite, it returns ErrNotFound if there is none.
+	UseInvite(ctx context.Context, codeHash string, now time.Time) error
+	// ReleaseInvite gives back a use counted by UseInvite when the registration failed.
+	ReleaseInvite(ctx context.Context, codeHash string) error
+}
+
+type AuditRepository interface {
+	Close() error
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	// ListAuditEvents returns up to limit most recent events of the user, newest first.
+	ListAuditEvents(ctx context.Context, userID string, limit int) ([]*models.AuditEvent, error)
+}
+
+type AccessTokenRepository interface {
+	Close() error
+	CreateAccessToken(ctx context.Context, token *models.AccessToken) error
+	ListAccessTokens(ctx context.Context, userID string) ([]*models.AccessToken, error)
+	FindAccessToken(ctx context.Context, id string) (*models.AccessToken, error)
+	DeleteAccessToken(ctx context.Context, userID, id string) error
+	TouchAccessToken(ctx context.Context, id string, at time.Time) error
+}
+
+type ShareRepository interface {
+	Close() error
+	// CreateUserKeys returns ErrKeysExist if the user has a key pair.
+	CreateUserKeys(ctx context.Context, keys *models.UserKeys) error
+	GetUserKeys(ctx context.Context, userID string) (*models.UserKeys, error)
+	// SaveShare creates the share or replaces its key.
+	SaveShare(ctx context.Context, share *models.Share) error
+	ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) ([]*models.Share, error)
+	DeleteShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) error
+	GetSharedRecords(ctx context.Context, recipientID string) ([]*models.SharedRecord, error)
+}
+
+type OrgRepository interface {
+	Close() error
+	// CreateOrg creates the organization with its first member.
+	CreateOrg(ctx context.Context, org *models.Organization, owner *models.OrgMember) error
+	ListOrgs(ctx context.Context, userID string) ([]*models.Organization, error)
+	// GetOrg returns the organization as seen by the member, ErrNotFound if
+	// the user is not a member.
+	GetOrg(ctx context.Context, orgID uuid.UUID, userID string) (*models.Organization, error)
+	DeleteOrg(ctx context.Context, orgID uuid.UUID) error
+	ListOrgMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrgMember, error)
+	GetOrgMember(ctx context.Context, orgID uuid.UUID, userID string) (*models.OrgMember, error)
+	// AddOrgMember returns ErrMemberExists if the user is a member and
+	// ErrVersionConflict if keyVersion is not the current key version.
+	AddOrgMember(ctx context.Context, member *models.OrgMember, keyVersion int64) error
+	// SetOrgMemberRole returns ErrLastOwner if the member is the only owner
+	// and the role is not owner.
+	SetOrgMemberRole(ctx context.Context, orgID uuid.UUID, userID string, role models.OrgRole) error
+	// DeleteOrgMember removes the member and marks the organization as
+	// needing a re-key. It returns ErrLastOwner for the only owner.
+	DeleteOrgMember(ctx context.Context, orgID uuid.UUID, userID string) error
+	// RekeyOrg replaces the keys of all members and all records at once. It
+	// returns ErrVersionConflict if keyVersion or the version of a record is
+	// not the next one and ErrInvalidOrg if a member or record is missing.
+	RekeyOrg(ctx context.Context, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) error
+	GetOrgRecords(ctx context.Context, orgID uuid.UUID) ([]*models.OrgRecord, error)
+	// SaveOrgRecord creates or updates the record. It returns
+	// ErrVersionConflict if keyVersion is not the current key version or the
+	// version of the record is not the next one.
+	SaveOrgRecord(ctx context.Context, rec *models.OrgRecord, keyVersion int64) error
+	DeleteOrgRecord(ctx context.Context, orgID, id uuid.UUID) error
+}
+
+type EmergencyRepository interface {
+	Close() error
+	// CreateEmergencyAccess returns ErrContactExists if the grantee already
+	// is a trusted contact of the grantor.
+	CreateEmergencyAccess(ctx context.Context, access *models.EmergencyAccess) error
+	GetEmergencyAccess(ctx context.Context, id uuid.UUID) (*models.EmergencyAccess, error)
+	ListEmergencyContacts(ctx context.Context, grantorID string) ([]*models.EmergencyAccess, error)
+	ListEmergencyGrants(ctx context.Context, granteeID string) ([]*models.EmergencyAccess, error)
+	// SetEmergencyStatus changes the status if it is from, otherwise it
+	// returns ErrEmergencyState. A zero requestedAt clears it.
+	SetEmergencyStatus(ctx context.Context, id uuid.UUID, from []models.EmergencyStatus, to models.EmergencyStatus, requestedAt time.Time) error
+	DeleteEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error
+}
+
+type SendRepository interface {
+	Close() error
+	CreateSend(ctx context.Context, send *models.Send) error
+	// GetSend returns ErrNotFound if the send expired at now or has no views left.
+	GetSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error)
+	// UseSend counts a view and deletes the send after its last one. It
+	// returns ErrNotFound if the send expired at now or has no views left.
+	UseSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error)
+	DeleteExpiredSends(ctx context.Context, now time.Time) error
 }
 
 type RecordRepository interface {
@@ -51,4 +236,5 @@ type RecordRepository interface {
 	UpdateRecord(ctx context.Context, rec *models.Record) error
 	GetRecord(ctx context.Context, userID string, id uuid.UUID) (*models.Record, error)
 	DeleteRecord(ctx context.Context, userID string, id uuid.UUID) error
+	GetRecordStats(ctx context.Context) (map[string]models.RecordStats, error)
 }
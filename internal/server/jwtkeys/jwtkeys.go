@@ -0,0 +1,332 @@
+// Package jwtkeys manages the asymmetric keys tokens are signed with.
+//
+// Keys are PKCS #8 PEM files in a directory, the key ID (kid) is the file
+// name without the .pem extension and the time a key takes effect is the
+// modification time of its file. The newest key in effect signs new tokens.
+// A key that has been superseded still verifies tokens for the overlap
+// window, so tokens issued just before a rotation stay valid until they
+// expire. Keys with a modification time in the future are published but do
+// not sign yet, which allows scheduling a rotation by hand.
+package jwtkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmES256 = "ES256"
+
+	keyFileExt = ".pem"
+)
+
+// Key is a signing key. Algorithm follows from the type of the key, so a
+// directory may hold keys of both algorithms while migrating between them.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+	// CreatedAt is when the key takes effect.
+	CreatedAt time.Time
+}
+
+type Options struct {
+	Dir string
+	// Algorithm of the keys generated on rotation.
+	Algorithm string
+	// Rotation is the age at which a new signing key is generated, zero
+	// disables rotation.
+	Rotation time.Duration
+	// Overlap is how long a superseded key keeps verifying tokens.
+	Overlap time.Duration
+}
+
+type KeySet struct {
+	opts Options
+
+	mu   sync.RWMutex
+	keys []*Key // sorted by CreatedAt
+}
+
+// Open loads the keys from opts.Dir, creating the directory and generating
+// a key if there is no key to sign with.
+func Open(opts Options) (*KeySet, error) {
+	switch opts.Algorithm {
+	case AlgorithmEdDSA, AlgorithmES256:
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", opts.Algorithm)
+	}
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	s := &KeySet{opts: opts}
+	if err := s.Refresh(time.Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Refresh reads the directory again, picking up keys added by the operator
+// or by other instances sharing it, and generates a new key if rotation is
+// due. On error the previously loaded keys stay in use.
+func (s *KeySet) Refresh(now time.Time) error {
+	keys, err := load(s.opts.Dir)
+	if err != nil {
+		return err
+	}
+	if signing := signingKey(keys, now); signing == nil || s.opts.Rotation > 0 && now.Sub(signing.CreatedAt) >= s.opts.Rotation {
+		key, err := generate(s.opts.Dir, s.opts.Algorithm)
+		if err != nil {
+			return fmt.Errorf("generate key: %w", err)
+		}
+		slog.Info("Generated JWT signing key", "kid", key.ID, "alg", key.Algorithm)
+		keys = append(keys, key)
+		sortKeys(keys)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the keys every interval until ctx is done.
+func (s *KeySet) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.Refresh(now); err != nil {
+				slog.Error("JWT keys refresh failed, keeping the previous keys", "error", err)
+			}
+		}
+	}
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (s *KeySet) SigningKey(now time.Time) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key := signingKey(s.keys, now); key != nil {
+		return key, nil
+	}
+	return nil, errors.New("no signing key in effect")
+}
+
+// VerificationKey returns the key with the given ID if it may verify tokens at now.
+func (s *KeySet) VerificationKey(id string, now time.Time) (*Key, bool) {
+	for _, key := range s.PublicKeys(now) {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// PublicKeys returns the keys that are not retired at now: the signing key,
+// the superseded keys within the overlap window and the scheduled ones.
+func (s *KeySet) PublicKeys(now time.Time) []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var active []*Key
+	for i, key := range s.keys {
+		if i+1 < len(s.keys) {
+			next := s.keys[i+1]
+			if !next.CreatedAt.After(now) && !now.Before(next.CreatedAt.Add(s.opts.Overlap)) {
+				continue
+			}
+		}
+		active = append(active, key)
+	}
+	return active
+}
+
+// JWKS returns the public keys in the JSON Web Key form.
+func (s *KeySet) JWKS(now time.Time) []models.JWK {
+	keys := s.PublicKeys(now)
+	jwks := make([]models.JWK, 0, len(keys))
+	for _, key := range keys {
+		jwks = append(jwks, key.JWK())
+	}
+	return jwks
+}
+
+func (k *Key) JWK() models.JWK {
+	jwk := models.JWK{KeyID: k.ID, Algorithm: k.Algorithm}
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *ecdsa.PublicKey:
+		// Uncompressed point: 0x04 || X || Y with fixed size coordinates.
+		point, _ := pub.ECDH()
+		raw := point.Bytes()[1:]
+		jwk.KeyType = "EC"
+		jwk.Curve = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(raw[:len(raw)/2])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(raw[len(raw)/2:])
+	}
+	return jwk
+}
+
+func signingKey(keys []*Key, now time.Time) *Key {
+	var signing *Key
+	for _, key := range keys {
+		if key.CreatedAt.After(now) {
+			break
+		}
+		signing = key
+	}
+	return signing
+}
+
+func sortKeys(keys []*Key) {
+	slices.SortFunc(keys, func(a, b *Key) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+func load(dir string) ([]*Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*Key
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+		key, err := loadKey(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys, nil
+}
+
+func loadKey(path string) (*Key, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("no PKCS #8 PRIVATE KEY PEM block found")
+	}
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newKey(strings.TrimSuffix(filepath.Base(path), keyFileExt), private)
+	if err != nil {
+		return nil, err
+	}
+	key.CreatedAt = info.ModTime()
+	return key, nil
+}
+
+func newKey(id string, private any) (*Key, error) {
+	key := &Key{ID: id}
+	switch private := private.(type) {
+	case ed25519.PrivateKey:
+		key.Algorithm = AlgorithmEdDSA
+		key.Private = private
+		key.Public = private.Public()
+	case *ecdsa.PrivateKey:
+		if private.Curve != elliptic.P256() {
+			return nil, errors.New("ECDSA keys must use the P-256 curve")
+		}
+		key.Algorithm = AlgorithmES256
+		key.Private = private
+		key.Public = private.Public()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use Ed25519 or ECDSA P-256", private)
+	}
+	return key, nil
+}
+
+// generate writes a new key to dir. The file is renamed into place so other
+// instances never read it half written.
+func generate(dir, algorithm string) (*Key, error) {
+	var private any
+	var err error
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case AlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err = rand.Read(id); err != nil {
+		return nil, err
+	}
+	key, err := newKey(hex.EncodeToString(id), private)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if err = pem.Encode(tmp, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, key.ID+keyFileExt)
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	key.CreatedAt = info.ModTime()
+	return key, nil
+}
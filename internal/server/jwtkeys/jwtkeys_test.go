@@ -0,0 +1,240 @@
+package jwtkeys_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/jwtkeys"
+)
+
+// writeKey writes a PKCS #8 key to dir as ID.pem, taking effect at createdAt.
+func writeKey(t *testing.T, dir, id string, private any, createdAt time.Time) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	path := filepath.Join(dir, id+".pem")
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chtimes(path, createdAt, createdAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newEd25519(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newECDSA(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func open(t *testing.T, opts jwtkeys.Options) *jwtkeys.KeySet {
+	t.Helper()
+	s, err := jwtkeys.Open(opts)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+func ids(keys []*jwtkeys.Key) []string {
+	var res []string
+	for _, key := range keys {
+		res = append(res, key.ID)
+	}
+	return res
+}
+
+func signingID(t *testing.T, s *jwtkeys.KeySet, now time.Time) string {
+	t.Helper()
+	key, err := s.SigningKey(now)
+	if err != nil {
+		t.Fatalf("SigningKey: %v", err)
+	}
+	return key.ID
+}
+
+func TestOpenGenerates(t *testing.T) {
+	for _, algorithm := range []string{jwtkeys.AlgorithmEdDSA, jwtkeys.AlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "keys")
+			s := open(t, jwtkeys.Options{Dir: dir, Algorithm: algorithm})
+			key, err := s.SigningKey(time.Now())
+			if err != nil {
+				t.Fatalf("SigningKey: %v", err)
+			}
+			if key.Algorithm != algorithm {
+				t.Fatalf("generated a key for %s, want %s", key.Algorithm, algorithm)
+			}
+			info, err := os.Stat(filepath.Join(dir, key.ID+".pem"))
+			if err != nil {
+				t.Fatalf("the generated key was not written: %v", err)
+			}
+			if info.Mode().Perm() != 0o600 {
+				t.Fatalf("key file mode %v, want 0600", info.Mode().Perm())
+			}
+
+			// Another instance sharing the directory signs with the same key.
+			if id := signingID(t, open(t, jwtkeys.Options{Dir: dir, Algorithm: algorithm}), time.Now()); id != key.ID {
+				t.Fatalf("the second instance signs with %s, want %s", id, key.ID)
+			}
+		})
+	}
+
+	if _, err := jwtkeys.Open(jwtkeys.Options{Dir: t.TempDir(), Algorithm: "HS256"}); err == nil {
+		t.Fatal("Open with HS256 succeeded")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeKey(t, dir, "old", newEd25519(t), now.Add(-2*time.Hour))
+	s := open(t, jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA, Rotation: time.Hour, Overlap: 30 * time.Minute})
+
+	current := signingID(t, s, time.Now())
+	if current == "old" {
+		t.Fatal("the key due for rotation still signs")
+	}
+	if got := ids(s.PublicKeys(time.Now())); !slices.Equal(got, []string{"old", current}) {
+		t.Fatalf("public keys %v within the overlap, want old and %s", got, current)
+	}
+	if _, ok := s.VerificationKey("old", time.Now()); !ok {
+		t.Fatal("the superseded key does not verify within the overlap")
+	}
+
+	later := time.Now().Add(31 * time.Minute)
+	if got := ids(s.PublicKeys(later)); !slices.Equal(got, []string{current}) {
+		t.Fatalf("public keys %v after the overlap, want %s", got, current)
+	}
+	if _, ok := s.VerificationKey("old", later); ok {
+		t.Fatal("the superseded key verifies after the overlap")
+	}
+	if _, ok := s.VerificationKey("unknown", time.Now()); ok {
+		t.Fatal("an unknown key verifies")
+	}
+
+	// A refresh before the next rotation is due keeps the key.
+	if err := s.Refresh(time.Now()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if id := signingID(t, s, time.Now()); id != current {
+		t.Fatalf("refresh rotated to %s before rotation was due", id)
+	}
+}
+
+func TestNoRotation(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "old", newEd25519(t), time.Now().Add(-24*time.Hour))
+	s := open(t, jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA})
+	if id := signingID(t, s, time.Now()); id != "old" {
+		t.Fatalf("signing with %s, want the old key without rotation", id)
+	}
+}
+
+// TestScheduledKey checks that a key dated in the future is published but
+// signs only once it takes effect.
+func TestScheduledKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeKey(t, dir, "current", newEd25519(t), now.Add(-time.Hour))
+	writeKey(t, dir, "scheduled", newECDSA(t, elliptic.P256()), now.Add(time.Hour))
+	s := open(t, jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA, Overlap: 30 * time.Minute})
+
+	if id := signingID(t, s, now); id != "current" {
+		t.Fatalf("signing with %s before the scheduled key takes effect", id)
+	}
+	if got := ids(s.PublicKeys(now)); !slices.Equal(got, []string{"current", "scheduled"}) {
+		t.Fatalf("public keys %v, want current and scheduled", got)
+	}
+
+	at := now.Add(time.Hour + time.Minute)
+	key, err := s.SigningKey(at)
+	if err != nil {
+		t.Fatalf("SigningKey: %v", err)
+	}
+	if key.ID != "scheduled" || key.Algorithm != jwtkeys.AlgorithmES256 {
+		t.Fatalf("signing with %s (%s) once the scheduled key took effect", key.ID, key.Algorithm)
+	}
+	if got := ids(s.PublicKeys(at.Add(30 * time.Minute))); !slices.Equal(got, []string{"scheduled"}) {
+		t.Fatalf("public keys %v after the overlap, want scheduled", got)
+	}
+}
+
+func TestRefreshKeepsKeysOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "current", newEd25519(t), time.Now().Add(-time.Hour))
+	s := open(t, jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA})
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(time.Now()); err == nil {
+		t.Fatal("Refresh with a broken key succeeded")
+	}
+	if id := signingID(t, s, time.Now()); id != "current" {
+		t.Fatalf("signing with %s after a failed refresh", id)
+	}
+}
+
+func TestUnsupportedKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, private := range map[string]any{
+		"RSA":   rsaKey,
+		"P-384": newECDSA(t, elliptic.P384()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKey(t, dir, "key", private, time.Now())
+			if _, err := jwtkeys.Open(jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA}); err == nil {
+				t.Fatal("Open succeeded")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeKey(t, dir, "ed", newEd25519(t), now.Add(-2*time.Hour))
+	writeKey(t, dir, "ec", newECDSA(t, elliptic.P256()), now.Add(-time.Hour))
+	s := open(t, jwtkeys.Options{Dir: dir, Algorithm: jwtkeys.AlgorithmEdDSA, Overlap: 2 * time.Hour})
+
+	jwks := s.JWKS(now)
+	if len(jwks) != 2 {
+		t.Fatalf("%d keys, want 2", len(jwks))
+	}
+	ed, ec := jwks[0], jwks[1]
+	if ed.KeyID != "ed" || ed.KeyType != "OKP" || ed.Curve != "Ed25519" || ed.Algorithm != jwtkeys.AlgorithmEdDSA || len(ed.X) != 43 || ed.Y != "" {
+		t.Fatalf("Ed25519 JWK %+v", ed)
+	}
+	if ec.KeyID != "ec" || ec.KeyType != "EC" || ec.Curve != "P-256" || ec.Algorithm != jwtkeys.AlgorithmES256 || len(ec.X) != 43 || len(ec.Y) != 43 {
+		t.Fatalf("P-256 JWK %+v", ec)
+	}
+}
@@ -0,0 +1,71 @@
+// Package logging configures structured logging of the server and carries
+// request metadata through the context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Request describes the HTTP request being served. Handlers fill in
+// Operation and UserID as they become known.
+type Request struct {
+	ID        string
+	RemoteIP  string
+	UserAgent string
+	Operation string
+	UserID    string
+}
+
+type contextKey struct{}
+
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+func RequestFromContext(ctx context.Context) (*Request, bool) {
+	req, ok := ctx.Value(contextKey{}).(*Request)
+	return req, ok
+}
+
+// New returns a logger that adds the request_id of the request in the
+// context to every record logged with one.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if req, ok := RequestFromContext(ctx); ok {
+		r.AddAttrs(slog.String("request_id", req.ID))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const meterName = "github.com/grnsv/GophKeeper/internal/server"
+
+// Metrics collects OpenTelemetry metrics of the server and exposes them
+// in the Prometheus text format.
+type Metrics struct {
+	provider     *sdkmetric.MeterProvider
+	registry     *prometheus.Registry
+	authFailures metric.Int64Counter
+	syncRecords  metric.Int64Counter
+	syncBytes    metric.Int64Counter
+}
+
+func New() (*Metrics, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metrics{
+		provider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter)),
+		registry: registry,
+	}
+	meter := m.provider.Meter(meterName)
+	if m.authFailures, err = meter.Int64Counter(
+		"gophkeeper.auth.failures",
+		metric.WithDescription("Rejected logins and bearer tokens."),
+		metric.WithUnit("{failure}"),
+	); err != nil {
+		return nil, err
+	}
+	if m.syncRecords, err = meter.Int64Counter(
+		"gophkeeper.sync.records",
+		metric.WithDescription("Records uploaded and downloaded by clients."),
+		metric.WithUnit("{record}"),
+	); err != nil {
+		return nil, err
+	}
+	if m.syncBytes, err = meter.Int64Counter(
+		"gophkeeper.sync.bytes",
+		metric.WithDescription("Encrypted record data uploaded and downloaded by clients."),
+		metric.WithUnit("By"),
+	); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// MeterProvider is meant for api.WithMeterProvider, which records
+// per-operation request counts, errors and latency.
+func (m *Metrics) MeterProvider() metric.MeterProvider {
+	return m.provider
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	return m.provider.Shutdown(ctx)
+}
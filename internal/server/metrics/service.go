@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	directionUpload   = metric.WithAttributes(attribute.String("direction", "upload"))
+	directionDownload = metric.WithAttributes(attribute.String("direction", "download"))
+)
+
+type service struct {
+	interfaces.Service
+	m *Metrics
+}
+
+// WrapService counts authentication failures and synchronized records of svc.
+func WrapService(svc interfaces.Service, m *Metrics) interfaces.Service {
+	return &service{Service: svc, m: m}
+}
+
+func (s *service) Login(ctx context.Context, login, password string) (string, error) {
+	token, err := s.Service.Login(ctx, login, password)
+	s.authFailed(ctx, "login", err)
+	return token, err
+}
+
+func (s *service) Authenticate(ctx context.Context, token string) (string, *models.AccessToken, error) {
+	userID, accessToken, err := s.Service.Authenticate(ctx, token)
+	method := "token"
+	if strings.HasPrefix(token, models.AccessTokenPrefix) {
+		method = "access_token"
+	}
+	s.authFailed(ctx, method, err)
+	return userID, accessToken, err
+}
+
+func (s *service) authFailed(ctx context.Context, method string, err error) {
+	var reason string
+	switch {
+	case errors.Is(err, interfaces.ErrUnauthorized):
+		reason = "unauthorized"
+	case errors.Is(err, interfaces.ErrAccountDisabled):
+		reason = "disabled"
+	default:
+		return
+	}
+	s.m.authFailures.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("reason", reason),
+	))
+}
+
+func (s *service) GetRecords(ctx context.Context, userID string) ([]*models.Record, error) {
+	records, err := s.Service.GetRecords(ctx, userID)
+	if err == nil {
+		s.synced(ctx, directionDownload, records...)
+	}
+	return records, err
+}
+
+func (s *service) SaveRecord(ctx context.Context, rec *models.Record) error {
+	err := s.Service.SaveRecord(ctx, rec)
+	if err == nil {
+		s.synced(ctx, directionUpload, rec)
+	}
+	return err
+}
+
+func (s *service) GetRecord(ctx context.Context, userID string, id uuid.UUID) (*models.Record, error) {
+	rec, err := s.Service.GetRecord(ctx, userID, id)
+	if err == nil {
+		s.synced(ctx, directionDownload, rec)
+	}
+	return rec, err
+}
+
+func (s *service) synced(ctx context.Context, direction metric.AddOption, records ...*models.Record) {
+	var size int64
+	for _, rec := range records {
+		size += int64(len(rec.Data))
+	}
+	s.m.syncRecords.Add(ctx, int64(len(records)), direction)
+	s.m.syncBytes.Add(ctx, size, direction)
+}
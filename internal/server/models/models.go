@@ -7,10 +7,86 @@ import (
 )
 
 type User struct {
-	ID           string
-	Login        string
-	PasswordHash string
-	CreatedAt    time.Time
+	ID                string
+	Login             string
+	PasswordHash      string
+	CreatedAt         time.Time
+	Disabled          bool
+	SessionsRevokedAt time.Time
+}
+
+type RegistrationMode string
+
+const (
+	RegistrationOpen       RegistrationMode = "open"
+	RegistrationInviteOnly RegistrationMode = "invite-only"
+	RegistrationClosed     RegistrationMode = "closed"
+)
+
+// Invite is a registration invite. Only the hash of its code is stored.
+// Zero MaxUses means unlimited uses, zero ExpiresAt means no expiry.
+type Invite struct {
+	ID        string
+	CodeHash  string
+	MaxUses   int
+	Uses      int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// JWK is a public key in the JSON Web Key form (RFC 7517). Y is empty for
+// Ed25519 keys.
+type JWK struct {
+	KeyID     string
+	KeyType   string
+	Algorithm string
+	Curve     string
+	X         string
+	Y         string
+}
+
+type AuditEventType string
+
+const (
+	AuditLogin            AuditEventType = "login"
+	AuditLoginFailed      AuditEventType = "login_failed"
+	AuditRegister         AuditEventType = "register"
+	AuditRecordDelete     AuditEventType = "record_delete"
+	AuditTokenIssued      AuditEventType = "token_issued"
+	AuditTokenCreated     AuditEventType = "access_token_created"
+	AuditTokenRevoked     AuditEventType = "access_token_revoked"
+	AuditShareCreated     AuditEventType = "record_shared"
+	AuditShareRevoked     AuditEventType = "record_share_revoked"
+	AuditOrgCreated       AuditEventType = "org_created"
+	AuditOrgDeleted       AuditEventType = "org_deleted"
+	AuditOrgMemberAdded   AuditEventType = "org_member_added"
+	AuditOrgRoleChanged   AuditEventType = "org_member_role_changed"
+	AuditOrgMemberRemoved AuditEventType = "org_member_removed"
+	AuditOrgRekeyed       AuditEventType = "org_rekeyed"
+	// The emergency access events are logged for the grantor, the requests
+	// and vault reads also for the contact.
+	AuditEmergencyAdded     AuditEventType = "emergency_contact_added"
+	AuditEmergencyRemoved   AuditEventType = "emergency_contact_removed"
+	AuditEmergencyRequested AuditEventType = "emergency_access_requested"
+	AuditEmergencyApproved  AuditEventType = "emergency_access_approved"
+	AuditEmergencyRejected  AuditEventType = "emergency_access_rejected"
+	AuditEmergencyAccessed  AuditEventType = "emergency_vault_accessed"
+	// The send events are logged for the creator of the send.
+	AuditSendCreated AuditEventType = "send_created"
+	AuditSendOpened  AuditEventType = "send_opened"
+)
+
+// AuditEvent is a security relevant action. UserID is empty for
+// failed logins with an unknown login.
+type AuditEvent struct {
+	ID        int64
+	UserID    string
+	Type      AuditEventType
+	Details   string
+	IP        string
+	UserAgent string
+	RequestID string
+	CreatedAt time.Time
 }
 
 type Record struct {
@@ -20,4 +96,202 @@ type Record struct {
 	Data    []byte
 	Nonce   []byte
 	Version int
+	// Key is the record key wrapped by the client, empty for records
+	// encrypted directly with the vault key.
+	Key []byte
+}
+
+type RecordStats struct {
+	Count int
+	Bytes int64
+}
+
+// AccessTokenPrefix starts personal access tokens, telling them from session tokens.
+const AccessTokenPrefix = "gkp_"
+
+// AccessToken is a personal access token for automation. Only the hash of
+// its secret is stored. The key part of the token never reaches the server,
+// it unwraps WrappedKey or the keys in Records on the client.
+type AccessToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	SecretHash string
+	ReadOnly   bool
+	// Records limits the token to these records and holds their record keys
+	// wrapped for the token. Nil means all records.
+	Records map[uuid.UUID][]byte
+	// Tags the records were selected by. They are informational, the client
+	// resolves them to Records as the server cannot read them.
+	Tags []string
+	// WrappedKey is the vault key wrapped for a token not limited to records.
+	WrappedKey []byte
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+func (t *AccessToken) CanRead(id uuid.UUID) bool {
+	if t.Records == nil {
+		return true
+	}
+	_, ok := t.Records[id]
+	return ok
+}
+
+func (t *AccessToken) CanWrite(id uuid.UUID) bool {
+	return !t.ReadOnly && t.CanRead(id)
+}
+
+// UserKeys is the X25519 key pair records are shared with. The private key
+// is wrapped by the client with the vault key of the user.
+type UserKeys struct {
+	UserID            string
+	PublicKey         []byte
+	WrappedPrivateKey []byte
+	CreatedAt         time.Time
+}
+
+// Share gives the recipient read access to a record of the owner. Key is
+// the record key wrapped for the public key of the recipient.
+type Share struct {
+	RecordID       uuid.UUID
+	OwnerID        string
+	RecipientID    string
+	RecipientLogin string
+	Key            []byte
+	CreatedAt      time.Time
+}
+
+// SharedRecord is a record shared with a user. Its Key is the record key
+// wrapped for that user, not the one of the owner.
+type SharedRecord struct {
+	Record
+	OwnerLogin string
+	SharedAt   time.Time
+}
+
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleWriter OrgRole = "writer"
+	OrgRoleReader OrgRole = "reader"
+)
+
+func (r OrgRole) Valid() bool {
+	switch r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleWriter, OrgRoleReader:
+		return true
+	}
+	return false
+}
+
+// CanWrite tells whether the role may change records.
+func (r OrgRole) CanWrite() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin || r == OrgRoleWriter
+}
+
+// CanManage tells whether the role may invite and remove members and re-key.
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+// Organization is a team vault. Its records are encrypted with the
+// organization key, which every member holds wrapped for their public key.
+// Role and Key are those of the member the organization was read for.
+type Organization struct {
+	ID   uuid.UUID
+	Name string
+	// KeyVersion is incremented on every re-key.
+	KeyVersion int64
+	// RekeyNeeded is set when a member leaves, a re-key clears it.
+	RekeyNeeded bool
+	CreatedAt   time.Time
+	Role        OrgRole
+	Key         []byte
+}
+
+// OrgMember is a member of an organization. Key is the organization key
+// wrapped for PublicKey.
+type OrgMember struct {
+	OrgID     uuid.UUID
+	UserID    string
+	Login     string
+	Role      OrgRole
+	Key       []byte
+	PublicKey []byte
+	CreatedAt time.Time
+}
+
+type OrgRecord struct {
+	ID      uuid.UUID
+	OrgID   uuid.UUID
+	Type    string
+	Data    []byte
+	Nonce   []byte
+	Version int
+}
+
+type EmergencyStatus string
+
+const (
+	// EmergencyGranted is a trusted contact who has not requested access.
+	EmergencyGranted   EmergencyStatus = "granted"
+	EmergencyRequested EmergencyStatus = "requested"
+	EmergencyApproved  EmergencyStatus = "approved"
+)
+
+// EmergencyAccess makes a user the trusted contact of the grantor. Key is
+// the vault key of the grantor wrapped for the public key of the contact,
+// it is only handed out once access is approved.
+type EmergencyAccess struct {
+	ID           uuid.UUID
+	GrantorID    string
+	GrantorLogin string
+	GranteeID    string
+	GranteeLogin string
+	WaitDays     int
+	Status       EmergencyStatus
+	Key          []byte
+	// RequestedAt is zero unless access was requested.
+	RequestedAt time.Time
+	CreatedAt   time.Time
+}
+
+// AvailableAt returns when a request is approved unless the grantor
+// rejects it, zero if access was not requested.
+func (a *EmergencyAccess) AvailableAt() time.Time {
+	if a.RequestedAt.IsZero() {
+		return time.Time{}
+	}
+	return a.RequestedAt.AddDate(0, 0, a.WaitDays)
+}
+
+// Approved tells whether the contact may read the vault at now: the grantor
+// approved the request or the waiting period is over.
+func (a *EmergencyAccess) Approved(now time.Time) bool {
+	switch a.Status {
+	case EmergencyApproved:
+		return true
+	case EmergencyRequested:
+		return !now.Before(a.AvailableAt())
+	}
+	return false
+}
+
+// Send is a one-time secret for someone without an account. Data is
+// encrypted with a key the server never sees. An empty PasswordHash means
+// the send is not password protected.
+type Send struct {
+	ID           uuid.UUID
+	UserID       string
+	Data         []byte
+	Nonce        []byte
+	PasswordHash string
+	MaxViews     int
+	ViewsLeft    int
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
 }
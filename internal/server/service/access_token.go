@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+const accessTokenSecretBytes = 32
+
+// CreateAccessToken checks the scope of the token, stores it and returns
+// "gkp_<id>_<secret>". The client appends the key part it wrapped the keys
+// of the token with.
+func (s *Service) CreateAccessToken(ctx context.Context, token *models.AccessToken) (string, error) {
+	if err := s.validateAccessToken(ctx, token); err != nil {
+		return "", err
+	}
+
+	id := uuid.New()
+	secret := make([]byte, accessTokenSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token.ID = id.String()
+	token.SecretHash = hashAccessTokenSecret(hex.EncodeToString(secret))
+	if err := s.storage.CreateAccessToken(ctx, token); err != nil {
+		return "", err
+	}
+	s.audit(ctx, models.AuditTokenCreated, token.UserID, fmt.Sprintf("access token %q (%s)", token.Name, token.ID))
+
+	return models.AccessTokenPrefix + hex.EncodeToString(id[:]) + "_" + hex.EncodeToString(secret), nil
+}
+
+func (s *Service) validateAccessToken(ctx context.Context, token *models.AccessToken) error {
+	switch {
+	case strings.TrimSpace(token.Name) == "":
+		return fmt.Errorf("%w: name is required", interfaces.ErrInvalidAccessToken)
+	case !token.ExpiresAt.After(time.Now()):
+		return fmt.Errorf("%w: expiry must be in the future", interfaces.ErrInvalidAccessToken)
+	case token.Records == nil && len(token.WrappedKey) == 0:
+		return fmt.Errorf("%w: a token for all records needs the wrapped vault key", interfaces.ErrInvalidAccessToken)
+	case token.Records != nil && len(token.Records) == 0:
+		return fmt.Errorf("%w: no records selected", interfaces.ErrInvalidAccessToken)
+	case token.Records != nil && len(token.WrappedKey) > 0:
+		return fmt.Errorf("%w: a token limited to records must not carry the vault key", interfaces.ErrInvalidAccessToken)
+	}
+	for id, key := range token.Records {
+		if len(key) == 0 {
+			return fmt.Errorf("%w: no key for record %s", interfaces.ErrInvalidAccessToken, id)
+		}
+		if _, err := s.storage.GetRecord(ctx, token.UserID, id); err != nil {
+			if errors.Is(err, interfaces.ErrNotFound) {
+				return fmt.Errorf("%w: record %s not found", interfaces.ErrInvalidAccessToken, id)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) ListAccessTokens(ctx context.Context, userID string) ([]*models.AccessToken, error) {
+	return s.storage.ListAccessTokens(ctx, userID)
+}
+
+func (s *Service) RevokeAccessToken(ctx context.Context, userID, id string) error {
+	if err := s.storage.DeleteAccessToken(ctx, userID, id); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditTokenRevoked, userID, "access token "+id)
+	return nil
+}
+
+// authenticateAccessToken checks a "gkp_<id>_<secret>" token. The key part
+// must not be sent, it is what keeps the wrapped keys away from the server.
+func (s *Service) authenticateAccessToken(ctx context.Context, token string) (*models.AccessToken, error) {
+	parts := strings.Split(strings.TrimPrefix(token, models.AccessTokenPrefix), "_")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: malformed access token, it must be sent without its key part", interfaces.ErrUnauthorized)
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed access token", interfaces.ErrUnauthorized)
+	}
+
+	accessToken, err := s.storage.FindAccessToken(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return nil, interfaces.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAccessTokenSecret(parts[1])), []byte(accessToken.SecretHash)) != 1 {
+		return nil, interfaces.ErrUnauthorized
+	}
+	now := time.Now()
+	if !now.Before(accessToken.ExpiresAt) {
+		return nil, fmt.Errorf("%w: access token expired", interfaces.ErrUnauthorized)
+	}
+	if err = s.storage.TouchAccessToken(ctx, accessToken.ID, now); err != nil {
+		slog.ErrorContext(ctx, "Failed to record access token use", "error", err)
+	}
+	return accessToken, nil
+}
+
+// hashAccessTokenSecret needs no salt or stretching, the secret is random.
+func hashAccessTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/grnsv/GophKeeper/internal/server/logging"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+// audit records a security event. It is logged even if it cannot be stored,
+// a failure to store it does not fail the request.
+func (s *Service) audit(ctx context.Context, eventType models.AuditEventType, userID, details string) {
+	event := &models.AuditEvent{
+		UserID:  userID,
+		Type:    eventType,
+		Details: details,
+	}
+	if req, ok := logging.RequestFromContext(ctx); ok {
+		event.IP = req.RemoteIP
+		event.UserAgent = req.UserAgent
+		event.RequestID = req.ID
+	}
+
+	slog.InfoContext(ctx, "Audit event",
+		slog.String("event", string(event.Type)),
+		slog.String("user_id", event.UserID),
+		slog.String("details", event.Details),
+		slog.String("remote_ip", event.IP),
+	)
+	if err := s.storage.CreateAuditEvent(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "Failed to store audit event", "event", event.Type, "error", err)
+	}
+}
+
+// GetAuditEvents returns the latest audit events of the user, newest first.
+func (s *Service) GetAuditEvents(ctx context.Context, userID string, limit int) ([]*models.AuditEvent, error) {
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+	return s.storage.ListAuditEvents(ctx, userID, min(limit, MaxAuditLimit))
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+// MaxEmergencyWaitDays is the longest waiting period of an emergency access.
+const MaxEmergencyWaitDays = 90
+
+// AddEmergencyContact makes the user with the login a trusted contact of the
+// grantor. The wrapped vault key is stored right away but only handed out
+// once access is approved.
+func (s *Service) AddEmergencyContact(ctx context.Context, access *models.EmergencyAccess, granteeLogin string) error {
+	switch {
+	case access.WaitDays < 1 || access.WaitDays > MaxEmergencyWaitDays:
+		return fmt.Errorf("%w: waiting period must be 1 to %d days", interfaces.ErrInvalidEmergency, MaxEmergencyWaitDays)
+	case len(access.Key) == 0:
+		return fmt.Errorf("%w: key is required", interfaces.ErrInvalidEmergency)
+	}
+	grantee, err := s.storage.FindUserByLogin(ctx, granteeLogin)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: user %q not found", interfaces.ErrInvalidEmergency, granteeLogin)
+		}
+		return err
+	}
+	if grantee.ID == access.GrantorID {
+		return fmt.Errorf("%w: a user cannot be their own trusted contact", interfaces.ErrInvalidEmergency)
+	}
+	if _, err = s.storage.GetUserKeys(ctx, grantee.ID); err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: user %q has no key pair yet", interfaces.ErrInvalidEmergency, granteeLogin)
+		}
+		return err
+	}
+
+	access.ID = uuid.New()
+	access.GranteeID = grantee.ID
+	access.GranteeLogin = grantee.Login
+	access.Status = models.EmergencyGranted
+	if err = s.storage.CreateEmergencyAccess(ctx, access); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEmergencyAdded, access.GrantorID, fmt.Sprintf("%s with a waiting period of %d days", grantee.Login, access.WaitDays))
+	return nil
+}
+
+func (s *Service) ListEmergencyContacts(ctx context.Context, grantorID string) ([]*models.EmergencyAccess, error) {
+	accesses, err := s.storage.ListEmergencyContacts(ctx, grantorID)
+	if err != nil {
+		return nil, err
+	}
+	return withEffectiveStatus(accesses), nil
+}
+
+func (s *Service) RemoveEmergencyContact(ctx context.Context, grantorID string, id uuid.UUID) error {
+	access, err := s.grantorEmergencyAccess(ctx, grantorID, id)
+	if err != nil {
+		return err
+	}
+	if err = s.storage.DeleteEmergencyAccess(ctx, grantorID, id); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEmergencyRemoved, grantorID, access.GranteeLogin)
+	return nil
+}
+
+func (s *Service) ApproveEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error {
+	access, err := s.grantorEmergencyAccess(ctx, grantorID, id)
+	if err != nil {
+		return err
+	}
+	err = s.storage.SetEmergencyStatus(ctx, id,
+		[]models.EmergencyStatus{models.EmergencyRequested}, models.EmergencyApproved, access.RequestedAt)
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEmergencyApproved, grantorID, access.GranteeLogin)
+	return nil
+}
+
+func (s *Service) RejectEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error {
+	access, err := s.grantorEmergencyAccess(ctx, grantorID, id)
+	if err != nil {
+		return err
+	}
+	err = s.storage.SetEmergencyStatus(ctx, id,
+		[]models.EmergencyStatus{models.EmergencyRequested, models.EmergencyApproved}, models.EmergencyGranted, time.Time{})
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEmergencyRejected, grantorID, access.GranteeLogin)
+	return nil
+}
+
+// grantorEmergencyAccess returns the access if the user is its grantor.
+func (s *Service) grantorEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) (*models.EmergencyAccess, error) {
+	access, err := s.storage.GetEmergencyAccess(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if access.GrantorID != grantorID {
+		return nil, interfaces.ErrNotFound
+	}
+	return access, nil
+}
+
+// ListEmergencyGrants returns the accesses granted to the user without the
+// wrapped keys.
+func (s *Service) ListEmergencyGrants(ctx context.Context, granteeID string) ([]*models.EmergencyAccess, error) {
+	accesses, err := s.storage.ListEmergencyGrants(ctx, granteeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, access := range accesses {
+		access.Key = nil
+	}
+	return withEffectiveStatus(accesses), nil
+}
+
+// RequestEmergencyAccess starts the waiting period. The request is logged
+// for the grantor too, so they can reject it in time.
+func (s *Service) RequestEmergencyAccess(ctx context.Context, granteeID string, id uuid.UUID) error {
+	access, err := s.granteeEmergencyAccess(ctx, granteeID, id)
+	if err != nil {
+		return err
+	}
+	err = s.storage.SetEmergencyStatus(ctx, id,
+		[]models.EmergencyStatus{models.EmergencyGranted}, models.EmergencyRequested, time.Now())
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditEmergencyRequested, granteeID, fmt.Sprintf("to the vault of %s", access.GrantorLogin))
+	s.audit(ctx, models.AuditEmergencyRequested, access.GrantorID, fmt.Sprintf("by %s, available in %d days unless rejected", access.GranteeLogin, access.WaitDays))
+	return nil
+}
+
+func (s *Service) GetEmergencyVault(ctx context.Context, granteeID string, id uuid.UUID) (*models.EmergencyAccess, []*models.Record, error) {
+	access, err := s.granteeEmergencyAccess(ctx, granteeID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !access.Approved(time.Now()) {
+		return nil, nil, interfaces.ErrForbidden
+	}
+	records, err := s.storage.GetRecords(ctx, access.GrantorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.audit(ctx, models.AuditEmergencyAccessed, granteeID, fmt.Sprintf("vault of %s", access.GrantorLogin))
+	s.audit(ctx, models.AuditEmergencyAccessed, access.GrantorID, fmt.Sprintf("by %s", access.GranteeLogin))
+	return access, records, nil
+}
+
+// granteeEmergencyAccess returns the access if it was granted to the user.
+func (s *Service) granteeEmergencyAccess(ctx context.Context, granteeID string, id uuid.UUID) (*models.EmergencyAccess, error) {
+	access, err := s.storage.GetEmergencyAccess(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if access.GranteeID != granteeID {
+		return nil, interfaces.ErrNotFound
+	}
+	return access, nil
+}
+
+// withEffectiveStatus reports requests whose waiting period is over as approved.
+func withEffectiveStatus(accesses []*models.EmergencyAccess) []*models.EmergencyAccess {
+	now := time.Now()
+	for _, access := range accesses {
+		if access.Approved(now) {
+			access.Status = models.EmergencyApproved
+		}
+	}
+	return accesses
+}
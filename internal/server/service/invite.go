@@ -0,0 +1,18 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// NewInviteCode generates a random invite code to be handed out to a new user.
+func NewInviteCode() string {
+	return rand.Text()
+}
+
+// HashInviteCode returns the form in which invite codes are stored.
+func HashInviteCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,13 +1,20 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/jwtkeys"
+	"github.com/grnsv/GophKeeper/internal/server/models"
 )
 
+// TokenLifetime is how long an issued token is valid.
+const TokenLifetime = time.Hour
+
+// JWTService signs tokens with HS256 and a shared secret.
 type JWTService struct {
 	secret        []byte
 	signingMethod jwt.SigningMethod
@@ -21,31 +28,88 @@ func NewJWTService(secret string) interfaces.JWTService {
 }
 
 func (s *JWTService) BuildJWT(userID string) (string, error) {
-	now := jwt.NewNumericDate(time.Now())
-	token := jwt.NewWithClaims(s.signingMethod, jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
-		NotBefore: now,
-		IssuedAt:  now,
-	})
+	token := jwt.NewWithClaims(s.signingMethod, newClaims(userID))
 
 	return token.SignedString(s.secret)
 }
 
-func (s *JWTService) ParseJWT(token string) (string, error) {
-	claims := &jwt.RegisteredClaims{}
-	jwtToken, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+func (s *JWTService) ParseJWT(token string) (string, time.Time, error) {
+	return parseJWT(token, func(t *jwt.Token) (any, error) {
 		if t.Method == nil || t.Method.Alg() != s.signingMethod.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
 		return s.secret, nil
 	})
+}
+
+func (s *JWTService) PublicKeys() []models.JWK {
+	return nil
+}
+
+// KeySetJWTService signs tokens with the current key of a key set and
+// names it in the kid header, so tokens signed with a previous key can be
+// verified during the overlap window.
+type KeySetJWTService struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewKeySetJWTService(keys *jwtkeys.KeySet) interfaces.JWTService {
+	return &KeySetJWTService{keys: keys}
+}
+
+func (s *KeySetJWTService) BuildJWT(userID string) (string, error) {
+	key, err := s.keys.SigningKey(time.Now())
 	if err != nil {
 		return "", err
 	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), newClaims(userID))
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+func (s *KeySetJWTService) ParseJWT(token string) (string, time.Time, error) {
+	return parseJWT(token, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := s.keys.VerificationKey(kid, time.Now())
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		if t.Method == nil || t.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return key.Public, nil
+	})
+}
+
+func (s *KeySetJWTService) PublicKeys() []models.JWK {
+	return s.keys.JWKS(time.Now())
+}
+
+func newClaims(userID string) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Subject:   userID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(TokenLifetime)),
+		NotBefore: jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+}
+
+func parseJWT(token string, keyFunc jwt.Keyfunc) (string, time.Time, error) {
+	claims := &jwt.RegisteredClaims{}
+	jwtToken, err := jwt.ParseWithClaims(token, claims, keyFunc)
+	if err != nil {
+		return "", time.Time{}, err
+	}
 	if !jwtToken.Valid {
-		return "", fmt.Errorf("token is not valid: %v", token)
+		return "", time.Time{}, errors.New("token is not valid")
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
 	}
 
-	return claims.Subject, nil
+	return claims.Subject, issuedAt, nil
 }
@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/jwtkeys"
+	"github.com/grnsv/GophKeeper/internal/server/service"
+)
+
+// writeKey writes a PKCS #8 key to dir as ID.pem, taking effect at createdAt.
+func writeKey(t *testing.T, dir, id string, private any, createdAt time.Time) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	path := filepath.Join(dir, id+".pem")
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chtimes(path, createdAt, createdAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// keys holds an Ed25519 key that took effect two hours ago and an ECDSA P-256
+// key that superseded it an hour ago.
+type keys struct {
+	dir string
+	ed  ed25519.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newKeys(t *testing.T) keys {
+	t.Helper()
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := keys{dir: t.TempDir(), ed: ed, ec: ec}
+	writeKey(t, k.dir, "ed", ed, time.Now().Add(-2*time.Hour))
+	writeKey(t, k.dir, "ec", ec, time.Now().Add(-time.Hour))
+	return k
+}
+
+func (k keys) service(t *testing.T, overlap time.Duration) interfaces.JWTService {
+	t.Helper()
+	set, err := jwtkeys.Open(jwtkeys.Options{Dir: k.dir, Algorithm: jwtkeys.AlgorithmES256, Overlap: overlap})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return service.NewKeySetJWTService(set)
+}
+
+// sign returns a token of the user signed with the method and key, naming
+// kid unless it is empty.
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Subject:   "user",
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestKeySetJWTService(t *testing.T) {
+	k := newKeys(t)
+	s := k.service(t, 2*time.Hour)
+
+	token, err := s.BuildJWT("user")
+	if err != nil {
+		t.Fatalf("BuildJWT: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if parsed.Header["kid"] != "ec" || parsed.Method.Alg() != jwtkeys.AlgorithmES256 {
+		t.Fatalf("token signed with %v (%s), want the newest key", parsed.Header["kid"], parsed.Method.Alg())
+	}
+	userID, issuedAt, err := s.ParseJWT(token)
+	if err != nil {
+		t.Fatalf("ParseJWT: %v", err)
+	}
+	if userID != "user" || time.Since(issuedAt) > time.Minute {
+		t.Fatalf("ParseJWT returned %q issued at %v", userID, issuedAt)
+	}
+
+	// A token of the superseded key is valid within the overlap window.
+	if _, _, err = s.ParseJWT(sign(t, jwt.SigningMethodEdDSA, k.ed, "ed")); err != nil {
+		t.Fatalf("ParseJWT of a token of the superseded key: %v", err)
+	}
+	if len(s.PublicKeys()) != 2 {
+		t.Fatalf("%d public keys within the overlap, want 2", len(s.PublicKeys()))
+	}
+}
+
+func TestKeySetJWTServiceRejects(t *testing.T) {
+	k := newKeys(t)
+	s := k.service(t, 30*time.Minute)
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		// The Ed25519 key was superseded an hour ago, beyond the overlap.
+		"retired key": sign(t, jwt.SigningMethodEdDSA, k.ed, "ed"),
+		"unknown kid": sign(t, jwt.SigningMethodEdDSA, other, "other"),
+		"no kid":      sign(t, jwt.SigningMethodES256, k.ec, ""),
+		"wrong key":   sign(t, jwt.SigningMethodEdDSA, other, "ec"),
+		// The algorithm must be the one of the key named by kid, whatever
+		// the token claims.
+		"HS256 with the public key": sign(t, jwt.SigningMethodHS256, []byte("secret"), "ec"),
+		"algorithm of another key":  sign(t, jwt.SigningMethodES384, newP384(t), "ec"),
+		"none": func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{Subject: "user"})
+			token.Header["kid"] = "ec"
+			signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return signed
+		}(),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if userID, _, err := s.ParseJWT(token); err == nil {
+				t.Fatalf("ParseJWT accepted the token of %q", userID)
+			}
+		})
+	}
+
+	// The algorithm is checked before the signature.
+	for _, token := range []string{tests["HS256 with the public key"], tests["algorithm of another key"]} {
+		if _, _, err = s.ParseJWT(token); err == nil || !strings.Contains(err.Error(), "unexpected signing method") {
+			t.Fatalf("ParseJWT returned %v, want an unexpected signing method", err)
+		}
+	}
+}
+
+func newP384(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
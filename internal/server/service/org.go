@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+// CreateOrg creates an organization with the user as its owner. org.Key is
+// the organization key wrapped for the public key of the user.
+func (s *Service) CreateOrg(ctx context.Context, userID string, org *models.Organization) error {
+	org.Name = strings.TrimSpace(org.Name)
+	switch {
+	case org.Name == "":
+		return fmt.Errorf("%w: name is required", interfaces.ErrInvalidOrg)
+	case len(org.Key) == 0:
+		return fmt.Errorf("%w: key is required", interfaces.ErrInvalidOrg)
+	}
+	if _, err := s.storage.GetUserKeys(ctx, userID); err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: publish a key pair first", interfaces.ErrInvalidOrg)
+		}
+		return err
+	}
+
+	org.ID = uuid.New()
+	org.Role = models.OrgRoleOwner
+	owner := &models.OrgMember{UserID: userID, Role: org.Role, Key: org.Key}
+	if err := s.storage.CreateOrg(ctx, org, owner); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgCreated, userID, fmt.Sprintf("organization %q (%s)", org.Name, org.ID))
+	return nil
+}
+
+func (s *Service) ListOrgs(ctx context.Context, userID string) ([]*models.Organization, error) {
+	return s.storage.ListOrgs(ctx, userID)
+}
+
+func (s *Service) DeleteOrg(ctx context.Context, userID string, orgID uuid.UUID) error {
+	org, err := s.storage.GetOrg(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if org.Role != models.OrgRoleOwner {
+		return interfaces.ErrForbidden
+	}
+	if err = s.storage.DeleteOrg(ctx, orgID); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgDeleted, userID, fmt.Sprintf("organization %q (%s)", org.Name, org.ID))
+	return nil
+}
+
+func (s *Service) ListOrgMembers(ctx context.Context, userID string, orgID uuid.UUID) ([]*models.OrgMember, error) {
+	if _, err := s.storage.GetOrg(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.storage.ListOrgMembers(ctx, orgID)
+}
+
+// InviteOrgMember adds a user to the organization right away, the client
+// already wrapped the organization key for them. Only owners may add owners.
+func (s *Service) InviteOrgMember(ctx context.Context, userID string, member *models.OrgMember, login string, keyVersion int64) error {
+	org, err := s.storage.GetOrg(ctx, member.OrgID, userID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !org.Role.CanManage():
+		return interfaces.ErrForbidden
+	case !member.Role.Valid():
+		return fmt.Errorf("%w: unknown role %q", interfaces.ErrInvalidOrg, member.Role)
+	case member.Role == models.OrgRoleOwner && org.Role != models.OrgRoleOwner:
+		return interfaces.ErrForbidden
+	case len(member.Key) == 0:
+		return fmt.Errorf("%w: key is required", interfaces.ErrInvalidOrg)
+	}
+
+	user, err := s.storage.FindUserByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: user %q not found", interfaces.ErrInvalidOrg, login)
+		}
+		return err
+	}
+	keys, err := s.storage.GetUserKeys(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: user %q has no key pair yet", interfaces.ErrInvalidOrg, login)
+		}
+		return err
+	}
+	member.UserID = user.ID
+	member.Login = user.Login
+	member.PublicKey = keys.PublicKey
+	if err = s.storage.AddOrgMember(ctx, member, keyVersion); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgMemberAdded, userID, fmt.Sprintf("%s as %s to organization %q (%s)", user.Login, member.Role, org.Name, org.ID))
+	return nil
+}
+
+// SetOrgMemberRole changes the role of a member. Only owners may change the
+// role of owners or make others owners, and the last owner stays one.
+func (s *Service) SetOrgMemberRole(ctx context.Context, userID string, orgID uuid.UUID, memberID string, role models.OrgRole) error {
+	org, err := s.storage.GetOrg(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !org.Role.CanManage() {
+		return interfaces.ErrForbidden
+	}
+	if !role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", interfaces.ErrInvalidOrg, role)
+	}
+	member, err := s.storage.GetOrgMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if (member.Role == models.OrgRoleOwner || role == models.OrgRoleOwner) && org.Role != models.OrgRoleOwner {
+		return interfaces.ErrForbidden
+	}
+	if err = s.storage.SetOrgMemberRole(ctx, orgID, memberID, role); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgRoleChanged, userID, fmt.Sprintf("%s from %s to %s in organization %q (%s)", member.Login, member.Role, role, org.Name, org.ID))
+	return nil
+}
+
+// RemoveOrgMember removes a member. Owners and admins may remove others,
+// only owners may remove owners and the last owner cannot leave. The removed
+// member may have kept the organization key, so the organization is marked
+// as needing a re-key.
+func (s *Service) RemoveOrgMember(ctx context.Context, userID string, orgID uuid.UUID, memberID string) error {
+	org, err := s.storage.GetOrg(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	member, err := s.storage.GetOrgMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if memberID != userID {
+		if !org.Role.CanManage() || member.Role == models.OrgRoleOwner && org.Role != models.OrgRoleOwner {
+			return interfaces.ErrForbidden
+		}
+	}
+	if err = s.storage.DeleteOrgMember(ctx, orgID, memberID); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgMemberRemoved, userID, fmt.Sprintf("%s from organization %q (%s)", member.Login, org.Name, org.ID))
+	return nil
+}
+
+// RekeyOrg replaces the organization key. keys must hold the new key wrapped
+// for every member and records every record encrypted with it.
+func (s *Service) RekeyOrg(ctx context.Context, userID string, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) error {
+	org, err := s.storage.GetOrg(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !org.Role.CanManage() {
+		return interfaces.ErrForbidden
+	}
+	seen := make(map[uuid.UUID]bool, len(records))
+	for _, rec := range records {
+		if seen[rec.ID] {
+			return fmt.Errorf("%w: record %s is given twice", interfaces.ErrInvalidOrg, rec.ID)
+		}
+		seen[rec.ID] = true
+		rec.OrgID = orgID
+	}
+	if err = s.storage.RekeyOrg(ctx, orgID, keyVersion, keys, records); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditOrgRekeyed, userID, fmt.Sprintf("organization %q (%s) to key version %d", org.Name, org.ID, keyVersion))
+	return nil
+}
+
+func (s *Service) GetOrgRecords(ctx context.Context, userID string, orgID uuid.UUID) ([]*models.OrgRecord, error) {
+	if _, err := s.storage.GetOrg(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.storage.GetOrgRecords(ctx, orgID)
+}
+
+func (s *Service) SaveOrgRecord(ctx context.Context, userID string, rec *models.OrgRecord, keyVersion int64) error {
+	if err := s.checkOrgWrite(ctx, userID, rec.OrgID); err != nil {
+		return err
+	}
+	return s.storage.SaveOrgRecord(ctx, rec, keyVersion)
+}
+
+func (s *Service) DeleteOrgRecord(ctx context.Context, userID string, orgID, id uuid.UUID) error {
+	if err := s.checkOrgWrite(ctx, userID, orgID); err != nil {
+		return err
+	}
+	return s.storage.DeleteOrgRecord(ctx, orgID, id)
+}
+
+func (s *Service) checkOrgWrite(ctx context.Context, userID string, orgID uuid.UUID) error {
+	org, err := s.storage.GetOrg(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !org.Role.CanWrite() {
+		return interfaces.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alexedwards/argon2id"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/grnsv/GophKeeper/internal/server/service")
+
+// hashPassword and comparePassword trace argon2id separately, it dominates the latency of logins.
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracer.Start(ctx, "argon2id.CreateHash")
+	defer span.End()
+	return argon2id.CreateHash(password, argon2id.DefaultParams)
+}
+
+func comparePassword(ctx context.Context, password, hash string) (bool, error) {
+	_, span := tracer.Start(ctx, "argon2id.ComparePasswordAndHash")
+	defer span.End()
+	return argon2id.ComparePasswordAndHash(password, hash)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+const (
+	// MaxSendViews is the largest view limit of a send.
+	MaxSendViews = 100
+	// MaxSendLifetime is how far ahead a send may expire.
+	MaxSendLifetime = 30 * 24 * time.Hour
+)
+
+func (s *Service) CreateSend(ctx context.Context, send *models.Send, password string) error {
+	now := time.Now()
+	switch {
+	case len(send.Data) == 0 || len(send.Nonce) == 0:
+		return fmt.Errorf("%w: data and nonce are required", interfaces.ErrInvalidSend)
+	case send.MaxViews < 1 || send.MaxViews > MaxSendViews:
+		return fmt.Errorf("%w: view limit must be 1 to %d", interfaces.ErrInvalidSend, MaxSendViews)
+	case !send.ExpiresAt.After(now) || send.ExpiresAt.After(now.Add(MaxSendLifetime)):
+		return fmt.Errorf("%w: expiry must be within %s", interfaces.ErrInvalidSend, MaxSendLifetime)
+	}
+
+	// Expired sends are purged here, nothing else writes the table.
+	if err := s.storage.DeleteExpiredSends(ctx, now); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete expired sends", "error", err)
+	}
+
+	if password != "" {
+		hash, err := hashPassword(ctx, password)
+		if err != nil {
+			return err
+		}
+		send.PasswordHash = hash
+	}
+	send.ID = uuid.New()
+	send.ViewsLeft = send.MaxViews
+	if err := s.storage.CreateSend(ctx, send); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditSendCreated, send.UserID, fmt.Sprintf("%s, %d views until %s", send.ID, send.MaxViews, send.ExpiresAt.UTC().Format(time.RFC3339)))
+	return nil
+}
+
+// OpenSend checks the password before counting the view, so a wrong
+// password does not use up the send.
+func (s *Service) OpenSend(ctx context.Context, id uuid.UUID, password string) (*models.Send, error) {
+	send, err := s.storage.GetSend(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if send.PasswordHash != "" {
+		match, err := comparePassword(ctx, password, send.PasswordHash)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			return nil, interfaces.ErrForbidden
+		}
+	}
+
+	if send, err = s.storage.UseSend(ctx, id, time.Now()); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, models.AuditSendOpened, send.UserID, fmt.Sprintf("%s, %d views left", send.ID, send.ViewsLeft))
+	return send, nil
+}
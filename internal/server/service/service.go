@@ -3,26 +3,30 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
-	"github.com/alexedwards/argon2id"
 	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/logging"
 	"github.com/grnsv/GophKeeper/internal/server/models"
 )
 
 type Service struct {
-	storage      interfaces.Storage
-	jwts         interfaces.JWTService
-	buildVersion string
-	buildDate    time.Time
+	storage          interfaces.Storage
+	jwts             interfaces.JWTService
+	registrationMode models.RegistrationMode
+	buildVersion     string
+	buildDate        time.Time
 }
 
-func New(storage interfaces.Storage, jwts interfaces.JWTService, buildVersion, buildDate string) (interfaces.Service, error) {
+func New(storage interfaces.Storage, jwts interfaces.JWTService, registrationMode models.RegistrationMode, buildVersion, buildDate string) (interfaces.Service, error) {
 	s := &Service{
-		storage:      storage,
-		jwts:         jwts,
-		buildVersion: buildVersion,
+		storage:          storage,
+		jwts:             jwts,
+		registrationMode: registrationMode,
+		buildVersion:     buildVersion,
 	}
 	if buildDate != "" {
 		date, err := time.Parse("2006-01-02", buildDate)
@@ -35,7 +39,20 @@ func New(storage interfaces.Storage, jwts interfaces.JWTService, buildVersion, b
 	return s, nil
 }
 
-func (s *Service) Register(ctx context.Context, login, password string) (string, error) {
+func (s *Service) Register(ctx context.Context, login, password, inviteCode string) (string, error) {
+	if s.registrationMode == models.RegistrationClosed {
+		return "", interfaces.ErrRegistrationClosed
+	}
+
+	// Without a valid invite nothing else is checked: the login is not
+	// looked up, so that it cannot be told whether it is taken, and the
+	// password is not hashed.
+	if s.registrationMode == models.RegistrationInviteOnly {
+		if err := s.checkInvite(ctx, inviteCode); err != nil {
+			return "", err
+		}
+	}
+
 	exists, err := s.storage.IsLoginExists(ctx, login)
 	if err != nil {
 		return "", err
@@ -47,34 +64,148 @@ func (s *Service) Register(ctx context.Context, login, password string) (string,
 	user := &models.User{
 		Login: login,
 	}
-	if user.PasswordHash, err = argon2id.CreateHash(password, argon2id.DefaultParams); err != nil {
+	if user.PasswordHash, err = hashPassword(ctx, password); err != nil {
 		return "", err
 	}
+
+	if s.registrationMode == models.RegistrationInviteOnly {
+		if err = s.useInvite(ctx, inviteCode); err != nil {
+			return "", err
+		}
+	}
 	if err = s.storage.CreateUser(ctx, user); err != nil {
+		if s.registrationMode == models.RegistrationInviteOnly {
+			// The invite was not used up by the failed registration.
+			if releaseErr := s.storage.ReleaseInvite(context.WithoutCancel(ctx), HashInviteCode(inviteCode)); releaseErr != nil {
+				err = errors.Join(err, releaseErr)
+			}
+		}
 		return "", err
 	}
+	s.audit(ctx, models.AuditRegister, user.ID, "")
+
+	return s.issueToken(ctx, user.ID)
+}
+
+func (s *Service) checkInvite(ctx context.Context, code string) error {
+	if code == "" {
+		return interfaces.ErrInvalidInvite
+	}
+	valid, err := s.storage.IsInviteValid(ctx, HashInviteCode(code), time.Now())
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return interfaces.ErrInvalidInvite
+	}
+	return nil
+}
 
-	return s.jwts.BuildJWT(user.ID)
+func (s *Service) useInvite(ctx context.Context, code string) error {
+	if code == "" {
+		return interfaces.ErrInvalidInvite
+	}
+	err := s.storage.UseInvite(ctx, HashInviteCode(code), time.Now())
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return interfaces.ErrInvalidInvite
+	}
+	return err
 }
 
 func (s *Service) Login(ctx context.Context, login, password string) (string, error) {
 	user, err := s.storage.FindUserByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, interfaces.ErrNotFound) {
+			s.audit(ctx, models.AuditLoginFailed, "", fmt.Sprintf("unknown login %q", login))
 			return "", interfaces.ErrUnauthorized
 		}
 		return "", err
 	}
 
-	match, err := argon2id.ComparePasswordAndHash(password, user.PasswordHash)
+	match, err := comparePassword(ctx, password, user.PasswordHash)
 	if err != nil {
 		return "", err
 	}
 	if !match {
+		s.audit(ctx, models.AuditLoginFailed, user.ID, "invalid password")
 		return "", interfaces.ErrUnauthorized
 	}
+	if user.Disabled {
+		s.audit(ctx, models.AuditLoginFailed, user.ID, "account is disabled")
+		return "", interfaces.ErrAccountDisabled
+	}
+	s.audit(ctx, models.AuditLogin, user.ID, "")
+
+	return s.issueToken(ctx, user.ID)
+}
 
-	return s.jwts.BuildJWT(user.ID)
+func (s *Service) issueToken(ctx context.Context, userID string) (string, error) {
+	token, err := s.jwts.BuildJWT(userID)
+	if err != nil {
+		return "", err
+	}
+	s.audit(ctx, models.AuditTokenIssued, userID, "session token")
+	if req, ok := logging.RequestFromContext(ctx); ok {
+		req.UserID = userID
+	}
+	return token, nil
+}
+
+// DeleteAccount deletes the user together with all their records
+// after checking the password once more. The only owner of an organization
+// cannot be deleted, see DeleteUser of the storage.
+func (s *Service) DeleteAccount(ctx context.Context, userID, password string) error {
+	user, err := s.storage.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return interfaces.ErrUnauthorized
+		}
+		return err
+	}
+
+	match, err := comparePassword(ctx, password, user.PasswordHash)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return interfaces.ErrUnauthorized
+	}
+
+	return s.storage.DeleteUser(ctx, user.ID)
+}
+
+// Authenticate validates a session or personal access token and checks that
+// its user still exists, is not disabled and the token was issued after the
+// last session revocation.
+func (s *Service) Authenticate(ctx context.Context, token string) (string, *models.AccessToken, error) {
+	var userID string
+	var issuedAt time.Time
+	var accessToken *models.AccessToken
+	var err error
+	if strings.HasPrefix(token, models.AccessTokenPrefix) {
+		if accessToken, err = s.authenticateAccessToken(ctx, token); err != nil {
+			return "", nil, err
+		}
+		userID, issuedAt = accessToken.UserID, accessToken.CreatedAt
+	} else if userID, issuedAt, err = s.jwts.ParseJWT(token); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", interfaces.ErrUnauthorized, err)
+	}
+
+	user, err := s.storage.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return "", nil, interfaces.ErrUnauthorized
+		}
+		return "", nil, err
+	}
+	if user.Disabled {
+		return "", nil, interfaces.ErrUnauthorized
+	}
+	if issuedAt.Before(user.SessionsRevokedAt.Truncate(time.Second)) {
+		return "", nil, interfaces.ErrUnauthorized
+	}
+
+	return user.ID, accessToken, nil
 }
 
 func (s *Service) GetRecords(ctx context.Context, userID string) ([]*models.Record, error) {
@@ -93,7 +224,19 @@ func (s *Service) GetRecord(ctx context.Context, userID string, id uuid.UUID) (*
 }
 
 func (s *Service) DeleteRecord(ctx context.Context, userID string, id uuid.UUID) error {
-	return s.storage.DeleteRecord(ctx, userID, id)
+	if err := s.storage.DeleteRecord(ctx, userID, id); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditRecordDelete, userID, "record "+id.String())
+	return nil
+}
+
+func (s *Service) GetRegistrationMode(ctx context.Context) models.RegistrationMode {
+	return s.registrationMode
+}
+
+func (s *Service) GetJWKS(ctx context.Context) []models.JWK {
+	return s.jwts.PublicKeys()
 }
 
 func (s *Service) GetVersion(ctx context.Context) (buildVersion string, buildDate time.Time) {
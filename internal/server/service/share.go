@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+func (s *Service) SetUserKeys(ctx context.Context, keys *models.UserKeys) error {
+	if _, err := ecdh.X25519().NewPublicKey(keys.PublicKey); err != nil {
+		return fmt.Errorf("%w: public key is not an X25519 key", interfaces.ErrInvalidShare)
+	}
+	if len(keys.WrappedPrivateKey) == 0 {
+		return fmt.Errorf("%w: wrapped private key is required", interfaces.ErrInvalidShare)
+	}
+	return s.storage.CreateUserKeys(ctx, keys)
+}
+
+func (s *Service) GetUserKeys(ctx context.Context, userID string) (*models.UserKeys, error) {
+	return s.storage.GetUserKeys(ctx, userID)
+}
+
+// GetPublicKey returns the keys of the user with the login, without the
+// wrapped private key.
+func (s *Service) GetPublicKey(ctx context.Context, login string) (*models.UserKeys, error) {
+	user, err := s.storage.FindUserByLogin(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.storage.GetUserKeys(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	keys.WrappedPrivateKey = nil
+	return keys, nil
+}
+
+// ShareRecord shares a record with the user with the login. The record must
+// have a record key, a record encrypted with the vault key cannot be shared
+// without giving away the vault key.
+func (s *Service) ShareRecord(ctx context.Context, share *models.Share, recipientLogin string) error {
+	rec, err := s.storage.GetRecord(ctx, share.OwnerID, share.RecordID)
+	if err != nil {
+		return err
+	}
+	if len(rec.Key) == 0 {
+		return fmt.Errorf("%w: the record has no record key", interfaces.ErrInvalidShare)
+	}
+	if len(share.Key) == 0 {
+		return fmt.Errorf("%w: key is required", interfaces.ErrInvalidShare)
+	}
+	recipient, err := s.storage.FindUserByLogin(ctx, recipientLogin)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return fmt.Errorf("%w: user %q not found", interfaces.ErrInvalidShare, recipientLogin)
+		}
+		return err
+	}
+	if recipient.ID == share.OwnerID {
+		return fmt.Errorf("%w: a record cannot be shared with its owner", interfaces.ErrInvalidShare)
+	}
+	share.RecipientID = recipient.ID
+	share.RecipientLogin = recipient.Login
+	if err = s.storage.SaveShare(ctx, share); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditShareCreated, share.OwnerID, fmt.Sprintf("record %s with %s", share.RecordID, recipient.Login))
+	return nil
+}
+
+func (s *Service) ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) ([]*models.Share, error) {
+	if _, err := s.storage.GetRecord(ctx, ownerID, recordID); err != nil {
+		return nil, err
+	}
+	return s.storage.ListShares(ctx, ownerID, recordID)
+}
+
+func (s *Service) RevokeShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) error {
+	if err := s.storage.DeleteShare(ctx, ownerID, recordID, recipientID); err != nil {
+		return err
+	}
+	s.audit(ctx, models.AuditShareRevoked, ownerID, fmt.Sprintf("record %s from user %s", recordID, recipientID))
+	return nil
+}
+
+func (s *Service) GetSharedRecords(ctx context.Context, userID string) ([]*models.SharedRecord, error) {
+	return s.storage.GetSharedRecords(ctx, userID)
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type AccessTokenRepository struct {
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func NewAccessTokenRepository(ctx context.Context, db *sql.DB) (interfaces.AccessTokenRepository, error) {
+	r := &AccessTokenRepository{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt, 5),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *AccessTokenRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"ListAccessTokens": `SELECT id, user_id, name, secret_hash, read_only, tags, wrapped_key, expires_at, last_used_at, created_at
+			FROM access_tokens WHERE user_id = $1 ORDER BY created_at`,
+		"FindAccessToken": `SELECT id, user_id, name, secret_hash, read_only, tags, wrapped_key, expires_at, last_used_at, created_at
+			FROM access_tokens WHERE id = $1`,
+		"GetAccessTokenRecords": `SELECT record_id, key FROM access_token_records WHERE token_id = $1`,
+		"DeleteAccessToken":     `DELETE FROM access_tokens WHERE id = $1 AND user_id = $2`,
+		"TouchAccessToken":      `UPDATE access_tokens SET last_used_at = $1 WHERE id = $2`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *AccessTokenRepository) CreateAccessToken(ctx context.Context, token *models.AccessToken) error {
+	tags, err := json.Marshal(token.Tags)
+	if err != nil {
+		return err
+	}
+	if token.Tags == nil {
+		tags = []byte("[]")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO access_tokens (id, user_id, name, secret_hash, read_only, tags, wrapped_key, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		token.ID, token.UserID, token.Name, token.SecretHash, token.ReadOnly, string(tags), token.WrappedKey, token.ExpiresAt.UTC(),
+	).Scan(&token.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for id, key := range token.Records {
+		if _, err = tx.ExecContext(ctx,
+			`INSERT INTO access_token_records (token_id, record_id, key) VALUES ($1, $2, $3)`,
+			token.ID, id, key,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AccessTokenRepository) ListAccessTokens(ctx context.Context, userID string) ([]*models.AccessToken, error) {
+	var tokens []*models.AccessToken
+	rows, err := r.stmts["ListAccessTokens"].QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		if err = r.loadRecords(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+func (r *AccessTokenRepository) FindAccessToken(ctx context.Context, id string) (*models.AccessToken, error) {
+	token, err := scanAccessToken(r.stmts["FindAccessToken"].QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrNotFound
+		}
+		return nil, err
+	}
+	if err = r.loadRecords(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// loadRecords fills in the records of a token limited to records. Such a
+// token carries no vault key, so it stays limited even if none are left.
+func (r *AccessTokenRepository) loadRecords(ctx context.Context, token *models.AccessToken) error {
+	if len(token.WrappedKey) > 0 {
+		return nil
+	}
+	rows, err := r.stmts["GetAccessTokenRecords"].QueryContext(ctx, token.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	token.Records = make(map[uuid.UUID][]byte)
+	for rows.Next() {
+		var id uuid.UUID
+		var key []byte
+		if err := rows.Scan(&id, &key); err != nil {
+			return err
+		}
+		token.Records[id] = key
+	}
+	return rows.Err()
+}
+
+func (r *AccessTokenRepository) DeleteAccessToken(ctx context.Context, userID, id string) error {
+	return execAffectingOne(r.stmts["DeleteAccessToken"].ExecContext(ctx, id, userID))
+}
+
+func (r *AccessTokenRepository) TouchAccessToken(ctx context.Context, id string, at time.Time) error {
+	return execAffectingOne(r.stmts["TouchAccessToken"].ExecContext(ctx, at.UTC(), id))
+}
+
+func scanAccessToken(row rowScanner) (*models.AccessToken, error) {
+	var token models.AccessToken
+	var tags string
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.SecretHash,
+		&token.ReadOnly,
+		&tags,
+		&token.WrappedKey,
+		&token.ExpiresAt,
+		&lastUsedAt,
+		&token.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tags), &token.Tags); err != nil {
+		return nil, err
+	}
+	token.LastUsedAt = lastUsedAt.Time
+	return &token, nil
+}
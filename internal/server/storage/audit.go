@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type AuditRepository struct {
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func NewAuditRepository(ctx context.Context, db *sql.DB) (interfaces.AuditRepository, error) {
+	r := &AuditRepository{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt, 2),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *AuditRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"CreateAuditEvent": `INSERT INTO audit_events (user_id, type, details, ip, user_agent, request_id)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		"ListAuditEvents": `SELECT id, user_id, type, details, ip, user_agent, request_id, created_at FROM audit_events
+			WHERE user_id = $1 ORDER BY id DESC LIMIT $2`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *AuditRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	userID := sql.NullString{String: event.UserID, Valid: event.UserID != ""}
+	return r.stmts["CreateAuditEvent"].QueryRowContext(ctx,
+		userID,
+		event.Type,
+		event.Details,
+		event.IP,
+		event.UserAgent,
+		event.RequestID,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+func (r *AuditRepository) ListAuditEvents(ctx context.Context, userID string, limit int) ([]*models.AuditEvent, error) {
+	var events []*models.AuditEvent
+	rows, err := r.stmts["ListAuditEvents"].QueryContext(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event models.AuditEvent
+		var userID sql.NullString
+		if err := rows.Scan(
+			&event.ID,
+			&userID,
+			&event.Type,
+			&event.Details,
+			&event.IP,
+			&event.UserAgent,
+			&event.RequestID,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		event.UserID = userID.String
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
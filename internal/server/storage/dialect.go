@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect is what differs between the SQL of the supported databases. The
+// queries are written once, for PostgreSQL: the SQLite driver binds the $n
+// placeholders by number as well, and SQLite accepts true and false.
+type Dialect struct {
+	// lockRows tells whether SELECT ... FOR UPDATE is supported. SQLite
+	// has no row locks, it runs one transaction at a time, see NewSQLite.
+	lockRows bool
+	// strpos is the function returning the position of a substring,
+	// counting from 1, or 0 if there is none.
+	strpos string
+	// isUniqueViolation tells whether err violates a unique constraint.
+	isUniqueViolation func(err error) bool
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL.
+	Postgres = Dialect{
+		lockRows: true,
+		strpos:   "strpos",
+		isUniqueViolation: func(err error) bool {
+			var pqErr *pq.Error
+			return errors.As(err, &pqErr) && pqErr.Code == "23505"
+		},
+	}
+	// SQLite is the dialect of SQLite, opened by NewSQLite.
+	SQLite = Dialect{
+		strpos: "instr",
+		isUniqueViolation: func(err error) bool {
+			var sqliteErr *sqlite.Error
+			return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+		},
+	}
+)
+
+// forUpdate returns the query locking the rows it selects, only those of
+// the tables if any are given, until the transaction ends.
+func (d Dialect) forUpdate(query string, tables ...string) string {
+	if !d.lockRows {
+		return query
+	}
+	query += " FOR UPDATE"
+	if len(tables) > 0 {
+		query += " OF " + strings.Join(tables, ", ")
+	}
+	return query
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type EmergencyRepository struct {
+	db      *sql.DB
+	dialect Dialect
+	stmts   map[string]*sql.Stmt
+}
+
+func NewEmergencyRepository(ctx context.Context, db *sql.DB, dialect Dialect) (interfaces.EmergencyRepository, error) {
+	r := &EmergencyRepository{
+		db:      db,
+		dialect: dialect,
+		stmts:   make(map[string]*sql.Stmt, 5),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+const selectEmergencyAccess = `SELECT e.id, e.grantor_id, g.login, e.grantee_id, c.login, e.wait_days, e.status, e.key, e.requested_at, e.created_at
+	FROM emergency_access e JOIN users g ON g.id = e.grantor_id JOIN users c ON c.id = e.grantee_id`
+
+func (r *EmergencyRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"CreateEmergencyAccess": `INSERT INTO emergency_access (id, grantor_id, grantee_id, wait_days, status, key) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (grantor_id, grantee_id) DO NOTHING RETURNING created_at`,
+		"GetEmergencyAccess":    selectEmergencyAccess + ` WHERE e.id = $1`,
+		"ListEmergencyContacts": selectEmergencyAccess + ` WHERE e.grantor_id = $1 ORDER BY c.login`,
+		"ListEmergencyGrants":   selectEmergencyAccess + ` WHERE e.grantee_id = $1 ORDER BY g.login`,
+		"DeleteEmergencyAccess": `DELETE FROM emergency_access WHERE grantor_id = $1 AND id = $2`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *EmergencyRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *EmergencyRepository) CreateEmergencyAccess(ctx context.Context, access *models.EmergencyAccess) error {
+	err := r.stmts["CreateEmergencyAccess"].QueryRowContext(ctx,
+		access.ID, access.GrantorID, access.GranteeID, access.WaitDays, access.Status, access.Key,
+	).Scan(&access.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return interfaces.ErrContactExists
+	}
+	return err
+}
+
+func (r *EmergencyRepository) GetEmergencyAccess(ctx context.Context, id uuid.UUID) (*models.EmergencyAccess, error) {
+	access, err := scanEmergencyAccess(r.stmts["GetEmergencyAccess"].QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrNotFound
+		}
+		return nil, err
+	}
+	return access, nil
+}
+
+func (r *EmergencyRepository) ListEmergencyContacts(ctx context.Context, grantorID string) ([]*models.EmergencyAccess, error) {
+	return r.list(ctx, "ListEmergencyContacts", grantorID)
+}
+
+func (r *EmergencyRepository) ListEmergencyGrants(ctx context.Context, granteeID string) ([]*models.EmergencyAccess, error) {
+	return r.list(ctx, "ListEmergencyGrants", granteeID)
+}
+
+func (r *EmergencyRepository) list(ctx context.Context, stmt, userID string) ([]*models.EmergencyAccess, error) {
+	var accesses []*models.EmergencyAccess
+	rows, err := r.stmts[stmt].QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		access, err := scanEmergencyAccess(rows)
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, access)
+	}
+	return accesses, rows.Err()
+}
+
+func (r *EmergencyRepository) SetEmergencyStatus(ctx context.Context, id uuid.UUID, from []models.EmergencyStatus, to models.EmergencyStatus, requestedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status models.EmergencyStatus
+	err = tx.QueryRowContext(ctx, r.dialect.forUpdate(`SELECT status FROM emergency_access WHERE id = $1`), id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrNotFound
+		}
+		return err
+	}
+	if !slices.Contains(from, status) {
+		return interfaces.ErrEmergencyState
+	}
+
+	var at sql.NullTime
+	if !requestedAt.IsZero() {
+		at = sql.NullTime{Time: requestedAt.UTC(), Valid: true}
+	}
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE emergency_access SET status = $1, requested_at = $2 WHERE id = $3`, to, at, id,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *EmergencyRepository) DeleteEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error {
+	return execAffectingOne(r.stmts["DeleteEmergencyAccess"].ExecContext(ctx, grantorID, id))
+}
+
+func scanEmergencyAccess(row rowScanner) (*models.EmergencyAccess, error) {
+	var access models.EmergencyAccess
+	var requestedAt sql.NullTime
+	if err := row.Scan(
+		&access.ID,
+		&access.GrantorID,
+		&access.GrantorLogin,
+		&access.GranteeID,
+		&access.GranteeLogin,
+		&access.WaitDays,
+		&access.Status,
+		&access.Key,
+		&requestedAt,
+		&access.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	access.RequestedAt = requestedAt.Time
+	return &access, nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type InviteRepository struct {
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func NewInviteRepository(ctx context.Context, db *sql.DB) (interfaces.InviteRepository, error) {
+	r := &InviteRepository{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt, 6),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *InviteRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"CreateInvite": `INSERT INTO invites (id, code_hash, max_uses, expires_at) VALUES ($1, $2, $3, $4) RETURNING created_at`,
+		"ListInvites":  `SELECT id, code_hash, max_uses, uses, expires_at, created_at FROM invites ORDER BY created_at`,
+		"DeleteInvite": `DELETE FROM invites WHERE id = $1`,
+		"IsInviteValid": `SELECT EXISTS(SELECT * FROM invites
+			WHERE code_hash = $1 AND (expires_at IS NULL OR expires_at > $2) AND (max_uses = 0 OR uses < max_uses))`,
+		"UseInvite": `UPDATE invites SET uses = uses + 1
+			WHERE code_hash = $1 AND (expires_at IS NULL OR expires_at > $2) AND (max_uses = 0 OR uses < max_uses)`,
+		"ReleaseInvite": `UPDATE invites SET uses = uses - 1 WHERE code_hash = $1 AND uses > 0`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *models.Invite) error {
+	var expiresAt sql.NullTime
+	if !invite.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: invite.ExpiresAt.UTC(), Valid: true}
+	}
+	id := uuid.NewString()
+	if err := r.stmts["CreateInvite"].QueryRowContext(ctx, id, invite.CodeHash, invite.MaxUses, expiresAt).Scan(&invite.CreatedAt); err != nil {
+		return err
+	}
+	invite.ID = id
+	return nil
+}
+
+func (r *InviteRepository) ListInvites(ctx context.Context) ([]*models.Invite, error) {
+	var invites []*models.Invite
+	rows, err := r.stmts["ListInvites"].QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invite models.Invite
+		var expiresAt sql.NullTime
+		if err := rows.Scan(
+			&invite.ID,
+			&invite.CodeHash,
+			&invite.MaxUses,
+			&invite.Uses,
+			&expiresAt,
+			&invite.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		invite.ExpiresAt = expiresAt.Time
+		invites = append(invites, &invite)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+func (r *InviteRepository) DeleteInvite(ctx context.Context, id string) error {
+	return execAffectingOne(r.stmts["DeleteInvite"].ExecContext(ctx, id))
+}
+
+func (r *InviteRepository) IsInviteValid(ctx context.Context, codeHash string, now time.Time) (bool, error) {
+	var valid bool
+	if err := r.stmts["IsInviteValid"].QueryRowContext(ctx, codeHash, now.UTC()).Scan(&valid); err != nil {
+		return false, err
+	}
+	return valid, nil
+}
+
+func (r *InviteRepository) UseInvite(ctx context.Context, codeHash string, now time.Time) error {
+	return execAffectingOne(r.stmts["UseInvite"].ExecContext(ctx, codeHash, now.UTC()))
+}
+
+func (r *InviteRepository) ReleaseInvite(ctx context.Context, codeHash string) error {
+	return execAffectingOne(r.stmts["ReleaseInvite"].ExecContext(ctx, codeHash))
+}
@@ -0,0 +1,1099 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+// Storage keeps users and records in process memory. It is meant for tests
+// and throwaway instances: everything is lost on Close.
+type Storage struct {
+	mu      sync.RWMutex
+	users   map[string]*models.User
+	logins  map[string]string
+	records map[string]map[uuid.UUID]*models.Record
+	invites map[string]*models.Invite
+	audit   []*models.AuditEvent
+	auditID int64
+	tokens  map[string]*models.AccessToken
+	keys    map[string]*models.UserKeys
+	shares  map[shareKey]*models.Share
+	orgs    map[uuid.UUID]*org
+	// emergency holds the emergency accesses without the logins, which are
+	// looked up on read.
+	emergency map[uuid.UUID]*models.EmergencyAccess
+	sends     map[uuid.UUID]*models.Send
+}
+
+// org holds an organization with its members by user ID and its records.
+type org struct {
+	models.Organization
+	members map[string]*models.OrgMember
+	records map[uuid.UUID]*models.OrgRecord
+}
+
+type shareKey struct {
+	recordID    uuid.UUID
+	ownerID     string
+	recipientID string
+}
+
+func New() interfaces.Storage {
+	return &Storage{
+		users:   make(map[string]*models.User),
+		logins:  make(map[string]string),
+		records: make(map[string]map[uuid.UUID]*models.Record),
+		invites: make(map[string]*models.Invite),
+		tokens:  make(map[string]*models.AccessToken),
+		keys:    make(map[string]*models.UserKeys),
+		shares:  make(map[shareKey]*models.Share),
+		orgs:    make(map[uuid.UUID]*org),
+
+		emergency: make(map[uuid.UUID]*models.EmergencyAccess),
+		sends:     make(map[uuid.UUID]*models.Send),
+	}
+}
+
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.users)
+	clear(s.logins)
+	clear(s.records)
+	clear(s.invites)
+	clear(s.tokens)
+	clear(s.keys)
+	clear(s.shares)
+	clear(s.orgs)
+	clear(s.emergency)
+	clear(s.sends)
+	s.audit = nil
+
+	return nil
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *Storage) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	return 0, false, nil
+}
+
+func (s *Storage) IsLoginExists(ctx context.Context, login string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.logins[login]
+	return exists, nil
+}
+
+func (s *Storage) CreateUser(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.logins[user.Login]; exists {
+		return interfaces.ErrLoginTaken
+	}
+
+	user.ID = uuid.NewString()
+	user.CreatedAt = time.Now()
+	stored := *user
+	s.users[stored.ID] = &stored
+	s.logins[stored.Login] = stored.ID
+
+	return nil
+}
+
+func (s *Storage) FindUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, exists := s.logins[login]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	user := *s.users[id]
+	return &user, nil
+}
+
+func (s *Storage) FindUserByID(ctx context.Context, id string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (s *Storage) ListUsers(ctx context.Context, search string) ([]*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []*models.User
+	search = strings.ToLower(search)
+	for _, user := range s.users {
+		if strings.Contains(strings.ToLower(user.Login), search) {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	slices.SortFunc(users, func(a, b *models.User) int {
+		return strings.Compare(a.Login, b.Login)
+	})
+	return users, nil
+}
+
+func (s *Storage) SetUserDisabled(ctx context.Context, id string, disabled bool) error {
+	return s.updateUser(id, func(user *models.User) {
+		user.Disabled = disabled
+	})
+}
+
+func (s *Storage) RevokeUserSessions(ctx context.Context, id string, at time.Time) error {
+	return s.updateUser(id, func(user *models.User) {
+		user.SessionsRevokedAt = at
+	})
+}
+
+func (s *Storage) updateUser(id string, update func(user *models.User)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	update(user)
+	return nil
+}
+
+func (s *Storage) DeleteUser(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	for _, o := range s.orgs {
+		if o.lastOwner(id) {
+			return fmt.Errorf("%w: the user is the only owner of organization %q, make another member an owner or delete it first", interfaces.ErrLastOwner, o.Name)
+		}
+	}
+	delete(s.logins, user.Login)
+	delete(s.users, id)
+	delete(s.records, id)
+	s.audit = slices.DeleteFunc(s.audit, func(event *models.AuditEvent) bool {
+		return event.UserID == id
+	})
+	maps.DeleteFunc(s.tokens, func(_ string, token *models.AccessToken) bool {
+		return token.UserID == id
+	})
+	delete(s.keys, id)
+	maps.DeleteFunc(s.shares, func(key shareKey, _ *models.Share) bool {
+		return key.ownerID == id || key.recipientID == id
+	})
+	for _, o := range s.orgs {
+		if _, exists := o.members[id]; exists {
+			delete(o.members, id)
+			o.RekeyNeeded = true
+		}
+	}
+	maps.DeleteFunc(s.emergency, func(_ uuid.UUID, access *models.EmergencyAccess) bool {
+		return access.GrantorID == id || access.GranteeID == id
+	})
+	maps.DeleteFunc(s.sends, func(_ uuid.UUID, send *models.Send) bool {
+		return send.UserID == id
+	})
+	return nil
+}
+
+func (s *Storage) GetRecords(ctx context.Context, userID string) ([]*models.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*models.Record
+	for _, rec := range s.records[userID] {
+		records = append(records, cloneRecord(rec))
+	}
+	return records, nil
+}
+
+func (s *Storage) CreateRecord(ctx context.Context, rec *models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[rec.UserID][rec.ID]; exists {
+		return interfaces.ErrVersionConflict
+	}
+	s.putRecord(rec)
+	return nil
+}
+
+func (s *Storage) UpdateOrCreateRecord(ctx context.Context, rec *models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[rec.UserID][rec.ID]; exists {
+		return s.updateRecord(rec)
+	}
+	s.putRecord(rec)
+	return nil
+}
+
+func (s *Storage) UpdateRecord(ctx context.Context, rec *models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateRecord(rec)
+}
+
+func (s *Storage) updateRecord(rec *models.Record) error {
+	current, exists := s.records[rec.UserID][rec.ID]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	if rec.Version-current.Version != 1 {
+		return interfaces.ErrVersionConflict
+	}
+	s.putRecord(rec)
+	return nil
+}
+
+func (s *Storage) putRecord(rec *models.Record) {
+	userRecords, ok := s.records[rec.UserID]
+	if !ok {
+		userRecords = make(map[uuid.UUID]*models.Record)
+		s.records[rec.UserID] = userRecords
+	}
+	userRecords[rec.ID] = cloneRecord(rec)
+}
+
+func (s *Storage) GetRecord(ctx context.Context, userID string, id uuid.UUID) (*models.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, exists := s.records[userID][id]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	return cloneRecord(rec), nil
+}
+
+func (s *Storage) DeleteRecord(ctx context.Context, userID string, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records[userID], id)
+	maps.DeleteFunc(s.shares, func(key shareKey, _ *models.Share) bool {
+		return key.ownerID == userID && key.recordID == id
+	})
+	return nil
+}
+
+func (s *Storage) GetRecordStats(ctx context.Context) (map[string]models.RecordStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := make(map[string]models.RecordStats, len(s.records))
+	for userID, records := range s.records {
+		if len(records) == 0 {
+			continue
+		}
+		var userStats models.RecordStats
+		for _, rec := range records {
+			userStats.Count++
+			userStats.Bytes += int64(len(rec.Data))
+		}
+		stats[userID] = userStats
+	}
+	return stats, nil
+}
+
+func (s *Storage) CreateInvite(ctx context.Context, invite *models.Invite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.invites {
+		if existing.CodeHash == invite.CodeHash {
+			return errors.New("invite code already exists")
+		}
+	}
+
+	invite.ID = uuid.NewString()
+	invite.CreatedAt = time.Now()
+	stored := *invite
+	s.invites[stored.ID] = &stored
+
+	return nil
+}
+
+func (s *Storage) ListInvites(ctx context.Context) ([]*models.Invite, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var invites []*models.Invite
+	for _, invite := range s.invites {
+		found := *invite
+		invites = append(invites, &found)
+	}
+	slices.SortFunc(invites, func(a, b *models.Invite) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return invites, nil
+}
+
+func (s *Storage) DeleteInvite(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.invites[id]; !exists {
+		return interfaces.ErrNotFound
+	}
+	delete(s.invites, id)
+	return nil
+}
+
+func (s *Storage) IsInviteValid(ctx context.Context, codeHash string, now time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.validInvite(codeHash, now) != nil, nil
+}
+
+func (s *Storage) UseInvite(ctx context.Context, codeHash string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invite := s.validInvite(codeHash, now)
+	if invite == nil {
+		return interfaces.ErrNotFound
+	}
+	invite.Uses++
+	return nil
+}
+
+// validInvite returns the invite with the code hash if it is not expired or
+// used up, the caller holds the lock.
+func (s *Storage) validInvite(codeHash string, now time.Time) *models.Invite {
+	for _, invite := range s.invites {
+		if invite.CodeHash != codeHash {
+			continue
+		}
+		if !invite.ExpiresAt.IsZero() && !invite.ExpiresAt.After(now) {
+			return nil
+		}
+		if invite.MaxUses != 0 && invite.Uses >= invite.MaxUses {
+			return nil
+		}
+		return invite
+	}
+	return nil
+}
+
+func (s *Storage) ReleaseInvite(ctx context.Context, codeHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, invite := range s.invites {
+		if invite.CodeHash == codeHash && invite.Uses > 0 {
+			invite.Uses--
+			return nil
+		}
+	}
+	return interfaces.ErrNotFound
+}
+
+func (s *Storage) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.auditID++
+	event.ID = s.auditID
+	event.CreatedAt = time.Now()
+	stored := *event
+	s.audit = append(s.audit, &stored)
+
+	return nil
+}
+
+func (s *Storage) ListAuditEvents(ctx context.Context, userID string, limit int) ([]*models.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []*models.AuditEvent
+	for i := len(s.audit) - 1; i >= 0 && len(events) < limit; i-- {
+		if s.audit[i].UserID == userID {
+			found := *s.audit[i]
+			events = append(events, &found)
+		}
+	}
+	return events, nil
+}
+
+func (s *Storage) CreateAccessToken(ctx context.Context, token *models.AccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tokens[token.ID]; exists {
+		return errors.New("access token already exists")
+	}
+	token.CreatedAt = time.Now()
+	s.tokens[token.ID] = cloneAccessToken(token)
+
+	return nil
+}
+
+func (s *Storage) ListAccessTokens(ctx context.Context, userID string) ([]*models.AccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tokens []*models.AccessToken
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, cloneAccessToken(token))
+		}
+	}
+	slices.SortFunc(tokens, func(a, b *models.AccessToken) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (s *Storage) FindAccessToken(ctx context.Context, id string) (*models.AccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	token, exists := s.tokens[id]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	return cloneAccessToken(token), nil
+}
+
+func (s *Storage) DeleteAccessToken(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token, exists := s.tokens[id]; !exists || token.UserID != userID {
+		return interfaces.ErrNotFound
+	}
+	delete(s.tokens, id)
+	return nil
+}
+
+func (s *Storage) TouchAccessToken(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, exists := s.tokens[id]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	token.LastUsedAt = at
+	return nil
+}
+
+func (s *Storage) CreateUserKeys(ctx context.Context, keys *models.UserKeys) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.keys[keys.UserID]; exists {
+		return interfaces.ErrKeysExist
+	}
+	keys.CreatedAt = time.Now()
+	stored := *keys
+	stored.PublicKey = slices.Clone(keys.PublicKey)
+	stored.WrappedPrivateKey = slices.Clone(keys.WrappedPrivateKey)
+	s.keys[keys.UserID] = &stored
+
+	return nil
+}
+
+func (s *Storage) GetUserKeys(ctx context.Context, userID string) (*models.UserKeys, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys, exists := s.keys[userID]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	found := *keys
+	found.PublicKey = slices.Clone(keys.PublicKey)
+	found.WrappedPrivateKey = slices.Clone(keys.WrappedPrivateKey)
+	return &found, nil
+}
+
+func (s *Storage) SaveShare(ctx context.Context, share *models.Share) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[share.OwnerID][share.RecordID]; !exists {
+		return errors.New("record does not exist")
+	}
+	if _, exists := s.users[share.RecipientID]; !exists {
+		return errors.New("recipient does not exist")
+	}
+	share.CreatedAt = time.Now()
+	stored := *share
+	stored.RecipientLogin = ""
+	stored.Key = slices.Clone(share.Key)
+	s.shares[shareKey{share.RecordID, share.OwnerID, share.RecipientID}] = &stored
+
+	return nil
+}
+
+func (s *Storage) ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) ([]*models.Share, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var shares []*models.Share
+	for key, share := range s.shares {
+		if key.ownerID != ownerID || key.recordID != recordID {
+			continue
+		}
+		found := *share
+		found.Key = slices.Clone(share.Key)
+		found.RecipientLogin = s.users[key.recipientID].Login
+		shares = append(shares, &found)
+	}
+	slices.SortFunc(shares, func(a, b *models.Share) int {
+		return strings.Compare(a.RecipientLogin, b.RecipientLogin)
+	})
+	return shares, nil
+}
+
+func (s *Storage) DeleteShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := shareKey{recordID, ownerID, recipientID}
+	if _, exists := s.shares[key]; !exists {
+		return interfaces.ErrNotFound
+	}
+	delete(s.shares, key)
+	return nil
+}
+
+func (s *Storage) GetSharedRecords(ctx context.Context, recipientID string) ([]*models.SharedRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*models.SharedRecord
+	for key, share := range s.shares {
+		if key.recipientID != recipientID {
+			continue
+		}
+		record := &models.SharedRecord{
+			Record:     *cloneRecord(s.records[key.ownerID][key.recordID]),
+			OwnerLogin: s.users[key.ownerID].Login,
+			SharedAt:   share.CreatedAt,
+		}
+		record.Key = slices.Clone(share.Key)
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b *models.SharedRecord) int {
+		return a.SharedAt.Compare(b.SharedAt)
+	})
+	return records, nil
+}
+
+func (s *Storage) CreateOrg(ctx context.Context, o *models.Organization, owner *models.OrgMember) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.orgs[o.ID]; exists {
+		return errors.New("organization already exists")
+	}
+	if _, exists := s.users[owner.UserID]; !exists {
+		return errors.New("user does not exist")
+	}
+	o.KeyVersion = 1
+	o.CreatedAt = time.Now()
+	owner.OrgID = o.ID
+	owner.CreatedAt = o.CreatedAt
+	stored := &org{
+		Organization: models.Organization{ID: o.ID, Name: o.Name, KeyVersion: o.KeyVersion, CreatedAt: o.CreatedAt},
+		members:      make(map[string]*models.OrgMember),
+		records:      make(map[uuid.UUID]*models.OrgRecord),
+	}
+	stored.members[owner.UserID] = cloneOrgMember(owner)
+	s.orgs[o.ID] = stored
+
+	return nil
+}
+
+func (s *Storage) ListOrgs(ctx context.Context, userID string) ([]*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var orgs []*models.Organization
+	for _, o := range s.orgs {
+		if member, ok := o.members[userID]; ok {
+			orgs = append(orgs, o.view(member))
+		}
+	}
+	slices.SortFunc(orgs, func(a, b *models.Organization) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return orgs, nil
+}
+
+func (s *Storage) GetOrg(ctx context.Context, orgID uuid.UUID, userID string) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	member, ok := o.members[userID]
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	return o.view(member), nil
+}
+
+func (s *Storage) DeleteOrg(ctx context.Context, orgID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.orgs[orgID]; !exists {
+		return interfaces.ErrNotFound
+	}
+	delete(s.orgs, orgID)
+	return nil
+}
+
+func (s *Storage) ListOrgMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrgMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return nil, nil
+	}
+	var members []*models.OrgMember
+	for _, member := range o.members {
+		if found, ok := s.orgMember(member); ok {
+			members = append(members, found)
+		}
+	}
+	slices.SortFunc(members, func(a, b *models.OrgMember) int {
+		return strings.Compare(a.Login, b.Login)
+	})
+	return members, nil
+}
+
+func (s *Storage) GetOrgMember(ctx context.Context, orgID uuid.UUID, userID string) (*models.OrgMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	member, exists := o.members[userID]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	found, ok := s.orgMember(member)
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	return found, nil
+}
+
+// orgMember fills in the login and public key of a member. Like the join
+// of the SQL storages it finds no member without a key pair.
+func (s *Storage) orgMember(member *models.OrgMember) (*models.OrgMember, bool) {
+	keys, ok := s.keys[member.UserID]
+	if !ok {
+		return nil, false
+	}
+	found := cloneOrgMember(member)
+	found.Login = s.users[member.UserID].Login
+	found.PublicKey = slices.Clone(keys.PublicKey)
+	return found, true
+}
+
+func (s *Storage) AddOrgMember(ctx context.Context, member *models.OrgMember, keyVersion int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.orgWithKeyVersion(member.OrgID, keyVersion)
+	if err != nil {
+		return err
+	}
+	if _, exists := o.members[member.UserID]; exists {
+		return interfaces.ErrMemberExists
+	}
+	if _, exists := s.users[member.UserID]; !exists {
+		return errors.New("user does not exist")
+	}
+	member.CreatedAt = time.Now()
+	o.members[member.UserID] = cloneOrgMember(member)
+	return nil
+}
+
+func (s *Storage) SetOrgMemberRole(ctx context.Context, orgID uuid.UUID, userID string, role models.OrgRole) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	member, exists := o.members[userID]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	if role != models.OrgRoleOwner && o.lastOwner(userID) {
+		return interfaces.ErrLastOwner
+	}
+	member.Role = role
+	return nil
+}
+
+func (s *Storage) DeleteOrgMember(ctx context.Context, orgID uuid.UUID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	if _, exists = o.members[userID]; !exists {
+		return interfaces.ErrNotFound
+	}
+	if o.lastOwner(userID) {
+		return interfaces.ErrLastOwner
+	}
+	delete(o.members, userID)
+	o.RekeyNeeded = true
+	return nil
+}
+
+func (s *Storage) RekeyOrg(ctx context.Context, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.orgWithKeyVersion(orgID, keyVersion-1)
+	if err != nil {
+		return err
+	}
+	if len(o.members) != len(keys) {
+		return interfaces.ErrInvalidOrg
+	}
+	for userID := range o.members {
+		if len(keys[userID]) == 0 {
+			return interfaces.ErrInvalidOrg
+		}
+	}
+	if len(o.records) != len(records) {
+		return interfaces.ErrInvalidOrg
+	}
+	for _, rec := range records {
+		current, ok := o.records[rec.ID]
+		if !ok {
+			return interfaces.ErrInvalidOrg
+		}
+		if rec.Version-current.Version != 1 {
+			return interfaces.ErrVersionConflict
+		}
+	}
+
+	for userID, key := range keys {
+		o.members[userID].Key = slices.Clone(key)
+	}
+	for _, rec := range records {
+		stored := cloneOrgRecord(rec)
+		stored.OrgID = orgID
+		o.records[rec.ID] = stored
+	}
+	o.KeyVersion = keyVersion
+	o.RekeyNeeded = false
+	return nil
+}
+
+func (s *Storage) GetOrgRecords(ctx context.Context, orgID uuid.UUID) ([]*models.OrgRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return nil, nil
+	}
+	var records []*models.OrgRecord
+	for _, rec := range o.records {
+		records = append(records, cloneOrgRecord(rec))
+	}
+	return records, nil
+}
+
+func (s *Storage) SaveOrgRecord(ctx context.Context, rec *models.OrgRecord, keyVersion int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.orgWithKeyVersion(rec.OrgID, keyVersion)
+	if err != nil {
+		return err
+	}
+	currentVersion := 0
+	if current, exists := o.records[rec.ID]; exists {
+		currentVersion = current.Version
+	}
+	if rec.Version-currentVersion != 1 {
+		return interfaces.ErrVersionConflict
+	}
+	o.records[rec.ID] = cloneOrgRecord(rec)
+	return nil
+}
+
+func (s *Storage) DeleteOrgRecord(ctx context.Context, orgID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	if _, exists = o.records[id]; !exists {
+		return interfaces.ErrNotFound
+	}
+	delete(o.records, id)
+	return nil
+}
+
+func (s *Storage) orgWithKeyVersion(orgID uuid.UUID, keyVersion int64) (*org, error) {
+	o, exists := s.orgs[orgID]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	if o.KeyVersion != keyVersion {
+		return nil, interfaces.ErrVersionConflict
+	}
+	return o, nil
+}
+
+// view returns the organization as seen by the member.
+// lastOwner reports whether the user is the only owner of the organization.
+func (o *org) lastOwner(userID string) bool {
+	member, exists := o.members[userID]
+	if !exists || member.Role != models.OrgRoleOwner {
+		return false
+	}
+	for id, other := range o.members {
+		if id != userID && other.Role == models.OrgRoleOwner {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *org) view(member *models.OrgMember) *models.Organization {
+	found := o.Organization
+	found.Role = member.Role
+	found.Key = slices.Clone(member.Key)
+	return &found
+}
+
+func (s *Storage) CreateEmergencyAccess(ctx context.Context, access *models.EmergencyAccess) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.emergency {
+		if existing.GrantorID == access.GrantorID && existing.GranteeID == access.GranteeID {
+			return interfaces.ErrContactExists
+		}
+	}
+	access.CreatedAt = time.Now()
+	stored := *access
+	stored.Key = slices.Clone(access.Key)
+	s.emergency[access.ID] = &stored
+	return nil
+}
+
+func (s *Storage) GetEmergencyAccess(ctx context.Context, id uuid.UUID) (*models.EmergencyAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	access, exists := s.emergency[id]
+	if !exists {
+		return nil, interfaces.ErrNotFound
+	}
+	return s.emergencyView(access), nil
+}
+
+func (s *Storage) ListEmergencyContacts(ctx context.Context, grantorID string) ([]*models.EmergencyAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var accesses []*models.EmergencyAccess
+	for _, access := range s.emergency {
+		if access.GrantorID == grantorID {
+			accesses = append(accesses, s.emergencyView(access))
+		}
+	}
+	slices.SortFunc(accesses, func(a, b *models.EmergencyAccess) int {
+		return strings.Compare(a.GranteeLogin, b.GranteeLogin)
+	})
+	return accesses, nil
+}
+
+func (s *Storage) ListEmergencyGrants(ctx context.Context, granteeID string) ([]*models.EmergencyAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var accesses []*models.EmergencyAccess
+	for _, access := range s.emergency {
+		if access.GranteeID == granteeID {
+			accesses = append(accesses, s.emergencyView(access))
+		}
+	}
+	slices.SortFunc(accesses, func(a, b *models.EmergencyAccess) int {
+		return strings.Compare(a.GrantorLogin, b.GrantorLogin)
+	})
+	return accesses, nil
+}
+
+func (s *Storage) SetEmergencyStatus(ctx context.Context, id uuid.UUID, from []models.EmergencyStatus, to models.EmergencyStatus, requestedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	access, exists := s.emergency[id]
+	if !exists {
+		return interfaces.ErrNotFound
+	}
+	if !slices.Contains(from, access.Status) {
+		return interfaces.ErrEmergencyState
+	}
+	access.Status = to
+	access.RequestedAt = time.Time{}
+	if !requestedAt.IsZero() {
+		access.RequestedAt = requestedAt.UTC()
+	}
+	return nil
+}
+
+func (s *Storage) DeleteEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	access, exists := s.emergency[id]
+	if !exists || access.GrantorID != grantorID {
+		return interfaces.ErrNotFound
+	}
+	delete(s.emergency, id)
+	return nil
+}
+
+func (s *Storage) emergencyView(access *models.EmergencyAccess) *models.EmergencyAccess {
+	view := *access
+	view.Key = slices.Clone(access.Key)
+	view.GrantorLogin = s.users[access.GrantorID].Login
+	view.GranteeLogin = s.users[access.GranteeID].Login
+	return &view
+}
+
+func (s *Storage) CreateSend(ctx context.Context, send *models.Send) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	send.CreatedAt = time.Now()
+	s.sends[send.ID] = cloneSend(send)
+	return nil
+}
+
+func (s *Storage) GetSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	send, exists := s.sends[id]
+	if !exists || send.ViewsLeft == 0 || !send.ExpiresAt.After(now) {
+		return nil, interfaces.ErrNotFound
+	}
+	return cloneSend(send), nil
+}
+
+func (s *Storage) UseSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	send, exists := s.sends[id]
+	if !exists || send.ViewsLeft == 0 || !send.ExpiresAt.After(now) {
+		return nil, interfaces.ErrNotFound
+	}
+	send.ViewsLeft--
+	if send.ViewsLeft == 0 {
+		delete(s.sends, id)
+	}
+	return cloneSend(send), nil
+}
+
+func (s *Storage) DeleteExpiredSends(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maps.DeleteFunc(s.sends, func(_ uuid.UUID, send *models.Send) bool {
+		return !send.ExpiresAt.After(now)
+	})
+	return nil
+}
+
+func cloneSend(send *models.Send) *models.Send {
+	clone := *send
+	clone.Data = slices.Clone(send.Data)
+	clone.Nonce = slices.Clone(send.Nonce)
+	return &clone
+}
+
+func cloneOrgMember(member *models.OrgMember) *models.OrgMember {
+	clone := *member
+	clone.Key = slices.Clone(member.Key)
+	clone.PublicKey = slices.Clone(member.PublicKey)
+	return &clone
+}
+
+func cloneOrgRecord(rec *models.OrgRecord) *models.OrgRecord {
+	clone := *rec
+	clone.Data = slices.Clone(rec.Data)
+	clone.Nonce = slices.Clone(rec.Nonce)
+	return &clone
+}
+
+func cloneRecord(rec *models.Record) *models.Record {
+	clone := *rec
+	clone.Data = slices.Clone(rec.Data)
+	clone.Nonce = slices.Clone(rec.Nonce)
+	clone.Key = slices.Clone(rec.Key)
+	return &clone
+}
+
+// cloneAccessToken keeps a token limited to records limited when none are
+// left, like the SQL storages do.
+func cloneAccessToken(token *models.AccessToken) *models.AccessToken {
+	clone := *token
+	clone.Tags = slices.Clone(token.Tags)
+	clone.WrappedKey = slices.Clone(token.WrappedKey)
+	clone.Records = maps.Clone(token.Records)
+	if clone.Records == nil && len(clone.WrappedKey) == 0 {
+		clone.Records = make(map[uuid.UUID][]byte)
+	}
+	return &clone
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/grnsv/GophKeeper/migrations"
+)
+
+var ErrNoMigrations = errors.New("storage has no migrations")
+
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+func (s MigrationStatus) UpToDate() bool {
+	return !s.Dirty && s.Version == s.Latest
+}
+
+// Migrator applies the embedded migrations of the storage dialect.
+type Migrator struct {
+	*migrate.Migrate
+	source source.Driver
+}
+
+func NewMigrator(dsn string) (*Migrator, error) {
+	src, err := migrationSource(dsn)
+	if err != nil {
+		return nil, err
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{Migrate: m, source: src}, nil
+}
+
+// migrationSource returns the embedded migrations of the dialect of dsn.
+func migrationSource(dsn string) (source.Driver, error) {
+	var dialect string
+	switch scheme(dsn) {
+	case "postgres", "postgresql":
+		dialect = "postgres"
+	case "sqlite":
+		dialect = "sqlite"
+	case "memory":
+		return nil, ErrNoMigrations
+	default:
+		return nil, fmt.Errorf("unsupported database scheme: %q", scheme(dsn))
+	}
+	return iofs.New(migrations.FS, dialect)
+}
+
+// LatestMigration returns the version of the latest embedded migration for
+// the storage of dsn without connecting to it.
+func LatestMigration(dsn string) (uint, error) {
+	src, err := migrationSource(dsn)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	return latestVersion(src)
+}
+
+// Migrate applies all pending migrations. It is a no-op for storages without migrations.
+func Migrate(dsn string) error {
+	m, err := NewMigrator(dsn)
+	if err != nil {
+		if errors.Is(err, ErrNoMigrations) {
+			return nil
+		}
+		return err
+	}
+	defer m.Close()
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Status reports the applied migration version and the latest embedded one.
+func (m *Migrator) Status() (status MigrationStatus, err error) {
+	status.Version, status.Dirty, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return
+	}
+	status.Latest, err = latestVersion(m.source)
+	return
+}
+
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return version, nil
+			}
+			return 0, err
+		}
+		version = next
+	}
+}
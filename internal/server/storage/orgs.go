@@ -0,0 +1,457 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type OrgRepository struct {
+	db      *sql.DB
+	dialect Dialect
+	stmts   map[string]*sql.Stmt
+}
+
+func NewOrgRepository(ctx context.Context, db *sql.DB, dialect Dialect) (interfaces.OrgRepository, error) {
+	r := &OrgRepository{
+		db:      db,
+		dialect: dialect,
+		stmts:   make(map[string]*sql.Stmt, 7),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *OrgRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"ListOrgs": `SELECT o.id, o.name, o.key_version, o.rekey_needed, o.created_at, m.role, m.key
+			FROM organizations o JOIN org_members m ON m.org_id = o.id
+			WHERE m.user_id = $1 ORDER BY o.name`,
+		"GetOrg": `SELECT o.id, o.name, o.key_version, o.rekey_needed, o.created_at, m.role, m.key
+			FROM organizations o JOIN org_members m ON m.org_id = o.id
+			WHERE o.id = $1 AND m.user_id = $2`,
+		"DeleteOrg": `DELETE FROM organizations WHERE id = $1`,
+		"ListOrgMembers": `SELECT m.org_id, m.user_id, u.login, m.role, m.key, k.public_key, m.created_at
+			FROM org_members m JOIN users u ON u.id = m.user_id JOIN user_keys k ON k.user_id = m.user_id
+			WHERE m.org_id = $1 ORDER BY u.login`,
+		"GetOrgMember": `SELECT m.org_id, m.user_id, u.login, m.role, m.key, k.public_key, m.created_at
+			FROM org_members m JOIN users u ON u.id = m.user_id JOIN user_keys k ON k.user_id = m.user_id
+			WHERE m.org_id = $1 AND m.user_id = $2`,
+		"GetOrgRecords":   `SELECT id, org_id, type, data, nonce, version FROM org_records WHERE org_id = $1`,
+		"DeleteOrgRecord": `DELETE FROM org_records WHERE org_id = $1 AND id = $2`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *OrgRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *OrgRepository) CreateOrg(ctx context.Context, org *models.Organization, owner *models.OrgMember) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	org.KeyVersion = 1
+	if err = tx.QueryRowContext(ctx,
+		`INSERT INTO organizations (id, name, key_version) VALUES ($1, $2, $3) RETURNING created_at`,
+		org.ID, org.Name, org.KeyVersion,
+	).Scan(&org.CreatedAt); err != nil {
+		return err
+	}
+	owner.OrgID = org.ID
+	if err = tx.QueryRowContext(ctx,
+		`INSERT INTO org_members (org_id, user_id, role, key) VALUES ($1, $2, $3, $4) RETURNING created_at`,
+		owner.OrgID, owner.UserID, owner.Role, owner.Key,
+	).Scan(&owner.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) ListOrgs(ctx context.Context, userID string) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	rows, err := r.stmts["ListOrgs"].QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		org, err := scanOrg(rows)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+func (r *OrgRepository) GetOrg(ctx context.Context, orgID uuid.UUID, userID string) (*models.Organization, error) {
+	org, err := scanOrg(r.stmts["GetOrg"].QueryRowContext(ctx, orgID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrNotFound
+	}
+	return org, err
+}
+
+func (r *OrgRepository) DeleteOrg(ctx context.Context, orgID uuid.UUID) error {
+	return execAffectingOne(r.stmts["DeleteOrg"].ExecContext(ctx, orgID))
+}
+
+func (r *OrgRepository) ListOrgMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrgMember, error) {
+	var members []*models.OrgMember
+	rows, err := r.stmts["ListOrgMembers"].QueryContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member, err := scanOrgMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *OrgRepository) GetOrgMember(ctx context.Context, orgID uuid.UUID, userID string) (*models.OrgMember, error) {
+	member, err := scanOrgMember(r.stmts["GetOrgMember"].QueryRowContext(ctx, orgID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrNotFound
+	}
+	return member, err
+}
+
+func (r *OrgRepository) AddOrgMember(ctx context.Context, member *models.OrgMember, keyVersion int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.lockOrgKeyVersion(ctx, tx, member.OrgID, keyVersion); err != nil {
+		return err
+	}
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO org_members (org_id, user_id, role, key) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, user_id) DO NOTHING RETURNING created_at`,
+		member.OrgID, member.UserID, member.Role, member.Key,
+	).Scan(&member.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrMemberExists
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) SetOrgMemberRole(ctx context.Context, orgID uuid.UUID, userID string, role models.OrgRole) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if role != models.OrgRoleOwner {
+		if err = r.checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+	if err = execAffectingOne(tx.ExecContext(ctx,
+		`UPDATE org_members SET role = $1 WHERE org_id = $2 AND user_id = $3`, role, orgID, userID,
+	)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) DeleteOrgMember(ctx context.Context, orgID uuid.UUID, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+		return err
+	}
+	if err = execAffectingOne(tx.ExecContext(ctx,
+		`DELETE FROM org_members WHERE org_id = $1 AND user_id = $2`, orgID, userID,
+	)); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE organizations SET rekey_needed = true WHERE id = $1`, orgID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) RekeyOrg(ctx context.Context, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.lockOrgKeyVersion(ctx, tx, orgID, keyVersion-1); err != nil {
+		return err
+	}
+
+	members, err := orgMemberIDs(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if len(members) != len(keys) {
+		return interfaces.ErrInvalidOrg
+	}
+	for _, userID := range members {
+		if len(keys[userID]) == 0 {
+			return interfaces.ErrInvalidOrg
+		}
+	}
+
+	versions, err := orgRecordVersions(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if len(versions) != len(records) {
+		return interfaces.ErrInvalidOrg
+	}
+	for _, rec := range records {
+		version, ok := versions[rec.ID]
+		if !ok {
+			return interfaces.ErrInvalidOrg
+		}
+		if rec.Version-version != 1 {
+			return interfaces.ErrVersionConflict
+		}
+	}
+
+	for userID, key := range keys {
+		if _, err = tx.ExecContext(ctx,
+			`UPDATE org_members SET key = $1 WHERE org_id = $2 AND user_id = $3`, key, orgID, userID,
+		); err != nil {
+			return err
+		}
+	}
+	for _, rec := range records {
+		if _, err = tx.ExecContext(ctx,
+			`UPDATE org_records SET type = $1, data = $2, nonce = $3, version = $4 WHERE org_id = $5 AND id = $6`,
+			rec.Type, rec.Data, rec.Nonce, rec.Version, orgID, rec.ID,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE organizations SET key_version = $1, rekey_needed = false WHERE id = $2`, keyVersion, orgID,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) GetOrgRecords(ctx context.Context, orgID uuid.UUID) ([]*models.OrgRecord, error) {
+	var records []*models.OrgRecord
+	rows, err := r.stmts["GetOrgRecords"].QueryContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec models.OrgRecord
+		if err := rows.Scan(&rec.ID, &rec.OrgID, &rec.Type, &rec.Data, &rec.Nonce, &rec.Version); err != nil {
+			return nil, err
+		}
+		records = append(records, &rec)
+	}
+	return records, rows.Err()
+}
+
+func (r *OrgRepository) SaveOrgRecord(ctx context.Context, rec *models.OrgRecord, keyVersion int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.lockOrgKeyVersion(ctx, tx, rec.OrgID, keyVersion); err != nil {
+		return err
+	}
+	var currentVersion int
+	err = tx.QueryRowContext(ctx,
+		`SELECT version FROM org_records WHERE org_id = $1 AND id = $2`, rec.OrgID, rec.ID,
+	).Scan(&currentVersion)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if rec.Version != 1 {
+			return interfaces.ErrVersionConflict
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO org_records (id, org_id, type, data, nonce, version) VALUES ($1, $2, $3, $4, $5, $6)`,
+			rec.ID, rec.OrgID, rec.Type, rec.Data, rec.Nonce, rec.Version,
+		)
+	case err != nil:
+		return err
+	case rec.Version-currentVersion != 1:
+		return interfaces.ErrVersionConflict
+	default:
+		_, err = tx.ExecContext(ctx,
+			`UPDATE org_records SET type = $1, data = $2, nonce = $3, version = $4 WHERE org_id = $5 AND id = $6`,
+			rec.Type, rec.Data, rec.Nonce, rec.Version, rec.OrgID, rec.ID,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *OrgRepository) DeleteOrgRecord(ctx context.Context, orgID, id uuid.UUID) error {
+	return execAffectingOne(r.stmts["DeleteOrgRecord"].ExecContext(ctx, orgID, id))
+}
+
+// lockOrgKeyVersion locks the organization for the transaction and checks
+// that its key version is keyVersion, so nothing is saved with a key that
+// was replaced meanwhile.
+func (r *OrgRepository) lockOrgKeyVersion(ctx context.Context, tx *sql.Tx, orgID uuid.UUID, keyVersion int64) error {
+	var current int64
+	err := tx.QueryRowContext(ctx,
+		r.dialect.forUpdate(`SELECT key_version FROM organizations WHERE id = $1`), orgID,
+	).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrNotFound
+		}
+		return err
+	}
+	if current != keyVersion {
+		return interfaces.ErrVersionConflict
+	}
+	return nil
+}
+
+// checkNotLastOwner locks the organization for the transaction and returns
+// ErrLastOwner if the user is its only owner, so that concurrent changes of
+// the owners cannot both pass the check.
+func (r *OrgRepository) checkNotLastOwner(ctx context.Context, tx *sql.Tx, orgID uuid.UUID, userID string) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		r.dialect.forUpdate(`SELECT true FROM organizations WHERE id = $1`), orgID,
+	).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrNotFound
+		}
+		return err
+	}
+
+	var last bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM org_members WHERE org_id = $1 AND user_id = $2 AND role = $3)
+			AND NOT EXISTS (SELECT 1 FROM org_members WHERE org_id = $1 AND user_id <> $2 AND role = $3)`,
+		orgID, userID, models.OrgRoleOwner,
+	).Scan(&last); err != nil {
+		return err
+	}
+	if last {
+		return interfaces.ErrLastOwner
+	}
+	return nil
+}
+
+func orgMemberIDs(ctx context.Context, tx *sql.Tx, orgID uuid.UUID) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT user_id FROM org_members WHERE org_id = $1`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func orgRecordVersions(ctx context.Context, tx *sql.Tx, orgID uuid.UUID) (map[uuid.UUID]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, version FROM org_records WHERE org_id = $1`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var id uuid.UUID
+		var version int
+		if err := rows.Scan(&id, &version); err != nil {
+			return nil, err
+		}
+		versions[id] = version
+	}
+	return versions, rows.Err()
+}
+
+func scanOrg(row rowScanner) (*models.Organization, error) {
+	var org models.Organization
+	if err := row.Scan(
+		&org.ID,
+		&org.Name,
+		&org.KeyVersion,
+		&org.RekeyNeeded,
+		&org.CreatedAt,
+		&org.Role,
+		&org.Key,
+	); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func scanOrgMember(row rowScanner) (*models.OrgMember, error) {
+	var member models.OrgMember
+	if err := row.Scan(
+		&member.OrgID,
+		&member.UserID,
+		&member.Login,
+		&member.Role,
+		&member.Key,
+		&member.PublicKey,
+		&member.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
@@ -11,14 +11,16 @@ import (
 )
 
 type RecordRepository struct {
-	db    *sql.DB
-	stmts map[string]*sql.Stmt
+	db      *sql.DB
+	dialect Dialect
+	stmts   map[string]*sql.Stmt
 }
 
-func NewRecordRepository(ctx context.Context, db *sql.DB) (interfaces.RecordRepository, error) {
+func NewRecordRepository(ctx context.Context, db *sql.DB, dialect Dialect) (interfaces.RecordRepository, error) {
 	r := &RecordRepository{
-		db:    db,
-		stmts: make(map[string]*sql.Stmt, 5),
+		db:      db,
+		dialect: dialect,
+		stmts:   make(map[string]*sql.Stmt, 6),
 	}
 	if err := r.initStatements(ctx); err != nil {
 		return nil, err
@@ -29,11 +31,12 @@ func NewRecordRepository(ctx context.Context, db *sql.DB) (interfaces.RecordRepo
 
 func (r *RecordRepository) initStatements(ctx context.Context) error {
 	queries := map[string]string{
-		"GetRecords":   `SELECT * FROM records WHERE user_id = $1`,
-		"CreateRecord": `INSERT INTO records (id, user_id, type, data, nonce, version) VALUES ($1, $2, $3, $4, $5, $6)`,
-		"ExistsRecord": `SELECT EXISTS (SELECT 1 FROM records WHERE id = $1 AND user_id = $2) as exists`,
-		"GetRecord":    `SELECT * FROM records WHERE id = $1 AND user_id = $2 LIMIT 1`,
-		"DeleteRecord": `DELETE FROM records WHERE id = $1 AND user_id = $2`,
+		"GetRecords":     `SELECT id, user_id, type, data, nonce, version, key FROM records WHERE user_id = $1`,
+		"CreateRecord":   `INSERT INTO records (id, user_id, type, data, nonce, version, key) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		"ExistsRecord":   `SELECT EXISTS (SELECT 1 FROM records WHERE id = $1 AND user_id = $2) AS "exists"`,
+		"GetRecord":      `SELECT id, user_id, type, data, nonce, version, key FROM records WHERE id = $1 AND user_id = $2 LIMIT 1`,
+		"DeleteRecord":   `DELETE FROM records WHERE id = $1 AND user_id = $2`,
+		"GetRecordStats": `SELECT user_id, count(*), coalesce(sum(length(data)), 0) FROM records GROUP BY user_id`,
 	}
 	for key, query := range queries {
 		stmt, err := r.db.PrepareContext(ctx, query)
@@ -74,6 +77,7 @@ func (r *RecordRepository) GetRecords(ctx context.Context, userID string) ([]*mo
 			&record.Data,
 			&record.Nonce,
 			&record.Version,
+			&record.Key,
 		); err != nil {
 			return nil, err
 		}
@@ -88,7 +92,7 @@ func (r *RecordRepository) GetRecords(ctx context.Context, userID string) ([]*mo
 }
 
 func (r *RecordRepository) CreateRecord(ctx context.Context, rec *models.Record) error {
-	if _, err := r.stmts["CreateRecord"].ExecContext(ctx, rec.ID, rec.UserID, rec.Type, rec.Data, rec.Nonce, rec.Version); err != nil {
+	if _, err := r.stmts["CreateRecord"].ExecContext(ctx, rec.ID, rec.UserID, rec.Type, rec.Data, rec.Nonce, rec.Version, rec.Key); err != nil {
 		return err
 	}
 	return nil
@@ -106,7 +110,7 @@ func (r *RecordRepository) UpdateOrCreateRecord(ctx context.Context, rec *models
 }
 
 func (r *RecordRepository) UpdateRecord(ctx context.Context, rec *models.Record) error {
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -114,18 +118,21 @@ func (r *RecordRepository) UpdateRecord(ctx context.Context, rec *models.Record)
 
 	var currentVersion int
 	err = tx.QueryRowContext(ctx,
-		"SELECT version FROM records WHERE id = $1 AND user_id = $2 FOR UPDATE",
+		r.dialect.forUpdate("SELECT version FROM records WHERE id = $1 AND user_id = $2"),
 		rec.ID, rec.UserID,
 	).Scan(&currentVersion)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrNotFound
+		}
 		return err
 	}
 	if rec.Version-currentVersion != 1 {
 		return interfaces.ErrVersionConflict
 	}
 	_, err = tx.ExecContext(ctx,
-		"UPDATE records SET type = $1, data = $2, nonce = $3, version = $4 WHERE id = $5 AND user_id = $6",
-		rec.Type, rec.Data, rec.Nonce, rec.Version, rec.ID, rec.UserID,
+		"UPDATE records SET type = $1, data = $2, nonce = $3, version = $4, key = $5 WHERE id = $6 AND user_id = $7",
+		rec.Type, rec.Data, rec.Nonce, rec.Version, rec.Key, rec.ID, rec.UserID,
 	)
 	if err != nil {
 		return err
@@ -142,6 +149,7 @@ func (r *RecordRepository) GetRecord(ctx context.Context, userID string, id uuid
 		&rec.Data,
 		&rec.Nonce,
 		&rec.Version,
+		&rec.Key,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, interfaces.ErrNotFound
@@ -157,3 +165,27 @@ func (r *RecordRepository) DeleteRecord(ctx context.Context, userID string, id u
 	}
 	return nil
 }
+
+func (r *RecordRepository) GetRecordStats(ctx context.Context) (map[string]models.RecordStats, error) {
+	stats := make(map[string]models.RecordStats)
+	rows, err := r.stmts["GetRecordStats"].QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var userStats models.RecordStats
+		if err := rows.Scan(&userID, &userStats.Count, &userStats.Bytes); err != nil {
+			return nil, err
+		}
+		stats[userID] = userStats
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
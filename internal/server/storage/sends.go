@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type SendRepository struct {
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func NewSendRepository(ctx context.Context, db *sql.DB) (interfaces.SendRepository, error) {
+	r := &SendRepository{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt, 5),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+const sendColumns = `id, user_id, data, nonce, password_hash, max_views, views_left, expires_at, created_at`
+
+func (r *SendRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"CreateSend": `INSERT INTO sends (id, user_id, data, nonce, password_hash, max_views, views_left, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		"GetSend": `SELECT ` + sendColumns + ` FROM sends WHERE id = $1 AND views_left > 0 AND expires_at > $2`,
+		"UseSend": `UPDATE sends SET views_left = views_left - 1
+			WHERE id = $1 AND views_left > 0 AND expires_at > $2 RETURNING ` + sendColumns,
+		"DeleteUsedSend":     `DELETE FROM sends WHERE id = $1 AND views_left = 0`,
+		"DeleteExpiredSends": `DELETE FROM sends WHERE expires_at <= $1`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *SendRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *SendRepository) CreateSend(ctx context.Context, send *models.Send) error {
+	return r.stmts["CreateSend"].QueryRowContext(ctx,
+		send.ID, send.UserID, send.Data, send.Nonce, send.PasswordHash, send.MaxViews, send.ViewsLeft, send.ExpiresAt.UTC(),
+	).Scan(&send.CreatedAt)
+}
+
+func (r *SendRepository) GetSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error) {
+	return scanSend(r.stmts["GetSend"].QueryRowContext(ctx, id, now.UTC()))
+}
+
+func (r *SendRepository) UseSend(ctx context.Context, id uuid.UUID, now time.Time) (*models.Send, error) {
+	send, err := scanSend(r.stmts["UseSend"].QueryRowContext(ctx, id, now.UTC()))
+	if err != nil {
+		return nil, err
+	}
+	if send.ViewsLeft == 0 {
+		if _, err = r.stmts["DeleteUsedSend"].ExecContext(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return send, nil
+}
+
+func (r *SendRepository) DeleteExpiredSends(ctx context.Context, now time.Time) error {
+	_, err := r.stmts["DeleteExpiredSends"].ExecContext(ctx, now.UTC())
+	return err
+}
+
+func scanSend(row rowScanner) (*models.Send, error) {
+	var send models.Send
+	if err := row.Scan(
+		&send.ID,
+		&send.UserID,
+		&send.Data,
+		&send.Nonce,
+		&send.PasswordHash,
+		&send.MaxViews,
+		&send.ViewsLeft,
+		&send.ExpiresAt,
+		&send.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrNotFound
+		}
+		return nil, err
+	}
+	return &send, nil
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+type ShareRepository struct {
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func NewShareRepository(ctx context.Context, db *sql.DB) (interfaces.ShareRepository, error) {
+	r := &ShareRepository{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt, 6),
+	}
+	if err := r.initStatements(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *ShareRepository) initStatements(ctx context.Context) error {
+	queries := map[string]string{
+		"CreateUserKeys": `INSERT INTO user_keys (user_id, public_key, wrapped_private_key) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id) DO NOTHING RETURNING created_at`,
+		"GetUserKeys": `SELECT user_id, public_key, wrapped_private_key, created_at FROM user_keys WHERE user_id = $1`,
+		"SaveShare": `INSERT INTO record_shares (record_id, owner_id, recipient_id, key) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (record_id, owner_id, recipient_id) DO UPDATE SET key = excluded.key, created_at = CURRENT_TIMESTAMP
+			RETURNING created_at`,
+		"ListShares": `SELECT s.record_id, s.owner_id, s.recipient_id, u.login, s.key, s.created_at
+			FROM record_shares s JOIN users u ON u.id = s.recipient_id
+			WHERE s.owner_id = $1 AND s.record_id = $2 ORDER BY u.login`,
+		"DeleteShare": `DELETE FROM record_shares WHERE owner_id = $1 AND record_id = $2 AND recipient_id = $3`,
+		"GetSharedRecords": `SELECT r.id, r.user_id, r.type, r.data, r.nonce, r.version, s.key, u.login, s.created_at
+			FROM record_shares s
+			JOIN records r ON r.id = s.record_id AND r.user_id = s.owner_id
+			JOIN users u ON u.id = s.owner_id
+			WHERE s.recipient_id = $1 ORDER BY s.created_at`,
+	}
+	for key, query := range queries {
+		stmt, err := r.db.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		r.stmts[key] = stmt
+	}
+
+	return nil
+}
+
+func (r *ShareRepository) Close() error {
+	var errs []error
+	for _, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *ShareRepository) CreateUserKeys(ctx context.Context, keys *models.UserKeys) error {
+	err := r.stmts["CreateUserKeys"].QueryRowContext(ctx, keys.UserID, keys.PublicKey, keys.WrappedPrivateKey).Scan(&keys.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return interfaces.ErrKeysExist
+	}
+	return err
+}
+
+func (r *ShareRepository) GetUserKeys(ctx context.Context, userID string) (*models.UserKeys, error) {
+	var keys models.UserKeys
+	err := r.stmts["GetUserKeys"].QueryRowContext(ctx, userID).Scan(
+		&keys.UserID,
+		&keys.PublicKey,
+		&keys.WrappedPrivateKey,
+		&keys.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrNotFound
+		}
+		return nil, err
+	}
+	return &keys, nil
+}
+
+func (r *ShareRepository) SaveShare(ctx context.Context, share *models.Share) error {
+	return r.stmts["SaveShare"].QueryRowContext(ctx, share.RecordID, share.OwnerID, share.RecipientID, share.Key).Scan(&share.CreatedAt)
+}
+
+func (r *ShareRepository) ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) ([]*models.Share, error) {
+	var shares []*models.Share
+	rows, err := r.stmts["ListShares"].QueryContext(ctx, ownerID, recordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var share models.Share
+		if err := rows.Scan(
+			&share.RecordID,
+			&share.OwnerID,
+			&share.RecipientID,
+			&share.RecipientLogin,
+			&share.Key,
+			&share.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		shares = append(shares, &share)
+	}
+	return shares, rows.Err()
+}
+
+func (r *ShareRepository) DeleteShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) error {
+	return execAffectingOne(r.stmts["DeleteShare"].ExecContext(ctx, ownerID, recordID, recipientID))
+}
+
+func (r *ShareRepository) GetSharedRecords(ctx context.Context, recipientID string) ([]*models.SharedRecord, error) {
+	var records []*models.SharedRecord
+	rows, err := r.stmts["GetSharedRecords"].QueryContext(ctx, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record models.SharedRecord
+		if err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.Type,
+			&record.Data,
+			&record.Nonce,
+			&record.Version,
+			&record.Key,
+			&record.OwnerLogin,
+			&record.SharedAt,
+		); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	return records, rows.Err()
+}
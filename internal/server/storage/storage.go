@@ -4,41 +4,115 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/storage/memory"
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
 type Storage struct {
 	interfaces.UserRepository
 	interfaces.RecordRepository
+	interfaces.InviteRepository
+	interfaces.AuditRepository
+	interfaces.AccessTokenRepository
+	interfaces.ShareRepository
+	interfaces.OrgRepository
+	interfaces.EmergencyRepository
+	interfaces.SendRepository
 	db *sql.DB
 }
 
-func New(ctx context.Context, dsn, migrationsPath string) (interfaces.Storage, error) {
+// New opens the storage backend selected by the scheme of dsn:
+// postgres:// and postgresql:// for PostgreSQL, sqlite:// for SQLite
+// and memory:// for a non-persistent in-memory storage.
+// The schema is expected to be up to date, see Migrate.
+func New(ctx context.Context, dsn string) (interfaces.Storage, error) {
+	switch scheme(dsn) {
+	case "postgres", "postgresql":
+		return NewPostgres(ctx, dsn)
+	case "sqlite":
+		return NewSQLite(ctx, dsn)
+	case "memory":
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported database scheme: %q", scheme(dsn))
+	}
+}
+
+func scheme(dsn string) string {
+	scheme, _, _ := strings.Cut(dsn, "://")
+	return scheme
+}
+
+func NewPostgres(ctx context.Context, dsn string) (interfaces.Storage, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
+	return newStorage(ctx, db, Postgres)
+}
 
-	m, err := migrate.New(migrationsPath, dsn)
+// NewSQLite opens the SQLite database at the path of a sqlite:// dsn,
+// e.g. sqlite:///var/lib/gophkeeper/gophkeeper.db.
+func NewSQLite(ctx context.Context, dsn string) (interfaces.Storage, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(dsn))
 	if err != nil {
 		return nil, err
 	}
-	defer m.Close()
-	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
-		return nil, err
+	// SQLite allows a single writer, serialize access instead of failing with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	return newStorage(ctx, db, SQLite)
+}
+
+func sqliteDSN(dsn string) string {
+	path := strings.TrimPrefix(dsn, "sqlite://")
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
 	}
+	return path + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
 
+func newStorage(ctx context.Context, db *sql.DB, dialect Dialect) (interfaces.Storage, error) {
+	var err error
 	storage := &Storage{db: db}
-	storage.UserRepository, err = NewUserRepository(ctx, db)
+	storage.UserRepository, err = NewUserRepository(ctx, db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	storage.RecordRepository, err = NewRecordRepository(ctx, db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	storage.InviteRepository, err = NewInviteRepository(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	storage.AuditRepository, err = NewAuditRepository(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	storage.AccessTokenRepository, err = NewAccessTokenRepository(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	storage.ShareRepository, err = NewShareRepository(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	storage.OrgRepository, err = NewOrgRepository(ctx, db, dialect)
 	if err != nil {
 		return nil, err
 	}
-	storage.RecordRepository, err = NewRecordRepository(ctx, db)
+	storage.EmergencyRepository, err = NewEmergencyRepository(ctx, db, dialect)
+	if err != nil {
+		return nil, err
+	}
+	storage.SendRepository, err = NewSendRepository(ctx, db)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +128,50 @@ func (s *Storage) Close() error {
 	if err := s.RecordRepository.Close(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := s.InviteRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.AuditRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.AccessTokenRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.ShareRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.OrgRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.EmergencyRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.SendRepository.Close(); err != nil {
+		errs = append(errs, err)
+	}
 	if err := s.db.Close(); err != nil {
 		errs = append(errs, err)
 	}
 
 	return errors.Join(errs...)
 }
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// SchemaVersion reads the version table of golang-migrate, see Migrate.
+func (s *Storage) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
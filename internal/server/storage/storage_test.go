@@ -0,0 +1,25 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/storage"
+	"github.com/grnsv/GophKeeper/internal/server/storage/storagetest"
+)
+
+func TestSQLite(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) interfaces.Storage {
+		dsn := "sqlite://" + filepath.Join(t.TempDir(), "gophkeeper.db")
+		if err := storage.Migrate(dsn); err != nil {
+			t.Fatalf("Migrate: %v", err)
+		}
+		s, err := storage.NewSQLite(context.Background(), dsn)
+		if err != nil {
+			t.Fatalf("NewSQLite: %v", err)
+		}
+		return s
+	})
+}
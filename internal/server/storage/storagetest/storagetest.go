@@ -0,0 +1,895 @@
+// Package storagetest implements a conformance suite for interfaces.Storage
+// implementations, in the spirit of testing/fstest.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+)
+
+// Run executes the conformance suite. newStorage must return an empty storage,
+// it is called once per subtest and the storage is closed when the subtest ends.
+func Run(t *testing.T, newStorage func(t *testing.T) interfaces.Storage) {
+	tests := map[string]func(t *testing.T, s interfaces.Storage){
+		"Users":           testUsers,
+		"UserAdmin":       testUserAdmin,
+		"UserDelete":      testUserDelete,
+		"RecordStats":     testRecordStats,
+		"Invites":         testInvites,
+		"AuditEvents":     testAuditEvents,
+		"AccessTokens":    testAccessTokens,
+		"Shares":          testShares,
+		"Orgs":            testOrgs,
+		"OrgOwners":       testOrgOwners,
+		"Emergency":       testEmergency,
+		"Sends":           testSends,
+		"RecordVersions":  testRecordVersions,
+		"RecordIsolation": testRecordIsolation,
+		"RecordDelete":    testRecordDelete,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := newStorage(t)
+			t.Cleanup(func() {
+				if err := s.Close(); err != nil {
+					t.Errorf("Close: %v", err)
+				}
+			})
+			test(t, s)
+		})
+	}
+}
+
+func testUsers(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+
+	exists, err := s.IsLoginExists(ctx, "alice")
+	if err != nil {
+		t.Fatalf("IsLoginExists: %v", err)
+	}
+	if exists {
+		t.Fatal("IsLoginExists reported an unknown login")
+	}
+
+	user := &models.User{Login: "alice", PasswordHash: "hash"}
+	if err = s.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.ID == "" {
+		t.Fatal("CreateUser did not assign an ID")
+	}
+
+	if exists, err = s.IsLoginExists(ctx, "alice"); err != nil {
+		t.Fatalf("IsLoginExists: %v", err)
+	}
+	if !exists {
+		t.Fatal("IsLoginExists did not report a created login")
+	}
+
+	found, err := s.FindUserByLogin(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindUserByLogin: %v", err)
+	}
+	if found.ID != user.ID || found.Login != user.Login || found.PasswordHash != user.PasswordHash {
+		t.Fatalf("FindUserByLogin = %+v, want %+v", found, user)
+	}
+
+	if _, err = s.FindUserByLogin(ctx, "bob"); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("FindUserByLogin of an unknown login: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+
+	err = s.CreateUser(ctx, &models.User{Login: "alice", PasswordHash: "other"})
+	if !errors.Is(err, interfaces.ErrLoginTaken) {
+		t.Fatalf("CreateUser with a taken login: got %v, want %v", err, interfaces.ErrLoginTaken)
+	}
+}
+
+func testUserAdmin(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	aliceID := createUser(t, s, "alice")
+	createUser(t, s, "bob")
+	createUser(t, s, "malice")
+
+	users, err := s.ListUsers(ctx, "LIC")
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(users) != 2 || users[0].Login != "alice" || users[1].Login != "malice" {
+		t.Fatalf("ListUsers returned %d users, want alice and malice", len(users))
+	}
+	if users, err = s.ListUsers(ctx, ""); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("ListUsers with an empty search returned %d users, want 3", len(users))
+	}
+
+	user, err := s.FindUserByID(ctx, aliceID)
+	if err != nil {
+		t.Fatalf("FindUserByID: %v", err)
+	}
+	if user.Login != "alice" || user.Disabled || !user.SessionsRevokedAt.IsZero() {
+		t.Fatalf("FindUserByID = %+v, want an active alice", user)
+	}
+
+	if err = s.SetUserDisabled(ctx, aliceID, true); err != nil {
+		t.Fatalf("SetUserDisabled: %v", err)
+	}
+	revokedAt := time.Now().Truncate(time.Second)
+	if err = s.RevokeUserSessions(ctx, aliceID, revokedAt); err != nil {
+		t.Fatalf("RevokeUserSessions: %v", err)
+	}
+	if user, err = s.FindUserByLogin(ctx, "alice"); err != nil {
+		t.Fatalf("FindUserByLogin: %v", err)
+	}
+	if !user.Disabled || !user.SessionsRevokedAt.Equal(revokedAt) {
+		t.Fatalf("FindUserByLogin = %+v, want disabled with sessions revoked at %v", user, revokedAt)
+	}
+
+	if err = s.SetUserDisabled(ctx, aliceID, false); err != nil {
+		t.Fatalf("SetUserDisabled: %v", err)
+	}
+	if user, err = s.FindUserByID(ctx, aliceID); err != nil {
+		t.Fatalf("FindUserByID: %v", err)
+	}
+	if user.Disabled {
+		t.Fatal("SetUserDisabled did not enable the user")
+	}
+
+	unknownID := uuid.NewString()
+	if _, err = s.FindUserByID(ctx, unknownID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("FindUserByID of an unknown user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err = s.SetUserDisabled(ctx, unknownID, true); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("SetUserDisabled of an unknown user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+}
+
+func testUserDelete(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	userID := createUser(t, s, "alice")
+	rec := newRecord(userID, 1)
+	if err := s.CreateRecord(ctx, rec); err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+
+	if err := s.DeleteUser(ctx, userID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := s.FindUserByID(ctx, userID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("FindUserByID of a deleted user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if _, err := s.GetRecord(ctx, userID, rec.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetRecord of a deleted user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.DeleteUser(ctx, userID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteUser of a deleted user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+
+	createUser(t, s, "alice")
+}
+
+func testRecordStats(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	aliceID := createUser(t, s, "alice")
+	bobID := createUser(t, s, "bob")
+	for range 2 {
+		if err := s.CreateRecord(ctx, newRecord(aliceID, 1)); err != nil {
+			t.Fatalf("CreateRecord: %v", err)
+		}
+	}
+
+	stats, err := s.GetRecordStats(ctx)
+	if err != nil {
+		t.Fatalf("GetRecordStats: %v", err)
+	}
+	want := models.RecordStats{Count: 2, Bytes: int64(2 * len("data"))}
+	if stats[aliceID] != want {
+		t.Fatalf("GetRecordStats for alice = %+v, want %+v", stats[aliceID], want)
+	}
+	if _, ok := stats[bobID]; ok {
+		t.Fatalf("GetRecordStats reported records for a user without records")
+	}
+}
+
+func testInvites(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	now := time.Now()
+	single := &models.Invite{CodeHash: "single", MaxUses: 1}
+	unlimited := &models.Invite{CodeHash: "unlimited", ExpiresAt: now.Add(time.Hour)}
+	expired := &models.Invite{CodeHash: "expired", MaxUses: 1, ExpiresAt: now.Add(-time.Hour)}
+	for _, invite := range []*models.Invite{single, unlimited, expired} {
+		if err := s.CreateInvite(ctx, invite); err != nil {
+			t.Fatalf("CreateInvite: %v", err)
+		}
+		if invite.ID == "" {
+			t.Fatal("CreateInvite did not assign an ID")
+		}
+	}
+
+	for _, codeHash := range []string{"single", "single", "unlimited"} {
+		valid, err := s.IsInviteValid(ctx, codeHash, now)
+		if err != nil {
+			t.Fatalf("IsInviteValid: %v", err)
+		}
+		if !valid {
+			t.Fatalf("IsInviteValid reported the invite %q as invalid", codeHash)
+		}
+	}
+	if err := s.UseInvite(ctx, "single", now); err != nil {
+		t.Fatalf("UseInvite: %v", err)
+	}
+	invalid := map[string]time.Time{"single": now, "unlimited": now.Add(2 * time.Hour), "expired": now, "unknown": now}
+	for codeHash, at := range invalid {
+		valid, err := s.IsInviteValid(ctx, codeHash, at)
+		if err != nil {
+			t.Fatalf("IsInviteValid: %v", err)
+		}
+		if valid {
+			t.Fatalf("IsInviteValid reported the invite %q as valid", codeHash)
+		}
+	}
+	if err := s.UseInvite(ctx, "single", now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseInvite of a used up invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.ReleaseInvite(ctx, "single"); err != nil {
+		t.Fatalf("ReleaseInvite: %v", err)
+	}
+	if err := s.ReleaseInvite(ctx, "single"); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("ReleaseInvite of an unused invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.UseInvite(ctx, "single", now); err != nil {
+		t.Fatalf("UseInvite of a released invite: %v", err)
+	}
+	for range 3 {
+		if err := s.UseInvite(ctx, "unlimited", now); err != nil {
+			t.Fatalf("UseInvite of an unlimited invite: %v", err)
+		}
+	}
+	if err := s.UseInvite(ctx, "unlimited", now.Add(2*time.Hour)); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseInvite after expiry: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.UseInvite(ctx, "expired", now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseInvite of an expired invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.UseInvite(ctx, "unknown", now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseInvite of an unknown invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+
+	invites, err := s.ListInvites(ctx)
+	if err != nil {
+		t.Fatalf("ListInvites: %v", err)
+	}
+	uses := make(map[string]int, len(invites))
+	for _, invite := range invites {
+		uses[invite.CodeHash] = invite.Uses
+	}
+	if len(invites) != 3 || uses["single"] != 1 || uses["unlimited"] != 3 || uses["expired"] != 0 {
+		t.Fatalf("ListInvites reported uses %v", uses)
+	}
+
+	if err = s.DeleteInvite(ctx, unlimited.ID); err != nil {
+		t.Fatalf("DeleteInvite: %v", err)
+	}
+	if err = s.UseInvite(ctx, "unlimited", now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseInvite of a deleted invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err = s.DeleteInvite(ctx, unlimited.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteInvite of a deleted invite: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+}
+
+func testAuditEvents(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	events := []*models.AuditEvent{
+		{UserID: alice, Type: models.AuditRegister, IP: "127.0.0.1", UserAgent: "test", RequestID: "1"},
+		{UserID: bob, Type: models.AuditLogin},
+		{Type: models.AuditLoginFailed, Details: "unknown login"},
+		{UserID: alice, Type: models.AuditLogin},
+		{UserID: alice, Type: models.AuditRecordDelete, Details: "record"},
+	}
+	for _, event := range events {
+		if err := s.CreateAuditEvent(ctx, event); err != nil {
+			t.Fatalf("CreateAuditEvent: %v", err)
+		}
+		if event.ID == 0 || event.CreatedAt.IsZero() {
+			t.Fatal("CreateAuditEvent did not assign an ID and creation time")
+		}
+	}
+
+	got, err := s.ListAuditEvents(ctx, alice, 2)
+	if err != nil {
+		t.Fatalf("ListAuditEvents: %v", err)
+	}
+	if len(got) != 2 || got[0].Type != models.AuditRecordDelete || got[1].Type != models.AuditLogin {
+		t.Fatalf("ListAuditEvents did not return the latest events first: %+v", got)
+	}
+	if got, err = s.ListAuditEvents(ctx, alice, 10); err != nil || len(got) != 3 {
+		t.Fatalf("ListAuditEvents: got %d events, %v; want 3", len(got), err)
+	}
+	first := got[2]
+	if first.UserID != alice || first.IP != "127.0.0.1" || first.UserAgent != "test" || first.RequestID != "1" {
+		t.Fatalf("ListAuditEvents returned %+v", first)
+	}
+
+	if err = s.DeleteUser(ctx, alice); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if got, err = s.ListAuditEvents(ctx, alice, 10); err != nil || len(got) != 0 {
+		t.Fatalf("ListAuditEvents of a deleted user: got %d events, %v; want none", len(got), err)
+	}
+	if got, err = s.ListAuditEvents(ctx, bob, 10); err != nil || len(got) != 1 {
+		t.Fatalf("ListAuditEvents of another user: got %d events, %v; want 1", len(got), err)
+	}
+}
+
+func testAccessTokens(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	recordID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	all := &models.AccessToken{
+		ID:         uuid.NewString(),
+		UserID:     alice,
+		Name:       "backup",
+		SecretHash: "hash1",
+		ReadOnly:   true,
+		WrappedKey: []byte("vault key"),
+		ExpiresAt:  expiresAt,
+	}
+	scoped := &models.AccessToken{
+		ID:         uuid.NewString(),
+		UserID:     alice,
+		Name:       "ci",
+		SecretHash: "hash2",
+		Records:    map[uuid.UUID][]byte{recordID: []byte("record key")},
+		Tags:       []string{"env=ci"},
+		ExpiresAt:  expiresAt,
+	}
+	for _, token := range []*models.AccessToken{all, scoped} {
+		if err := s.CreateAccessToken(ctx, token); err != nil {
+			t.Fatalf("CreateAccessToken: %v", err)
+		}
+		if token.CreatedAt.IsZero() {
+			t.Fatal("CreateAccessToken did not assign a creation time")
+		}
+	}
+
+	got, err := s.FindAccessToken(ctx, all.ID)
+	if err != nil {
+		t.Fatalf("FindAccessToken: %v", err)
+	}
+	if got.UserID != alice || !got.ReadOnly || string(got.WrappedKey) != "vault key" || got.Records != nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("FindAccessToken = %+v", got)
+	}
+	if got, err = s.FindAccessToken(ctx, scoped.ID); err != nil {
+		t.Fatalf("FindAccessToken: %v", err)
+	}
+	if string(got.Records[recordID]) != "record key" || len(got.Records) != 1 || len(got.Tags) != 1 || got.Tags[0] != "env=ci" {
+		t.Fatalf("FindAccessToken = %+v", got)
+	}
+	if !got.CanRead(recordID) || !got.CanWrite(recordID) || got.CanRead(uuid.New()) {
+		t.Fatal("a token limited to records allows access to other records")
+	}
+
+	usedAt := time.Now().UTC().Truncate(time.Second)
+	if err = s.TouchAccessToken(ctx, all.ID, usedAt); err != nil {
+		t.Fatalf("TouchAccessToken: %v", err)
+	}
+	list, err := s.ListAccessTokens(ctx, alice)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListAccessTokens: got %d tokens, %v; want 2", len(list), err)
+	}
+	for _, token := range list {
+		if token.ID == all.ID && !token.LastUsedAt.Equal(usedAt) {
+			t.Fatalf("TouchAccessToken did not set the last use: %v", token.LastUsedAt)
+		}
+	}
+
+	if err = s.DeleteAccessToken(ctx, bob, all.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteAccessToken of another user: got %v, want ErrNotFound", err)
+	}
+	if err = s.DeleteAccessToken(ctx, alice, all.ID); err != nil {
+		t.Fatalf("DeleteAccessToken: %v", err)
+	}
+	if _, err = s.FindAccessToken(ctx, all.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("FindAccessToken of a deleted token: got %v, want ErrNotFound", err)
+	}
+
+	if err = s.DeleteUser(ctx, alice); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err = s.FindAccessToken(ctx, scoped.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("FindAccessToken of a deleted user: got %v, want ErrNotFound", err)
+	}
+}
+
+func testShares(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	carol := createUser(t, s, "carol")
+
+	keys := &models.UserKeys{UserID: bob, PublicKey: []byte("public"), WrappedPrivateKey: []byte("private")}
+	if err := s.CreateUserKeys(ctx, keys); err != nil {
+		t.Fatalf("CreateUserKeys: %v", err)
+	}
+	if err := s.CreateUserKeys(ctx, &models.UserKeys{UserID: bob, PublicKey: []byte("other"), WrappedPrivateKey: []byte("other")}); !errors.Is(err, interfaces.ErrKeysExist) {
+		t.Fatalf("CreateUserKeys again: got %v, want ErrKeysExist", err)
+	}
+	gotKeys, err := s.GetUserKeys(ctx, bob)
+	if err != nil || string(gotKeys.PublicKey) != "public" || string(gotKeys.WrappedPrivateKey) != "private" {
+		t.Fatalf("GetUserKeys = %+v, %v", gotKeys, err)
+	}
+	if _, err = s.GetUserKeys(ctx, carol); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetUserKeys without keys: got %v, want ErrNotFound", err)
+	}
+
+	rec := newRecord(alice, 1)
+	if err = s.CreateRecord(ctx, rec); err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+	for _, recipient := range []string{bob, carol} {
+		if err = s.SaveShare(ctx, &models.Share{RecordID: rec.ID, OwnerID: alice, RecipientID: recipient, Key: []byte("old")}); err != nil {
+			t.Fatalf("SaveShare: %v", err)
+		}
+	}
+	if err = s.SaveShare(ctx, &models.Share{RecordID: rec.ID, OwnerID: alice, RecipientID: bob, Key: []byte("shared")}); err != nil {
+		t.Fatalf("SaveShare again: %v", err)
+	}
+
+	shares, err := s.ListShares(ctx, alice, rec.ID)
+	if err != nil || len(shares) != 2 {
+		t.Fatalf("ListShares: got %d shares, %v; want 2", len(shares), err)
+	}
+	if shares[0].RecipientLogin != "bob" || string(shares[0].Key) != "shared" {
+		t.Fatalf("ListShares[0] = %+v", shares[0])
+	}
+
+	shared, err := s.GetSharedRecords(ctx, bob)
+	if err != nil || len(shared) != 1 {
+		t.Fatalf("GetSharedRecords: got %d records, %v; want 1", len(shared), err)
+	}
+	if shared[0].ID != rec.ID || shared[0].OwnerLogin != "alice" || string(shared[0].Key) != "shared" || string(shared[0].Data) != string(rec.Data) {
+		t.Fatalf("GetSharedRecords = %+v", shared[0])
+	}
+
+	if err = s.DeleteShare(ctx, bob, rec.ID, carol); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteShare by another user: got %v, want ErrNotFound", err)
+	}
+	if err = s.DeleteShare(ctx, alice, rec.ID, carol); err != nil {
+		t.Fatalf("DeleteShare: %v", err)
+	}
+	if shared, _ = s.GetSharedRecords(ctx, carol); len(shared) != 0 {
+		t.Fatalf("GetSharedRecords after DeleteShare: got %d records, want 0", len(shared))
+	}
+
+	if err = s.DeleteRecord(ctx, alice, rec.ID); err != nil {
+		t.Fatalf("DeleteRecord: %v", err)
+	}
+	if shared, _ = s.GetSharedRecords(ctx, bob); len(shared) != 0 {
+		t.Fatalf("GetSharedRecords after DeleteRecord: got %d records, want 0", len(shared))
+	}
+}
+
+func testOrgs(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	for _, userID := range []string{alice, bob} {
+		if err := s.CreateUserKeys(ctx, &models.UserKeys{UserID: userID, PublicKey: []byte("public " + userID), WrappedPrivateKey: []byte("private")}); err != nil {
+			t.Fatalf("CreateUserKeys: %v", err)
+		}
+	}
+
+	org := &models.Organization{ID: uuid.New(), Name: "team"}
+	if err := s.CreateOrg(ctx, org, &models.OrgMember{UserID: alice, Role: models.OrgRoleOwner, Key: []byte("alice key")}); err != nil {
+		t.Fatalf("CreateOrg: %v", err)
+	}
+	if org.KeyVersion != 1 {
+		t.Fatalf("CreateOrg: key version %d, want 1", org.KeyVersion)
+	}
+	if _, err := s.GetOrg(ctx, org.ID, bob); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetOrg by non-member: got %v, want ErrNotFound", err)
+	}
+
+	member := &models.OrgMember{OrgID: org.ID, UserID: bob, Role: models.OrgRoleReader, Key: []byte("bob key")}
+	if err := s.AddOrgMember(ctx, member, 2); !errors.Is(err, interfaces.ErrVersionConflict) {
+		t.Fatalf("AddOrgMember with stale key version: got %v, want ErrVersionConflict", err)
+	}
+	if err := s.AddOrgMember(ctx, member, 1); err != nil {
+		t.Fatalf("AddOrgMember: %v", err)
+	}
+	if err := s.AddOrgMember(ctx, member, 1); !errors.Is(err, interfaces.ErrMemberExists) {
+		t.Fatalf("AddOrgMember again: got %v, want ErrMemberExists", err)
+	}
+	if err := s.SetOrgMemberRole(ctx, org.ID, bob, models.OrgRoleWriter); err != nil {
+		t.Fatalf("SetOrgMemberRole: %v", err)
+	}
+	got, err := s.GetOrg(ctx, org.ID, bob)
+	if err != nil || got.Name != "team" || got.Role != models.OrgRoleWriter || string(got.Key) != "bob key" {
+		t.Fatalf("GetOrg = %+v, %v", got, err)
+	}
+	members, err := s.ListOrgMembers(ctx, org.ID)
+	if err != nil || len(members) != 2 {
+		t.Fatalf("ListOrgMembers: got %d members, %v; want 2", len(members), err)
+	}
+	if members[0].Login != "alice" || members[0].Role != models.OrgRoleOwner || string(members[0].PublicKey) != "public "+alice {
+		t.Fatalf("ListOrgMembers[0] = %+v", members[0])
+	}
+
+	rec := &models.OrgRecord{ID: uuid.New(), OrgID: org.ID, Type: "text", Data: []byte("data"), Nonce: []byte("nonce"), Version: 1}
+	if err = s.SaveOrgRecord(ctx, rec, 1); err != nil {
+		t.Fatalf("SaveOrgRecord: %v", err)
+	}
+	if err = s.SaveOrgRecord(ctx, rec, 1); !errors.Is(err, interfaces.ErrVersionConflict) {
+		t.Fatalf("SaveOrgRecord same version: got %v, want ErrVersionConflict", err)
+	}
+
+	if err = s.DeleteOrgMember(ctx, org.ID, bob); err != nil {
+		t.Fatalf("DeleteOrgMember: %v", err)
+	}
+	if got, _ = s.GetOrg(ctx, org.ID, alice); !got.RekeyNeeded {
+		t.Fatal("GetOrg after DeleteOrgMember: re-key is not needed")
+	}
+	rekeyed := &models.OrgRecord{ID: rec.ID, OrgID: org.ID, Type: "text", Data: []byte("new data"), Nonce: []byte("new nonce"), Version: 2}
+	keys := map[string][]byte{alice: []byte("new alice key")}
+	if err = s.RekeyOrg(ctx, org.ID, 2, map[string][]byte{alice: []byte("x"), bob: []byte("x")}, []*models.OrgRecord{rekeyed}); !errors.Is(err, interfaces.ErrInvalidOrg) {
+		t.Fatalf("RekeyOrg with a removed member: got %v, want ErrInvalidOrg", err)
+	}
+	if err = s.RekeyOrg(ctx, org.ID, 2, keys, nil); !errors.Is(err, interfaces.ErrInvalidOrg) {
+		t.Fatalf("RekeyOrg without records: got %v, want ErrInvalidOrg", err)
+	}
+	if err = s.RekeyOrg(ctx, org.ID, 3, keys, []*models.OrgRecord{rekeyed}); !errors.Is(err, interfaces.ErrVersionConflict) {
+		t.Fatalf("RekeyOrg skipping a version: got %v, want ErrVersionConflict", err)
+	}
+	if err = s.RekeyOrg(ctx, org.ID, 2, keys, []*models.OrgRecord{rekeyed}); err != nil {
+		t.Fatalf("RekeyOrg: %v", err)
+	}
+	got, err = s.GetOrg(ctx, org.ID, alice)
+	if err != nil || got.KeyVersion != 2 || got.RekeyNeeded || string(got.Key) != "new alice key" {
+		t.Fatalf("GetOrg after RekeyOrg = %+v, %v", got, err)
+	}
+	records, err := s.GetOrgRecords(ctx, org.ID)
+	if err != nil || len(records) != 1 || string(records[0].Data) != "new data" || records[0].Version != 2 {
+		t.Fatalf("GetOrgRecords after RekeyOrg = %v, %v", records, err)
+	}
+	rec.Version = 3
+	if err = s.SaveOrgRecord(ctx, rec, 1); !errors.Is(err, interfaces.ErrVersionConflict) {
+		t.Fatalf("SaveOrgRecord with the old key: got %v, want ErrVersionConflict", err)
+	}
+
+	if err = s.DeleteOrgRecord(ctx, org.ID, rec.ID); err != nil {
+		t.Fatalf("DeleteOrgRecord: %v", err)
+	}
+	if err = s.DeleteOrgRecord(ctx, org.ID, rec.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteOrgRecord again: got %v, want ErrNotFound", err)
+	}
+	if err = s.DeleteOrg(ctx, org.ID); err != nil {
+		t.Fatalf("DeleteOrg: %v", err)
+	}
+	if orgs, _ := s.ListOrgs(ctx, alice); len(orgs) != 0 {
+		t.Fatalf("ListOrgs after DeleteOrg: got %d, want 0", len(orgs))
+	}
+}
+
+func testOrgOwners(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	for _, userID := range []string{alice, bob} {
+		if err := s.CreateUserKeys(ctx, &models.UserKeys{UserID: userID, PublicKey: []byte("public " + userID), WrappedPrivateKey: []byte("private")}); err != nil {
+			t.Fatalf("CreateUserKeys: %v", err)
+		}
+	}
+	org := &models.Organization{ID: uuid.New(), Name: "team"}
+	if err := s.CreateOrg(ctx, org, &models.OrgMember{UserID: alice, Role: models.OrgRoleOwner, Key: []byte("alice key")}); err != nil {
+		t.Fatalf("CreateOrg: %v", err)
+	}
+	if err := s.AddOrgMember(ctx, &models.OrgMember{OrgID: org.ID, UserID: bob, Role: models.OrgRoleAdmin, Key: []byte("bob key")}, 1); err != nil {
+		t.Fatalf("AddOrgMember: %v", err)
+	}
+
+	if err := s.SetOrgMemberRole(ctx, org.ID, alice, models.OrgRoleAdmin); !errors.Is(err, interfaces.ErrLastOwner) {
+		t.Fatalf("SetOrgMemberRole of the only owner: got %v, want ErrLastOwner", err)
+	}
+	if err := s.DeleteOrgMember(ctx, org.ID, alice); !errors.Is(err, interfaces.ErrLastOwner) {
+		t.Fatalf("DeleteOrgMember of the only owner: got %v, want ErrLastOwner", err)
+	}
+	if err := s.DeleteUser(ctx, alice); !errors.Is(err, interfaces.ErrLastOwner) {
+		t.Fatalf("DeleteUser of the only owner: got %v, want ErrLastOwner", err)
+	}
+	if _, err := s.FindUserByID(ctx, alice); err != nil {
+		t.Fatalf("FindUserByID after a refused DeleteUser: %v", err)
+	}
+
+	if err := s.SetOrgMemberRole(ctx, org.ID, bob, models.OrgRoleOwner); err != nil {
+		t.Fatalf("SetOrgMemberRole: %v", err)
+	}
+	if err := s.DeleteUser(ctx, alice); err != nil {
+		t.Fatalf("DeleteUser of one of two owners: %v", err)
+	}
+	got, err := s.GetOrg(ctx, org.ID, bob)
+	if err != nil || !got.RekeyNeeded {
+		t.Fatalf("GetOrg after DeleteUser of a member = %+v, %v; want a re-key to be needed", got, err)
+	}
+	if members, err := s.ListOrgMembers(ctx, org.ID); err != nil || len(members) != 1 {
+		t.Fatalf("ListOrgMembers after DeleteUser: got %d members, %v; want 1", len(members), err)
+	}
+	if err = s.SetOrgMemberRole(ctx, org.ID, bob, models.OrgRoleReader); !errors.Is(err, interfaces.ErrLastOwner) {
+		t.Fatalf("SetOrgMemberRole of the remaining owner: got %v, want ErrLastOwner", err)
+	}
+}
+
+func testRecordVersions(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	userID := createUser(t, s, "alice")
+	rec := newRecord(userID, 1)
+
+	if err := s.UpdateOrCreateRecord(ctx, rec); err != nil {
+		t.Fatalf("UpdateOrCreateRecord: %v", err)
+	}
+	assertRecord(t, s, rec)
+
+	if err := s.UpdateOrCreateRecord(ctx, rec); !errors.Is(err, interfaces.ErrVersionConflict) {
+		t.Fatalf("UpdateOrCreateRecord of the same version: got %v, want %v", err, interfaces.ErrVersionConflict)
+	}
+
+	updated := *rec
+	updated.Version = 2
+	updated.Data = []byte("updated")
+	if err := s.UpdateRecord(ctx, &updated); err != nil {
+		t.Fatalf("UpdateRecord: %v", err)
+	}
+	assertRecord(t, s, &updated)
+
+	for _, version := range []int{1, 2, 4} {
+		stale := updated
+		stale.Version = version
+		stale.Data = []byte("stale")
+		if err := s.UpdateRecord(ctx, &stale); !errors.Is(err, interfaces.ErrVersionConflict) {
+			t.Fatalf("UpdateRecord with version %d: got %v, want %v", version, err, interfaces.ErrVersionConflict)
+		}
+	}
+	assertRecord(t, s, &updated)
+
+	if err := s.UpdateRecord(ctx, newRecord(userID, 2)); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UpdateRecord of a missing record: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+}
+
+func testRecordIsolation(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	aliceID := createUser(t, s, "alice")
+	bobID := createUser(t, s, "bob")
+	rec := newRecord(aliceID, 1)
+
+	if err := s.CreateRecord(ctx, rec); err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+
+	if _, err := s.GetRecord(ctx, bobID, rec.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetRecord of another user: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	records, err := s.GetRecords(ctx, bobID)
+	if err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("GetRecords of another user returned %d records", len(records))
+	}
+
+	if records, err = s.GetRecords(ctx, aliceID); err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if len(records) != 1 || !equalRecords(records[0], rec) {
+		t.Fatalf("GetRecords = %+v, want [%+v]", records, rec)
+	}
+}
+
+func testRecordDelete(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	userID := createUser(t, s, "alice")
+	rec := newRecord(userID, 1)
+
+	if err := s.CreateRecord(ctx, rec); err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+	if err := s.DeleteRecord(ctx, userID, rec.ID); err != nil {
+		t.Fatalf("DeleteRecord: %v", err)
+	}
+	if _, err := s.GetRecord(ctx, userID, rec.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetRecord of a deleted record: got %v, want %v", err, interfaces.ErrNotFound)
+	}
+	if err := s.DeleteRecord(ctx, userID, rec.ID); err != nil {
+		t.Fatalf("DeleteRecord of a missing record: %v", err)
+	}
+
+	if err := s.UpdateOrCreateRecord(ctx, rec); err != nil {
+		t.Fatalf("UpdateOrCreateRecord of a deleted record: %v", err)
+	}
+	assertRecord(t, s, rec)
+}
+
+func testEmergency(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	carol := createUser(t, s, "carol")
+
+	access := &models.EmergencyAccess{ID: uuid.New(), GrantorID: alice, GranteeID: bob, WaitDays: 7, Status: models.EmergencyGranted, Key: []byte("wrapped")}
+	if err := s.CreateEmergencyAccess(ctx, access); err != nil {
+		t.Fatalf("CreateEmergencyAccess: %v", err)
+	}
+	again := &models.EmergencyAccess{ID: uuid.New(), GrantorID: alice, GranteeID: bob, WaitDays: 1, Status: models.EmergencyGranted, Key: []byte("other")}
+	if err := s.CreateEmergencyAccess(ctx, again); !errors.Is(err, interfaces.ErrContactExists) {
+		t.Fatalf("CreateEmergencyAccess again: got %v, want ErrContactExists", err)
+	}
+	other := &models.EmergencyAccess{ID: uuid.New(), GrantorID: carol, GranteeID: bob, WaitDays: 1, Status: models.EmergencyGranted, Key: []byte("carol")}
+	if err := s.CreateEmergencyAccess(ctx, other); err != nil {
+		t.Fatalf("CreateEmergencyAccess for another grantor: %v", err)
+	}
+
+	got, err := s.GetEmergencyAccess(ctx, access.ID)
+	if err != nil {
+		t.Fatalf("GetEmergencyAccess: %v", err)
+	}
+	if got.GrantorLogin != "alice" || got.GranteeLogin != "bob" || got.WaitDays != 7 || got.Status != models.EmergencyGranted ||
+		string(got.Key) != "wrapped" || !got.RequestedAt.IsZero() || got.CreatedAt.IsZero() {
+		t.Fatalf("GetEmergencyAccess = %+v", got)
+	}
+	if _, err = s.GetEmergencyAccess(ctx, uuid.New()); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetEmergencyAccess unknown: got %v, want ErrNotFound", err)
+	}
+
+	contacts, err := s.ListEmergencyContacts(ctx, alice)
+	if err != nil || len(contacts) != 1 || contacts[0].GranteeLogin != "bob" {
+		t.Fatalf("ListEmergencyContacts = %+v, %v", contacts, err)
+	}
+	grants, err := s.ListEmergencyGrants(ctx, bob)
+	if err != nil || len(grants) != 2 || grants[0].GrantorLogin != "alice" || grants[1].GrantorLogin != "carol" {
+		t.Fatalf("ListEmergencyGrants = %+v, %v", grants, err)
+	}
+
+	requestedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	granted := []models.EmergencyStatus{models.EmergencyGranted}
+	if err = s.SetEmergencyStatus(ctx, access.ID, granted, models.EmergencyRequested, requestedAt); err != nil {
+		t.Fatalf("SetEmergencyStatus: %v", err)
+	}
+	if err = s.SetEmergencyStatus(ctx, access.ID, granted, models.EmergencyRequested, requestedAt); !errors.Is(err, interfaces.ErrEmergencyState) {
+		t.Fatalf("SetEmergencyStatus from another status: got %v, want ErrEmergencyState", err)
+	}
+	if err = s.SetEmergencyStatus(ctx, uuid.New(), granted, models.EmergencyRequested, requestedAt); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("SetEmergencyStatus unknown: got %v, want ErrNotFound", err)
+	}
+	got, _ = s.GetEmergencyAccess(ctx, access.ID)
+	if got.Status != models.EmergencyRequested || !got.RequestedAt.Equal(requestedAt) {
+		t.Fatalf("GetEmergencyAccess after request = %+v, want requested at %v", got, requestedAt)
+	}
+	if err = s.SetEmergencyStatus(ctx, access.ID, []models.EmergencyStatus{models.EmergencyRequested}, models.EmergencyGranted, time.Time{}); err != nil {
+		t.Fatalf("SetEmergencyStatus back: %v", err)
+	}
+	if got, _ = s.GetEmergencyAccess(ctx, access.ID); got.Status != models.EmergencyGranted || !got.RequestedAt.IsZero() {
+		t.Fatalf("GetEmergencyAccess after reject = %+v", got)
+	}
+
+	if err = s.DeleteEmergencyAccess(ctx, bob, access.ID); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("DeleteEmergencyAccess by the grantee: got %v, want ErrNotFound", err)
+	}
+	if err = s.DeleteEmergencyAccess(ctx, alice, access.ID); err != nil {
+		t.Fatalf("DeleteEmergencyAccess: %v", err)
+	}
+	if contacts, _ = s.ListEmergencyContacts(ctx, alice); len(contacts) != 0 {
+		t.Fatalf("ListEmergencyContacts after delete: got %d, want 0", len(contacts))
+	}
+
+	if err = s.DeleteUser(ctx, carol); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if grants, _ = s.ListEmergencyGrants(ctx, bob); len(grants) != 0 {
+		t.Fatalf("ListEmergencyGrants after deleting the grantor: got %d, want 0", len(grants))
+	}
+}
+
+func testSends(t *testing.T, s interfaces.Storage) {
+	ctx := context.Background()
+	alice := createUser(t, s, "alice")
+	now := time.Now()
+
+	send := &models.Send{ID: uuid.New(), UserID: alice, Data: []byte("data"), Nonce: []byte("nonce"), PasswordHash: "hash",
+		MaxViews: 2, ViewsLeft: 2, ExpiresAt: now.Add(time.Hour).Truncate(time.Second)}
+	if err := s.CreateSend(ctx, send); err != nil {
+		t.Fatalf("CreateSend: %v", err)
+	}
+	expired := &models.Send{ID: uuid.New(), UserID: alice, Data: []byte("old"), Nonce: []byte("nonce"),
+		MaxViews: 1, ViewsLeft: 1, ExpiresAt: now.Add(-time.Minute)}
+	if err := s.CreateSend(ctx, expired); err != nil {
+		t.Fatalf("CreateSend expired: %v", err)
+	}
+
+	got, err := s.GetSend(ctx, send.ID, now)
+	if err != nil {
+		t.Fatalf("GetSend: %v", err)
+	}
+	if got.UserID != alice || string(got.Data) != "data" || string(got.Nonce) != "nonce" || got.PasswordHash != "hash" ||
+		got.MaxViews != 2 || got.ViewsLeft != 2 || !got.ExpiresAt.Equal(send.ExpiresAt) || got.CreatedAt.IsZero() {
+		t.Fatalf("GetSend = %+v", got)
+	}
+	if _, err = s.GetSend(ctx, expired.ID, now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetSend expired: got %v, want ErrNotFound", err)
+	}
+	if _, err = s.UseSend(ctx, expired.ID, now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseSend expired: got %v, want ErrNotFound", err)
+	}
+
+	for want := 1; want >= 0; want-- {
+		got, err = s.UseSend(ctx, send.ID, now)
+		if err != nil || got.ViewsLeft != want || string(got.Data) != "data" {
+			t.Fatalf("UseSend = %+v, %v, want %d views left", got, err, want)
+		}
+	}
+	if _, err = s.UseSend(ctx, send.ID, now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("UseSend after the last view: got %v, want ErrNotFound", err)
+	}
+	if _, err = s.GetSend(ctx, send.ID, now); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetSend after the last view: got %v, want ErrNotFound", err)
+	}
+
+	if err = s.DeleteExpiredSends(ctx, now); err != nil {
+		t.Fatalf("DeleteExpiredSends: %v", err)
+	}
+	if _, err = s.GetSend(ctx, expired.ID, now.Add(-time.Hour)); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("GetSend after DeleteExpiredSends: got %v, want ErrNotFound", err)
+	}
+}
+
+func createUser(t *testing.T, s interfaces.Storage, login string) string {
+	t.Helper()
+	user := &models.User{Login: login, PasswordHash: "hash"}
+	if err := s.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return user.ID
+}
+
+func newRecord(userID string, version int) *models.Record {
+	return &models.Record{
+		ID:      uuid.New(),
+		UserID:  userID,
+		Type:    "text",
+		Data:    []byte("data"),
+		Nonce:   []byte("nonce"),
+		Version: version,
+		Key:     []byte("key"),
+	}
+}
+
+func assertRecord(t *testing.T, s interfaces.Storage, want *models.Record) {
+	t.Helper()
+	got, err := s.GetRecord(context.Background(), want.UserID, want.ID)
+	if err != nil {
+		t.Fatalf("GetRecord: %v", err)
+	}
+	if !equalRecords(got, want) {
+		t.Fatalf("GetRecord = %+v, want %+v", got, want)
+	}
+}
+
+func equalRecords(a, b *models.Record) bool {
+	return a.ID == b.ID &&
+		a.UserID == b.UserID &&
+		a.Type == b.Type &&
+		string(a.Data) == string(b.Data) &&
+		string(a.Nonce) == string(b.Nonce) &&
+		a.Version == b.Version &&
+		string(a.Key) == string(b.Key)
+}
@@ -0,0 +1,406 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grnsv/GophKeeper/internal/server/interfaces"
+	"github.com/grnsv/GophKeeper/internal/server/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/grnsv/GophKeeper/internal/server/storage"
+
+type tracedStorage struct {
+	storage interfaces.Storage
+	tracer  trace.Tracer
+	system  attribute.KeyValue
+}
+
+// WithTracing records a span for every query made through storage.
+func WithTracing(storage interfaces.Storage, dsn string, provider trace.TracerProvider) interfaces.Storage {
+	return &tracedStorage{
+		storage: storage,
+		tracer:  provider.Tracer(tracerName),
+		system:  semconv.DBSystemKey.String(scheme(dsn)),
+	}
+}
+
+func (s *tracedStorage) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(s.system, semconv.DBOperationName(operation)),
+	)
+}
+
+// end finishes the span, expected outcomes such as a missing row are not errors.
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *tracedStorage) Close() error {
+	return s.storage.Close()
+}
+
+func (s *tracedStorage) Ping(ctx context.Context) (err error) {
+	ctx, span := s.start(ctx, "Ping")
+	defer func() { end(span, err) }()
+	return s.storage.Ping(ctx)
+}
+
+func (s *tracedStorage) SchemaVersion(ctx context.Context) (_ uint, _ bool, err error) {
+	ctx, span := s.start(ctx, "SchemaVersion")
+	defer func() { end(span, err) }()
+	return s.storage.SchemaVersion(ctx)
+}
+
+func (s *tracedStorage) IsLoginExists(ctx context.Context, login string) (_ bool, err error) {
+	ctx, span := s.start(ctx, "IsLoginExists")
+	defer func() { end(span, err) }()
+	return s.storage.IsLoginExists(ctx, login)
+}
+
+func (s *tracedStorage) CreateUser(ctx context.Context, user *models.User) (err error) {
+	ctx, span := s.start(ctx, "CreateUser")
+	defer func() { end(span, err) }()
+	return s.storage.CreateUser(ctx, user)
+}
+
+func (s *tracedStorage) FindUserByLogin(ctx context.Context, login string) (_ *models.User, err error) {
+	ctx, span := s.start(ctx, "FindUserByLogin")
+	defer func() { end(span, err) }()
+	return s.storage.FindUserByLogin(ctx, login)
+}
+
+func (s *tracedStorage) FindUserByID(ctx context.Context, id string) (_ *models.User, err error) {
+	ctx, span := s.start(ctx, "FindUserByID")
+	defer func() { end(span, err) }()
+	return s.storage.FindUserByID(ctx, id)
+}
+
+func (s *tracedStorage) ListUsers(ctx context.Context, search string) (_ []*models.User, err error) {
+	ctx, span := s.start(ctx, "ListUsers")
+	defer func() { end(span, err) }()
+	return s.storage.ListUsers(ctx, search)
+}
+
+func (s *tracedStorage) SetUserDisabled(ctx context.Context, id string, disabled bool) (err error) {
+	ctx, span := s.start(ctx, "SetUserDisabled")
+	defer func() { end(span, err) }()
+	return s.storage.SetUserDisabled(ctx, id, disabled)
+}
+
+func (s *tracedStorage) RevokeUserSessions(ctx context.Context, id string, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "RevokeUserSessions")
+	defer func() { end(span, err) }()
+	return s.storage.RevokeUserSessions(ctx, id, at)
+}
+
+func (s *tracedStorage) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteUser")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteUser(ctx, id)
+}
+
+func (s *tracedStorage) GetRecords(ctx context.Context, userID string) (_ []*models.Record, err error) {
+	ctx, span := s.start(ctx, "GetRecords")
+	defer func() { end(span, err) }()
+	return s.storage.GetRecords(ctx, userID)
+}
+
+func (s *tracedStorage) CreateRecord(ctx context.Context, rec *models.Record) (err error) {
+	ctx, span := s.start(ctx, "CreateRecord")
+	defer func() { end(span, err) }()
+	return s.storage.CreateRecord(ctx, rec)
+}
+
+func (s *tracedStorage) UpdateOrCreateRecord(ctx context.Context, rec *models.Record) (err error) {
+	ctx, span := s.start(ctx, "UpdateOrCreateRecord")
+	defer func() { end(span, err) }()
+	return s.storage.UpdateOrCreateRecord(ctx, rec)
+}
+
+func (s *tracedStorage) UpdateRecord(ctx context.Context, rec *models.Record) (err error) {
+	ctx, span := s.start(ctx, "UpdateRecord")
+	defer func() { end(span, err) }()
+	return s.storage.UpdateRecord(ctx, rec)
+}
+
+func (s *tracedStorage) GetRecord(ctx context.Context, userID string, id uuid.UUID) (_ *models.Record, err error) {
+	ctx, span := s.start(ctx, "GetRecord")
+	defer func() { end(span, err) }()
+	return s.storage.GetRecord(ctx, userID, id)
+}
+
+func (s *tracedStorage) DeleteRecord(ctx context.Context, userID string, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteRecord")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteRecord(ctx, userID, id)
+}
+
+func (s *tracedStorage) GetRecordStats(ctx context.Context) (_ map[string]models.RecordStats, err error) {
+	ctx, span := s.start(ctx, "GetRecordStats")
+	defer func() { end(span, err) }()
+	return s.storage.GetRecordStats(ctx)
+}
+
+func (s *tracedStorage) CreateInvite(ctx context.Context, invite *models.Invite) (err error) {
+	ctx, span := s.start(ctx, "CreateInvite")
+	defer func() { end(span, err) }()
+	return s.storage.CreateInvite(ctx, invite)
+}
+
+func (s *tracedStorage) ListInvites(ctx context.Context) (_ []*models.Invite, err error) {
+	ctx, span := s.start(ctx, "ListInvites")
+	defer func() { end(span, err) }()
+	return s.storage.ListInvites(ctx)
+}
+
+func (s *tracedStorage) DeleteInvite(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteInvite")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteInvite(ctx, id)
+}
+
+func (s *tracedStorage) IsInviteValid(ctx context.Context, codeHash string, now time.Time) (_ bool, err error) {
+	ctx, span := s.start(ctx, "IsInviteValid")
+	defer func() { end(span, err) }()
+	return s.storage.IsInviteValid(ctx, codeHash, now)
+}
+
+func (s *tracedStorage) UseInvite(ctx context.Context, codeHash string, now time.Time) (err error) {
+	ctx, span := s.start(ctx, "UseInvite")
+	defer func() { end(span, err) }()
+	return s.storage.UseInvite(ctx, codeHash, now)
+}
+
+func (s *tracedStorage) ReleaseInvite(ctx context.Context, codeHash string) (err error) {
+	ctx, span := s.start(ctx, "ReleaseInvite")
+	defer func() { end(span, err) }()
+	return s.storage.ReleaseInvite(ctx, codeHash)
+}
+
+func (s *tracedStorage) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) (err error) {
+	ctx, span := s.start(ctx, "CreateAuditEvent")
+	defer func() { end(span, err) }()
+	return s.storage.CreateAuditEvent(ctx, event)
+}
+
+func (s *tracedStorage) ListAuditEvents(ctx context.Context, userID string, limit int) (_ []*models.AuditEvent, err error) {
+	ctx, span := s.start(ctx, "ListAuditEvents")
+	defer func() { end(span, err) }()
+	return s.storage.ListAuditEvents(ctx, userID, limit)
+}
+
+func (s *tracedStorage) CreateAccessToken(ctx context.Context, token *models.AccessToken) (err error) {
+	ctx, span := s.start(ctx, "CreateAccessToken")
+	defer func() { end(span, err) }()
+	return s.storage.CreateAccessToken(ctx, token)
+}
+
+func (s *tracedStorage) ListAccessTokens(ctx context.Context, userID string) (_ []*models.AccessToken, err error) {
+	ctx, span := s.start(ctx, "ListAccessTokens")
+	defer func() { end(span, err) }()
+	return s.storage.ListAccessTokens(ctx, userID)
+}
+
+func (s *tracedStorage) FindAccessToken(ctx context.Context, id string) (_ *models.AccessToken, err error) {
+	ctx, span := s.start(ctx, "FindAccessToken")
+	defer func() { end(span, err) }()
+	return s.storage.FindAccessToken(ctx, id)
+}
+
+func (s *tracedStorage) DeleteAccessToken(ctx context.Context, userID, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteAccessToken")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteAccessToken(ctx, userID, id)
+}
+
+func (s *tracedStorage) TouchAccessToken(ctx context.Context, id string, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "TouchAccessToken")
+	defer func() { end(span, err) }()
+	return s.storage.TouchAccessToken(ctx, id, at)
+}
+
+func (s *tracedStorage) CreateUserKeys(ctx context.Context, keys *models.UserKeys) (err error) {
+	ctx, span := s.start(ctx, "CreateUserKeys")
+	defer func() { end(span, err) }()
+	return s.storage.CreateUserKeys(ctx, keys)
+}
+
+func (s *tracedStorage) GetUserKeys(ctx context.Context, userID string) (_ *models.UserKeys, err error) {
+	ctx, span := s.start(ctx, "GetUserKeys")
+	defer func() { end(span, err) }()
+	return s.storage.GetUserKeys(ctx, userID)
+}
+
+func (s *tracedStorage) SaveShare(ctx context.Context, share *models.Share) (err error) {
+	ctx, span := s.start(ctx, "SaveShare")
+	defer func() { end(span, err) }()
+	return s.storage.SaveShare(ctx, share)
+}
+
+func (s *tracedStorage) ListShares(ctx context.Context, ownerID string, recordID uuid.UUID) (_ []*models.Share, err error) {
+	ctx, span := s.start(ctx, "ListShares")
+	defer func() { end(span, err) }()
+	return s.storage.ListShares(ctx, ownerID, recordID)
+}
+
+func (s *tracedStorage) DeleteShare(ctx context.Context, ownerID string, recordID uuid.UUID, recipientID string) (err error) {
+	ctx, span := s.start(ctx, "DeleteShare")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteShare(ctx, ownerID, recordID, recipientID)
+}
+
+func (s *tracedStorage) GetSharedRecords(ctx context.Context, recipientID string) (_ []*models.SharedRecord, err error) {
+	ctx, span := s.start(ctx, "GetSharedRecords")
+	defer func() { end(span, err) }()
+	return s.storage.GetSharedRecords(ctx, recipientID)
+}
+
+func (s *tracedStorage) CreateOrg(ctx context.Context, org *models.Organization, owner *models.OrgMember) (err error) {
+	ctx, span := s.start(ctx, "CreateOrg")
+	defer func() { end(span, err) }()
+	return s.storage.CreateOrg(ctx, org, owner)
+}
+
+func (s *tracedStorage) ListOrgs(ctx context.Context, userID string) (_ []*models.Organization, err error) {
+	ctx, span := s.start(ctx, "ListOrgs")
+	defer func() { end(span, err) }()
+	return s.storage.ListOrgs(ctx, userID)
+}
+
+func (s *tracedStorage) GetOrg(ctx context.Context, orgID uuid.UUID, userID string) (_ *models.Organization, err error) {
+	ctx, span := s.start(ctx, "GetOrg")
+	defer func() { end(span, err) }()
+	return s.storage.GetOrg(ctx, orgID, userID)
+}
+
+func (s *tracedStorage) DeleteOrg(ctx context.Context, orgID uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteOrg")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteOrg(ctx, orgID)
+}
+
+func (s *tracedStorage) ListOrgMembers(ctx context.Context, orgID uuid.UUID) (_ []*models.OrgMember, err error) {
+	ctx, span := s.start(ctx, "ListOrgMembers")
+	defer func() { end(span, err) }()
+	return s.storage.ListOrgMembers(ctx, orgID)
+}
+
+func (s *tracedStorage) GetOrgMember(ctx context.Context, orgID uuid.UUID, userID string) (_ *models.OrgMember, err error) {
+	ctx, span := s.start(ctx, "GetOrgMember")
+	defer func() { end(span, err) }()
+	return s.storage.GetOrgMember(ctx, orgID, userID)
+}
+
+func (s *tracedStorage) AddOrgMember(ctx context.Context, member *models.OrgMember, keyVersion int64) (err error) {
+	ctx, span := s.start(ctx, "AddOrgMember")
+	defer func() { end(span, err) }()
+	return s.storage.AddOrgMember(ctx, member, keyVersion)
+}
+
+func (s *tracedStorage) SetOrgMemberRole(ctx context.Context, orgID uuid.UUID, userID string, role models.OrgRole) (err error) {
+	ctx, span := s.start(ctx, "SetOrgMemberRole")
+	defer func() { end(span, err) }()
+	return s.storage.SetOrgMemberRole(ctx, orgID, userID, role)
+}
+
+func (s *tracedStorage) DeleteOrgMember(ctx context.Context, orgID uuid.UUID, userID string) (err error) {
+	ctx, span := s.start(ctx, "DeleteOrgMember")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteOrgMember(ctx, orgID, userID)
+}
+
+func (s *tracedStorage) RekeyOrg(ctx context.Context, orgID uuid.UUID, keyVersion int64, keys map[string][]byte, records []*models.OrgRecord) (err error) {
+	ctx, span := s.start(ctx, "RekeyOrg")
+	defer func() { end(span, err) }()
+	return s.storage.RekeyOrg(ctx, orgID, keyVersion, keys, records)
+}
+
+func (s *tracedStorage) GetOrgRecords(ctx context.Context, orgID uuid.UUID) (_ []*models.OrgRecord, err error) {
+	ctx, span := s.start(ctx, "GetOrgRecords")
+	defer func() { end(span, err) }()
+	return s.storage.GetOrgRecords(ctx, orgID)
+}
+
+func (s *tracedStorage) SaveOrgRecord(ctx context.Context, rec *models.OrgRecord, keyVersion int64) (err error) {
+	ctx, span := s.start(ctx, "SaveOrgRecord")
+	defer func() { end(span, err) }()
+	return s.storage.SaveOrgRecord(ctx, rec, keyVersion)
+}
+
+func (s *tracedStorage) DeleteOrgRecord(ctx context.Context, orgID, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteOrgRecord")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteOrgRecord(ctx, orgID, id)
+}
+
+func (s *tracedStorage) CreateEmergencyAccess(ctx context.Context, access *models.EmergencyAccess) (err error) {
+	ctx, span := s.start(ctx, "CreateEmergencyAccess")
+	defer func() { end(span, err) }()
+	return s.storage.CreateEmergencyAccess(ctx, access)
+}
+
+func (s *tracedStorage) GetEmergencyAccess(ctx context.Context, id uuid.UUID) (_ *models.EmergencyAccess, err error) {
+	ctx, span := s.start(ctx, "GetEmergencyAccess")
+	defer func() { end(span, err) }()
+	return s.storage.GetEmergencyAccess(ctx, id)
+}
+
+func (s *tracedStorage) ListEmergencyContacts(ctx context.Context, grantorID string) (_ []*models.EmergencyAccess, err error) {
+	ctx, span := s.start(ctx, "ListEmergencyContacts")
+	defer func() { end(span, err) }()
+	return s.storage.ListEmergencyContacts(ctx, grantorID)
+}
+
+func (s *tracedStorage) ListEmergencyGrants(ctx context.Context, granteeID string) (_ []*models.EmergencyAccess, err error) {
+	ctx, span := s.start(ctx, "ListEmergencyGrants")
+	defer func() { end(span, err) }()
+	return s.storage.ListEmergencyGrants(ctx, granteeID)
+}
+
+func (s *tracedStorage) SetEmergencyStatus(ctx context.Context, id uuid.UUID, from []models.EmergencyStatus, to models.EmergencyStatus, requestedAt time.Time) (err error) {
+	ctx, span := s.start(ctx, "SetEmergencyStatus")
+	defer func() { end(span, err) }()
+	return s.storage.SetEmergencyStatus(ctx, id, from, to, requestedAt)
+}
+
+func (s *tracedStorage) DeleteEmergencyAccess(ctx context.Context, grantorID string, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteEmergencyAccess")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteEmergencyAccess(ctx, grantorID, id)
+}
+
+func (s *tracedStorage) CreateSend(ctx context.Context, send *models.Send) (err error) {
+	ctx, span := s.start(ctx, "CreateSend")
+	defer func() { end(span, err) }()
+	return s.storage.CreateSend(ctx, send)
+}
+
+func (s *tracedStorage) GetSend(ctx context.Context, id uuid.UUID, now time.Time) (_ *models.Send, err error) {
+	ctx, span := s.start(ctx, "GetSend")
+	defer func() { end(span, err) }()
+	return s.storage.GetSend(ctx, id, now)
+}
+
+func (s *tracedStorage) UseSend(ctx context.Context, id uuid.UUID, now time.Time) (_ *models.Send, err error) {
+	ctx, span := s.start(ctx, "UseSend")
+	defer func() { end(span, err) }()
+	return s.storage.UseSend(ctx, id, now)
+}
+
+func (s *tracedStorage) DeleteExpiredSends(ctx context.Context, now time.Time) (err error) {
+	ctx, span := s.start(ctx, "DeleteExpiredSends")
+	defer func() { end(span, err) }()
+	return s.storage.DeleteExpiredSends(ctx, now)
+}
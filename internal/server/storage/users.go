@@ -4,20 +4,27 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/grnsv/GophKeeper/internal/server/interfaces"
 	"github.com/grnsv/GophKeeper/internal/server/models"
 )
 
+const userColumns = `id, login, password_hash, created_at, disabled, sessions_revoked_at`
+
 type UserRepository struct {
-	db    *sql.DB
-	stmts map[string]*sql.Stmt
+	db      *sql.DB
+	dialect Dialect
+	stmts   map[string]*sql.Stmt
 }
 
-func NewUserRepository(ctx context.Context, db *sql.DB) (interfaces.UserRepository, error) {
+func NewUserRepository(ctx context.Context, db *sql.DB, dialect Dialect) (interfaces.UserRepository, error) {
 	r := &UserRepository{
-		db:    db,
-		stmts: make(map[string]*sql.Stmt, 4),
+		db:      db,
+		dialect: dialect,
+		stmts:   make(map[string]*sql.Stmt, 7),
 	}
 	if err := r.initStatements(ctx); err != nil {
 		return nil, err
@@ -28,9 +35,13 @@ func NewUserRepository(ctx context.Context, db *sql.DB) (interfaces.UserReposito
 
 func (r *UserRepository) initStatements(ctx context.Context) error {
 	queries := map[string]string{
-		"IsLoginExists":   `SELECT EXISTS(SELECT * FROM users WHERE login = $1) AS exists`,
-		"CreateUser":      `INSERT INTO users (login, password_hash) VALUES ($1, $2) RETURNING id`,
-		"FindUserByLogin": `SELECT * FROM users WHERE login = $1 LIMIT 1`,
+		"IsLoginExists":      `SELECT EXISTS(SELECT * FROM users WHERE login = $1) AS "exists"`,
+		"CreateUser":         `INSERT INTO users (id, login, password_hash) VALUES ($1, $2, $3) RETURNING created_at`,
+		"FindUserByLogin":    `SELECT ` + userColumns + ` FROM users WHERE login = $1 LIMIT 1`,
+		"FindUserByID":       `SELECT ` + userColumns + ` FROM users WHERE id = $1 LIMIT 1`,
+		"ListUsers":          `SELECT ` + userColumns + ` FROM users WHERE ` + r.dialect.strpos + `(lower(login), lower($1)) > 0 ORDER BY login`,
+		"SetUserDisabled":    `UPDATE users SET disabled = $2 WHERE id = $1`,
+		"RevokeUserSessions": `UPDATE users SET sessions_revoked_at = $2 WHERE id = $1`,
 	}
 	for key, query := range queries {
 		stmt, err := r.db.PrepareContext(ctx, query)
@@ -63,24 +74,138 @@ func (r *UserRepository) IsLoginExists(ctx context.Context, login string) (bool,
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
-	if err := r.stmts["CreateUser"].QueryRowContext(ctx, user.Login, user.PasswordHash).Scan(&user.ID); err != nil {
+	id := uuid.NewString()
+	if err := r.stmts["CreateUser"].QueryRowContext(ctx, id, user.Login, user.PasswordHash).Scan(&user.CreatedAt); err != nil {
+		if r.dialect.isUniqueViolation(err) {
+			return interfaces.ErrLoginTaken
+		}
 		return err
 	}
+	user.ID = id
 	return nil
 }
 
 func (r *UserRepository) FindUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	return scanUser(r.stmts["FindUserByLogin"].QueryRowContext(ctx, login))
+}
+
+func (r *UserRepository) FindUserByID(ctx context.Context, id string) (*models.User, error) {
+	return scanUser(r.stmts["FindUserByID"].QueryRowContext(ctx, id))
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, search string) ([]*models.User, error) {
+	var users []*models.User
+	rows, err := r.stmts["ListUsers"].QueryContext(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) SetUserDisabled(ctx context.Context, id string, disabled bool) error {
+	return execAffectingOne(r.stmts["SetUserDisabled"].ExecContext(ctx, id, disabled))
+}
+
+func (r *UserRepository) RevokeUserSessions(ctx context.Context, id string, at time.Time) error {
+	return execAffectingOne(r.stmts["RevokeUserSessions"].ExecContext(ctx, id, at.UTC()))
+}
+
+// DeleteUser deletes the user with everything they own. The organizations
+// the user is a member of need a re-key, and the user must not be the only
+// owner of one.
+func (r *UserRepository) DeleteUser(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the organizations of the user first, so that the owners checked
+	// next cannot change until the user is deleted.
+	if _, err = tx.ExecContext(ctx, r.dialect.forUpdate(
+		`SELECT o.id FROM organizations o JOIN org_members m ON m.org_id = o.id WHERE m.user_id = $1`, "o",
+	), id); err != nil {
+		return err
+	}
+	if err = checkLastOwner(ctx, tx, `SELECT o.name FROM organizations o JOIN org_members m ON m.org_id = o.id
+		WHERE m.user_id = $1 AND m.role = $2
+			AND NOT EXISTS (SELECT 1 FROM org_members x WHERE x.org_id = o.id AND x.user_id <> $1 AND x.role = $2)
+		ORDER BY o.name LIMIT 1`, id); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE organizations SET rekey_needed = true WHERE id IN (SELECT org_id FROM org_members WHERE user_id = $1)`, id,
+	); err != nil {
+		return err
+	}
+	if err = execAffectingOne(tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkLastOwner runs the query for the name of an organization the user
+// is the only owner of, and returns ErrLastOwner if there is one.
+func checkLastOwner(ctx context.Context, tx *sql.Tx, query, userID string) error {
+	var name string
+	err := tx.QueryRowContext(ctx, query, userID, models.OrgRoleOwner).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: the user is the only owner of organization %q, make another member an owner or delete it first", interfaces.ErrLastOwner, name)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
 	var user models.User
-	if err := r.stmts["FindUserByLogin"].QueryRowContext(ctx, login).Scan(
+	var sessionsRevokedAt sql.NullTime
+	if err := row.Scan(
 		&user.ID,
 		&user.Login,
 		&user.PasswordHash,
 		&user.CreatedAt,
+		&user.Disabled,
+		&sessionsRevokedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, interfaces.ErrNotFound
 		}
 		return nil, err
 	}
+	user.SessionsRevokedAt = sessionsRevokedAt.Time
 	return &user, nil
 }
+
+func execAffectingOne(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return interfaces.ErrNotFound
+	}
+	return nil
+}
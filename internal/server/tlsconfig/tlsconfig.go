@@ -0,0 +1,92 @@
+// Package tlsconfig serves TLS with certificates that can be reloaded
+// without restarting the server.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// Reloader holds the server certificate and, for mutual TLS, the pool of CAs
+// client certificates must be issued by. Reload swaps them atomically so that
+// new connections use the new files while established ones are kept.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	cert      atomic.Pointer[tls.Certificate]
+	clientCAs atomic.Pointer[x509.CertPool]
+}
+
+// NewReloader loads the certificate and key. If clientCAFile is not empty,
+// clients must present a certificate issued by one of its CAs.
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads all files again. On error the previous certificates stay in use.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		if clientCAs, err = loadCertPool(r.clientCAFile); err != nil {
+			return fmt.Errorf("load client CA: %w", err)
+		}
+	}
+
+	r.cert.Store(&cert)
+	r.clientCAs.Store(clientCAs)
+	return nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM certificates found in " + path)
+	}
+	return pool, nil
+}
+
+// MutualTLS reports whether client certificates are required.
+func (r *Reloader) MutualTLS() bool {
+	return r.clientCAFile != ""
+}
+
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert.Load()},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if clientCAs := r.clientCAs.Load(); clientCAs != nil {
+				cfg.ClientCAs = clientCAs
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
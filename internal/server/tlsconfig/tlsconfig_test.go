@@ -0,0 +1,251 @@
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grnsv/GophKeeper/internal/server/tlsconfig"
+)
+
+// authority is a CA generated for a test.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	t.Helper()
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          newSerial(t),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &authority{cert: cert, key: key, pool: pool}
+}
+
+// issue returns a leaf certificate for localhost, usable by servers and
+// clients.
+func (a *authority) issue(t *testing.T, name string) tls.Certificate {
+	t.Helper()
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber: newSerial(t),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newSerial(t *testing.T) *big.Int {
+	t.Helper()
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("rand.Int: %v", err)
+	}
+	return serial
+}
+
+// writeKeyPair writes the certificate and key as PEM files.
+func writeKeyPair(t *testing.T, certFile, keyFile string, cert tls.Certificate) {
+	t.Helper()
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", cert.Certificate[0])
+	writePEM(t, keyFile, "PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+// handshake connects a client to a server using the config over loopback. It
+// returns the certificate the server presented and the error of the server.
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) (*x509.Certificate, error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		tlsConn := tls.Server(conn, server)
+		if err = tlsConn.Handshake(); err == nil {
+			// With TLS 1.3 the server checks the client certificate after the
+			// client finished its handshake, read to get the outcome.
+			_, err = tlsConn.Read(make([]byte, 1))
+		}
+		serverErr <- err
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+	var peer *x509.Certificate
+	if err == nil {
+		peer = conn.ConnectionState().PeerCertificates[0]
+		_, _ = conn.Write([]byte{0})
+		_, _ = io.Copy(io.Discard, conn)
+		conn.Close()
+	}
+	return peer, <-serverErr
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	ca := newAuthority(t, "test CA")
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "first"))
+
+	r, err := tlsconfig.NewReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	if r.MutualTLS() {
+		t.Fatal("MutualTLS without a client CA file")
+	}
+	server := r.TLSConfig()
+	client := &tls.Config{RootCAs: ca.pool, ServerName: "localhost"}
+	served := func() string {
+		t.Helper()
+		peer, err := handshake(t, server, client)
+		if err != nil {
+			t.Fatalf("handshake: %v", err)
+		}
+		return peer.Subject.CommonName
+	}
+	if name := served(); name != "first" {
+		t.Fatalf("served %q, want %q", name, "first")
+	}
+
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "second"))
+	if err = r.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if name := served(); name != "second" {
+		t.Fatalf("served %q after Reload, want %q", name, "second")
+	}
+
+	if err = os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err = r.Reload(); err == nil {
+		t.Fatal("Reload of an invalid certificate succeeded")
+	}
+	if name := served(); name != "second" {
+		t.Fatalf("served %q after a failed Reload, want %q", name, "second")
+	}
+}
+
+func TestNewReloaderInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	if _, err := tlsconfig.NewReloader(certFile, keyFile, ""); err == nil {
+		t.Fatal("NewReloader without files succeeded")
+	}
+
+	writeKeyPair(t, certFile, keyFile, newAuthority(t, "test CA").issue(t, "server"))
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := tlsconfig.NewReloader(certFile, keyFile, caFile); err == nil {
+		t.Fatal("NewReloader with an invalid client CA file succeeded")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "clients.crt")
+	serverCA := newAuthority(t, "server CA")
+	clientCA := newAuthority(t, "client CA")
+	otherCA := newAuthority(t, "other CA")
+	writeKeyPair(t, certFile, keyFile, serverCA.issue(t, "server"))
+	writePEM(t, caFile, "CERTIFICATE", clientCA.cert.Raw)
+
+	r, err := tlsconfig.NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	if !r.MutualTLS() {
+		t.Fatal("MutualTLS with a client CA file")
+	}
+	server := r.TLSConfig()
+
+	tests := []struct {
+		name    string
+		certs   []tls.Certificate
+		wantErr bool
+	}{
+		{name: "trusted client", certs: []tls.Certificate{clientCA.issue(t, "client")}},
+		{name: "no certificate", wantErr: true},
+		{name: "untrusted client", certs: []tls.Certificate{otherCA.issue(t, "client")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &tls.Config{RootCAs: serverCA.pool, ServerName: "localhost", Certificates: tt.certs}
+			_, err := handshake(t, server, client)
+			if tt.wantErr && err == nil {
+				t.Fatal("handshake succeeded, want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("handshake: %v", err)
+			}
+		})
+	}
+}
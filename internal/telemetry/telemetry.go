@@ -0,0 +1,138 @@
+// Package telemetry sets up OpenTelemetry tracing shared by the client and the server.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+var propagator = propagation.TraceContext{}
+
+type TracingConfig struct {
+	// Exporter is one of none, otlp, stdout or file.
+	Exporter string
+	// Endpoint is the host:port of an OTLP/HTTP collector. If empty,
+	// the standard OTEL_EXPORTER_OTLP_* environment variables are used.
+	Endpoint string
+	// File receives spans as JSON lines when Exporter is file.
+	File string
+}
+
+func (cfg TracingConfig) Validate() error {
+	switch cfg.Exporter {
+	case "", ExporterNone, ExporterOTLP, ExporterStdout:
+		return nil
+	case ExporterFile:
+		if cfg.File == "" {
+			return errors.New("trace file is required for the file exporter")
+		}
+		return nil
+	default:
+		return fmt.Errorf("trace exporter must be one of %q, %q, %q or %q, got %q",
+			ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile, cfg.Exporter)
+	}
+}
+
+// NewTracerProvider builds a tracer provider exporting spans as configured.
+// With no exporter spans are sampled but dropped. Shutdown flushes pending spans.
+func NewTracerProvider(ctx context.Context, cfg TracingConfig, serviceName, serviceVersion string) (*sdktrace.TracerProvider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(serviceVersion),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		var otlpOpts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			otlpOpts = append(otlpOpts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, otlpOpts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterFile:
+		exporter, err = newFileExporter(cfg.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+type fileExporter struct {
+	*stdouttrace.Exporter
+	file *os.File
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	return &fileExporter{Exporter: exporter, file: file}, nil
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.file.Close())
+}
+
+// Transport injects the trace context of outgoing requests into W3C traceparent headers.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		propagator.Inject(r.Context(), propagation.HeaderCarrier(r.Header))
+		return base.RoundTrip(r)
+	})
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Handler continues the trace of the client from traceparent headers of incoming requests.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
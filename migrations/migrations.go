@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations of every supported storage dialect,
+// one directory per dialect.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,3 @@
+ALTER TABLE public.users
+    DROP COLUMN sessions_revoked_at,
+    DROP COLUMN disabled;
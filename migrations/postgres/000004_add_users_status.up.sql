@@ -0,0 +1,3 @@
+ALTER TABLE public.users
+    ADD COLUMN disabled boolean DEFAULT false NOT NULL,
+    ADD COLUMN sessions_revoked_at timestamp;
@@ -0,0 +1,10 @@
+CREATE TABLE public.invites (
+    id uuid DEFAULT gen_random_uuid () NOT NULL,
+    code_hash text NOT NULL,
+    max_uses int4 DEFAULT 1 NOT NULL,
+    uses int4 DEFAULT 0 NOT NULL,
+    expires_at timestamp,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT invites_pkey PRIMARY KEY (id),
+    CONSTRAINT invites_code_hash_key UNIQUE (code_hash)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE public.audit_events (
+    id bigserial NOT NULL,
+    user_id uuid,
+    type text NOT NULL,
+    details text DEFAULT '' NOT NULL,
+    ip text DEFAULT '' NOT NULL,
+    user_agent text DEFAULT '' NOT NULL,
+    request_id text DEFAULT '' NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT audit_events_pkey PRIMARY KEY (id),
+    CONSTRAINT audit_events_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX audit_events_user_id_idx ON public.audit_events USING btree (user_id, id);
@@ -0,0 +1 @@
+ALTER TABLE public.records DROP COLUMN key;
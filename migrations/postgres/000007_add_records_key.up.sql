@@ -0,0 +1 @@
+ALTER TABLE public.records ADD COLUMN key bytea;
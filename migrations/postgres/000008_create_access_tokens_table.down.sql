@@ -0,0 +1,2 @@
+DROP TABLE public.access_token_records;
+DROP TABLE public.access_tokens;
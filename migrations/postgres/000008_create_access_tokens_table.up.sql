@@ -0,0 +1,24 @@
+CREATE TABLE public.access_tokens (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    secret_hash text NOT NULL,
+    read_only boolean DEFAULT false NOT NULL,
+    tags text DEFAULT '[]' NOT NULL,
+    wrapped_key bytea,
+    expires_at timestamp NOT NULL,
+    last_used_at timestamp,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT access_tokens_pkey PRIMARY KEY (id),
+    CONSTRAINT access_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX access_tokens_user_id_idx ON public.access_tokens USING btree (user_id);
+
+CREATE TABLE public.access_token_records (
+    token_id uuid NOT NULL,
+    record_id uuid NOT NULL,
+    key bytea NOT NULL,
+    CONSTRAINT access_token_records_pkey PRIMARY KEY (token_id, record_id),
+    CONSTRAINT access_token_records_token_id_fkey FOREIGN KEY (token_id) REFERENCES public.access_tokens(id) ON DELETE CASCADE
+);
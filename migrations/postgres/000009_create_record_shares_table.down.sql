@@ -0,0 +1,2 @@
+DROP TABLE public.record_shares;
+DROP TABLE public.user_keys;
@@ -0,0 +1,21 @@
+CREATE TABLE public.user_keys (
+    user_id uuid NOT NULL,
+    public_key bytea NOT NULL,
+    wrapped_private_key bytea NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT user_keys_pkey PRIMARY KEY (user_id),
+    CONSTRAINT user_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE public.record_shares (
+    record_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    recipient_id uuid NOT NULL,
+    key bytea NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT record_shares_pkey PRIMARY KEY (record_id, owner_id, recipient_id),
+    CONSTRAINT record_shares_record_fkey FOREIGN KEY (record_id, owner_id) REFERENCES public.records(id, user_id) ON DELETE CASCADE,
+    CONSTRAINT record_shares_recipient_id_fkey FOREIGN KEY (recipient_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX record_shares_recipient_id_idx ON public.record_shares USING btree (recipient_id);
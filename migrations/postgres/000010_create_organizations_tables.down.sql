@@ -0,0 +1,3 @@
+DROP TABLE public.org_records;
+DROP TABLE public.org_members;
+DROP TABLE public.organizations;
@@ -0,0 +1,32 @@
+CREATE TABLE public.organizations (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    key_version int8 DEFAULT 1 NOT NULL,
+    rekey_needed boolean DEFAULT false NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT organizations_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE public.org_members (
+    org_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    role text NOT NULL CHECK (role IN ('owner', 'admin', 'writer', 'reader')),
+    key bytea NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT org_members_pkey PRIMARY KEY (org_id, user_id),
+    CONSTRAINT org_members_org_id_fkey FOREIGN KEY (org_id) REFERENCES public.organizations(id) ON DELETE CASCADE,
+    CONSTRAINT org_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX org_members_user_id_idx ON public.org_members USING btree (user_id);
+
+CREATE TABLE public.org_records (
+    id uuid NOT NULL,
+    org_id uuid NOT NULL,
+    type public.record_type NOT NULL,
+    data bytea NOT NULL,
+    nonce bytea NOT NULL,
+    version int8 DEFAULT 0 NOT NULL,
+    CONSTRAINT org_records_pkey PRIMARY KEY (org_id, id),
+    CONSTRAINT org_records_org_id_fkey FOREIGN KEY (org_id) REFERENCES public.organizations(id) ON DELETE CASCADE
+);
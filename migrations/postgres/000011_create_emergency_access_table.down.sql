@@ -0,0 +1 @@
+DROP TABLE public.emergency_access;
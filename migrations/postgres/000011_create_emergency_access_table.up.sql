@@ -0,0 +1,16 @@
+CREATE TABLE public.emergency_access (
+    id uuid NOT NULL,
+    grantor_id uuid NOT NULL,
+    grantee_id uuid NOT NULL,
+    wait_days integer NOT NULL CHECK (wait_days > 0),
+    status text DEFAULT 'granted' NOT NULL CHECK (status IN ('granted', 'requested', 'approved')),
+    key bytea NOT NULL,
+    requested_at timestamp,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT emergency_access_pkey PRIMARY KEY (id),
+    CONSTRAINT emergency_access_grantor_grantee_key UNIQUE (grantor_id, grantee_id),
+    CONSTRAINT emergency_access_grantor_id_fkey FOREIGN KEY (grantor_id) REFERENCES public.users(id) ON DELETE CASCADE,
+    CONSTRAINT emergency_access_grantee_id_fkey FOREIGN KEY (grantee_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX emergency_access_grantee_id_idx ON public.emergency_access USING btree (grantee_id);
@@ -0,0 +1,15 @@
+CREATE TABLE public.sends (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    data bytea NOT NULL,
+    nonce bytea NOT NULL,
+    password_hash text DEFAULT '' NOT NULL,
+    max_views integer NOT NULL CHECK (max_views > 0),
+    views_left integer NOT NULL CHECK (views_left >= 0),
+    expires_at timestamp NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT sends_pkey PRIMARY KEY (id),
+    CONSTRAINT sends_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX sends_expires_at_idx ON public.sends USING btree (expires_at);
@@ -0,0 +1,8 @@
+DELETE FROM public.records WHERE type = 'ssh_key';
+DELETE FROM public.org_records WHERE type = 'ssh_key';
+
+ALTER TYPE record_type RENAME TO record_type_old;
+CREATE TYPE record_type AS ENUM ('credentials', 'text', 'binary', 'card');
+ALTER TABLE public.records ALTER COLUMN type TYPE record_type USING type::text::record_type;
+ALTER TABLE public.org_records ALTER COLUMN type TYPE record_type USING type::text::record_type;
+DROP TYPE record_type_old;
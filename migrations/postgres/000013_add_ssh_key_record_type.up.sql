@@ -0,0 +1 @@
+ALTER TYPE record_type ADD VALUE 'ssh_key';
@@ -0,0 +1,8 @@
+CREATE TABLE users (
+    id text NOT NULL,
+    login text NOT NULL,
+    password_hash text NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT users_pkey PRIMARY KEY (id),
+    CONSTRAINT users_login_key UNIQUE (login)
+);
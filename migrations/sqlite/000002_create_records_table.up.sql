@@ -0,0 +1,12 @@
+CREATE TABLE records (
+	id text NOT NULL,
+	user_id text NOT NULL,
+	type text NOT NULL CHECK (type IN ('credentials', 'text', 'binary', 'card')),
+	data blob NOT NULL,
+	nonce blob NOT NULL,
+	version integer DEFAULT 0 NOT NULL,
+	CONSTRAINT records_pk PRIMARY KEY (id, user_id),
+	CONSTRAINT records_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX records_user_id_idx ON records (user_id);
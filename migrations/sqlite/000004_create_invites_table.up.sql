@@ -0,0 +1,10 @@
+CREATE TABLE invites (
+    id text NOT NULL,
+    code_hash text NOT NULL,
+    max_uses integer DEFAULT 1 NOT NULL,
+    uses integer DEFAULT 0 NOT NULL,
+    expires_at timestamp,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT invites_pkey PRIMARY KEY (id),
+    CONSTRAINT invites_code_hash_key UNIQUE (code_hash)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE audit_events (
+    id integer NOT NULL,
+    user_id text,
+    type text NOT NULL,
+    details text DEFAULT '' NOT NULL,
+    ip text DEFAULT '' NOT NULL,
+    user_agent text DEFAULT '' NOT NULL,
+    request_id text DEFAULT '' NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT audit_events_pkey PRIMARY KEY (id),
+    CONSTRAINT audit_events_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX audit_events_user_id_idx ON audit_events (user_id, id);
@@ -0,0 +1,2 @@
+DROP TABLE access_token_records;
+DROP TABLE access_tokens;
@@ -0,0 +1,24 @@
+CREATE TABLE access_tokens (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    name text NOT NULL,
+    secret_hash text NOT NULL,
+    read_only boolean DEFAULT false NOT NULL,
+    tags text DEFAULT '[]' NOT NULL,
+    wrapped_key blob,
+    expires_at timestamp NOT NULL,
+    last_used_at timestamp,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT access_tokens_pkey PRIMARY KEY (id),
+    CONSTRAINT access_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX access_tokens_user_id_idx ON access_tokens (user_id);
+
+CREATE TABLE access_token_records (
+    token_id text NOT NULL,
+    record_id text NOT NULL,
+    key blob NOT NULL,
+    CONSTRAINT access_token_records_pkey PRIMARY KEY (token_id, record_id),
+    CONSTRAINT access_token_records_token_id_fkey FOREIGN KEY (token_id) REFERENCES access_tokens(id) ON DELETE CASCADE
+);
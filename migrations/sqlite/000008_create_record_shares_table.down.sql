@@ -0,0 +1,2 @@
+DROP TABLE record_shares;
+DROP TABLE user_keys;
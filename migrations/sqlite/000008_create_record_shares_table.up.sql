@@ -0,0 +1,21 @@
+CREATE TABLE user_keys (
+    user_id text NOT NULL,
+    public_key blob NOT NULL,
+    wrapped_private_key blob NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_keys_pkey PRIMARY KEY (user_id),
+    CONSTRAINT user_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE record_shares (
+    record_id text NOT NULL,
+    owner_id text NOT NULL,
+    recipient_id text NOT NULL,
+    key blob NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT record_shares_pkey PRIMARY KEY (record_id, owner_id, recipient_id),
+    CONSTRAINT record_shares_record_fkey FOREIGN KEY (record_id, owner_id) REFERENCES records(id, user_id) ON DELETE CASCADE,
+    CONSTRAINT record_shares_recipient_id_fkey FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX record_shares_recipient_id_idx ON record_shares (recipient_id);
@@ -0,0 +1,32 @@
+CREATE TABLE organizations (
+    id text NOT NULL,
+    name text NOT NULL,
+    key_version integer DEFAULT 1 NOT NULL,
+    rekey_needed boolean DEFAULT false NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT organizations_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE org_members (
+    org_id text NOT NULL,
+    user_id text NOT NULL,
+    role text NOT NULL CHECK (role IN ('owner', 'admin', 'writer', 'reader')),
+    key blob NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT org_members_pkey PRIMARY KEY (org_id, user_id),
+    CONSTRAINT org_members_org_id_fkey FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT org_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX org_members_user_id_idx ON org_members (user_id);
+
+CREATE TABLE org_records (
+    id text NOT NULL,
+    org_id text NOT NULL,
+    type text NOT NULL CHECK (type IN ('credentials', 'text', 'binary', 'card')),
+    data blob NOT NULL,
+    nonce blob NOT NULL,
+    version integer DEFAULT 0 NOT NULL,
+    CONSTRAINT org_records_pkey PRIMARY KEY (org_id, id),
+    CONSTRAINT org_records_org_id_fkey FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
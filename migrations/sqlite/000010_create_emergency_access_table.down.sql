@@ -0,0 +1 @@
+DROP TABLE emergency_access;
@@ -0,0 +1,15 @@
+CREATE TABLE sends (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    data blob NOT NULL,
+    nonce blob NOT NULL,
+    password_hash text DEFAULT '' NOT NULL,
+    max_views integer NOT NULL CHECK (max_views > 0),
+    views_left integer NOT NULL CHECK (views_left >= 0),
+    expires_at timestamp NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT sends_pkey PRIMARY KEY (id),
+    CONSTRAINT sends_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX sends_expires_at_idx ON sends (expires_at);
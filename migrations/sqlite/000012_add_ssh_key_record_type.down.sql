@@ -0,0 +1,39 @@
+CREATE TABLE record_shares_backup AS
+	SELECT s.* FROM record_shares s JOIN records r ON r.id = s.record_id AND r.user_id = s.owner_id
+	WHERE r.type != 'ssh_key';
+
+CREATE TABLE records_new (
+	id text NOT NULL,
+	user_id text NOT NULL,
+	type text NOT NULL CHECK (type IN ('credentials', 'text', 'binary', 'card')),
+	data blob NOT NULL,
+	nonce blob NOT NULL,
+	version integer DEFAULT 0 NOT NULL,
+	key blob,
+	CONSTRAINT records_pk PRIMARY KEY (id, user_id),
+	CONSTRAINT records_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+INSERT INTO records_new (id, user_id, type, data, nonce, version, key)
+	SELECT id, user_id, type, data, nonce, version, key FROM records WHERE type != 'ssh_key';
+DROP TABLE records;
+ALTER TABLE records_new RENAME TO records;
+CREATE INDEX records_user_id_idx ON records (user_id);
+
+DELETE FROM record_shares;
+INSERT INTO record_shares SELECT * FROM record_shares_backup;
+DROP TABLE record_shares_backup;
+
+CREATE TABLE org_records_new (
+    id text NOT NULL,
+    org_id text NOT NULL,
+    type text NOT NULL CHECK (type IN ('credentials', 'text', 'binary', 'card')),
+    data blob NOT NULL,
+    nonce blob NOT NULL,
+    version integer DEFAULT 0 NOT NULL,
+    CONSTRAINT org_records_pkey PRIMARY KEY (org_id, id),
+    CONSTRAINT org_records_org_id_fkey FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+INSERT INTO org_records_new (id, org_id, type, data, nonce, version)
+	SELECT id, org_id, type, data, nonce, version FROM org_records WHERE type != 'ssh_key';
+DROP TABLE org_records;
+ALTER TABLE org_records_new RENAME TO org_records;